@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	cloudfrontv1 "github.com/aws/aws-sdk-go/service/cloudfront"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	cloudfrontv2 "github.com/aws/aws-sdk-go-v2/service/cloudfront"
+	cloudfronttypes "github.com/aws/aws-sdk-go-v2/service/cloudfront/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// distribution is an SDK-neutral view of a CloudFront distribution summary.
+type distribution struct {
+	ID         string
+	DomainName string
+	Enabled    bool
+	Origins    []string
+}
+
+func (d distribution) String() string {
+	return fmt.Sprintf("%s (Domain: %s, Enabled: %v, Origins: %s)",
+		d.ID, d.DomainName, d.Enabled, strings.Join(d.Origins, ", "))
+}
+
+// This example demonstrates listing CloudFront distributions with both SDKs.
+// Both expose the same Marker/NextMarker pagination, which is walked by hand
+// here to show how the fields map across versions.
+func main() {
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	fmt.Print("=== CloudFront Distribution Interop Test ===\n\n")
+
+	// CloudFront is a global service served from us-east-1.
+	region := "us-east-1"
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	cfClientV1 := cloudfrontv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	cfClientV2 := cloudfrontv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Use v1 to list distributions
+	fmt.Println("1. Using SDK v1 to list distributions (following NextMarker)...")
+	distsV1 := make(map[string]distribution)
+	input := &cloudfrontv1.ListDistributionsInput{}
+	for page := 1; ; page++ {
+		out, err := cfClientV1.ListDistributions(input)
+		if err != nil {
+			log.Fatalf("Failed to list distributions with v1: %v", err)
+		}
+		list := out.DistributionList
+		if list == nil {
+			break
+		}
+		for _, summary := range list.Items {
+			d := distributionFromV1(summary)
+			distsV1[d.ID] = d
+		}
+		fmt.Printf("   page %d: %d distributions\n", page, len(list.Items))
+		if !aws.BoolValue(list.IsTruncated) {
+			break
+		}
+		input.Marker = list.NextMarker
+	}
+	fmt.Printf("   ✓ Found %d distributions using SDK v1\n", len(distsV1))
+
+	// Use v2 to list distributions
+	fmt.Println("\n2. Using SDK v2 to list distributions (following NextMarker)...")
+	distsV2 := make(map[string]distribution)
+	inputV2 := &cloudfrontv2.ListDistributionsInput{}
+	for page := 1; ; page++ {
+		out, err := cfClientV2.ListDistributions(ctx, inputV2)
+		if err != nil {
+			log.Fatalf("Failed to list distributions with v2: %v", err)
+		}
+		list := out.DistributionList
+		if list == nil {
+			break
+		}
+		for _, summary := range list.Items {
+			d := distributionFromV2(summary)
+			distsV2[d.ID] = d
+		}
+		fmt.Printf("   page %d: %d distributions\n", page, len(list.Items))
+		if list.IsTruncated == nil || !*list.IsTruncated {
+			break
+		}
+		inputV2.Marker = list.NextMarker
+	}
+	fmt.Printf("   ✓ Found %d distributions using SDK v2\n", len(distsV2))
+
+	if len(distsV1) == 0 && len(distsV2) == 0 {
+		fmt.Println("\nNo CloudFront distributions in this account; both SDKs agree on the empty list.")
+		return
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing distributions...")
+	ids := make([]string, 0, len(distsV1))
+	for id := range distsV1 {
+		ids = append(ids, id)
+	}
+	for id := range distsV2 {
+		if _, ok := distsV1[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	mismatches := 0
+	for _, id := range ids {
+		v1, inV1 := distsV1[id]
+		v2, inV2 := distsV2[id]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", id)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", id)
+			mismatches++
+		case v1.String() != v2.String():
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", id, v1, v2)
+			mismatches++
+		default:
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d distributions did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d distributions\n", len(ids))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Origins.Items is []*Origin, v2 is []Origin")
+	fmt.Println("  - v2 pagination fields (IsTruncated, NextMarker) still need nil checks")
+}
+
+func distributionFromV1(summary *cloudfrontv1.DistributionSummary) distribution {
+	d := distribution{
+		ID:         aws.StringValue(summary.Id),
+		DomainName: aws.StringValue(summary.DomainName),
+		Enabled:    aws.BoolValue(summary.Enabled),
+	}
+	if summary.Origins != nil {
+		for _, origin := range summary.Origins.Items {
+			d.Origins = append(d.Origins, aws.StringValue(origin.Id)+"="+aws.StringValue(origin.DomainName))
+		}
+	}
+	sort.Strings(d.Origins)
+	return d
+}
+
+func distributionFromV2(summary cloudfronttypes.DistributionSummary) distribution {
+	d := distribution{}
+	if summary.Id != nil {
+		d.ID = *summary.Id
+	}
+	if summary.DomainName != nil {
+		d.DomainName = *summary.DomainName
+	}
+	if summary.Enabled != nil {
+		d.Enabled = *summary.Enabled
+	}
+	if summary.Origins != nil {
+		for _, origin := range summary.Origins.Items {
+			id, domain := "", ""
+			if origin.Id != nil {
+				id = *origin.Id
+			}
+			if origin.DomainName != nil {
+				domain = *origin.DomainName
+			}
+			d.Origins = append(d.Origins, id+"="+domain)
+		}
+	}
+	sort.Strings(d.Origins)
+	return d
+}
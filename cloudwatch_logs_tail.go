@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	logsv1 "github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	logsv2 "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	logstypes "github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// logEvent is an SDK-neutral view of a log event. Timestamps are kept in
+// milliseconds since the epoch, as both SDKs return them.
+type logEvent struct {
+	ID        string
+	Stream    string
+	Timestamp int64
+	Message   string
+}
+
+func (e logEvent) String() string {
+	return fmt.Sprintf("%s %s: %q", time.UnixMilli(e.Timestamp).UTC().Format(time.RFC3339Nano), e.Stream, e.Message)
+}
+
+// This example demonstrates reading recent CloudWatch Logs events with both
+// SDKs: FilterLogEvents across the whole group, then GetLogEvents on the
+// most recently written stream, whose forward token never becomes nil.
+func main() {
+	logGroup := flag.String("log-group", "", "log group to read (required)")
+	window := flag.Duration("window", 5*time.Minute, "how far back to read events")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== CloudWatch Logs Tail Interop Test ===\n\n")
+
+	if *logGroup == "" {
+		fmt.Fprintln(os.Stderr, "-log-group is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	// Use one window for both SDKs so their results are comparable.
+	endTime := time.Now()
+	startTime := endTime.Add(-*window)
+	startMs, endMs := startTime.UnixMilli(), endTime.UnixMilli()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	logsClientV1 := logsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	logsClientV2 := logsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Printf("Log group: %s, window: %s to %s\n\n", *logGroup,
+		startTime.UTC().Format(time.RFC3339), endTime.UTC().Format(time.RFC3339))
+
+	// Use v1 to filter events
+	fmt.Println("1. Using SDK v1 to filter log events (all pages)...")
+	eventsV1 := make(map[string]logEvent)
+	err = logsClientV1.FilterLogEventsPagesWithContext(ctx, &logsv1.FilterLogEventsInput{
+		LogGroupName: aws.String(*logGroup),
+		StartTime:    aws.Int64(startMs),
+		EndTime:      aws.Int64(endMs),
+	}, func(page *logsv1.FilterLogEventsOutput, lastPage bool) bool {
+		for _, event := range page.Events {
+			e := logEventFromV1(event)
+			eventsV1[e.ID] = e
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatalf("Failed to filter log events with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d events using SDK v1\n", len(eventsV1))
+
+	// Use v2 to filter events
+	fmt.Println("\n2. Using SDK v2 to filter log events (all pages)...")
+	eventsV2 := make(map[string]logEvent)
+	paginator := logsv2.NewFilterLogEventsPaginator(logsClientV2, &logsv2.FilterLogEventsInput{
+		LogGroupName: aws.String(*logGroup),
+		StartTime:    aws.Int64(startMs),
+		EndTime:      aws.Int64(endMs),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Fatalf("Failed to filter log events with v2: %v", err)
+		}
+		for _, event := range page.Events {
+			e := logEventFromV2(event)
+			eventsV2[e.ID] = e
+		}
+	}
+	fmt.Printf("   ✓ Found %d events using SDK v2\n", len(eventsV2))
+
+	if len(eventsV1) == 0 && len(eventsV2) == 0 {
+		fmt.Printf("\nNo events in %s during the last %s; both SDKs agree on the empty result.\n", *logGroup, *window)
+		return
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing filtered events...")
+	ids := make([]string, 0, len(eventsV1))
+	for id := range eventsV1 {
+		ids = append(ids, id)
+	}
+	for id := range eventsV2 {
+		if _, ok := eventsV1[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	mismatches := 0
+	for _, id := range ids {
+		v1, inV1 := eventsV1[id]
+		v2, inV2 := eventsV2[id]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", v2)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", v1)
+			mismatches++
+		case v1 != v2:
+			fmt.Printf("   ✗ Event %s differs\n       v1: %s\n       v2: %s\n", id, v1, v2)
+			mismatches++
+		}
+	}
+	fmt.Printf("   %d of %d events match\n", len(ids)-mismatches, len(ids))
+
+	// Read the latest stream with GetLogEvents
+	fmt.Println("\n4. Reading the most recent stream with GetLogEvents...")
+	streams, err := logsClientV2.DescribeLogStreams(ctx, &logsv2.DescribeLogStreamsInput{
+		LogGroupName: aws.String(*logGroup),
+		OrderBy:      logstypes.OrderByLastEventTime,
+		Descending:   aws.Bool(true),
+		Limit:        aws.Int32(1),
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe log streams with v2: %v", err)
+	}
+	if len(streams.LogStreams) == 0 {
+		fmt.Println("   No log streams in the group")
+	} else {
+		stream := aws.StringValue(streams.LogStreams[0].LogStreamName)
+		countV1, err := getLogEventsV1(ctx, logsClientV1, *logGroup, stream, startMs, endMs)
+		if err != nil {
+			log.Fatalf("Failed to get log events with v1: %v", err)
+		}
+		countV2, err := getLogEventsV2(ctx, logsClientV2, *logGroup, stream, startMs, endMs)
+		if err != nil {
+			log.Fatalf("Failed to get log events with v2: %v", err)
+		}
+		if countV1 != countV2 {
+			fmt.Printf("   ✗ %s: %d events with v1, %d with v2\n", stream, countV1, countV2)
+			mismatches++
+		} else {
+			fmt.Printf("   ✓ %s: %d events with both SDKs\n", stream, countV1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d log event checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs return the same %d events from %s\n", len(ids), *logGroup)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Timestamps are *int64 milliseconds in both; v2 Limit is *int32 instead of *int64")
+	fmt.Println("  - FilterLogEvents has a paginator in v2; GetLogEvents does not, in either SDK")
+	fmt.Println("  - GetLogEvents never returns a nil NextForwardToken: stop when it equals the token sent")
+}
+
+// getLogEventsV1 reads a stream forward from startMs with v1 and returns the
+// number of events. The end of the stream is reached when the returned
+// forward token is the one that was sent.
+func getLogEventsV1(ctx context.Context, client *logsv1.CloudWatchLogs, group, stream string, startMs, endMs int64) (int, error) {
+	input := &logsv1.GetLogEventsInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+		StartTime:     aws.Int64(startMs),
+		EndTime:       aws.Int64(endMs),
+		StartFromHead: aws.Bool(true),
+	}
+	count := 0
+	for {
+		out, err := client.GetLogEventsWithContext(ctx, input)
+		if err != nil {
+			return 0, err
+		}
+		count += len(out.Events)
+		if input.NextToken != nil && aws.StringValue(out.NextForwardToken) == aws.StringValue(input.NextToken) {
+			return count, nil
+		}
+		input.NextToken = out.NextForwardToken
+	}
+}
+
+// getLogEventsV2 is the v2 counterpart of getLogEventsV1.
+func getLogEventsV2(ctx context.Context, client *logsv2.Client, group, stream string, startMs, endMs int64) (int, error) {
+	input := &logsv2.GetLogEventsInput{
+		LogGroupName:  aws.String(group),
+		LogStreamName: aws.String(stream),
+		StartTime:     aws.Int64(startMs),
+		EndTime:       aws.Int64(endMs),
+		StartFromHead: aws.Bool(true),
+	}
+	count := 0
+	for {
+		out, err := client.GetLogEvents(ctx, input)
+		if err != nil {
+			return 0, err
+		}
+		count += len(out.Events)
+		if input.NextToken != nil && aws.StringValue(out.NextForwardToken) == aws.StringValue(input.NextToken) {
+			return count, nil
+		}
+		input.NextToken = out.NextForwardToken
+	}
+}
+
+func logEventFromV1(event *logsv1.FilteredLogEvent) logEvent {
+	return logEvent{
+		ID:        aws.StringValue(event.EventId),
+		Stream:    aws.StringValue(event.LogStreamName),
+		Timestamp: aws.Int64Value(event.Timestamp),
+		Message:   aws.StringValue(event.Message),
+	}
+}
+
+func logEventFromV2(event logstypes.FilteredLogEvent) logEvent {
+	e := logEvent{}
+	if event.EventId != nil {
+		e.ID = *event.EventId
+	}
+	if event.LogStreamName != nil {
+		e.Stream = *event.LogStreamName
+	}
+	if event.Timestamp != nil {
+		e.Timestamp = *event.Timestamp
+	}
+	if event.Message != nil {
+		e.Message = *event.Message
+	}
+	return e
+}
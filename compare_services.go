@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates the generic comparison driver: every registered
+// ServiceComparer describes its resources with both SDKs, and the normalized
+// results are diffed as JSON. Adding a service only requires a new comparer.
+// With -profile-a and -profile-b it instead compares two accounts with SDK
+// v2, for example before and after an account migration. With -config the
+// services, regions and per-service options come from a JSON or YAML file,
+// such as testdata/config/checks.yaml, and every comparer runs in each of
+// its regions.
+func main() {
+	flag.String("config", "", "JSON or YAML file listing the services, regions and per-service options to check")
+	flag.String("services", "", "comma-separated comparers to run (default: all registered)")
+	region := flag.String("region", "us-east-1", "region to compare resources in; \"\" uses each SDK's default region")
+	list := flag.Bool("list", false, "list the registered comparers and exit")
+	listRegions := flag.Bool("list-regions", false, "list the regions enabled for the account, as both SDKs report them, and exit")
+	profileA := flag.String("profile-a", "", "shared config profile of the first account to compare (requires -profile-b)")
+	profileB := flag.String("profile-b", "", "shared config profile of the second account to compare (requires -profile-a)")
+	maxRetries := flag.Int("max-retries", -1, "retries per request in both SDKs (v2 gets one more attempt); negative keeps the SDK defaults")
+	dualStack := flag.Bool("dualstack", false, "use dual-stack (IPv4 and IPv6) endpoints in both SDKs")
+	fips := flag.Bool("fips", false, "use FIPS 140 validated endpoints in both SDKs")
+	allowRegionMismatch := flag.Bool("allow-region-mismatch", false, "compare even if the v1 session and v2 config resolve different regions, as they can with -region \"\"")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	outFile := flag.String("out", "", "write the report, including log output, to this file instead of stdout")
+	markdownFile := flag.String("markdown", "", "also write a Markdown summary of the results to this file, for pasting into a pull request")
+	sortBy := flag.String("sort-by", string(interop.InstanceOrderID), "order EC2 instances are compared in: id, launch-time or type")
+	captureDir := flag.String("capture", "", "record every API response into this directory, for -replay")
+	replayDir := flag.String("replay", "", "answer every API call from the responses recorded with -capture, without credentials or network")
+	useCache := flag.Bool("cache", false, "make each describe and list call once per region and SDK, serving repeats from a cache for the rest of the run")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			log.Fatalf("Failed to create report file: %v", err)
+		}
+		defer f.Close()
+		interop.SetOutput(f)
+	}
+	w := interop.Output
+
+	order, err := interop.ParseInstanceOrder(*sortBy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -sort-by: %v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	interop.SortInstancesBy = order
+
+	if *list {
+		for _, sc := range interop.Comparers() {
+			fmt.Fprintln(w, sc.Name())
+		}
+		return
+	}
+
+	fmt.Fprint(w, "=== Service Comparison Driver ===\n\n")
+
+	selected := interop.Comparers()
+	if len(cfg.Services) > 0 {
+		selected = nil
+		for _, name := range cfg.Services {
+			sc, ok := interop.LookupComparer(name)
+			if !ok {
+				log.Fatalf("Unknown comparer %q (use -list to see the registered ones)", name)
+			}
+			selected = append(selected, sc)
+		}
+	}
+
+	ctx := context.Background()
+	opts := []interop.ClientOption{interop.WithReadOnly(*readOnly), interop.WithMaxRetries(*maxRetries),
+		interop.WithDualStack(*dualStack), interop.WithFIPS(*fips), interop.WithRegionMismatch(*allowRegionMismatch)}
+
+	if *captureDir != "" && *replayDir != "" {
+		fmt.Fprintln(os.Stderr, "-capture and -replay cannot be used together")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if (*captureDir != "" || *replayDir != "") && (*profileA != "" || *profileB != "") {
+		// Both accounts make the same calls, concurrently, so their
+		// responses could not be told apart.
+		fmt.Fprintln(os.Stderr, "-capture and -replay cannot be used with -profile-a and -profile-b")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *useCache && (*profileA != "" || *profileB != "") {
+		// The cache is keyed by region, not account, so the two accounts
+		// would be served each other's results.
+		fmt.Fprintln(os.Stderr, "-cache cannot be used with -profile-a and -profile-b")
+		flag.Usage()
+		os.Exit(2)
+	}
+	var cache *interop.ResultCache
+	if *useCache {
+		cache = interop.NewResultCache()
+		opts = append(opts, interop.WithCache(cache))
+	}
+	if *captureDir != "" || *replayDir != "" {
+		dir, mode := *captureDir, interop.TraceCapture
+		if *replayDir != "" {
+			dir, mode = *replayDir, interop.TraceReplay
+		}
+		trace, err := interop.NewTrace(dir, mode)
+		if err != nil {
+			log.Fatalf("Failed to open trace directory: %v", err)
+		}
+		opts = append(opts, interop.WithTrace(trace))
+		fmt.Fprintf(w, "Trace: %s %s\n\n", mode, dir)
+	}
+
+	if *profileA != "" || *profileB != "" {
+		if *profileA == "" || *profileB == "" {
+			fmt.Fprintln(os.Stderr, "-profile-a and -profile-b must be used together")
+			flag.Usage()
+			os.Exit(2)
+		}
+		if len(cfg.Regions) > 1 {
+			fmt.Fprintf(os.Stderr, "-profile-a and -profile-b compare one region, but %s lists %d\n", cfg.File, len(cfg.Regions))
+			flag.Usage()
+			os.Exit(2)
+		}
+		results := compareAccounts(ctx, w, *region, *profileA, *profileB, selected, opts)
+		writeMarkdown(w, *markdownFile, results)
+		return
+	}
+
+	clients, err := interop.NewClients(ctx, *region, opts...)
+	if err != nil {
+		log.Fatalf("Failed to create clients: %v", err)
+	}
+	interop.PrintCredentialSources(ctx, clients.SessionV1, clients.ConfigV2)
+
+	if *listRegions {
+		regions, err := interop.EnabledRegions(ctx, clients)
+		if err != nil {
+			log.Fatalf("Failed to list enabled regions: %v", err)
+		}
+		fmt.Fprintf(w, "%d enabled regions:\n", len(regions))
+		for _, r := range regions {
+			fmt.Fprintf(w, "   %s\n", r)
+		}
+		return
+	}
+
+	// Clients are created once per region, starting with the one above.
+	clientsByRegion := map[string]*interop.Clients{*region: clients}
+	plan := planChecks(cfg, selected)
+	failures, checks := 0, 0
+	var results interop.ComparisonResults
+	for i, rc := range plan {
+		regionClients, ok := clientsByRegion[rc.region]
+		if !ok {
+			regionClients, err = interop.NewClients(ctx, rc.region, opts...)
+			if err != nil {
+				log.Fatalf("Failed to create clients for %s: %v", rc.region, err)
+			}
+			clientsByRegion[rc.region] = regionClients
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if len(plan) > 1 {
+			fmt.Fprintf(w, "Region %s:\n", regionClients.Region)
+		}
+		for _, sc := range rc.comparers {
+			checks++
+			interop.SortInstancesBy = order
+			if o := cfg.Options[sc.Name()]; o.SortBy != "" && !flagGiven("sort-by") {
+				// LoadConfigFile has validated the order.
+				interop.SortInstancesBy = interop.InstanceOrder(o.SortBy)
+			}
+			fmt.Fprintf(w, "%d. Comparing %s...\n", checks, sc.Name())
+			result := interop.Compare(ctx, regionClients, sc)
+			if len(plan) > 1 {
+				result.Name += " (" + regionClients.Region + ")"
+			}
+			results = append(results, result)
+			switch {
+			case result.Unavailable:
+				// Not every service exists in every region; move on.
+				fmt.Fprintf(w, "   - Service not available in %s\n", regionClients.Region)
+				interop.Verbosef("%s: %v", sc.Name(), result.Err)
+			case result.Err != nil:
+				fmt.Fprintf(w, "   ✗ %v\n", result.Err)
+				failures++
+			case !result.Match():
+				fmt.Fprintf(w, "   ✗ %d differences\n", len(result.Diffs))
+				for _, diff := range result.Diffs {
+					fmt.Fprintf(w, "       %s\n", diff)
+				}
+				failures++
+			default:
+				fmt.Fprintln(w, "   ✓ Normalized results match")
+			}
+		}
+	}
+
+	writeMarkdown(w, *markdownFile, results)
+	if cache != nil {
+		fmt.Fprintf(w, "\nCache: %s\n", cache.Stats())
+	}
+
+	where := clients.Region
+	if len(plan) > 1 {
+		where = fmt.Sprintf("%d regions", len(plan))
+	}
+	if failures > 0 {
+		fmt.Fprintf(w, "\n✗ %d of %d comparisons reported problems\n", failures, checks)
+		os.Exit(1)
+	}
+
+	fmt.Fprintln(w, "\n=== Conclusion ===")
+	if unavailable := results.Unavailable(); unavailable > 0 {
+		fmt.Fprintf(w, "✓ All %d comparisons of services available in %s found identical results in both SDKs (%d skipped)\n",
+			checks-unavailable, where, unavailable)
+	} else {
+		fmt.Fprintf(w, "✓ All %d comparisons in %s found identical results in both SDKs\n", checks, where)
+	}
+	fmt.Fprintln(w, "\nKey differences between v1 and v2:")
+	fmt.Fprintln(w, "  - v1 list results are slices of pointers, v2 slices of values")
+	fmt.Fprintln(w, "  - v1 enums are *string, v2 uses named string types")
+	fmt.Fprintln(w, "  - both shapes encode to the same JSON once normalized")
+}
+
+// regionChecks are the comparers to run in one region.
+type regionChecks struct {
+	region    string
+	comparers []interop.ServiceComparer
+}
+
+// planChecks groups the selected comparers by the regions they run in: the
+// regions of cfg, or those of the comparer's options when the config file
+// sets them. Regions are in the order they are first listed.
+func planChecks(cfg interop.Config, selected []interop.ServiceComparer) []regionChecks {
+	var plan []regionChecks
+	index := make(map[string]int)
+	for _, sc := range selected {
+		regions := cfg.Regions
+		if o := cfg.Options[sc.Name()]; len(o.Regions) > 0 {
+			regions = o.Regions
+		}
+		for _, r := range regions {
+			i, ok := index[r]
+			if !ok {
+				i = len(plan)
+				index[r] = i
+				plan = append(plan, regionChecks{region: r})
+			}
+			plan[i].comparers = append(plan[i].comparers, sc)
+		}
+	}
+	return plan
+}
+
+// flagGiven reports whether the flag name was set on the command line.
+func flagGiven(name string) bool {
+	given := false
+	flag.Visit(func(f *flag.Flag) { given = given || f.Name == name })
+	return given
+}
+
+// compareAccounts runs the selected comparers with SDK v2 against the
+// accounts behind two profiles, describing both accounts concurrently,
+// writes the differences to w resource by resource and returns the results.
+func compareAccounts(ctx context.Context, w io.Writer, region, profileA, profileB string, selected []interop.ServiceComparer, opts []interop.ClientOption) interop.ComparisonResults {
+	fmt.Fprintf(w, "Comparing account A (profile %s) with account B (profile %s) using SDK v2\n\n", profileA, profileB)
+
+	optsA := append([]interop.ClientOption{interop.WithProfile(profileA)}, opts...)
+	optsB := append([]interop.ClientOption{interop.WithProfile(profileB)}, opts...)
+	var clientsA, clientsB *interop.Clients
+	var errA, errB error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		clientsA, errA = interop.NewClients(ctx, region, optsA...)
+	}()
+	go func() {
+		defer wg.Done()
+		clientsB, errB = interop.NewClients(ctx, region, optsB...)
+	}()
+	wg.Wait()
+	if errA != nil {
+		log.Fatalf("Failed to create clients for profile %s: %v", profileA, errA)
+	}
+	if errB != nil {
+		log.Fatalf("Failed to create clients for profile %s: %v", profileB, errB)
+	}
+
+	identical := 0
+	var results interop.ComparisonResults
+	for i, sc := range selected {
+		fmt.Fprintf(w, "%d. Comparing %s...\n", i+1, sc.Name())
+		result := interop.CompareAccounts(ctx, clientsA, clientsB, sc)
+		results = append(results, result)
+		switch {
+		case result.Unavailable:
+			fmt.Fprintf(w, "   - Service not available in %s\n", region)
+		case result.Err != nil:
+			log.Fatalf("Failed to compare %s: %v", sc.Name(), result.Err)
+		case !result.Match():
+			fmt.Fprintf(w, "   %d differences (first = A, second = B)\n", len(result.Diffs))
+			for _, diff := range result.Diffs {
+				fmt.Fprintf(w, "       %s\n", diff)
+			}
+		default:
+			fmt.Fprintln(w, "   ✓ Both accounts have identical resources")
+			identical++
+		}
+	}
+
+	// Accounts are expected to differ, so differences are reported but do
+	// not fail the run; only errors do.
+	fmt.Fprintln(w, "\n=== Conclusion ===")
+	fmt.Fprintf(w, "%d of %d comparers found identical resources in both accounts\n", identical, len(selected))
+	return results
+}
+
+// writeMarkdown writes the Markdown summary of results to path, unless path
+// is empty. The text report on w is unaffected.
+func writeMarkdown(w io.Writer, path string, results interop.ComparisonResults) {
+	if path == "" {
+		return
+	}
+	if err := os.WriteFile(path, []byte(results.RenderMarkdown()), 0o644); err != nil {
+		log.Printf("Warning: Failed to write Markdown summary: %v", err)
+		return
+	}
+	fmt.Fprintf(w, "\nMarkdown summary written to %s\n", path)
+}
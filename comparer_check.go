@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// fakeVolume is the normalized form the fake comparers describe.
+type fakeVolume struct {
+	ID   string `json:"id,omitempty"`
+	Size int    `json:"size"`
+}
+
+// fakeComparer is a ServiceComparer that returns canned results instead of
+// calling AWS. DescribeV1 returns v1 and errV1; DescribeV2 returns the
+// result of the clients' region from v2 and errV2, so that CompareAccounts
+// can be given two accounts that differ.
+type fakeComparer struct {
+	v1    []fakeVolume
+	v2    map[string][]fakeVolume
+	errV1 error
+	errV2 map[string]error
+}
+
+func (f *fakeComparer) Name() string { return "fake" }
+
+func (f *fakeComparer) DescribeV1(ctx context.Context, c *interop.Clients) (any, error) {
+	return f.v1, f.errV1
+}
+
+func (f *fakeComparer) DescribeV2(ctx context.Context, c *interop.Clients) (any, error) {
+	return f.v2[c.Region], f.errV2[c.Region]
+}
+
+func (f *fakeComparer) Normalize(out any) any { return out }
+
+// keyedFakeComparer matches volumes by ID in CompareAccounts.
+type keyedFakeComparer struct{ fakeComparer }
+
+func (k *keyedFakeComparer) KeyField() string { return "id" }
+
+// comparerCase is one comparison and the result it must have: a match, a
+// diff containing wantDiff, or an error containing wantErr, Unavailable
+// when wantUnavailable is set.
+type comparerCase struct {
+	name            string
+	sc              interop.ServiceComparer
+	wantDiff        string
+	wantErr         string
+	wantUnavailable bool
+}
+
+// check returns why result is not what c wants, or "" if it is.
+func (c comparerCase) check(result interop.ComparisonResult) string {
+	switch {
+	case c.wantErr != "":
+		if result.Err == nil || !strings.Contains(result.Err.Error(), c.wantErr) {
+			return fmt.Sprintf("got error %v, want one containing %q", result.Err, c.wantErr)
+		}
+		if result.Unavailable != c.wantUnavailable {
+			return fmt.Sprintf("got Unavailable %t, want %t", result.Unavailable, c.wantUnavailable)
+		}
+	case result.Err != nil:
+		return fmt.Sprintf("got error %v", result.Err)
+	case c.wantDiff != "":
+		if len(result.Diffs) != 1 || !strings.Contains(result.Diffs[0], c.wantDiff) {
+			return fmt.Sprintf("got diffs %q, want one containing %q", result.Diffs, c.wantDiff)
+		}
+	case !result.Match():
+		return fmt.Sprintf("got diffs %q, want a match", result.Diffs)
+	}
+	return ""
+}
+
+// describe returns what result holds, for a passing case.
+func describe(result interop.ComparisonResult) string {
+	switch {
+	case result.Err != nil && result.Unavailable:
+		return "unavailable: " + result.Err.Error()
+	case result.Err != nil:
+		return "error: " + result.Err.Error()
+	case len(result.Diffs) > 0:
+		return "diff: " + strings.Join(result.Diffs, "; ")
+	}
+	return "match"
+}
+
+// This example demonstrates interop.Compare and interop.CompareAccounts,
+// which compare-services runs for every comparer, against fake comparers
+// that return canned results instead of calling AWS. Each case checks that
+// a match, a field that differs and a describe call that fails on either
+// side, or in a region without the service, give the ComparisonResult
+// compare-services reports. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Comparer Test ===\n\n")
+
+	ctx := context.Background()
+	// No client is used: the fake comparers only read the region.
+	east, west := &interop.Clients{Region: "us-east-1"}, &interop.Clients{Region: "eu-west-1"}
+
+	volumes := []fakeVolume{{ID: "vol-1", Size: 8}, {ID: "vol-2", Size: 100}}
+	resized := []fakeVolume{{ID: "vol-1", Size: 8}, {ID: "vol-2", Size: 200}}
+	// An error naming no host, as both SDKs return for a service that is
+	// absent from the region.
+	noHost := fmt.Errorf("send request: %w", &net.DNSError{Err: "no such host", Name: "fake.amazonaws.com", IsNotFound: true})
+	denied := errors.New("AccessDenied: not authorized")
+
+	compareCases := []comparerCase{
+		{
+			name: "Same volumes in both SDKs",
+			sc:   &fakeComparer{v1: volumes, v2: map[string][]fakeVolume{"us-east-1": volumes}},
+		},
+		{
+			name:     "A size that differs",
+			sc:       &fakeComparer{v1: volumes, v2: map[string][]fakeVolume{"us-east-1": resized}},
+			wantDiff: "$[1].size: 100 != 200",
+		},
+		{
+			name:    "v1 describe call failing",
+			sc:      &fakeComparer{errV1: denied},
+			wantErr: "describing with v1: AccessDenied",
+		},
+		{
+			name:    "v2 describe call failing",
+			sc:      &fakeComparer{v1: volumes, errV2: map[string]error{"us-east-1": denied}},
+			wantErr: "describing with v2: AccessDenied",
+		},
+		{
+			name:            "Service absent from the region",
+			sc:              &fakeComparer{errV1: noHost},
+			wantErr:         "service not available in us-east-1",
+			wantUnavailable: true,
+		},
+	}
+
+	accountCases := []comparerCase{
+		{
+			name: "Same volumes in both accounts",
+			sc:   &keyedFakeComparer{fakeComparer{v2: map[string][]fakeVolume{"us-east-1": volumes, "eu-west-1": volumes}}},
+		},
+		{
+			// Matched by ID, the extra volume is the only difference.
+			name: "A volume only in the second account",
+			sc: &keyedFakeComparer{fakeComparer{v2: map[string][]fakeVolume{
+				"us-east-1": volumes[1:], "eu-west-1": volumes,
+			}}},
+			wantDiff: "$.vol-1: only in second",
+		},
+		{
+			name:     "A size that differs between accounts",
+			sc:       &keyedFakeComparer{fakeComparer{v2: map[string][]fakeVolume{"us-east-1": volumes, "eu-west-1": resized}}},
+			wantDiff: "$.vol-2.size: 100 != 200",
+		},
+		{
+			name:    "First account describe call failing",
+			sc:      &fakeComparer{v2: map[string][]fakeVolume{"eu-west-1": volumes}, errV2: map[string]error{"us-east-1": denied}},
+			wantErr: "describing first account: AccessDenied",
+		},
+		{
+			name:            "Second account without the service",
+			sc:              &fakeComparer{v2: map[string][]fakeVolume{"us-east-1": volumes}, errV2: map[string]error{"eu-west-1": noHost}},
+			wantErr:         "service not available in eu-west-1",
+			wantUnavailable: true,
+		},
+		{
+			name:    "A volume without its key field",
+			sc:      &keyedFakeComparer{fakeComparer{v2: map[string][]fakeVolume{"us-east-1": volumes, "eu-west-1": {{Size: 8}}}}},
+			wantErr: `indexing second account: item 0 has no string "id" field`,
+		},
+	}
+
+	failures := 0
+	run := func(cases []comparerCase, compare func(interop.ServiceComparer) interop.ComparisonResult) {
+		for _, c := range cases {
+			result := compare(c.sc)
+			if problem := c.check(result); problem != "" {
+				fmt.Printf("   ✗ %s: %s\n", c.name, problem)
+				failures++
+				continue
+			}
+			fmt.Printf("   ✓ %s: %s\n", c.name, describe(result))
+		}
+	}
+
+	fmt.Println("1. Comparing v1 and v2 results with interop.Compare...")
+	run(compareCases, func(sc interop.ServiceComparer) interop.ComparisonResult {
+		return interop.Compare(ctx, east, sc)
+	})
+
+	fmt.Println("\n2. Comparing two accounts with interop.CompareAccounts...")
+	run(accountCases, func(sc interop.ServiceComparer) interop.ComparisonResult {
+		return interop.CompareAccounts(ctx, east, west, sc)
+	})
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d comparer checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ All %d comparisons gave the expected match, diff or error\n", len(compareCases)+len(accountCases))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None here: Compare diffs the normalized v1 and v2 results, and CompareAccounts two v2 results, so both only see what Normalize returns")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// echoTransport answers EC2 DescribeInstances with a single running instance
+// whose ID is the first InstanceId in the request, so every caller can check
+// that it received the response to its own request.
+type echoTransport struct {
+	requests atomic.Int64
+}
+
+func (t *echoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	id := form.Get("InstanceId.1")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body: io.NopCloser(strings.NewReader(`<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">` +
+			`<requestId>` + id + `</requestId><reservationSet><item><reservationId>r-` + id + `</reservationId>` +
+			`<instancesSet><item><instanceId>` + id + `</instanceId><instanceState><code>16</code><name>running</name></instanceState></item></instancesSet>` +
+			`</item></reservationSet></DescribeInstancesResponse>`)),
+		Request: req,
+	}, nil
+}
+
+// stressResult is what one SDK's run of the stress check observed.
+type stressResult struct {
+	Calls    int64
+	Failures int64
+	Panics   int64
+	Elapsed  time.Duration
+}
+
+func (r stressResult) String() string {
+	return fmt.Sprintf("%d calls in %s, %d failures, %d panics", r.Calls, r.Elapsed.Round(time.Millisecond), r.Failures, r.Panics)
+}
+
+// This example demonstrates that one v1 client and one v2 client can be
+// shared by many goroutines. Each goroutine describes its own instance ID
+// against a stub transport and checks that it gets that instance back. It
+// runs offline; use "go run -race concurrent_clients.go" to also check for
+// data races.
+func main() {
+	goroutines := flag.Int("goroutines", 50, "number of goroutines sharing each client")
+	calls := flag.Int("calls", 20, "DescribeInstances calls per goroutine")
+	flag.Parse()
+
+	fmt.Print("=== Shared Client Concurrency Stress Test ===\n\n")
+
+	if *goroutines < 1 || *calls < 1 {
+		fmt.Fprintln(os.Stderr, "-goroutines and -calls must be at least 1")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	region := "us-east-1"
+	ctx := context.Background()
+
+	transportV1 := &echoTransport{}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transportV1},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	ec2ClientV1 := ec2v1.New(sessV1)
+
+	transportV2 := &echoTransport{}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transportV2}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+
+	fmt.Printf("%d goroutines x %d calls per SDK\n\n", *goroutines, *calls)
+
+	describeV1 := func(id string) (string, error) {
+		out, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{
+			InstanceIds: []*string{aws.String(id)},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(out.Reservations) != 1 || len(out.Reservations[0].Instances) != 1 {
+			return "", fmt.Errorf("unexpected response shape")
+		}
+		return aws.StringValue(out.Reservations[0].Instances[0].InstanceId), nil
+	}
+	describeV2 := func(id string) (string, error) {
+		out, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{
+			InstanceIds: []string{id},
+		})
+		if err != nil {
+			return "", err
+		}
+		if len(out.Reservations) != 1 || len(out.Reservations[0].Instances) != 1 {
+			return "", fmt.Errorf("unexpected response shape")
+		}
+		return aws.StringValue(out.Reservations[0].Instances[0].InstanceId), nil
+	}
+
+	fmt.Println("1. Sharing one SDK v1 client...")
+	resultV1 := stress(*goroutines, *calls, describeV1)
+	fmt.Printf("   %s\n", resultV1)
+
+	fmt.Println("\n2. Sharing one SDK v2 client...")
+	resultV2 := stress(*goroutines, *calls, describeV2)
+	fmt.Printf("   %s\n", resultV2)
+
+	fmt.Println("\n3. Checking results...")
+	want := int64(*goroutines * *calls)
+	failures := 0
+	check := func(sdk string, r stressResult, requests int64) {
+		switch {
+		case r.Panics > 0 || r.Failures > 0:
+			fmt.Printf("   ✗ %s: %s\n", sdk, r)
+			failures++
+		case r.Calls != want || requests != want:
+			fmt.Printf("   ✗ %s: %d calls and %d requests, want %d of each\n", sdk, r.Calls, requests, want)
+			failures++
+		default:
+			fmt.Printf("   ✓ %s: all %d calls returned their own instance\n", sdk, r.Calls)
+		}
+	}
+	check("SDK v1", resultV1, transportV1.requests.Load())
+	check("SDK v2", resultV2, transportV2.requests.Load())
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d SDKs misbehaved under concurrent use\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs' clients are safe to share between goroutines")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 clients and sessions are safe for concurrent use once built; do not modify Handlers afterwards")
+	fmt.Println("  - v2 clients are immutable; per-call changes go through functional options instead")
+	fmt.Println("  - Neither SDK needs a client per goroutine; create one per service and share it")
+}
+
+// stress runs calls describe calls in each of goroutines goroutines, each
+// with its own instance ID, and reports calls that failed, panicked or
+// returned another goroutine's instance.
+func stress(goroutines, calls int, describe func(id string) (string, error)) stressResult {
+	var result stressResult
+	var wg sync.WaitGroup
+	start := time.Now()
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < calls; i++ {
+				id := fmt.Sprintf("i-%08x%08x", g, i)
+				func() {
+					defer func() {
+						if p := recover(); p != nil {
+							log.Printf("Warning: %s panicked: %v", id, p)
+							atomic.AddInt64(&result.Panics, 1)
+						}
+					}()
+					atomic.AddInt64(&result.Calls, 1)
+					got, err := describe(id)
+					switch {
+					case err != nil:
+						log.Printf("Warning: %s failed: %v", id, err)
+						atomic.AddInt64(&result.Failures, 1)
+					case got != id:
+						log.Printf("Warning: %s got the response for %s", id, got)
+						atomic.AddInt64(&result.Failures, 1)
+					}
+				}()
+			}
+		}(g)
+	}
+	wg.Wait()
+	result.Elapsed = time.Since(start)
+	return result
+}
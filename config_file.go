@@ -0,0 +1,135 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// invalidConfig is a config file LoadConfigFile must reject, with an error
+// containing wantErr.
+type invalidConfig struct {
+	name    string
+	file    string
+	content string
+	wantErr string
+}
+
+var invalidConfigs = []invalidConfig{
+	{
+		name: "misspelt top-level key", file: "typo.yaml",
+		content: "services: [ec2]\nregons: [eu-west-1]\n",
+		wantErr: `line 2: unknown key "regons"`,
+	},
+	{
+		name: "misspelt service option", file: "typo.json",
+		content: `{"options": {"ec2": {"sort": "type"}}}`,
+		wantErr: `unknown key "sort"`,
+	},
+	{
+		name: "unregistered comparer", file: "service.yaml",
+		content: "services: [ec2, lambda]\n",
+		wantErr: `unknown comparer "lambda"`,
+	},
+	{
+		name: "option of an unregistered comparer", file: "option.json",
+		content: `{"options": {"rds": {"regions": ["us-east-1"]}}}`,
+		wantErr: `options: unknown comparer "rds"`,
+	},
+	{
+		name: "sort order of a comparer without one", file: "sort.yaml",
+		content: "options:\n  s3:\n    sortBy: id\n",
+		wantErr: "only the ec2 comparer has a sort order",
+	},
+	{
+		name: "unknown sort order", file: "order.yml",
+		content: "options:\n  ec2:\n    sortBy: name\n",
+		wantErr: `unknown instance order "name"`,
+	},
+	{
+		name: "both region and regions", file: "regions.json",
+		content: `{"region": "us-east-1", "regions": ["eu-west-1"]}`,
+		wantErr: "region and regions cannot both be set",
+	},
+	{
+		name: "value of the wrong type", file: "type.yaml",
+		content: "readOnly: sometimes\n",
+		wantErr: "sometimes",
+	},
+	{
+		name: "unsupported extension", file: "checks.toml",
+		content: "services = [\"ec2\"]\n",
+		wantErr: "unsupported config file extension",
+	},
+}
+
+// This example demonstrates interop.LoadConfigFile, which reads the
+// -config file of a migration-validation suite. The JSON and YAML samples
+// in testdata/config must load to the same ConfigFile, and files with a
+// misspelt key, an unregistered comparer or an option that does not apply
+// must be rejected with an error naming the problem. Nothing is sent to
+// AWS.
+func main() {
+	interop.ParseFlags()
+
+	fmt.Print("=== Config File Test ===\n\n")
+
+	failures := 0
+
+	fmt.Println("1. Loading the sample config files...")
+	yamlFile, err := interop.LoadConfigFile(filepath.Join("testdata", "config", "checks.yaml"))
+	if err != nil {
+		log.Fatalf("Failed to load the YAML sample: %v", err)
+	}
+	jsonFile, err := interop.LoadConfigFile(filepath.Join("testdata", "config", "checks.json"))
+	if err != nil {
+		log.Fatalf("Failed to load the JSON sample: %v", err)
+	}
+	if reflect.DeepEqual(yamlFile, jsonFile) {
+		fmt.Printf("   ✓ Both samples list services %s in regions %s, with options for %d services\n",
+			strings.Join(yamlFile.Services, ","), strings.Join(yamlFile.Regions, ","), len(yamlFile.Options))
+	} else {
+		fmt.Printf("   ✗ The samples differ\n       YAML: %+v\n       JSON: %+v\n", *yamlFile, *jsonFile)
+		failures++
+	}
+
+	dir, err := os.MkdirTemp("", "config-file")
+	if err != nil {
+		log.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	fmt.Println("\n2. Loading invalid config files...")
+	for _, c := range invalidConfigs {
+		path := filepath.Join(dir, c.file)
+		if err := os.WriteFile(path, []byte(c.content), 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", path, err)
+		}
+		_, err := interop.LoadConfigFile(path)
+		switch {
+		case err == nil:
+			fmt.Printf("   ✗ %s: accepted\n", c.name)
+			failures++
+		case !strings.Contains(err.Error(), c.wantErr) || !strings.Contains(err.Error(), c.file):
+			fmt.Printf("   ✗ %s: got %v, want an error naming %s and %q\n", c.name, err, c.file, c.wantErr)
+			failures++
+		default:
+			fmt.Printf("   ✓ %s: %v\n", c.name, strings.TrimPrefix(err.Error(), dir+string(filepath.Separator)))
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d config file checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ The JSON and YAML samples load alike, and every invalid file is rejected with an error naming the file and the problem")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None: the config file is read by the programs, not by either SDK, and sets what both SDKs are given")
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// precedenceCase resolves a Config from args and env. withFlags gives the
+// program the shared flags, with regionDefault as the default of -region;
+// without them the program has no flags, like the programs that only read
+// their region from the Config, and an -output of text or csv. wantErr,
+// when set, is a substring of the expected error, and wantWarning of the
+// one expected warning.
+type precedenceCase struct {
+	name          string
+	withFlags     bool
+	regionDefault string
+	args          []string
+	env           map[string]string
+	want          interop.Config
+	wantErr       string
+	wantWarning   string
+}
+
+// defaults is the Config of a program that sets nothing.
+var defaults = interop.Config{
+	Region:    interop.DefaultRegion,
+	Output:    interop.OutputText,
+	UserAgent: interop.UserAgentSuffix,
+	Regions:   []string{interop.DefaultRegion},
+}
+
+// with returns defaults changed by edit.
+func with(edit func(c *interop.Config)) interop.Config {
+	return edited(defaults, edit)
+}
+
+// edited returns c changed by edit. Regions follows Region unless edit sets
+// it.
+func edited(c interop.Config, edit func(c *interop.Config)) interop.Config {
+	c.Regions = nil
+	edit(&c)
+	if c.Regions == nil {
+		c.Regions = []string{c.Region}
+	}
+	return c
+}
+
+// checksFile is the sample config file, and fromFile the Config it gives a
+// program that sets nothing else.
+var (
+	checksFile = "testdata/config/checks.yaml"
+	fromFile   = with(func(c *interop.Config) {
+		c.File, c.ReadOnly, c.UserAgent = checksFile, true, "migration-suite/1.0"
+		c.Region, c.Regions = "us-east-1", []string{"us-east-1", "eu-west-1"}
+		c.Services = []string{"ec2", "s3"}
+		c.Options = map[string]interop.ServiceOptions{
+			"ec2": {SortBy: "launch-time"},
+			"s3":  {Regions: []string{"us-east-1"}},
+		}
+	})
+)
+
+// withFile returns fromFile changed by edit.
+func withFile(edit func(c *interop.Config)) interop.Config {
+	return edited(fromFile, edit)
+}
+
+var precedenceCases = []precedenceCase{
+	{name: "nothing set keeps the defaults", withFlags: true, want: defaults},
+	{
+		name: "environment only", withFlags: true,
+		env:  map[string]string{interop.RegionEnv: "eu-west-1", interop.VerboseEnv: "true", interop.OutputEnv: "csv"},
+		want: with(func(c *interop.Config) { c.Region, c.Verbose, c.Output = "eu-west-1", true, "csv" }),
+	},
+	{
+		name: "SDKMT_OUTPUT the program does not support is ignored", withFlags: true,
+		env:         map[string]string{interop.OutputEnv: interop.OutputJSONL},
+		want:        defaults,
+		wantWarning: interop.OutputEnv,
+	},
+	{
+		name: "SDKMT_OUTPUT without the flag is kept",
+		env:  map[string]string{interop.OutputEnv: interop.OutputJSONL},
+		want: with(func(c *interop.Config) { c.Output = interop.OutputJSONL }),
+	},
+	{
+		name: "flag only", withFlags: true,
+		args: []string{"-region", "ap-south-1", "-read-only"},
+		want: with(func(c *interop.Config) { c.Region, c.ReadOnly = "ap-south-1", true }),
+	},
+	{
+		name: "flag overrides environment", withFlags: true,
+		args: []string{"-region", "ap-south-1", "-user-agent", "flag/1"},
+		env:  map[string]string{interop.RegionEnv: "eu-west-1", interop.UserAgentEnv: "env/1"},
+		want: with(func(c *interop.Config) { c.Region, c.UserAgent = "ap-south-1", "flag/1" }),
+	},
+	{
+		name: "flag set to its default still overrides environment", withFlags: true,
+		args: []string{"-region", "us-east-1", "-verbose=false"},
+		env:  map[string]string{interop.RegionEnv: "eu-west-1", interop.VerboseEnv: "true"},
+		want: defaults,
+	},
+	{
+		name: "empty environment variable is unset", withFlags: true,
+		env:  map[string]string{interop.RegionEnv: "", interop.AssumeYesEnv: ""},
+		want: defaults,
+	},
+	{
+		name: "program default of -region is kept", withFlags: true, regionDefault: "us-west-2",
+		want: with(func(c *interop.Config) { c.Region = "us-west-2" }),
+	},
+	{
+		name: "environment overrides the program default", withFlags: true, regionDefault: "us-west-2",
+		env:  map[string]string{interop.RegionEnv: "eu-west-1"},
+		want: with(func(c *interop.Config) { c.Region = "eu-west-1" }),
+	},
+	{name: "no flags keeps the package defaults", want: defaults},
+	{
+		name: "no flags reads the environment",
+		env:  map[string]string{interop.RegionEnv: "eu-west-1", interop.AssumeYesEnv: "1", interop.ProfileEnv: "ci"},
+		want: with(func(c *interop.Config) { c.Region, c.AssumeYes, c.Profile = "eu-west-1", true, "ci" }),
+	},
+	{
+		name: "config file overrides the defaults", withFlags: true,
+		args: []string{"-config", checksFile},
+		want: fromFile,
+	},
+	{
+		name: "environment overrides the config file", withFlags: true,
+		args: []string{"-config", checksFile},
+		env:  map[string]string{interop.RegionEnv: "eu-central-1", interop.ReadOnlyEnv: "false"},
+		want: withFile(func(c *interop.Config) { c.Region, c.ReadOnly = "eu-central-1", false }),
+	},
+	{
+		name: "flag overrides the config file", withFlags: true,
+		args: []string{"-config", checksFile, "-region", "ap-south-1", "-services", "s3", "-user-agent", "flag/1"},
+		want: withFile(func(c *interop.Config) { c.Region, c.Services, c.UserAgent = "ap-south-1", []string{"s3"}, "flag/1" }),
+	},
+	{
+		name: "-config overrides SDKMT_CONFIG", withFlags: true,
+		args: []string{"-config", checksFile},
+		env:  map[string]string{interop.ConfigEnv: "testdata/config/missing.yaml"},
+		want: fromFile,
+	},
+	{
+		name: "no flags reads the config file of SDKMT_CONFIG",
+		env:  map[string]string{interop.ConfigEnv: checksFile},
+		want: fromFile,
+	},
+	{
+		name: "missing config file", withFlags: true,
+		env:     map[string]string{interop.ConfigEnv: "testdata/config/missing.yaml"},
+		wantErr: "missing.yaml",
+	},
+	{
+		name: "invalid boolean with the flag", withFlags: true,
+		env:     map[string]string{interop.ReadOnlyEnv: "maybe"},
+		wantErr: interop.ReadOnlyEnv,
+	},
+	{
+		name: "-output the program does not support", withFlags: true,
+		args:    []string{"-output", interop.OutputJSONL},
+		wantErr: "-output",
+	},
+	{
+		name:    "invalid boolean without the flag",
+		env:     map[string]string{interop.VerboseEnv: "yes please"},
+		wantErr: interop.VerboseEnv,
+	},
+}
+
+// resolve runs one case, returning the Config and, for programs with flags,
+// the values the program's own flag variables ended up with.
+func resolve(c precedenceCase) (cfg interop.Config, region string, verbose bool, err error) {
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var regionFlag *string
+	var verboseFlag *bool
+	if c.withFlags {
+		def := c.regionDefault
+		if def == "" {
+			def = interop.DefaultRegion
+		}
+		fs.Bool("yes", false, "")
+		fs.Bool("read-only", false, "")
+		verboseFlag = fs.Bool("verbose", false, "")
+		fs.String("user-agent", interop.UserAgentSuffix, "")
+		regionFlag = fs.String("region", def, "")
+		interop.OutputFlag(fs, "", interop.OutputText, interop.OutputCSV)
+		fs.String("config", "", "")
+		fs.String("services", "", "")
+	}
+	if err := fs.Parse(c.args); err != nil {
+		return cfg, "", false, err
+	}
+	cfg, err = interop.ResolveConfig(fs, func(name string) (string, bool) {
+		v, ok := c.env[name]
+		return v, ok
+	})
+	if regionFlag != nil {
+		region, verbose = *regionFlag, *verboseFlag
+	}
+	return cfg, region, verbose, err
+}
+
+// This example demonstrates interop.ResolveConfig, which gives every
+// program the same configuration precedence: a flag given on the command
+// line, then its SDKMT_* environment variable, then the config file of
+// -config or SDKMT_CONFIG, then the default. Each case
+// resolves a Config from fixed arguments and a fake environment, for a
+// program with the shared flags and for one without any, and checks the
+// result and the program's own flag variables. Nothing is sent to AWS.
+func main() {
+	interop.ParseFlags()
+
+	fmt.Print("=== Configuration Precedence Test ===\n\n")
+
+	failures := 0
+	for i, c := range precedenceCases {
+		fmt.Printf("%d. %s...\n", i+1, c.name)
+		cfg, region, verbose, err := resolve(c)
+		warnings := cfg.Warnings
+		cfg.Warnings = nil
+		switch {
+		case c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)):
+			fmt.Printf("   ✗ Got error %v, want one naming %s\n", err, c.wantErr)
+			failures++
+		case c.wantErr != "":
+			fmt.Printf("   ✓ Rejected: %v\n", err)
+		case err != nil:
+			fmt.Printf("   ✗ Unexpected error: %v\n", err)
+			failures++
+		case c.wantWarning == "" && len(warnings) > 0,
+			c.wantWarning != "" && (len(warnings) != 1 || !strings.Contains(warnings[0], c.wantWarning)):
+			fmt.Printf("   ✗ Got warnings %q, want one naming %s\n", warnings, c.wantWarning)
+			failures++
+		case !reflect.DeepEqual(cfg, c.want):
+			fmt.Printf("   ✗ Got  %+v\n     want %+v\n", cfg, c.want)
+			failures++
+		case c.withFlags && (region != cfg.Region || verbose != cfg.Verbose):
+			fmt.Printf("   ✗ The program's flags hold -region=%s -verbose=%t, the Config %s and %t\n", region, verbose, cfg.Region, cfg.Verbose)
+			failures++
+		case len(warnings) > 0:
+			fmt.Printf("   ✓ output=%s, warned: %s\n", cfg.Output, warnings[0])
+		default:
+			fmt.Printf("   ✓ regions=%s output=%s verbose=%t read-only=%t yes=%t profile=%q services=%s\n",
+				strings.Join(cfg.Regions, ","), cfg.Output, cfg.Verbose, cfg.ReadOnly, cfg.AssumeYes, cfg.Profile, strings.Join(cfg.Services, ","))
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d precedence checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Flags override SDKMT_* variables, which override the config file, which overrides the defaults; nothing set keeps the defaults")
+	fmt.Println("✓ An SDKMT_OUTPUT format the program does not support is ignored with a warning")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None: the SDKMT_* variables are read by the programs, not by either SDK, and set what both SDKs are given")
+	fmt.Println("  - AWS_REGION and AWS_PROFILE are still read by the SDKs themselves wherever a program leaves the setting to them")
+}
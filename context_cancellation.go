@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// slowTransport holds every request until its context is cancelled or the
+// delay passes, and signals on started when a request reaches it.
+type slowTransport struct {
+	delay   time.Duration
+	started chan struct{}
+}
+
+func (t *slowTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	select {
+	case t.started <- struct{}{}:
+	default:
+	}
+	timer := time.NewTimer(t.delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	case <-timer.C:
+		return nil, errors.New("slow transport: request was not cancelled")
+	}
+}
+
+// This example demonstrates that cancelling a context aborts an in-flight
+// call in both SDKs. A transport that never answers stands in for a slow
+// service, so the example runs offline without credentials.
+func main() {
+	fmt.Print("=== Context Cancellation Test ===\n\n")
+
+	region := "us-east-1"
+	ctx := context.Background()
+	goroutinesBefore := runtime.NumGoroutine()
+
+	transportV1 := &slowTransport{delay: 30 * time.Second, started: make(chan struct{}, 1)}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transportV1},
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	ec2ClientV1 := ec2.New(sessV1)
+
+	transportV2 := &slowTransport{delay: 30 * time.Second, started: make(chan struct{}, 1)}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transportV2}),
+		config.WithRetryMaxAttempts(1),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+
+	failures := 0
+
+	fmt.Println("1. Cancelling an SDK v1 DescribeInstancesWithContext call mid-flight...")
+	elapsed, errV1 := cancelMidFlight(ctx, transportV1.started, func(ctx context.Context) error {
+		_, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{})
+		return err
+	})
+	if !reportCancellation(errV1, elapsed, canceledV1(errV1)) {
+		failures++
+	}
+
+	fmt.Println("\n2. Cancelling an SDK v2 DescribeInstances call mid-flight...")
+	elapsed, errV2 := cancelMidFlight(ctx, transportV2.started, func(ctx context.Context) error {
+		_, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{})
+		return err
+	})
+	if !reportCancellation(errV2, elapsed, errors.Is(errV2, context.Canceled)) {
+		failures++
+	}
+
+	fmt.Println("\n3. Checking that no goroutines were left behind...")
+	goroutinesAfter := waitForGoroutines(goroutinesBefore, 2*time.Second)
+	if goroutinesAfter > goroutinesBefore {
+		fmt.Printf("   ✗ %d goroutines before, %d after\n", goroutinesBefore, goroutinesAfter)
+		failures++
+	} else {
+		fmt.Printf("   ✓ %d goroutines before, %d after\n", goroutinesBefore, goroutinesAfter)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d cancellation checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs abort an in-flight call promptly when its context is cancelled")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 only honours cancellation through the *WithContext method variants")
+	fmt.Println("  - v1 reports an awserr.Error with code RequestCanceled; errors.Is(err, context.Canceled) is false")
+	fmt.Println("  - v2 errors wrap context.Canceled, so errors.Is works directly")
+}
+
+// cancelMidFlight runs call in a goroutine, cancels its context once the
+// request reaches the transport, and returns how long the call took to return
+// after the cancellation, together with its error.
+func cancelMidFlight(parent context.Context, started <-chan struct{}, call func(ctx context.Context) error) (time.Duration, error) {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- call(ctx) }()
+
+	select {
+	case <-started:
+	case err := <-done:
+		return 0, err
+	case <-time.After(5 * time.Second):
+		return 0, errors.New("request never reached the transport")
+	}
+
+	cancelledAt := time.Now()
+	cancel()
+	err := <-done
+	return time.Since(cancelledAt), err
+}
+
+// canceledV1 reports whether a v1 error is the RequestCanceled error caused
+// by context.Canceled. v1 errors do not implement Unwrap, so the original
+// error has to be reached through OrigErr.
+func canceledV1(err error) bool {
+	var aerr awserr.Error
+	if !errors.As(err, &aerr) || aerr.Code() != request.CanceledErrorCode {
+		return false
+	}
+	return errors.Is(aerr.OrigErr(), context.Canceled)
+}
+
+func reportCancellation(err error, elapsed time.Duration, canceled bool) bool {
+	switch {
+	case err == nil:
+		fmt.Println("   ✗ Call succeeded despite the cancellation")
+		return false
+	case !canceled:
+		fmt.Printf("   ✗ Error does not wrap context.Canceled: %v\n", err)
+		return false
+	case elapsed > time.Second:
+		fmt.Printf("   ✗ Call took %s to return after cancellation\n", elapsed)
+		return false
+	}
+	fmt.Printf("   ✓ Returned %s after cancellation: %v\n", elapsed.Round(time.Microsecond), err)
+	return true
+}
+
+// waitForGoroutines waits up to timeout for the goroutine count to drop back
+// to want, and returns the last count observed.
+func waitForGoroutines(want int, timeout time.Duration) int {
+	deadline := time.Now().Add(timeout)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= want || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates keeping v2 converters in step with the SDK. Every
+// exported field of each converted v2 type is filled by reflection and then
+// cleared in turn, to find fields that the converter never reads. A field
+// added by an SDK upgrade is reported until the converter maps it or lists
+// it as ignored. It runs offline and needs no credentials.
+func main() {
+	fmt.Print("=== Converter Coverage Check ===\n\n")
+
+	fmt.Println("1. Checking converters against the v2 types...")
+	results, err := interop.ConverterCoverageChecks()
+	if err != nil {
+		log.Fatalf("Failed to check converters: %v", err)
+	}
+
+	failures := 0
+	for _, cov := range results {
+		if cov.OK() {
+			fmt.Printf("   ✓ %s: %d fields mapped (%s), %d ignored\n",
+				cov.Name, len(cov.Mapped), strings.Join(cov.Mapped, ", "), len(cov.Ignored))
+			continue
+		}
+		failures++
+		fmt.Printf("   ✗ %s: %d fields mapped, %d ignored\n", cov.Name, len(cov.Mapped), len(cov.Ignored))
+		if len(cov.Unmapped) > 0 {
+			fmt.Printf("       unmapped: %s\n", strings.Join(cov.Unmapped, ", "))
+		}
+		if len(cov.Stale) > 0 {
+			fmt.Printf("       ignored but mapped or missing: %s\n", strings.Join(cov.Stale, ", "))
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d of %d converters do not account for every field\n", failures, len(results))
+		fmt.Println("  Map each unmapped field in the converter, or add it to the converter's ignore list")
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ All %d converters account for every exported field of their v2 type\n", len(results))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v2 types are plain structs whose new fields compile silently into existing converters")
+	fmt.Println("  - v2 pointer fields are nil when absent, so a filled value is needed to see them read")
+	fmt.Println("  - v2 union members are interfaces, which cannot be filled generically and must be ignored")
+}
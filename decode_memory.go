@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// fixtureTransport answers every request with the same canned response body.
+type fixtureTransport struct {
+	body []byte
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": []string{"text/xml"}},
+		ContentLength: int64(len(t.body)),
+		Body:          io.NopCloser(bytes.NewReader(t.body)),
+		Request:       req,
+	}, nil
+}
+
+// describeInstancesFixture returns a DescribeInstances response with one
+// reservation of n instances, each with tags, a network interface and a
+// block device, roughly the shape of a real instance.
+func describeInstancesFixture(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString(`<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">`)
+	b.WriteString(`<requestId>fixture</requestId><reservationSet><item><reservationId>r-fixture</reservationId><instancesSet>`)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("i-%017x", i)
+		fmt.Fprintf(&b, `<item><instanceId>%s</instanceId><imageId>ami-0123456789abcdef0</imageId>`+
+			`<instanceState><code>16</code><name>running</name></instanceState>`+
+			`<privateDnsName>ip-10-0-%d-%d.ec2.internal</privateDnsName><instanceType>m5.large</instanceType>`+
+			`<launchTime>2024-01-02T03:04:05.000Z</launchTime>`+
+			`<placement><availabilityZone>us-east-1a</availabilityZone><tenancy>default</tenancy></placement>`+
+			`<privateIpAddress>10.0.%d.%d</privateIpAddress><subnetId>subnet-0123456789abcdef0</subnetId>`+
+			`<vpcId>vpc-0123456789abcdef0</vpcId><architecture>x86_64</architecture>`+
+			`<rootDeviceType>ebs</rootDeviceType><rootDeviceName>/dev/xvda</rootDeviceName>`+
+			`<blockDeviceMapping><item><deviceName>/dev/xvda</deviceName><ebs><volumeId>vol-%017x</volumeId>`+
+			`<status>attached</status><attachTime>2024-01-02T03:04:06.000Z</attachTime>`+
+			`<deleteOnTermination>true</deleteOnTermination></ebs></item></blockDeviceMapping>`+
+			`<networkInterfaceSet><item><networkInterfaceId>eni-%017x</networkInterfaceId>`+
+			`<subnetId>subnet-0123456789abcdef0</subnetId><vpcId>vpc-0123456789abcdef0</vpcId>`+
+			`<status>in-use</status><privateIpAddress>10.0.%d.%d</privateIpAddress>`+
+			`<groupSet><item><groupId>sg-0123456789abcdef0</groupId><groupName>default</groupName></item></groupSet>`+
+			`</item></networkInterfaceSet>`+
+			`<tagSet><item><key>Name</key><value>fixture-%d</value></item>`+
+			`<item><key>team</key><value>sdk-migration</value></item>`+
+			`<item><key>env</key><value>test</value></item></tagSet></item>`,
+			id, i/256, i%256, i/256, i%256, i, i, i/256, i%256, i)
+	}
+	b.WriteString(`</instancesSet></item></reservationSet></DescribeInstancesResponse>`)
+	return b.Bytes()
+}
+
+// decodeStats is what decoding the fixture cost one SDK.
+type decodeStats struct {
+	Instances int
+	Bytes     uint64
+	Mallocs   uint64
+	Elapsed   time.Duration
+}
+
+func (s decodeStats) String() string {
+	return fmt.Sprintf("%d instances, %.1f MiB allocated in %d allocations, %s",
+		s.Instances, float64(s.Bytes)/(1<<20), s.Mallocs, s.Elapsed.Round(time.Millisecond))
+}
+
+// measure runs decode iterations times and reports the allocations made
+// while it ran.
+func measure(iterations int, decode func() (int, error)) (decodeStats, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	var stats decodeStats
+	for i := 0; i < iterations; i++ {
+		n, err := decode()
+		if err != nil {
+			return stats, err
+		}
+		stats.Instances = n
+	}
+	stats.Elapsed = time.Since(start) / time.Duration(iterations)
+	runtime.ReadMemStats(&after)
+	stats.Bytes = (after.TotalAlloc - before.TotalAlloc) / uint64(iterations)
+	stats.Mallocs = (after.Mallocs - before.Mallocs) / uint64(iterations)
+	return stats, nil
+}
+
+// writeHeapProfile writes a heap profile to path. The GC run first makes the
+// profile current, since it otherwise reflects the heap as of the last GC.
+func writeHeapProfile(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	runtime.GC()
+	return pprof.WriteHeapProfile(f)
+}
+
+// This example demonstrates the memory cost of decoding a large response
+// with each SDK. A synthetic DescribeInstances response is served from
+// memory, so it runs offline, and each SDK decodes it -iterations times.
+// With -memprofile, heap profiles are written before and after each SDK's
+// decode runs, so that diffing consecutive profiles isolates the decode
+// path from setup.
+func main() {
+	instances := flag.Int("instances", 5000, "number of instances in the fixture response")
+	iterations := flag.Int("iterations", 3, "times each SDK decodes the fixture")
+	memprofile := flag.String("memprofile", "", "write heap profiles to FILE.base, FILE.v1 and FILE.v2")
+	flag.Parse()
+
+	fmt.Print("=== Large Response Decoding Memory Test ===\n\n")
+
+	if *instances < 1 || *iterations < 1 {
+		fmt.Fprintln(os.Stderr, "-instances and -iterations must be at least 1")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *memprofile != "" {
+		// Sample every few KiB instead of every 512 KiB, so that the
+		// many small decode allocations are represented.
+		runtime.MemProfileRate = 4096
+	}
+
+	region := "us-east-1"
+	ctx := context.Background()
+
+	fixture := describeInstancesFixture(*instances)
+	fmt.Printf("Fixture: %d instances, %.1f MiB of XML\n", *instances, float64(len(fixture))/(1<<20))
+	fmt.Printf("Iterations per SDK: %d\n\n", *iterations)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: &fixtureTransport{body: fixture}},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	ec2ClientV1 := ec2v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: &fixtureTransport{body: fixture}}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+
+	decodeV1 := func() (int, error) {
+		out, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{})
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for _, r := range out.Reservations {
+			n += len(r.Instances)
+		}
+		return n, nil
+	}
+	decodeV2 := func() (int, error) {
+		out, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{})
+		if err != nil {
+			return 0, err
+		}
+		n := 0
+		for _, r := range out.Reservations {
+			n += len(r.Instances)
+		}
+		return n, nil
+	}
+
+	// A small warm-up call per SDK keeps one-time initialization, such as
+	// endpoint resolution and signer setup, out of the measurements.
+	warmUp := &fixtureTransport{body: describeInstancesFixture(1)}
+	if _, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{}, func(r *request.Request) {
+		r.Config.HTTPClient = &http.Client{Transport: warmUp}
+	}); err != nil {
+		log.Fatalf("Warm-up call failed with v1: %v", err)
+	}
+	if _, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{}, func(o *ec2v2.Options) {
+		o.HTTPClient = &http.Client{Transport: warmUp}
+	}); err != nil {
+		log.Fatalf("Warm-up call failed with v2: %v", err)
+	}
+
+	if *memprofile != "" {
+		if err := writeHeapProfile(*memprofile + ".base"); err != nil {
+			log.Fatalf("Failed to write heap profile: %v", err)
+		}
+	}
+
+	fmt.Println("1. Decoding the fixture with SDK v1...")
+	statsV1, err := measure(*iterations, decodeV1)
+	if err != nil {
+		log.Fatalf("Failed to decode with v1: %v", err)
+	}
+	fmt.Printf("   ✓ %s per call\n", statsV1)
+	if *memprofile != "" {
+		if err := writeHeapProfile(*memprofile + ".v1"); err != nil {
+			log.Fatalf("Failed to write heap profile: %v", err)
+		}
+	}
+
+	fmt.Println("\n2. Decoding the fixture with SDK v2...")
+	statsV2, err := measure(*iterations, decodeV2)
+	if err != nil {
+		log.Fatalf("Failed to decode with v2: %v", err)
+	}
+	fmt.Printf("   ✓ %s per call\n", statsV2)
+	if *memprofile != "" {
+		if err := writeHeapProfile(*memprofile + ".v2"); err != nil {
+			log.Fatalf("Failed to write heap profile: %v", err)
+		}
+	}
+
+	fmt.Println("\n3. Comparing results...")
+	if statsV1.Instances != *instances || statsV2.Instances != *instances {
+		fmt.Printf("   ✗ Decoded %d instances with v1 and %d with v2, want %d\n", statsV1.Instances, statsV2.Instances, *instances)
+		os.Exit(1)
+	}
+	fmt.Printf("   ✓ Both SDKs decoded all %d instances\n", *instances)
+	fmt.Printf("   v2 allocates %.0f%% of v1's bytes and %.0f%% of its allocations\n",
+		100*float64(statsV2.Bytes)/float64(statsV1.Bytes), 100*float64(statsV2.Mallocs)/float64(statsV1.Mallocs))
+	if *memprofile != "" {
+		fmt.Printf("\n   Heap profiles written. To view what each SDK's decode allocated:\n")
+		fmt.Printf("     go tool pprof -sample_index=alloc_space -base %[1]s.base %[1]s.v1\n", *memprofile)
+		fmt.Printf("     go tool pprof -sample_index=alloc_space -base %[1]s.v1 %[1]s.v2\n", *memprofile)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs decode the same large response; the figures above quantify the difference")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 decodes XML through reflection over generic shapes, v2 through generated deserializers")
+	fmt.Println("  - v1 builds slices of pointers to structs, v2 slices of values, which means fewer allocations")
+	fmt.Println("  - Neither SDK streams the response: the whole decoded result is in memory at once")
+}
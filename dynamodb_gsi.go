@@ -0,0 +1,352 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// gsiName is the global secondary index the example creates and queries.
+const gsiName = "status-created"
+
+// gsiDescription is an SDK-neutral view of a global secondary index as
+// returned by DescribeTable.
+type gsiDescription struct {
+	Name       string
+	KeySchema  []string // "attribute KEYTYPE", in schema order
+	Projection string   // projection type, then sorted non-key attributes
+	Status     string
+}
+
+func (d gsiDescription) String() string {
+	return fmt.Sprintf("%s keys=[%s] projection=%s status=%s", d.Name, strings.Join(d.KeySchema, ", "), d.Projection, d.Status)
+}
+
+// This example demonstrates global secondary indexes across SDKs. A table
+// with an INCLUDE-projected GSI is created and filled with SDK v1, and the
+// index is queried with SDK v2 once DescribeTable reports it ACTIVE. Only
+// the projected attributes must come back, in index sort order.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== DynamoDB Global Secondary Index Interop Test ===\n\n")
+
+	tableName := interop.GenerateName("gsi")
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test table name: %s\n\n", tableName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	dynamoClientV1 := dynamodbv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	dynamoClientV2 := dynamodbv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create the table with its GSI using SDK v1 =====
+	fmt.Println("PHASE 1: Creating table with a global secondary index using SDK v1")
+	fmt.Println("---------------------------------------------------------------------")
+
+	// Every key attribute, of the table or of an index, must be defined;
+	// non-key attributes must not be.
+	_, err = dynamoClientV1.CreateTableWithContext(ctx, &dynamodbv1.CreateTableInput{
+		TableName: aws.String(tableName),
+		AttributeDefinitions: []*dynamodbv1.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: aws.String(dynamodbv1.ScalarAttributeTypeS)},
+			{AttributeName: aws.String("status"), AttributeType: aws.String(dynamodbv1.ScalarAttributeTypeS)},
+			{AttributeName: aws.String("createdAt"), AttributeType: aws.String(dynamodbv1.ScalarAttributeTypeN)},
+		},
+		KeySchema: []*dynamodbv1.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodbv1.KeyTypeHash)},
+		},
+		GlobalSecondaryIndexes: []*dynamodbv1.GlobalSecondaryIndex{{
+			IndexName: aws.String(gsiName),
+			KeySchema: []*dynamodbv1.KeySchemaElement{
+				{AttributeName: aws.String("status"), KeyType: aws.String(dynamodbv1.KeyTypeHash)},
+				{AttributeName: aws.String("createdAt"), KeyType: aws.String(dynamodbv1.KeyTypeRange)},
+			},
+			Projection: &dynamodbv1.Projection{
+				ProjectionType:   aws.String(dynamodbv1.ProjectionTypeInclude),
+				NonKeyAttributes: aws.StringSlice([]string{"title"}),
+			},
+		}},
+		BillingMode: aws.String(dynamodbv1.BillingModePayPerRequest),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create table with v1: %v", err)
+	}
+
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Deleting table (and with it, its index)")
+		fmt.Println("--------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("table '%s'", tableName)) {
+			fmt.Printf("\nPlease manually delete table: %s\n", tableName)
+			return
+		}
+		_, err := dynamoClientV2.DeleteTable(ctx, &dynamodbv2.DeleteTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete table: %v", err)
+			fmt.Printf("\nPlease manually delete table: %s\n", tableName)
+		} else {
+			fmt.Println("✓ Table deleted successfully with SDK v2")
+		}
+	}
+
+	if err := dynamoClientV1.WaitUntilTableExistsWithContext(ctx, &dynamodbv1.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}); err != nil {
+		cleanup()
+		log.Fatalf("Table did not become active: %v", err)
+	}
+	fmt.Println("✓ Table created successfully with SDK v1")
+
+	// An ACTIVE table does not imply an ACTIVE index: the index status is
+	// reported separately and can lag behind.
+	var indexV2 gsiDescription
+	err = interop.Poll(ctx, 5*time.Second, 5*time.Minute, func(ctx context.Context) (bool, error) {
+		out, err := dynamoClientV2.DescribeTable(ctx, &dynamodbv2.DescribeTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, gsi := range out.Table.GlobalSecondaryIndexes {
+			if aws.StringValue(gsi.IndexName) == gsiName {
+				indexV2 = gsiDescriptionFromV2(gsi)
+			}
+		}
+		if indexV2.Status != string(ddbtypes.IndexStatusActive) {
+			fmt.Printf("  Index status: %s, waiting...\n", indexV2.Status)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Index %s did not become ACTIVE: %v", gsiName, err)
+	}
+	fmt.Printf("✓ Index ACTIVE according to SDK v2: %s\n", indexV2)
+
+	descV1, err := dynamoClientV1.DescribeTableWithContext(ctx, &dynamodbv1.DescribeTableInput{
+		TableName: aws.String(tableName),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to describe table with v1: %v", err)
+	}
+	mismatches := 0
+	var indexV1 gsiDescription
+	for _, gsi := range descV1.Table.GlobalSecondaryIndexes {
+		if aws.StringValue(gsi.IndexName) == gsiName {
+			indexV1 = gsiDescriptionFromV1(gsi)
+		}
+	}
+	if indexV1.String() != indexV2.String() {
+		fmt.Printf("✗ Index descriptions differ\n     v1: %s\n     v2: %s\n", indexV1, indexV2)
+		mismatches++
+	} else {
+		fmt.Println("✓ SDK v1 describes the index identically")
+	}
+
+	// ===== PHASE 2: Write items with SDK v1 =====
+	fmt.Println("\n\nPHASE 2: Writing items using SDK v1")
+	fmt.Println("-------------------------------------")
+
+	items := []struct {
+		pk, status, title string
+		createdAt         int
+	}{
+		{"order-1", "open", "first open order", 300},
+		{"order-2", "closed", "a closed order", 100},
+		{"order-3", "open", "second open order", 100},
+		{"order-4", "open", "third open order", 200},
+		{"order-5", "closed", "another closed order", 200},
+	}
+	// want holds the GSI view of the open items, in index sort order: the
+	// key attributes and title, but not the unprojected secret.
+	var want []map[string]*dynamodbv1.AttributeValue
+	for _, it := range items {
+		item := map[string]*dynamodbv1.AttributeValue{
+			"pk":        {S: aws.String(it.pk)},
+			"status":    {S: aws.String(it.status)},
+			"createdAt": {N: aws.String(fmt.Sprint(it.createdAt))},
+			"title":     {S: aws.String(it.title)},
+			"secret":    {S: aws.String("not projected into " + gsiName)},
+		}
+		_, err := dynamoClientV1.PutItemWithContext(ctx, &dynamodbv1.PutItemInput{
+			TableName: aws.String(tableName),
+			Item:      item,
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to put item %s with v1: %v", it.pk, err)
+		}
+		if it.status == "open" {
+			delete(item, "secret")
+			want = append(want, item)
+		}
+	}
+	sort.Slice(want, func(i, j int) bool {
+		a, _ := strconv.Atoi(aws.StringValue(want[i]["createdAt"].N))
+		b, _ := strconv.Atoi(aws.StringValue(want[j]["createdAt"].N))
+		return a < b
+	})
+	fmt.Printf("✓ Wrote %d items with SDK v1, %d of them open\n", len(items), len(want))
+
+	// ===== PHASE 3: Query the GSI with SDK v2 =====
+	fmt.Println("\n\nPHASE 3: Querying the index using SDK v2")
+	fmt.Println("------------------------------------------")
+
+	// Index reads are always eventually consistent, so a query straight
+	// after the writes may miss some of them.
+	var got []map[string]ddbtypes.AttributeValue
+	err = interop.Poll(ctx, time.Second, 30*time.Second, func(ctx context.Context) (bool, error) {
+		out, err := dynamoClientV2.Query(ctx, &dynamodbv2.QueryInput{
+			TableName:                aws.String(tableName),
+			IndexName:                aws.String(gsiName),
+			KeyConditionExpression:   aws.String("#status = :status"),
+			ExpressionAttributeNames: map[string]string{"#status": "status"},
+			ExpressionAttributeValues: map[string]ddbtypes.AttributeValue{
+				":status": &ddbtypes.AttributeValueMemberS{Value: "open"},
+			},
+		})
+		if err != nil {
+			return false, err
+		}
+		got = out.Items
+		if len(got) < len(want) {
+			fmt.Printf("  %d of %d items indexed, retrying...\n", len(got), len(want))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to query the index with v2: %v", err)
+	}
+	fmt.Printf("✓ Query returned %d items with SDK v2\n", len(got))
+
+	gotV1 := make([]map[string]*dynamodbv1.AttributeValue, len(got))
+	for i, item := range got {
+		if gotV1[i], err = interop.ConvertAttributeValuesV2ToV1(item); err != nil {
+			cleanup()
+			log.Fatalf("Failed to convert item %d to v1: %v", i, err)
+		}
+	}
+	diffs, err := interop.DiffJSON(want, gotV1)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to compare items: %v", err)
+	}
+	if len(diffs) > 0 {
+		fmt.Printf("✗ Index items differ from the projection of the written items in %d places:\n", len(diffs))
+		for _, diff := range diffs {
+			fmt.Printf("     %s\n", diff)
+		}
+		mismatches++
+	} else {
+		fmt.Println("✓ Items come back in createdAt order with only the key attributes and title projected")
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d index checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ A GSI created with SDK v1 is queried with SDK v2 with the expected projection and order")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 KeyType, ProjectionType and IndexStatus are *string, v2 uses named string types")
+	fmt.Println("  - v1 GlobalSecondaryIndexes and KeySchema are slices of pointers, v2 slices of values")
+	fmt.Println("  - v2 expression values are AttributeValue union members rather than one struct")
+}
+
+// gsiDescriptionFromV1 converts a v1 index description.
+func gsiDescriptionFromV1(gsi *dynamodbv1.GlobalSecondaryIndexDescription) gsiDescription {
+	d := gsiDescription{
+		Name:   aws.StringValue(gsi.IndexName),
+		Status: aws.StringValue(gsi.IndexStatus),
+	}
+	for _, k := range gsi.KeySchema {
+		d.KeySchema = append(d.KeySchema, aws.StringValue(k.AttributeName)+" "+aws.StringValue(k.KeyType))
+	}
+	if p := gsi.Projection; p != nil {
+		d.Projection = projectionString(aws.StringValue(p.ProjectionType), aws.StringValueSlice(p.NonKeyAttributes))
+	}
+	return d
+}
+
+// gsiDescriptionFromV2 converts a v2 index description.
+func gsiDescriptionFromV2(gsi ddbtypes.GlobalSecondaryIndexDescription) gsiDescription {
+	d := gsiDescription{
+		Name:   aws.StringValue(gsi.IndexName),
+		Status: string(gsi.IndexStatus),
+	}
+	for _, k := range gsi.KeySchema {
+		d.KeySchema = append(d.KeySchema, aws.StringValue(k.AttributeName)+" "+string(k.KeyType))
+	}
+	if p := gsi.Projection; p != nil {
+		d.Projection = projectionString(string(p.ProjectionType), p.NonKeyAttributes)
+	}
+	return d
+}
+
+// projectionString formats a projection type and its non-key attributes,
+// which DynamoDB does not return in any particular order.
+func projectionString(projectionType string, nonKey []string) string {
+	if len(nonKey) == 0 {
+		return projectionType
+	}
+	sorted := append([]string(nil), nonKey...)
+	sort.Strings(sorted)
+	return fmt.Sprintf("%s[%s]", projectionType, strings.Join(sorted, ","))
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// partiQLQuery is one parameterized statement, with its parameters in both
+// SDKs' forms and the items it must return.
+type partiQLQuery struct {
+	name     string
+	template string // %s is replaced by the quoted table name
+	paramsV1 []*dynamodbv1.AttributeValue
+	paramsV2 []ddbtypes.AttributeValue
+	want     []map[string]*dynamodbv1.AttributeValue
+}
+
+// This example demonstrates DynamoDB PartiQL across SDKs. Items are written
+// with SDK v1's classic PutItem and read back with SDK v2's ExecuteStatement,
+// using parameterized SELECT statements whose ? placeholders are bound in
+// order. Parameters are []*AttributeValue structs in v1 but AttributeValue
+// union members in v2; the same statements are also run with SDK v1, and
+// the v2 results are decoded through interop.ConvertAttributeValuesV2ToV1 so
+// that both are compared with the written items.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== DynamoDB PartiQL Interop Test ===\n\n")
+
+	tableName := interop.GenerateName("partiql")
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test table name: %s\n\n", tableName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	dynamoClientV1 := dynamodbv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	dynamoClientV2 := dynamodbv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create the table using SDK v1 =====
+	fmt.Println("PHASE 1: Creating table using SDK v1")
+	fmt.Println("--------------------------------------")
+
+	_, err = dynamoClientV1.CreateTableWithContext(ctx, &dynamodbv1.CreateTableInput{
+		TableName: aws.String(tableName),
+		AttributeDefinitions: []*dynamodbv1.AttributeDefinition{
+			{AttributeName: aws.String("pk"), AttributeType: aws.String(dynamodbv1.ScalarAttributeTypeS)},
+			{AttributeName: aws.String("sk"), AttributeType: aws.String(dynamodbv1.ScalarAttributeTypeN)},
+		},
+		KeySchema: []*dynamodbv1.KeySchemaElement{
+			{AttributeName: aws.String("pk"), KeyType: aws.String(dynamodbv1.KeyTypeHash)},
+			{AttributeName: aws.String("sk"), KeyType: aws.String(dynamodbv1.KeyTypeRange)},
+		},
+		BillingMode: aws.String(dynamodbv1.BillingModePayPerRequest),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create table with v1: %v", err)
+	}
+
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Deleting table")
+		fmt.Println("------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("table '%s'", tableName)) {
+			fmt.Printf("\nPlease manually delete table: %s\n", tableName)
+			return
+		}
+		_, err := dynamoClientV2.DeleteTable(ctx, &dynamodbv2.DeleteTableInput{
+			TableName: aws.String(tableName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete table: %v", err)
+			fmt.Printf("\nPlease manually delete table: %s\n", tableName)
+		} else {
+			fmt.Println("✓ Table deleted successfully with SDK v2")
+		}
+	}
+
+	if err := dynamoClientV1.WaitUntilTableExistsWithContext(ctx, &dynamodbv1.DescribeTableInput{
+		TableName: aws.String(tableName),
+	}); err != nil {
+		cleanup()
+		log.Fatalf("Table did not become active: %v", err)
+	}
+	fmt.Println("✓ Table created successfully with SDK v1")
+
+	// ===== PHASE 2: Write items with SDK v1's PutItem =====
+	fmt.Println("\n\nPHASE 2: Writing items using SDK v1 PutItem")
+	fmt.Println("---------------------------------------------")
+
+	// Items of one partition are listed in sort key order, which is the
+	// order a PartiQL SELECT on the partition key returns them in. Numbers
+	// are written in canonical form because DynamoDB drops trailing zeros.
+	items := []map[string]*dynamodbv1.AttributeValue{
+		{
+			"pk": {S: aws.String("customer-1")}, "sk": {N: aws.String("1")},
+			"total": {N: aws.String("19.99")}, "active": {BOOL: aws.Bool(true)},
+			"tags": {SS: aws.StringSlice([]string{"gift", "priority"})},
+		},
+		{
+			"pk": {S: aws.String("customer-1")}, "sk": {N: aws.String("2")},
+			"total": {N: aws.String("5")}, "active": {BOOL: aws.Bool(false)},
+			"note": {NULL: aws.Bool(true)},
+		},
+		{
+			"pk": {S: aws.String("customer-1")}, "sk": {N: aws.String("3")},
+			"total": {N: aws.String("120.5")}, "active": {BOOL: aws.Bool(true)},
+			"lines": {L: []*dynamodbv1.AttributeValue{
+				{M: map[string]*dynamodbv1.AttributeValue{"sku": {S: aws.String("A-1")}, "qty": {N: aws.String("2")}}},
+				{M: map[string]*dynamodbv1.AttributeValue{"sku": {S: aws.String("B-7")}, "qty": {N: aws.String("1")}}},
+			}},
+		},
+		{
+			"pk": {S: aws.String("customer-2")}, "sk": {N: aws.String("1")},
+			"total": {N: aws.String("42")}, "active": {BOOL: aws.Bool(true)},
+		},
+	}
+	for i, item := range items {
+		_, err := dynamoClientV1.PutItemWithContext(ctx, &dynamodbv1.PutItemInput{
+			TableName: aws.String(tableName),
+			Item:      item,
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to put item %d with v1: %v", i+1, err)
+		}
+	}
+	fmt.Printf("✓ Wrote %d items with SDK v1 (strings, numbers, booleans, a string set, NULL and a list of maps)\n", len(items))
+
+	// ===== PHASE 3: Read them with PartiQL =====
+	fmt.Println("\n\nPHASE 3: Reading items with parameterized PartiQL statements")
+	fmt.Println("--------------------------------------------------------------")
+
+	queries := []partiQLQuery{
+		{
+			name:     "one placeholder",
+			template: `SELECT * FROM %s WHERE pk = ?`,
+			paramsV1: []*dynamodbv1.AttributeValue{{S: aws.String("customer-1")}},
+			paramsV2: []ddbtypes.AttributeValue{&ddbtypes.AttributeValueMemberS{Value: "customer-1"}},
+			want:     items[:3],
+		},
+		{
+			name:     "string and number placeholders",
+			template: `SELECT * FROM %s WHERE pk = ? AND sk >= ?`,
+			paramsV1: []*dynamodbv1.AttributeValue{{S: aws.String("customer-1")}, {N: aws.String("2")}},
+			paramsV2: []ddbtypes.AttributeValue{
+				&ddbtypes.AttributeValueMemberS{Value: "customer-1"},
+				&ddbtypes.AttributeValueMemberN{Value: "2"},
+			},
+			want: items[1:3],
+		},
+		{
+			name:     "boolean placeholder and projection",
+			template: `SELECT sk, total FROM %s WHERE pk = ? AND active = ?`,
+			paramsV1: []*dynamodbv1.AttributeValue{{S: aws.String("customer-1")}, {BOOL: aws.Bool(true)}},
+			paramsV2: []ddbtypes.AttributeValue{
+				&ddbtypes.AttributeValueMemberS{Value: "customer-1"},
+				&ddbtypes.AttributeValueMemberBOOL{Value: true},
+			},
+			want: []map[string]*dynamodbv1.AttributeValue{
+				{"sk": items[0]["sk"], "total": items[0]["total"]},
+				{"sk": items[2]["sk"], "total": items[2]["total"]},
+			},
+		},
+	}
+
+	mismatches := 0
+	for i, q := range queries {
+		// Table names with dashes must be quoted as identifiers.
+		statement := fmt.Sprintf(q.template, `"`+tableName+`"`)
+		fmt.Printf("%d. %s: %s\n", i+1, q.name, fmt.Sprintf(q.template, "<table>"))
+
+		gotV2, err := executeStatementV2(ctx, dynamoClientV2, statement, q.paramsV2)
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to execute statement with v2: %v", err)
+		}
+		decoded := make([]map[string]*dynamodbv1.AttributeValue, len(gotV2))
+		for j, item := range gotV2 {
+			if decoded[j], err = interop.ConvertAttributeValuesV2ToV1(item); err != nil {
+				cleanup()
+				log.Fatalf("Failed to convert item %d to v1: %v", j+1, err)
+			}
+		}
+		gotV1, err := executeStatementV1(ctx, dynamoClientV1, statement, q.paramsV1)
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to execute statement with v1: %v", err)
+		}
+
+		for _, r := range []struct {
+			sdk string
+			got []map[string]*dynamodbv1.AttributeValue
+		}{{"v2", decoded}, {"v1", gotV1}} {
+			diffs, err := interop.DiffJSON(q.want, r.got)
+			if err != nil {
+				cleanup()
+				log.Fatalf("Failed to compare items: %v", err)
+			}
+			if len(diffs) > 0 {
+				fmt.Printf("   ✗ SDK %s results differ from the written items in %d places:\n", r.sdk, len(diffs))
+				for _, diff := range diffs {
+					fmt.Printf("       %s\n", diff)
+				}
+				mismatches++
+				continue
+			}
+			fmt.Printf("   ✓ SDK %s returned the %d expected items\n", r.sdk, len(r.got))
+		}
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d PartiQL results did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Printf("✓ Items written with PutItem are read back unchanged by %d parameterized PartiQL statements in both SDKs\n", len(queries))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Parameters is []*dynamodb.AttributeValue, v2 is []types.AttributeValue of union members such as *types.AttributeValueMemberS")
+	fmt.Println("  - Placeholders are positional in both SDKs; PartiQL has no named parameters like :value in expressions")
+	fmt.Println("  - Both SDKs page ExecuteStatement results with NextToken, and neither has a paginator for it")
+}
+
+// executeStatementV1 runs statement with SDK v1, following NextToken.
+func executeStatementV1(ctx context.Context, client *dynamodbv1.DynamoDB, statement string, params []*dynamodbv1.AttributeValue) ([]map[string]*dynamodbv1.AttributeValue, error) {
+	var items []map[string]*dynamodbv1.AttributeValue
+	input := &dynamodbv1.ExecuteStatementInput{
+		Statement:      aws.String(statement),
+		Parameters:     params,
+		ConsistentRead: aws.Bool(true),
+	}
+	for {
+		out, err := client.ExecuteStatementWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+		if out.NextToken == nil {
+			return items, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
+
+// executeStatementV2 runs statement with SDK v2, following NextToken.
+func executeStatementV2(ctx context.Context, client *dynamodbv2.Client, statement string, params []ddbtypes.AttributeValue) ([]map[string]ddbtypes.AttributeValue, error) {
+	var items []map[string]ddbtypes.AttributeValue
+	input := &dynamodbv2.ExecuteStatementInput{
+		Statement:      aws.String(statement),
+		Parameters:     params,
+		ConsistentRead: aws.Bool(true),
+	}
+	for {
+		out, err := client.ExecuteStatement(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, out.Items...)
+		if out.NextToken == nil {
+			return items, nil
+		}
+		input.NextToken = out.NextToken
+	}
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// eipTags are the tags the test address is allocated with.
+var eipTags = map[string]string{
+	"app":  "sdk-migration-test",
+	"Name": "sdk-migration-eip",
+}
+
+// elasticIP is an SDK-neutral view of an Elastic IP address, holding the
+// allocation and association fields that are compared between v1 and v2.
+type elasticIP struct {
+	AllocationID       string
+	PublicIP           string
+	Domain             string
+	NetworkBorderGroup string
+	PublicIPv4Pool     string
+	AssociationID      string
+	InstanceID         string
+	NetworkInterfaceID string
+	PrivateIPAddress   string
+	Tags               map[string]string
+}
+
+func (e elasticIP) String() string {
+	s := fmt.Sprintf("%s (%s, Domain: %s, Border group: %s, Pool: %s)",
+		e.PublicIP, e.AllocationID, e.Domain, e.NetworkBorderGroup, e.PublicIPv4Pool)
+	if e.AssociationID != "" {
+		s += fmt.Sprintf(", associated as %s with %s/%s at %s",
+			e.AssociationID, e.InstanceID, e.NetworkInterfaceID, e.PrivateIPAddress)
+	}
+	return s
+}
+
+// This example demonstrates allocating an Elastic IP address with SDK v1,
+// describing it with both SDKs and comparing the allocation and association
+// fields. v1 returns Domain as a *string and v2 as the ec2types.DomainType
+// enum. With -instance-id, the address is also associated with that
+// instance using v2 and disassociated using v1. Elastic IPs are billed while
+// allocated, so the address is only allocated with -allow-eip, and it is
+// released in cleanup whether or not the checks pass.
+func main() {
+	allow := flag.Bool("allow-eip", false, "actually allocate (and then release) an Elastic IP address")
+	instanceID := flag.String("instance-id", "", "running instance to associate the address with (optional)")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Elastic IP Interop Test ===\n\n")
+
+	if !*allow {
+		fmt.Println("This example allocates one Elastic IP address and releases it afterwards.")
+		fmt.Println("Elastic IP addresses are billed while allocated.")
+		fmt.Println("Re-run with -allow-eip to proceed.")
+		return
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	var tagsV1 []*ec2.Tag
+	for k, v := range eipTags {
+		tagsV1 = append(tagsV1, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	fmt.Println("1. Using SDK v1 to allocate an Elastic IP address...")
+	allocOut, err := ec2ClientV1.AllocateAddressWithContext(ctx, &ec2.AllocateAddressInput{
+		Domain: aws.String(ec2.DomainTypeVpc),
+		TagSpecifications: []*ec2.TagSpecification{{
+			ResourceType: aws.String(ec2.ResourceTypeElasticIp),
+			Tags:         tagsV1,
+		}},
+	})
+	if err != nil {
+		log.Fatalf("Failed to allocate address with v1: %v", err)
+	}
+	allocated := elasticIP{
+		AllocationID:       aws.StringValue(allocOut.AllocationId),
+		PublicIP:           aws.StringValue(allocOut.PublicIp),
+		Domain:             aws.StringValue(allocOut.Domain),
+		NetworkBorderGroup: aws.StringValue(allocOut.NetworkBorderGroup),
+		PublicIPv4Pool:     aws.StringValue(allocOut.PublicIpv4Pool),
+	}
+	fmt.Printf("   ✓ Allocated %s\n", allocated)
+
+	var associationID string
+	cleanup := func() {
+		fmt.Println("\nCLEANUP: Releasing the test address")
+		fmt.Println("----------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("Elastic IP '%s' (%s)", allocated.PublicIP, allocated.AllocationID)) {
+			fmt.Printf("\nPlease manually release address: %s\n", allocated.AllocationID)
+			return
+		}
+		if associationID != "" {
+			_, err := ec2ClientV1.DisassociateAddressWithContext(ctx, &ec2.DisassociateAddressInput{
+				AssociationId: aws.String(associationID),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to disassociate address: %v", err)
+			} else {
+				fmt.Printf("✓ Association %s removed (SDK v1)\n", associationID)
+			}
+		}
+		_, err := ec2ClientV2.ReleaseAddress(ctx, &ec2v2.ReleaseAddressInput{
+			AllocationId: aws.String(allocated.AllocationID),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to release address: %v", err)
+			fmt.Printf("\nPlease manually release address: %s\n", allocated.AllocationID)
+		} else {
+			fmt.Printf("✓ Address %s released (SDK v2)\n", allocated.PublicIP)
+		}
+	}
+
+	step := 2
+	if *instanceID != "" {
+		fmt.Printf("\n%d. Using SDK v2 to associate the address with %s...\n", step, *instanceID)
+		step++
+		assocOut, err := ec2ClientV2.AssociateAddress(ctx, &ec2v2.AssociateAddressInput{
+			AllocationId: aws.String(allocated.AllocationID),
+			InstanceId:   aws.String(*instanceID),
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to associate address with v2: %v", err)
+		}
+		associationID = aws.StringValue(assocOut.AssociationId)
+		fmt.Printf("   ✓ Associated as %s\n", associationID)
+	}
+
+	fmt.Printf("\n%d. Using SDK v1 to describe the address...\n", step)
+	step++
+	descV1, err := ec2ClientV1.DescribeAddressesWithContext(ctx, &ec2.DescribeAddressesInput{
+		AllocationIds: aws.StringSlice([]string{allocated.AllocationID}),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to describe address with v1: %v", err)
+	}
+	if len(descV1.Addresses) != 1 {
+		cleanup()
+		log.Fatalf("SDK v1 described %d addresses for %s, want 1", len(descV1.Addresses), allocated.AllocationID)
+	}
+	gotV1 := elasticIPFromV1(descV1.Addresses[0])
+	fmt.Printf("   ✓ %s\n", gotV1)
+
+	fmt.Printf("\n%d. Using SDK v2 to describe the address...\n", step)
+	step++
+	descV2, err := ec2ClientV2.DescribeAddresses(ctx, &ec2v2.DescribeAddressesInput{
+		AllocationIds: []string{allocated.AllocationID},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to describe address with v2: %v", err)
+	}
+	if len(descV2.Addresses) != 1 {
+		cleanup()
+		log.Fatalf("SDK v2 described %d addresses for %s, want 1", len(descV2.Addresses), allocated.AllocationID)
+	}
+	gotV2 := elasticIPFromV2(descV2.Addresses[0])
+	fmt.Printf("   ✓ %s\n", gotV2)
+
+	fmt.Printf("\n%d. Comparing the descriptions...\n", step)
+	failures := 0
+	diffs, err := interop.DiffJSON(gotV1, gotV2)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to compare addresses: %v", err)
+	}
+	if len(diffs) > 0 {
+		fmt.Printf("   ✗ The SDKs describe the address differently in %d places:\n", len(diffs))
+		for _, diff := range diffs {
+			fmt.Printf("     %s\n", diff)
+		}
+		failures++
+	} else {
+		fmt.Println("   ✓ Both SDKs describe the same allocation and association")
+	}
+
+	if gotV2.AllocationID != allocated.AllocationID || gotV2.PublicIP != allocated.PublicIP || gotV2.Domain != allocated.Domain {
+		fmt.Printf("   ✗ Described differently than it was allocated\n       allocated: %s\n       described: %s\n", allocated, gotV2)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Allocation ID, public IP and domain %s match the allocation\n", gotV2.Domain)
+	}
+
+	switch {
+	case *instanceID == "" && gotV2.AssociationID != "":
+		fmt.Printf("   ✗ The address is unexpectedly associated as %s\n", gotV2.AssociationID)
+		failures++
+	case *instanceID == "":
+		fmt.Println("   ✓ Association fields are empty in both SDKs")
+	case gotV2.AssociationID != associationID || gotV2.InstanceID != *instanceID:
+		fmt.Printf("   ✗ Association %s with %s was described as %s with %s\n",
+			associationID, *instanceID, gotV2.AssociationID, gotV2.InstanceID)
+		failures++
+	case gotV2.NetworkInterfaceID == "" || gotV2.PrivateIPAddress == "":
+		fmt.Println("   ✗ The association has no network interface or private IP address")
+		failures++
+	default:
+		fmt.Printf("   ✓ Association %s points at %s (%s, %s)\n",
+			gotV2.AssociationID, gotV2.InstanceID, gotV2.NetworkInterfaceID, gotV2.PrivateIPAddress)
+	}
+
+	if !interop.TagsEqual(gotV2.Tags, eipTags) {
+		fmt.Printf("   ✗ Tags differ from the allocation tags %v: %v\n", eipTags, gotV2.Tags)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Both SDKs read the %d allocation tags\n", len(eipTags))
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d Elastic IP checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ An address allocated with v1 is described identically by both SDKs")
+	if *instanceID != "" {
+		fmt.Println("✓ An association made with v2 is seen by v1 and removed with v1")
+	}
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Domain is a *string compared with ec2.DomainTypeVpc, v2 the ec2types.DomainType enum")
+	fmt.Println("  - v1 DescribeAddressesInput.AllocationIds is []*string, v2 is []string")
+	fmt.Println("  - Association fields are nil pointers in both SDKs while the address is unassociated")
+	fmt.Println("  - v1 ResourceType is a *string (ec2.ResourceTypeElasticIp), v2 ec2types.ResourceTypeElasticIp")
+}
+
+func elasticIPFromV1(addr *ec2.Address) elasticIP {
+	e := elasticIP{
+		AllocationID:       aws.StringValue(addr.AllocationId),
+		PublicIP:           aws.StringValue(addr.PublicIp),
+		Domain:             aws.StringValue(addr.Domain),
+		NetworkBorderGroup: aws.StringValue(addr.NetworkBorderGroup),
+		PublicIPv4Pool:     aws.StringValue(addr.PublicIpv4Pool),
+		AssociationID:      aws.StringValue(addr.AssociationId),
+		InstanceID:         aws.StringValue(addr.InstanceId),
+		NetworkInterfaceID: aws.StringValue(addr.NetworkInterfaceId),
+		PrivateIPAddress:   aws.StringValue(addr.PrivateIpAddress),
+	}
+	for _, t := range addr.Tags {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+		e.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return e
+}
+
+func elasticIPFromV2(addr ec2types.Address) elasticIP {
+	e := elasticIP{
+		AllocationID:       aws.StringValue(addr.AllocationId),
+		PublicIP:           aws.StringValue(addr.PublicIp),
+		Domain:             string(addr.Domain),
+		NetworkBorderGroup: aws.StringValue(addr.NetworkBorderGroup),
+		PublicIPv4Pool:     aws.StringValue(addr.PublicIpv4Pool),
+		AssociationID:      aws.StringValue(addr.AssociationId),
+		InstanceID:         aws.StringValue(addr.InstanceId),
+		NetworkInterfaceID: aws.StringValue(addr.NetworkInterfaceId),
+		PrivateIPAddress:   aws.StringValue(addr.PrivateIpAddress),
+	}
+	for _, t := range addr.Tags {
+		if e.Tags == nil {
+			e.Tags = make(map[string]string)
+		}
+		e.Tags[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+	}
+	return e
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates a field-by-field coverage check of one EC2
+// instance described by both SDKs. It reports fields that only one SDK's
+// Instance struct declares, and fields that are populated in one result but
+// not the other, which is where a migration can silently lose data.
+func main() {
+	instanceID := flag.String("instance-id", "", "instance to describe (default: the first one found)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Instance Field Coverage ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Pick an instance
+	if *instanceID == "" {
+		fmt.Println("1. Using SDK v1 to find an instance...")
+		out, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+			MaxResults: aws.Int64(5),
+		})
+		if err != nil {
+			log.Fatalf("Failed to describe instances with v1: %v", err)
+		}
+		for _, reservation := range out.Reservations {
+			if len(reservation.Instances) > 0 {
+				*instanceID = aws.StringValue(reservation.Instances[0].InstanceId)
+				break
+			}
+		}
+		if *instanceID == "" {
+			fmt.Printf("   No instances in %s; launch one or pass -instance-id to compare field coverage.\n", region)
+			return
+		}
+		fmt.Printf("   ✓ Using %s\n", *instanceID)
+	} else {
+		fmt.Printf("1. Using instance %s\n", *instanceID)
+	}
+
+	// Describe it with both SDKs
+	fmt.Println("\n2. Describing the instance with SDK v1 and SDK v2...")
+	outV1, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{
+		InstanceIds: []*string{instanceID},
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe %s with v1: %v", *instanceID, err)
+	}
+	outV2, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{
+		InstanceIds: []string{*instanceID},
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe %s with v2: %v", *instanceID, err)
+	}
+	if len(outV1.Reservations) == 0 || len(outV1.Reservations[0].Instances) == 0 ||
+		len(outV2.Reservations) == 0 || len(outV2.Reservations[0].Instances) == 0 {
+		log.Fatalf("Instance %s was not returned by both SDKs", *instanceID)
+	}
+	fmt.Println("   ✓ Both SDKs returned the instance")
+
+	// Compare field coverage
+	fmt.Println("\n3. Field coverage of ec2.Instance (v1) vs types.Instance (v2):")
+	fmt.Println()
+	rows, err := interop.CompareFieldCoverage(outV1.Reservations[0].Instances[0], outV2.Reservations[0].Instances[0])
+	if err != nil {
+		log.Fatalf("Failed to compare field coverage: %v", err)
+	}
+	if err := interop.WriteFieldCoverage(interop.Output, rows); err != nil {
+		log.Fatalf("Failed to write coverage table: %v", err)
+	}
+
+	onlyV1, onlyV2, dropped := 0, 0, 0
+	for _, row := range rows {
+		switch {
+		case !row.InV2:
+			onlyV1++
+		case !row.InV1:
+			onlyV2++
+		}
+		if row.Dropped() {
+			dropped++
+		}
+	}
+	fmt.Printf("\n   %d fields compared: %d only in v1, %d only in v2, %d populated by one SDK only\n",
+		len(rows), onlyV1, onlyV2, dropped)
+
+	if dropped > 0 {
+		fmt.Printf("\n✗ %d fields are populated by one SDK but not the other\n", dropped)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Every field populated by one SDK is populated by the other")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 fields are all pointers; v2 uses values for enums, slices and some booleans")
+	fmt.Println("  - An unset v2 enum is \"\", where v1 would leave the *string nil")
+	fmt.Println("  - Fields added to the API after v1 entered maintenance may exist only in v2")
+}
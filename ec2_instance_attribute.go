@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates instance attributes across SDKs. It reads an
+// instance's DisableApiTermination attribute with v1, toggles it with v2's
+// ModifyInstanceAttribute and reads it back with v1 to confirm, then
+// restores the original value with v1. Both SDKs wrap the flag in an
+// AttributeBooleanValue, *ec2.AttributeBooleanValue in v1 and
+// ec2types.AttributeBooleanValue in v2. Changing an instance's termination
+// protection is only done with -allow-modify.
+func main() {
+	instanceID := flag.String("instance-id", "", "instance whose DisableApiTermination attribute is read and toggled (required)")
+	allow := flag.Bool("allow-modify", false, "actually toggle the attribute (and then restore it)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	if *instanceID == "" {
+		fmt.Fprintln(os.Stderr, "-instance-id is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== EC2 Instance Attribute Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Printf("Instance: %s\n\n", *instanceID)
+
+	readV1 := func() (bool, error) {
+		out, err := ec2ClientV1.DescribeInstanceAttributeWithContext(ctx, &ec2v1.DescribeInstanceAttributeInput{
+			InstanceId: aws.String(*instanceID),
+			Attribute:  aws.String(ec2v1.InstanceAttributeNameDisableApiTermination),
+		})
+		if err != nil {
+			return false, err
+		}
+		if out.DisableApiTermination == nil {
+			return false, fmt.Errorf("response has no DisableApiTermination")
+		}
+		return aws.BoolValue(out.DisableApiTermination.Value), nil
+	}
+	readV2 := func() (bool, error) {
+		out, err := ec2ClientV2.DescribeInstanceAttribute(ctx, &ec2v2.DescribeInstanceAttributeInput{
+			InstanceId: aws.String(*instanceID),
+			Attribute:  ec2types.InstanceAttributeNameDisableApiTermination,
+		})
+		if err != nil {
+			return false, err
+		}
+		if out.DisableApiTermination == nil || out.DisableApiTermination.Value == nil {
+			return false, fmt.Errorf("response has no DisableApiTermination")
+		}
+		return *out.DisableApiTermination.Value, nil
+	}
+
+	// Read with v1
+	fmt.Println("1. Using SDK v1 to read DisableApiTermination...")
+	original, err := readV1()
+	if interop.IsNotFound(err) {
+		fmt.Printf("   ✗ Instance %s does not exist in %s\n", *instanceID, region)
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalf("Failed to describe instance attribute with v1: %v", err)
+	}
+	fmt.Printf("   ✓ DisableApiTermination: %t\n", original)
+
+	if !*allow {
+		fmt.Printf("\nToggling the attribute would turn termination protection %s for %s.\n", onOff(!original), *instanceID)
+		fmt.Println("Re-run with -allow-modify to proceed; the original value is restored afterwards.")
+		return
+	}
+
+	failures := 0
+	toggled := !original
+
+	// Modify with v2
+	fmt.Printf("\n2. Using SDK v2 to set DisableApiTermination to %t...\n", toggled)
+	_, err = ec2ClientV2.ModifyInstanceAttribute(ctx, &ec2v2.ModifyInstanceAttributeInput{
+		InstanceId:            aws.String(*instanceID),
+		DisableApiTermination: &ec2types.AttributeBooleanValue{Value: aws.Bool(toggled)},
+	})
+	if err != nil {
+		log.Fatalf("Failed to modify instance attribute with v2: %v", err)
+	}
+	fmt.Println("   ✓ Modified")
+
+	cleanup := func() {
+		fmt.Println("\nCLEANUP: Restoring DisableApiTermination")
+		fmt.Println("----------------------------------------")
+		_, err := ec2ClientV1.ModifyInstanceAttributeWithContext(ctx, &ec2v1.ModifyInstanceAttributeInput{
+			InstanceId:            aws.String(*instanceID),
+			DisableApiTermination: &ec2v1.AttributeBooleanValue{Value: aws.Bool(original)},
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to restore DisableApiTermination: %v", err)
+			fmt.Printf("\nPlease manually set DisableApiTermination back to %t on instance: %s\n", original, *instanceID)
+			failures++
+			return
+		}
+		fmt.Printf("✓ DisableApiTermination restored to %t (SDK v1)\n", original)
+		if got, err := readV2(); err != nil {
+			log.Printf("Warning: Failed to read the restored attribute with v2: %v", err)
+		} else if got != original {
+			fmt.Printf("✗ SDK v2 reads %t after the restore\n", got)
+			failures++
+		} else {
+			fmt.Printf("✓ SDK v2 reads %t after the restore\n", got)
+		}
+	}
+
+	// Read back with v1
+	fmt.Println("\n3. Using SDK v1 to read the attribute back...")
+	got, err := readV1()
+	switch {
+	case err != nil:
+		fmt.Printf("   ✗ Failed to read the attribute back: %v\n", err)
+		failures++
+	case got != toggled:
+		fmt.Printf("   ✗ SDK v1 reads %t, want %t\n", got, toggled)
+		failures++
+	default:
+		fmt.Printf("   ✓ SDK v1 reads %t, the value set with SDK v2\n", got)
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d instance attribute checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ DisableApiTermination set with one SDK reads back the same with the other")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Both wrap the flag in AttributeBooleanValue: v1 *ec2.AttributeBooleanValue, v2 *ec2types.AttributeBooleanValue")
+	fmt.Println("  - v1 Attribute is a *string constant, v2 the ec2types.InstanceAttributeName enum")
+	fmt.Println("  - Both also accept Attribute with a string Value in ModifyInstanceAttribute; the typed field avoids encoding the bool")
+}
+
+// onOff describes a termination protection setting.
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
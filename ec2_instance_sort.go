@@ -0,0 +1,179 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// sortFixture is one instance of the fixture, described the same way with
+// both SDKs. Several share a type or a launch time, to check tie-breaking.
+type sortFixture struct {
+	id, instanceType, launched string
+}
+
+var sortFixtures = []sortFixture{
+	{"i-0a00000000000000e", "t3.micro", "2024-03-01T10:00:00Z"},
+	{"i-0a000000000000003", "m5.large", "2024-01-15T08:30:00Z"},
+	{"i-0a00000000000000b", "t3.micro", "2024-01-15T08:30:00Z"},
+	{"i-0a000000000000001", "c6i.xlarge", "2024-06-30T23:59:59Z"},
+	{"i-0a000000000000007", "m5.large", "2023-12-31T00:00:00Z"},
+	{"i-0a000000000000005", "t3.micro", "2024-03-01T10:00:00.250Z"},
+}
+
+// wantOrders are the expected instance ID orders, written out by hand
+// rather than computed, so that the check does not share the sort's logic.
+var wantOrders = map[interop.InstanceOrder][]string{
+	interop.InstanceOrderID: {
+		"i-0a000000000000001", "i-0a000000000000003", "i-0a000000000000005",
+		"i-0a000000000000007", "i-0a00000000000000b", "i-0a00000000000000e",
+	},
+	// Launch times are compared to the second, so the .250s launch ties
+	// with the one before it and the tie goes by ID.
+	interop.InstanceOrderLaunchTime: {
+		"i-0a000000000000007", "i-0a000000000000003", "i-0a00000000000000b",
+		"i-0a000000000000005", "i-0a00000000000000e", "i-0a000000000000001",
+	},
+	interop.InstanceOrderType: {
+		"i-0a000000000000001", "i-0a000000000000003", "i-0a000000000000007",
+		"i-0a000000000000005", "i-0a00000000000000b", "i-0a00000000000000e",
+	},
+}
+
+// This example demonstrates sorting DescribeInstances results for stable
+// diffs. DescribeInstances returns instances in no particular order, so the
+// same fixture is shuffled differently for each SDK and sorted with
+// interop.SortInstancesV1 and interop.SortInstancesV2 by every
+// interop.InstanceOrder; both must give the same, expected order. The ec2
+// comparer, which sorts by interop.SortInstancesBy, must then find no
+// differences between the shuffled results either. -seed reproduces a
+// shuffle.
+func main() {
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seed of the shuffles")
+	rounds := flag.Int("rounds", 20, "shuffles checked per order")
+	interop.ParseFlags()
+
+	if *rounds < 1 {
+		fmt.Fprintf(os.Stderr, "Invalid -rounds %d: must be at least 1\n", *rounds)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== EC2 Instance Sort Test ===\n\n")
+	fmt.Printf("Seed: %d\n\n", *seed)
+
+	rng := rand.New(rand.NewSource(*seed))
+	failures := 0
+
+	fmt.Printf("1. Sorting %d shuffles of %d instances by each order with both SDKs...\n", *rounds, len(sortFixtures))
+	for _, order := range interop.InstanceOrders {
+		want := strings.Join(wantOrders[order], " ")
+		failed := false
+		for round := 0; round < *rounds && !failed; round++ {
+			v1, v2 := fixtureInstancesV1(), fixtureInstancesV2()
+			rng.Shuffle(len(v1), func(i, j int) { v1[i], v1[j] = v1[j], v1[i] })
+			rng.Shuffle(len(v2), func(i, j int) { v2[i], v2[j] = v2[j], v2[i] })
+			interop.SortInstancesV1(v1, order)
+			interop.SortInstancesV2(v2, order)
+
+			var idsV1, idsV2 []string
+			for _, inst := range v1 {
+				idsV1 = append(idsV1, aws.StringValue(inst.InstanceId))
+			}
+			for _, inst := range v2 {
+				idsV2 = append(idsV2, aws.StringValue(inst.InstanceId))
+			}
+			gotV1, gotV2 := strings.Join(idsV1, " "), strings.Join(idsV2, " ")
+			if gotV1 != want || gotV2 != want {
+				fmt.Printf("   ✗ %s (round %d)\n       v1:   %s\n       v2:   %s\n       want: %s\n", order, round+1, gotV1, gotV2, want)
+				failed = true
+				failures++
+			}
+		}
+		if !failed {
+			fmt.Printf("   ✓ %-11s %s\n", order, want)
+		}
+	}
+
+	fmt.Println("\n2. Comparing shuffled results with the ec2 comparer...")
+	comparer, ok := interop.LookupComparer("ec2")
+	if !ok {
+		log.Fatal("The ec2 comparer is not registered")
+	}
+	for _, order := range interop.InstanceOrders {
+		interop.SortInstancesBy = order
+		v1, v2 := fixtureInstancesV1(), fixtureInstancesV2()
+		rng.Shuffle(len(v1), func(i, j int) { v1[i], v1[j] = v1[j], v1[i] })
+		rng.Shuffle(len(v2), func(i, j int) { v2[i], v2[j] = v2[j], v2[i] })
+		diffs, err := interop.DiffJSON(comparer.Normalize(v1), comparer.Normalize(v2))
+		switch {
+		case err != nil:
+			fmt.Printf("   ✗ %s: %v\n", order, err)
+			failures++
+		case len(diffs) > 0:
+			fmt.Printf("   ✗ %s: %d differences, first %s\n", order, len(diffs), diffs[0])
+			failures++
+		default:
+			fmt.Printf("   ✓ %s: no differences\n", order)
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d sort checks failed (rerun with -seed %d)\n", failures, *seed)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs' instances sort into the same order, whatever order they arrive in")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 returns []*ec2.Instance, v2 []ec2types.Instance, so each needs its own sort")
+	fmt.Println("  - v1 InstanceType is *string, v2 the ec2types.InstanceType enum; both sort by name")
+	fmt.Println("  - Neither SDK orders DescribeInstances results; sort before diffing or printing")
+}
+
+func fixtureInstancesV1() []*ec2v1.Instance {
+	var instances []*ec2v1.Instance
+	for _, f := range sortFixtures {
+		instances = append(instances, &ec2v1.Instance{
+			InstanceId:   aws.String(f.id),
+			InstanceType: aws.String(f.instanceType),
+			LaunchTime:   aws.Time(mustParseTime(f.launched)),
+			State:        &ec2v1.InstanceState{Name: aws.String(ec2v1.InstanceStateNameRunning)},
+		})
+	}
+	return instances
+}
+
+func fixtureInstancesV2() []ec2types.Instance {
+	var instances []ec2types.Instance
+	for _, f := range sortFixtures {
+		instances = append(instances, ec2types.Instance{
+			InstanceId:   aws.String(f.id),
+			InstanceType: ec2types.InstanceType(f.instanceType),
+			LaunchTime:   aws.Time(mustParseTime(f.launched)),
+			State:        &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		})
+	}
+	return instances
+}
+
+func mustParseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		log.Fatalf("Invalid fixture time %q: %v", s, err)
+	}
+	return t
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// instanceTypeInfo is an SDK-neutral view of the compared fields of an
+// instance type. GPUs is empty for types without GPUs, whose GpuInfo is nil
+// in both SDKs.
+type instanceTypeInfo struct {
+	Type               string
+	VCPUs              int64
+	MemoryMiB          int64
+	NetworkPerformance string
+	MaxInterfaces      int64
+	EnaSupport         string
+	Architectures      []string // sorted
+	GPUs               []string // "count x manufacturer name (MiB)", sorted
+}
+
+func (t instanceTypeInfo) String() string {
+	gpus := "none"
+	if len(t.GPUs) > 0 {
+		gpus = strings.Join(t.GPUs, ", ")
+	}
+	return fmt.Sprintf("%s: %d vCPUs, %d MiB, network %q (max %d ENIs, ENA %s), arch [%s], GPUs %s",
+		t.Type, t.VCPUs, t.MemoryMiB, t.NetworkPerformance, t.MaxInterfaces, t.EnaSupport,
+		strings.Join(t.Architectures, ","), gpus)
+}
+
+// This example demonstrates describing instance types with both SDKs. The
+// response is deeply nested and full of enums, and nested structs such as
+// GpuInfo are nil for types that lack the feature, so each converter must
+// check every level. The request is limited to the types passed in -types.
+func main() {
+	types := flag.String("types", "t3.micro,m5.large,g4dn.xlarge", "comma-separated instance types to describe")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Instance Types Interop Test ===\n\n")
+
+	var names []string
+	for _, name := range strings.Split(*types, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	// DescribeInstanceTypes accepts at most 100 types per request.
+	if len(names) == 0 || len(names) > 100 {
+		fmt.Fprintln(os.Stderr, "-types must name between 1 and 100 instance types")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Printf("Instance types: %s\n\n", strings.Join(names, ", "))
+
+	// A type that does not exist fails the whole request rather than being
+	// left out of the result.
+	explain := func(sdk string, err error) {
+		if interop.ErrorCode(err) == "InvalidInstanceType" {
+			log.Fatalf("At least one of %s is not an instance type in %s (SDK %s): %v", strings.Join(names, ", "), region, sdk, err)
+		}
+		log.Fatalf("Failed to describe instance types with %s: %v", sdk, err)
+	}
+
+	// Use v1 to describe the instance types
+	fmt.Println("1. Using SDK v1 to describe instance types...")
+	typesV1 := make(map[string]instanceTypeInfo)
+	err = ec2ClientV1.DescribeInstanceTypesPagesWithContext(ctx, &ec2.DescribeInstanceTypesInput{
+		InstanceTypes: aws.StringSlice(names),
+	}, func(page *ec2.DescribeInstanceTypesOutput, lastPage bool) bool {
+		for _, it := range page.InstanceTypes {
+			info := instanceTypeInfoFromV1(it)
+			typesV1[info.Type] = info
+		}
+		return true
+	})
+	if err != nil {
+		explain("v1", err)
+	}
+	fmt.Printf("   ✓ Found %d instance types using SDK v1\n", len(typesV1))
+
+	// Use v2 to describe the instance types
+	fmt.Println("\n2. Using SDK v2 to describe instance types...")
+	typesV2 := make(map[string]instanceTypeInfo)
+	input := &ec2v2.DescribeInstanceTypesInput{}
+	for _, name := range names {
+		input.InstanceTypes = append(input.InstanceTypes, ec2types.InstanceType(name))
+	}
+	paginator := ec2v2.NewDescribeInstanceTypesPaginator(ec2ClientV2, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			explain("v2", err)
+		}
+		for _, it := range page.InstanceTypes {
+			info := instanceTypeInfoFromV2(it)
+			typesV2[info.Type] = info
+		}
+	}
+	fmt.Printf("   ✓ Found %d instance types using SDK v2\n", len(typesV2))
+
+	// Compare
+	fmt.Println("\n3. Comparing instance types...")
+	keys := append([]string(nil), names...)
+	sort.Strings(keys)
+
+	mismatches := 0
+	for _, k := range keys {
+		v1, inV1 := typesV1[k]
+		v2, inV2 := typesV2[k]
+		switch {
+		case !inV1 && !inV2:
+			fmt.Printf("   ✗ %s was not returned by either SDK\n", k)
+			mismatches++
+		case !inV1:
+			fmt.Printf("   ✗ %s only returned by SDK v2\n", v2)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only returned by SDK v1\n", v1)
+			mismatches++
+		case v1.String() != v2.String():
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", k, v1, v2)
+			mismatches++
+		default:
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d instance types did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs describe the same %d instance types\n", len(keys))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 counts such as DefaultVCpus are *int64, v2 *int32 (MemoryInfo.SizeInMiB stays *int64)")
+	fmt.Println("  - v1 InstanceTypes and SupportedArchitectures are []*string, v2 slices of enum types")
+	fmt.Println("  - GpuInfo and other optional nested structs are nil pointers in both SDKs when absent")
+}
+
+func instanceTypeInfoFromV1(it *ec2.InstanceTypeInfo) instanceTypeInfo {
+	info := instanceTypeInfo{
+		Type: aws.StringValue(it.InstanceType),
+	}
+	if it.VCpuInfo != nil {
+		info.VCPUs = aws.Int64Value(it.VCpuInfo.DefaultVCpus)
+	}
+	if it.MemoryInfo != nil {
+		info.MemoryMiB = aws.Int64Value(it.MemoryInfo.SizeInMiB)
+	}
+	if it.NetworkInfo != nil {
+		info.NetworkPerformance = aws.StringValue(it.NetworkInfo.NetworkPerformance)
+		info.MaxInterfaces = aws.Int64Value(it.NetworkInfo.MaximumNetworkInterfaces)
+		info.EnaSupport = aws.StringValue(it.NetworkInfo.EnaSupport)
+	}
+	if it.ProcessorInfo != nil {
+		info.Architectures = aws.StringValueSlice(it.ProcessorInfo.SupportedArchitectures)
+		sort.Strings(info.Architectures)
+	}
+	if it.GpuInfo != nil {
+		for _, gpu := range it.GpuInfo.Gpus {
+			var mib int64
+			if gpu.MemoryInfo != nil {
+				mib = aws.Int64Value(gpu.MemoryInfo.SizeInMiB)
+			}
+			info.GPUs = append(info.GPUs, fmt.Sprintf("%d x %s %s (%d MiB)",
+				aws.Int64Value(gpu.Count), aws.StringValue(gpu.Manufacturer), aws.StringValue(gpu.Name), mib))
+		}
+		sort.Strings(info.GPUs)
+	}
+	return info
+}
+
+func instanceTypeInfoFromV2(it ec2types.InstanceTypeInfo) instanceTypeInfo {
+	info := instanceTypeInfo{
+		Type: string(it.InstanceType),
+	}
+	if it.VCpuInfo != nil && it.VCpuInfo.DefaultVCpus != nil {
+		info.VCPUs = int64(*it.VCpuInfo.DefaultVCpus)
+	}
+	if it.MemoryInfo != nil && it.MemoryInfo.SizeInMiB != nil {
+		info.MemoryMiB = *it.MemoryInfo.SizeInMiB
+	}
+	if n := it.NetworkInfo; n != nil {
+		if n.NetworkPerformance != nil {
+			info.NetworkPerformance = *n.NetworkPerformance
+		}
+		if n.MaximumNetworkInterfaces != nil {
+			info.MaxInterfaces = int64(*n.MaximumNetworkInterfaces)
+		}
+		info.EnaSupport = string(n.EnaSupport)
+	}
+	if it.ProcessorInfo != nil {
+		for _, arch := range it.ProcessorInfo.SupportedArchitectures {
+			info.Architectures = append(info.Architectures, string(arch))
+		}
+		sort.Strings(info.Architectures)
+	}
+	if it.GpuInfo != nil {
+		for _, gpu := range it.GpuInfo.Gpus {
+			var count, mib int64
+			if gpu.Count != nil {
+				count = int64(*gpu.Count)
+			}
+			if gpu.MemoryInfo != nil && gpu.MemoryInfo.SizeInMiB != nil {
+				mib = int64(*gpu.MemoryInfo.SizeInMiB)
+			}
+			var manufacturer, name string
+			if gpu.Manufacturer != nil {
+				manufacturer = *gpu.Manufacturer
+			}
+			if gpu.Name != nil {
+				name = *gpu.Name
+			}
+			info.GPUs = append(info.GPUs, fmt.Sprintf("%d x %s %s (%d MiB)", count, manufacturer, name, mib))
+		}
+		sort.Strings(info.GPUs)
+	}
+	return info
+}
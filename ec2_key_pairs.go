@@ -0,0 +1,269 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// keyPair is an SDK-neutral view of a key pair, holding only the fields that
+// are compared between v1 and v2.
+type keyPair struct {
+	Name        string
+	ID          string
+	Type        string
+	Fingerprint string
+}
+
+func (k keyPair) String() string {
+	return fmt.Sprintf("%s (ID: %s, Type: %s, Fingerprint: %s)", k.Name, k.ID, k.Type, k.Fingerprint)
+}
+
+// This example demonstrates describing EC2 key pairs with both SDKs and
+// comparing their names, fingerprints and key types. The key type is a
+// *string in v1 and the ec2types.KeyType enum in v2. With -create, an RSA
+// key pair is created with v1 and an ED25519 key pair with v2 first, so that
+// both types are compared even in an account without key pairs, and both are
+// deleted at the end.
+func main() {
+	create := flag.Bool("create", false, "create an RSA and an ED25519 test key pair to compare, and delete them afterwards")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Key Pair Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	var created []keyPair
+	cleanup := func() {
+		if len(created) == 0 {
+			return
+		}
+		fmt.Println("\nCleaning up...")
+		if !interop.ConfirmDestructive(fmt.Sprintf("%d test key pairs", len(created))) {
+			for _, k := range created {
+				fmt.Printf("Please manually delete key pair: %s\n", k.Name)
+			}
+			return
+		}
+		for _, k := range created {
+			_, err := ec2ClientV2.DeleteKeyPair(ctx, &ec2v2.DeleteKeyPairInput{
+				KeyPairId: aws.String(k.ID),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete key pair %s: %v", k.Name, err)
+				continue
+			}
+			fmt.Printf("✓ Deleted key pair %s\n", k.Name)
+		}
+	}
+
+	step := 1
+	if *create {
+		suffix := interop.DefaultClock.Now().Unix()
+
+		// Create an RSA key pair with v1
+		fmt.Printf("%d. Using SDK v1 to create an RSA key pair...\n", step)
+		step++
+		outV1, err := ec2ClientV1.CreateKeyPairWithContext(ctx, &ec2.CreateKeyPairInput{
+			KeyName: aws.String(fmt.Sprintf("sdk-migration-test-rsa-%d", suffix)),
+			KeyType: aws.String(ec2.KeyTypeRsa),
+		})
+		if err != nil {
+			log.Fatalf("Failed to create RSA key pair with v1: %v", err)
+		}
+		k := keyPair{
+			Name:        aws.StringValue(outV1.KeyName),
+			ID:          aws.StringValue(outV1.KeyPairId),
+			Type:        ec2.KeyTypeRsa,
+			Fingerprint: aws.StringValue(outV1.KeyFingerprint),
+		}
+		created = append(created, k)
+		fmt.Printf("   ✓ Created %s\n", k)
+
+		// Create an ED25519 key pair with v2
+		fmt.Printf("\n%d. Using SDK v2 to create an ED25519 key pair...\n", step)
+		step++
+		outV2, err := ec2ClientV2.CreateKeyPair(ctx, &ec2v2.CreateKeyPairInput{
+			KeyName: aws.String(fmt.Sprintf("sdk-migration-test-ed25519-%d", suffix)),
+			KeyType: ec2types.KeyTypeEd25519,
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to create ED25519 key pair with v2: %v", err)
+		}
+		k = keyPair{Type: string(ec2types.KeyTypeEd25519)}
+		if outV2.KeyName != nil {
+			k.Name = *outV2.KeyName
+		}
+		if outV2.KeyPairId != nil {
+			k.ID = *outV2.KeyPairId
+		}
+		if outV2.KeyFingerprint != nil {
+			k.Fingerprint = *outV2.KeyFingerprint
+		}
+		created = append(created, k)
+		fmt.Printf("   ✓ Created %s\n\n", k)
+	}
+
+	// Use v1 to list key pairs
+	fmt.Printf("%d. Using SDK v1 to list key pairs...\n", step)
+	step++
+	outV1, err := ec2ClientV1.DescribeKeyPairsWithContext(ctx, &ec2.DescribeKeyPairsInput{})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to describe key pairs with v1: %v", err)
+	}
+	keysV1 := make(map[string]keyPair)
+	for _, info := range outV1.KeyPairs {
+		k := keyPairFromV1(info)
+		keysV1[k.Name] = k
+	}
+	fmt.Printf("   ✓ Found %d key pairs using SDK v1\n", len(keysV1))
+
+	// Use v2 to list key pairs
+	fmt.Printf("\n%d. Using SDK v2 to list key pairs...\n", step)
+	step++
+	outV2, err := ec2ClientV2.DescribeKeyPairs(ctx, &ec2v2.DescribeKeyPairsInput{})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to describe key pairs with v2: %v", err)
+	}
+	keysV2 := make(map[string]keyPair)
+	for _, info := range outV2.KeyPairs {
+		k := keyPairFromV2(info)
+		keysV2[k.Name] = k
+	}
+	fmt.Printf("   ✓ Found %d key pairs using SDK v2\n", len(keysV2))
+
+	if len(keysV1) == 0 && len(keysV2) == 0 {
+		fmt.Printf("\nNo key pairs exist in %s, so there is nothing to compare.\n", region)
+		fmt.Println("Re-run with -create to compare an RSA and an ED25519 test key pair.")
+		return
+	}
+
+	// Compare
+	fmt.Printf("\n%d. Comparing key pairs...\n", step)
+	names := make([]string, 0, len(keysV1))
+	for name := range keysV1 {
+		names = append(names, name)
+	}
+	for name := range keysV2 {
+		if _, ok := keysV1[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	mismatches := 0
+	types := make(map[string]int)
+	for _, name := range names {
+		v1, inV1 := keysV1[name]
+		v2, inV2 := keysV2[name]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", name)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", name)
+			mismatches++
+		case v1 != v2:
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", name, v1, v2)
+			mismatches++
+		default:
+			types[v1.Type]++
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+	for _, k := range created {
+		if got, ok := keysV2[k.Name]; ok && got != k {
+			fmt.Printf("   ✗ %s was described differently than it was created\n       created:   %s\n       described: %s\n", k.Name, k, got)
+			mismatches++
+		}
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d key pairs did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d key pairs (%d rsa, %d ed25519)\n",
+		len(names), types[ec2.KeyTypeRsa], types[string(ec2types.KeyTypeEd25519)])
+	fmt.Println("✓ Names, IDs, key types and fingerprints agree")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 KeyType is a *string compared with ec2.KeyTypeRsa, v2 the ec2types.KeyType enum")
+	fmt.Println("  - v2 CreateKeyPairInput.KeyType is a value; leaving it empty creates an RSA key pair, as omitting it does in v1")
+	fmt.Println("  - Fingerprints are SHA-1 digests for RSA key pairs created by AWS but base64 SHA-256 for ED25519, in both SDKs")
+}
+
+func keyPairFromV1(info *ec2.KeyPairInfo) keyPair {
+	return keyPair{
+		Name:        aws.StringValue(info.KeyName),
+		ID:          aws.StringValue(info.KeyPairId),
+		Type:        aws.StringValue(info.KeyType),
+		Fingerprint: aws.StringValue(info.KeyFingerprint),
+	}
+}
+
+func keyPairFromV2(info ec2types.KeyPairInfo) keyPair {
+	k := keyPair{
+		Type: string(info.KeyType),
+	}
+	if info.KeyName != nil {
+		k.Name = *info.KeyName
+	}
+	if info.KeyPairId != nil {
+		k.ID = *info.KeyPairId
+	}
+	if info.KeyFingerprint != nil {
+		k.Fingerprint = *info.KeyFingerprint
+	}
+	return k
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// amiParameter is the public SSM parameter holding the latest Amazon Linux
+// 2023 AMI for the region.
+const amiParameter = "/aws/service/ami-amazon-linux-latest/al2023-ami-kernel-default-x86_64"
+
+// launchTags are the tags the instance is launched with.
+var launchTags = map[string]string{
+	"app":  "sdk-migration-test",
+	"Name": "sdk-migration-launch",
+}
+
+// This example demonstrates idempotent instance launches across SDKs. One
+// client token is passed to RunInstances three times, twice with SDK v1 and
+// once with SDK v2, and every call must return the same instance, whose
+// tags both SDKs then read back. It launches a real instance, so it only
+// does so with -allow-launch.
+func main() {
+	allow := flag.Bool("allow-launch", false, "actually launch (and then terminate) a test instance")
+	instanceType := flag.String("instance-type", "t3.micro", "instance type to launch")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Idempotent Launch Interop Test ===\n\n")
+
+	if !*allow {
+		fmt.Printf("This example launches one %s instance and terminates it afterwards.\n", *instanceType)
+		fmt.Println("Re-run with -allow-launch to proceed.")
+		return
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+	token := interop.NewClientToken()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	ssmClientV2 := ssmv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Printf("Client token: %s\n\n", token)
+
+	fmt.Println("1. Resolving the latest Amazon Linux 2023 AMI...")
+	param, err := ssmClientV2.GetParameter(ctx, &ssmv2.GetParameterInput{
+		Name: aws.String(amiParameter),
+	})
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v", amiParameter, err)
+	}
+	imageID := aws.StringValue(param.Parameter.Value)
+	fmt.Printf("   ✓ %s\n", imageID)
+
+	var launchTagsV1 []*ec2.Tag
+	var launchTagsV2 []ec2types.Tag
+	for k, v := range launchTags {
+		launchTagsV1 = append(launchTagsV1, &ec2.Tag{Key: aws.String(k), Value: aws.String(v)})
+		launchTagsV2 = append(launchTagsV2, ec2types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+
+	runV1 := func() (string, error) {
+		out, err := ec2ClientV1.RunInstancesWithContext(ctx, &ec2.RunInstancesInput{
+			ImageId:      aws.String(imageID),
+			InstanceType: aws.String(*instanceType),
+			MinCount:     aws.Int64(1),
+			MaxCount:     aws.Int64(1),
+			ClientToken:  aws.String(token),
+			TagSpecifications: []*ec2.TagSpecification{{
+				ResourceType: aws.String(ec2.ResourceTypeInstance),
+				Tags:         launchTagsV1,
+			}},
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(out.Instances[0].InstanceId), nil
+	}
+	runV2 := func() (string, error) {
+		out, err := ec2ClientV2.RunInstances(ctx, &ec2v2.RunInstancesInput{
+			ImageId:      aws.String(imageID),
+			InstanceType: ec2types.InstanceType(*instanceType),
+			MinCount:     aws.Int32(1),
+			MaxCount:     aws.Int32(1),
+			ClientToken:  aws.String(token),
+			TagSpecifications: []ec2types.TagSpecification{{
+				ResourceType: ec2types.ResourceTypeInstance,
+				Tags:         launchTagsV2,
+			}},
+		})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(out.Instances[0].InstanceId), nil
+	}
+
+	fmt.Println("\n2. Using SDK v1 to launch an instance with the client token...")
+	instanceID, err := runV1()
+	if err != nil {
+		log.Fatalf("Failed to run instance with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Launched %s\n", instanceID)
+
+	cleanup := func() {
+		fmt.Println("\nCLEANUP: Terminating the test instance")
+		fmt.Println("----------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("instance '%s'", instanceID)) {
+			fmt.Printf("\nPlease manually terminate instance: %s\n", instanceID)
+			return
+		}
+		_, err := ec2ClientV2.TerminateInstances(ctx, &ec2v2.TerminateInstancesInput{
+			InstanceIds: []string{instanceID},
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to terminate instance: %v", err)
+			fmt.Printf("\nPlease manually terminate instance: %s\n", instanceID)
+		} else {
+			fmt.Printf("✓ Instance %s terminating (SDK v2)\n", instanceID)
+		}
+	}
+
+	failures := 0
+	repeat := func(step, sdk string, run func() (string, error)) {
+		fmt.Printf("\n%s. Repeating the launch with SDK %s and the same token...\n", step, sdk)
+		id, err := run()
+		switch {
+		case err != nil:
+			fmt.Printf("   ✗ Repeated launch failed: %v\n", err)
+			failures++
+		case id != instanceID:
+			fmt.Printf("   ✗ A second instance %s was created\n", id)
+			failures++
+			// Terminate the duplicate too, so the check never leaks instances.
+			if _, err := ec2ClientV2.TerminateInstances(ctx, &ec2v2.TerminateInstancesInput{InstanceIds: []string{id}}); err != nil {
+				log.Printf("Warning: Failed to terminate duplicate instance %s: %v", id, err)
+			}
+		default:
+			fmt.Printf("   ✓ Returned the existing instance %s; nothing new was launched\n", id)
+		}
+	}
+	repeat("3", "v1", runV1)
+	repeat("4", "v2", runV2)
+
+	// Instances launched from a launch template, or by CloudFormation or
+	// Auto Scaling, also carry aws: tags, which interop.TagsEqual ignores.
+	fmt.Println("\n5. Reading the instance's tags with both SDKs...")
+	filters := interop.FilterSet{}.Add("resource-id", instanceID)
+	tagsV1 := map[string]string{}
+	tagsOutV1, errV1 := ec2ClientV1.DescribeTagsWithContext(ctx, &ec2.DescribeTagsInput{Filters: filters.V1()})
+	if errV1 == nil {
+		for _, t := range tagsOutV1.Tags {
+			tagsV1[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+		}
+	}
+	tagsV2 := map[string]string{}
+	tagsOutV2, errV2 := ec2ClientV2.DescribeTags(ctx, &ec2v2.DescribeTagsInput{Filters: filters.V2()})
+	if errV2 == nil {
+		for _, t := range tagsOutV2.Tags {
+			tagsV2[aws.StringValue(t.Key)] = aws.StringValue(t.Value)
+		}
+	}
+	switch {
+	case errV1 != nil || errV2 != nil:
+		fmt.Printf("   ✗ DescribeTags failed: v1 %v, v2 %v\n", errV1, errV2)
+		failures++
+	case !interop.TagsEqual(tagsV1, launchTags) || !interop.TagsEqual(tagsV2, launchTags):
+		fmt.Printf("   ✗ Tags differ from the launch tags %v\n       v1: %v\n       v2: %v\n", launchTags, tagsV1, tagsV2)
+		failures++
+	default:
+		fmt.Printf("   ✓ Both SDKs read the %d launch tags\n", len(launchTags))
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d launch checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ A client token makes RunInstances idempotent, across SDK versions too")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Both SDKs generate a token when ClientToken is empty, but a new one per call")
+	fmt.Println("  - v1 MinCount/MaxCount are *int64, v2 are *int32")
+	fmt.Println("  - v1 InstanceType and ResourceType are *string, v2 uses typed enums")
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// networkInterface is an SDK-neutral view of an ENI, holding only the fields
+// that are compared between v1 and v2.
+type networkInterface struct {
+	ID               string
+	Status           string
+	AttachmentStatus string
+	InstanceID       string
+	PrivateIPs       []string
+	SecurityGroups   []string
+}
+
+func (n networkInterface) String() string {
+	attachment := "detached"
+	if n.AttachmentStatus != "" {
+		attachment = n.AttachmentStatus
+		if n.InstanceID != "" {
+			attachment += " to " + n.InstanceID
+		}
+	}
+	return fmt.Sprintf("%s (Status: %s, Attachment: %s, IPs: %s, SGs: %s)",
+		n.ID, n.Status, attachment, strings.Join(n.PrivateIPs, ","), strings.Join(n.SecurityGroups, ","))
+}
+
+// This example demonstrates describing EC2 network interfaces with both SDKs.
+// ENIs are deeply nested and nil-heavy, so each SDK's result is flattened
+// into the same shape before the two are compared.
+func main() {
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Network Interface Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Use v1 to list network interfaces
+	fmt.Println("1. Using SDK v1 to list network interfaces (all pages)...")
+	enisV1 := make(map[string]networkInterface)
+	err = ec2ClientV1.DescribeNetworkInterfacesPages(&ec2.DescribeNetworkInterfacesInput{},
+		func(page *ec2.DescribeNetworkInterfacesOutput, lastPage bool) bool {
+			for _, eni := range page.NetworkInterfaces {
+				n := networkInterfaceFromV1(eni)
+				enisV1[n.ID] = n
+			}
+			return true
+		})
+	if err != nil {
+		log.Fatalf("Failed to list network interfaces with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d network interfaces using SDK v1\n", len(enisV1))
+
+	// Use v2 to list network interfaces
+	fmt.Println("\n2. Using SDK v2 to list network interfaces (all pages)...")
+	enisV2 := make(map[string]networkInterface)
+	paginator := ec2v2.NewDescribeNetworkInterfacesPaginator(ec2ClientV2, &ec2v2.DescribeNetworkInterfacesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Fatalf("Failed to list network interfaces with v2: %v", err)
+		}
+		for _, eni := range page.NetworkInterfaces {
+			n := networkInterfaceFromV2(eni)
+			enisV2[n.ID] = n
+		}
+	}
+	fmt.Printf("   ✓ Found %d network interfaces using SDK v2\n", len(enisV2))
+
+	// Compare
+	fmt.Println("\n3. Comparing network interfaces...")
+	ids := make([]string, 0, len(enisV1))
+	for id := range enisV1 {
+		ids = append(ids, id)
+	}
+	for id := range enisV2 {
+		if _, ok := enisV1[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	mismatches := 0
+	for _, id := range ids {
+		v1, inV1 := enisV1[id]
+		v2, inV2 := enisV2[id]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", id)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", id)
+			mismatches++
+		case v1.String() != v2.String():
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", id, v1, v2)
+			mismatches++
+		default:
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d network interfaces did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d network interfaces\n", len(ids))
+	fmt.Println("✓ Attachment status, private IPs and security groups agree")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 pages through a callback, v2 uses an explicit paginator")
+	fmt.Println("  - v2 Attachment and Status fields are typed enums instead of *string")
+	fmt.Println("  - Nested pointers (Attachment, PrivateIpAddress, GroupId) can be nil in both SDKs; a nil address or group is skipped rather than listed as empty")
+}
+
+func networkInterfaceFromV1(eni *ec2.NetworkInterface) networkInterface {
+	n := networkInterface{
+		ID:     aws.StringValue(eni.NetworkInterfaceId),
+		Status: aws.StringValue(eni.Status),
+	}
+	if eni.Attachment != nil {
+		n.AttachmentStatus = aws.StringValue(eni.Attachment.Status)
+		n.InstanceID = aws.StringValue(eni.Attachment.InstanceId)
+	}
+	for _, ip := range eni.PrivateIpAddresses {
+		if ip.PrivateIpAddress != nil {
+			n.PrivateIPs = append(n.PrivateIPs, *ip.PrivateIpAddress)
+		}
+	}
+	for _, group := range eni.Groups {
+		if group.GroupId != nil {
+			n.SecurityGroups = append(n.SecurityGroups, *group.GroupId)
+		}
+	}
+	sort.Strings(n.PrivateIPs)
+	sort.Strings(n.SecurityGroups)
+	return n
+}
+
+func networkInterfaceFromV2(eni ec2types.NetworkInterface) networkInterface {
+	n := networkInterface{
+		ID:     awsv2.ToString(eni.NetworkInterfaceId),
+		Status: string(eni.Status),
+	}
+	if eni.Attachment != nil {
+		n.AttachmentStatus = string(eni.Attachment.Status)
+		n.InstanceID = awsv2.ToString(eni.Attachment.InstanceId)
+	}
+	for _, ip := range eni.PrivateIpAddresses {
+		if ip.PrivateIpAddress != nil {
+			n.PrivateIPs = append(n.PrivateIPs, *ip.PrivateIpAddress)
+		}
+	}
+	for _, group := range eni.Groups {
+		if group.GroupId != nil {
+			n.SecurityGroups = append(n.SecurityGroups, *group.GroupId)
+		}
+	}
+	sort.Strings(n.PrivateIPs)
+	sort.Strings(n.SecurityGroups)
+	return n
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// spotPrice is an SDK-neutral view of one spot price history entry. Both
+// SDKs return the price as a string; it is kept as such, and parsed only to
+// check that it is a valid number.
+type spotPrice struct {
+	AvailabilityZone   string
+	ProductDescription string
+	Timestamp          time.Time
+	Price              string
+}
+
+func (p spotPrice) key() string {
+	return p.AvailabilityZone + "|" + p.ProductDescription + "|" + p.Timestamp.UTC().Format(time.RFC3339)
+}
+
+func (p spotPrice) String() string {
+	return fmt.Sprintf("%s %s at %s: $%s", p.AvailabilityZone, p.ProductDescription,
+		p.Timestamp.UTC().Format(time.RFC3339), p.Price)
+}
+
+// This example demonstrates describing spot price history with both SDKs.
+// The query is bounded to the last hour and to Linux/UNIX prices so it stays
+// fast; both SDKs are given the exact same time window.
+func main() {
+	instanceType := flag.String("instance-type", "t3.micro", "instance type to fetch spot prices for")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== EC2 Spot Price History Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	// Compute the window once: letting each SDK call time.Now() separately
+	// would make the two result sets differ at the edges.
+	endTime := time.Now().UTC()
+	startTime := endTime.Add(-time.Hour)
+	productDescription := "Linux/UNIX"
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Printf("Instance type: %s, window: %s to %s\n\n",
+		*instanceType, startTime.Format(time.RFC3339), endTime.Format(time.RFC3339))
+
+	// Use v1 to describe spot price history
+	fmt.Println("1. Using SDK v1 to describe spot price history (all pages)...")
+	pricesV1 := make(map[string]spotPrice)
+	err = ec2ClientV1.DescribeSpotPriceHistoryPages(&ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       aws.StringSlice([]string{*instanceType}),
+		ProductDescriptions: aws.StringSlice([]string{productDescription}),
+		StartTime:           aws.Time(startTime),
+		EndTime:             aws.Time(endTime),
+	}, func(page *ec2.DescribeSpotPriceHistoryOutput, lastPage bool) bool {
+		for _, entry := range page.SpotPriceHistory {
+			p := spotPriceFromV1(entry)
+			pricesV1[p.key()] = p
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe spot price history with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d price points using SDK v1\n", len(pricesV1))
+
+	// Use v2 to describe spot price history
+	fmt.Println("\n2. Using SDK v2 to describe spot price history (all pages)...")
+	pricesV2 := make(map[string]spotPrice)
+	paginator := ec2v2.NewDescribeSpotPriceHistoryPaginator(ec2ClientV2, &ec2v2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []ec2types.InstanceType{ec2types.InstanceType(*instanceType)},
+		ProductDescriptions: []string{productDescription},
+		StartTime:           aws.Time(startTime),
+		EndTime:             aws.Time(endTime),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Fatalf("Failed to describe spot price history with v2: %v", err)
+		}
+		for _, entry := range page.SpotPriceHistory {
+			p := spotPriceFromV2(entry)
+			pricesV2[p.key()] = p
+		}
+	}
+	fmt.Printf("   ✓ Found %d price points using SDK v2\n", len(pricesV2))
+
+	if len(pricesV1) == 0 && len(pricesV2) == 0 {
+		fmt.Printf("\nNo spot price changes for %s in the last hour; both SDKs agree on the empty history.\n", *instanceType)
+		return
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing price points...")
+	keys := make([]string, 0, len(pricesV1))
+	for k := range pricesV1 {
+		keys = append(keys, k)
+	}
+	for k := range pricesV2 {
+		if _, ok := pricesV1[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	mismatches := 0
+	for _, k := range keys {
+		v1, inV1 := pricesV1[k]
+		v2, inV2 := pricesV2[k]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", v2)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", v1)
+			mismatches++
+		case v1.Price != v2.Price:
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", k, v1, v2)
+			mismatches++
+		default:
+			if _, err := strconv.ParseFloat(v1.Price, 64); err != nil {
+				fmt.Printf("   ✗ %s has an unparsable price %q\n", k, v1.Price)
+				mismatches++
+				continue
+			}
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d price points did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d spot price points\n", len(keys))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - SpotPrice is a string in both SDKs; parse it explicitly before doing arithmetic")
+	fmt.Println("  - v1 InstanceTypes is []*string, v2 is []types.InstanceType")
+	fmt.Println("  - v2 ProductDescription in the response is the RIProductDescription enum")
+	fmt.Println("  - StartTime/EndTime are *time.Time in both; pass the same values to compare results")
+}
+
+func spotPriceFromV1(entry *ec2.SpotPrice) spotPrice {
+	return spotPrice{
+		AvailabilityZone:   aws.StringValue(entry.AvailabilityZone),
+		ProductDescription: aws.StringValue(entry.ProductDescription),
+		Timestamp:          aws.TimeValue(entry.Timestamp),
+		Price:              aws.StringValue(entry.SpotPrice),
+	}
+}
+
+func spotPriceFromV2(entry ec2types.SpotPrice) spotPrice {
+	p := spotPrice{
+		ProductDescription: string(entry.ProductDescription),
+	}
+	if entry.AvailabilityZone != nil {
+		p.AvailabilityZone = *entry.AvailabilityZone
+	}
+	if entry.Timestamp != nil {
+		p.Timestamp = *entry.Timestamp
+	}
+	if entry.SpotPrice != nil {
+		p.Price = *entry.SpotPrice
+	}
+	return p
+}
@@ -0,0 +1,277 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// terminateTimeout bounds the wait for the instances to terminate.
+const terminateTimeout = 10 * time.Minute
+
+// liveStates are the instance states worth cleaning up: instances that are
+// shutting down or terminated already need nothing more.
+var liveStates = []string{"pending", "running", "stopping", "stopped"}
+
+// taggedInstance is an SDK-neutral view of an instance found by the filter.
+type taggedInstance struct {
+	ID       string
+	Name     string
+	State    string
+	Type     string
+	Launched time.Time
+}
+
+// instanceFinder lists, terminates and waits for instances with one SDK.
+type instanceFinder struct {
+	find      func(ctx context.Context, filters interop.FilterSet) ([]taggedInstance, error)
+	terminate func(ctx context.Context, ids []string) error
+	wait      func(ctx context.Context, ids []string) error
+}
+
+// This example demonstrates a tag-based EC2 cleanup that works with either
+// SDK. The same interop.FilterSet, tag:app=sdk-migration-test by default,
+// selects the live test instances left behind by other examples, through v1
+// or v2 as chosen with -sdk. The instances are listed first, and only
+// terminated, then waited for, with -force.
+func main() {
+	tag := flag.String("tag", "app=sdk-migration-test", "KEY=VALUE tag selecting the instances to terminate")
+	sdk := flag.String("sdk", "v2", "SDK to find and terminate instances with: v1 or v2")
+	force := flag.Bool("force", false, "actually terminate the instances found")
+	region := flag.String("region", "us-east-1", "region to clean up")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	fmt.Print("=== EC2 Tag-Based Instance Cleanup ===\n\n")
+
+	key, value, ok := strings.Cut(*tag, "=")
+	if !ok || key == "" || value == "" {
+		fmt.Fprintln(os.Stderr, "-tag must have the form KEY=VALUE")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *sdk != "v1" && *sdk != "v2" {
+		fmt.Fprintln(os.Stderr, "-sdk must be v1 or v2")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(*region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	var finder instanceFinder
+	if *sdk == "v1" {
+		finder = instanceFinderV1(ec2.New(sessV1))
+	} else {
+		finder = instanceFinderV2(ec2v2.NewFromConfig(cfgV2))
+	}
+
+	filters := interop.FilterSet{}.Tag(key, value).Add("instance-state-name", liveStates...)
+	fmt.Printf("Region: %s\nSDK: %s\nFilters: %s\n\n", *region, *sdk, filters)
+
+	fmt.Printf("1. Finding instances with SDK %s...\n", *sdk)
+	instances, err := finder.find(ctx, filters)
+	if err != nil {
+		log.Fatalf("Failed to describe instances with %s: %v", *sdk, err)
+	}
+	if len(instances) == 0 {
+		fmt.Printf("   ✓ No live instances are tagged %s\n", *tag)
+		return
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].ID < instances[j].ID })
+	table := interop.NewTablePrinter("ID", "NAME", "STATE", "TYPE", "LAUNCHED")
+	table.Indent = "   "
+	ids := make([]string, len(instances))
+	for i, inst := range instances {
+		ids[i] = inst.ID
+		table.AddRow(inst.ID, inst.Name, inst.State, inst.Type, inst.Launched.Format(time.RFC3339))
+	}
+	if err := table.Write(interop.Output, interop.OutputText); err != nil {
+		log.Printf("Warning: Failed to print table: %v", err)
+	}
+	fmt.Printf("   Found %d instances: %s\n", len(ids), strings.Join(ids, " "))
+
+	if !*force {
+		fmt.Println("\nNothing was terminated. Re-run with -force to terminate these instances.")
+		return
+	}
+	if !interop.ConfirmDestructive(fmt.Sprintf("%d instances tagged %s", len(ids), *tag)) {
+		fmt.Println("\nNothing was terminated.")
+		return
+	}
+
+	fmt.Printf("\n2. Terminating instances with SDK %s...\n", *sdk)
+	if err := finder.terminate(ctx, ids); err != nil {
+		log.Fatalf("Failed to terminate instances with %s: %v", *sdk, err)
+	}
+	fmt.Printf("   ✓ Termination requested for %d instances\n", len(ids))
+
+	fmt.Printf("\n3. Waiting up to %s for the instances to terminate...\n", terminateTimeout)
+	start := time.Now()
+	if err := finder.wait(ctx, ids); err != nil {
+		fmt.Printf("   ✗ Instances did not all terminate: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("   ✓ All %d instances terminated after %s\n", len(ids), time.Since(start).Round(time.Second))
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Terminated %d instances tagged %s with SDK %s\n", len(ids), *tag, *sdk)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 filters are []*ec2.Filter with []*string values, v2 []types.Filter with []string values")
+	fmt.Println("  - v1 waits with WaitUntilInstanceTerminatedWithContext, v2 with an InstanceTerminatedWaiter and a max duration")
+	fmt.Println("  - TerminateInstances accepts at most 1000 IDs per call in both SDKs")
+}
+
+// terminateBatch is the largest number of instance IDs one
+// TerminateInstances call accepts.
+const terminateBatch = 1000
+
+// terminateBatches splits ids into the batches of one TerminateInstances
+// call each, which are sent concurrently.
+func terminateBatches(ids []string) [][]string {
+	return slices.Collect(slices.Chunk(ids, terminateBatch))
+}
+
+func instanceFinderV1(client *ec2.EC2) instanceFinder {
+	return instanceFinder{
+		find: func(ctx context.Context, filters interop.FilterSet) ([]taggedInstance, error) {
+			var out []taggedInstance
+			err := client.DescribeInstancesPagesWithContext(ctx, &ec2.DescribeInstancesInput{
+				Filters: filters.V1(),
+			}, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+				for _, r := range page.Reservations {
+					for _, inst := range r.Instances {
+						ti := taggedInstance{
+							ID:       aws.StringValue(inst.InstanceId),
+							Type:     aws.StringValue(inst.InstanceType),
+							Launched: aws.TimeValue(inst.LaunchTime),
+						}
+						if inst.State != nil {
+							ti.State = aws.StringValue(inst.State.Name)
+						}
+						for _, t := range inst.Tags {
+							if aws.StringValue(t.Key) == "Name" {
+								ti.Name = aws.StringValue(t.Value)
+							}
+						}
+						out = append(out, ti)
+					}
+				}
+				return true
+			})
+			return out, err
+		},
+		terminate: func(ctx context.Context, ids []string) error {
+			errs := interop.ForEachConcurrently(terminateBatches(ids), interop.DefaultWorkers, func(batch []string) error {
+				_, err := client.TerminateInstancesWithContext(ctx, &ec2.TerminateInstancesInput{
+					InstanceIds: aws.StringSlice(batch),
+				})
+				return err
+			})
+			return errors.Join(errs...)
+		},
+		wait: func(ctx context.Context, ids []string) error {
+			ctx, cancel := context.WithTimeout(ctx, terminateTimeout)
+			defer cancel()
+			return client.WaitUntilInstanceTerminatedWithContext(ctx, &ec2.DescribeInstancesInput{
+				InstanceIds: aws.StringSlice(ids),
+			})
+		},
+	}
+}
+
+func instanceFinderV2(client *ec2v2.Client) instanceFinder {
+	return instanceFinder{
+		find: func(ctx context.Context, filters interop.FilterSet) ([]taggedInstance, error) {
+			var out []taggedInstance
+			paginator := ec2v2.NewDescribeInstancesPaginator(client, &ec2v2.DescribeInstancesInput{
+				Filters: filters.V2(),
+			})
+			for paginator.HasMorePages() {
+				page, err := paginator.NextPage(ctx)
+				if err != nil {
+					return nil, err
+				}
+				for _, r := range page.Reservations {
+					for _, inst := range r.Instances {
+						ti := taggedInstance{
+							ID:   aws.StringValue(inst.InstanceId),
+							Type: string(inst.InstanceType),
+						}
+						if inst.LaunchTime != nil {
+							ti.Launched = *inst.LaunchTime
+						}
+						if inst.State != nil {
+							ti.State = string(inst.State.Name)
+						}
+						for _, t := range inst.Tags {
+							if aws.StringValue(t.Key) == "Name" {
+								ti.Name = aws.StringValue(t.Value)
+							}
+						}
+						out = append(out, ti)
+					}
+				}
+			}
+			return out, nil
+		},
+		terminate: func(ctx context.Context, ids []string) error {
+			errs := interop.ForEachConcurrently(terminateBatches(ids), interop.DefaultWorkers, func(batch []string) error {
+				_, err := client.TerminateInstances(ctx, &ec2v2.TerminateInstancesInput{
+					InstanceIds: batch,
+				})
+				return err
+			})
+			return errors.Join(errs...)
+		},
+		wait: func(ctx context.Context, ids []string) error {
+			waiter := ec2v2.NewInstanceTerminatedWaiter(client)
+			return waiter.Wait(ctx, &ec2v2.DescribeInstancesInput{
+				InstanceIds: ids,
+			}, terminateTimeout)
+		},
+	}
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	elbv1 "github.com/aws/aws-sdk-go/service/elb"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	elbv2 "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing"
+	elbtypes "github.com/aws/aws-sdk-go-v2/service/elasticloadbalancing/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// loadBalancer is an SDK-neutral view of a classic load balancer, holding
+// only the fields that are compared between v1 and v2.
+type loadBalancer struct {
+	Name        string
+	DNSName     string
+	Scheme      string
+	Listeners   []string
+	HealthCheck string
+}
+
+func (lb loadBalancer) String() string {
+	return fmt.Sprintf("%s (DNS: %s, Scheme: %s, Listeners: %s, HealthCheck: %s)",
+		lb.Name, lb.DNSName, lb.Scheme, strings.Join(lb.Listeners, ","), lb.HealthCheck)
+}
+
+// formatListener renders a listener as LB-PROTOCOL:PORT->INSTANCE-PROTOCOL:PORT,
+// followed by the certificate for HTTPS and SSL listeners.
+func formatListener(protocol string, port int64, instanceProtocol string, instancePort int64, certificate string) string {
+	s := fmt.Sprintf("%s:%d->%s:%d", protocol, port, instanceProtocol, instancePort)
+	if certificate != "" {
+		s += "[" + certificate + "]"
+	}
+	return s
+}
+
+// formatHealthCheck renders a health check as its target followed by the
+// interval, timeout and thresholds.
+func formatHealthCheck(target string, interval, timeout, healthy, unhealthy int64) string {
+	return fmt.Sprintf("%s every %ds, timeout %ds, healthy %d, unhealthy %d", target, interval, timeout, healthy, unhealthy)
+}
+
+// This example demonstrates describing classic load balancers with both
+// SDKs. Classic ELB is service/elb in v1 but service/elasticloadbalancing in
+// v2, and its ports and health check settings change from *int64 to int32 or
+// *int32, so each SDK's result is flattened into the same shape before the
+// DNS names, listeners and health checks are compared.
+func main() {
+	lbName := flag.String("lb-name", "", "only describe the classic load balancer with this name")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== Classic ELB Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	elbClientV1 := elbv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	elbClientV2 := elbv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	var names []string
+	if *lbName != "" {
+		names = []string{*lbName}
+		fmt.Printf("Load balancer: %s\n\n", *lbName)
+	}
+
+	// Use v1 to list load balancers
+	fmt.Println("1. Using SDK v1 to list classic load balancers (all pages)...")
+	lbsV1 := make(map[string]loadBalancer)
+	err = elbClientV1.DescribeLoadBalancersPagesWithContext(ctx, &elbv1.DescribeLoadBalancersInput{
+		LoadBalancerNames: aws.StringSlice(names),
+	}, func(page *elbv1.DescribeLoadBalancersOutput, lastPage bool) bool {
+		for _, desc := range page.LoadBalancerDescriptions {
+			lb := loadBalancerFromV1(desc)
+			lbsV1[lb.Name] = lb
+		}
+		return true
+	})
+	if interop.IsNotFound(err) {
+		fmt.Printf("   ✗ No classic load balancer named %s in %s\n", *lbName, region)
+		os.Exit(1)
+	}
+	if err != nil {
+		log.Fatalf("Failed to describe load balancers with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d classic load balancers using SDK v1\n", len(lbsV1))
+
+	// Use v2 to list load balancers
+	fmt.Println("\n2. Using SDK v2 to list classic load balancers (all pages)...")
+	lbsV2 := make(map[string]loadBalancer)
+	paginator := elbv2.NewDescribeLoadBalancersPaginator(elbClientV2, &elbv2.DescribeLoadBalancersInput{
+		LoadBalancerNames: names,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Fatalf("Failed to describe load balancers with v2: %v", err)
+		}
+		for _, desc := range page.LoadBalancerDescriptions {
+			lb := loadBalancerFromV2(desc)
+			lbsV2[lb.Name] = lb
+		}
+	}
+	fmt.Printf("   ✓ Found %d classic load balancers using SDK v2\n", len(lbsV2))
+
+	if len(lbsV1) == 0 && len(lbsV2) == 0 {
+		fmt.Printf("\nNo classic load balancers exist in %s, so there is nothing to compare.\n", region)
+		return
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing load balancers...")
+	all := make([]string, 0, len(lbsV1))
+	for name := range lbsV1 {
+		all = append(all, name)
+	}
+	for name := range lbsV2 {
+		if _, ok := lbsV1[name]; !ok {
+			all = append(all, name)
+		}
+	}
+	sort.Strings(all)
+
+	mismatches := 0
+	for _, name := range all {
+		v1, inV1 := lbsV1[name]
+		v2, inV2 := lbsV2[name]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", name)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", name)
+			mismatches++
+		case v1.String() != v2.String():
+			fmt.Printf("   ✗ %s differs\n       v1: %s\n       v2: %s\n", name, v1, v2)
+			mismatches++
+		default:
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d load balancers did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d classic load balancers\n", len(all))
+	fmt.Println("✓ DNS names, listeners and health checks agree")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Classic ELB is service/elb in v1 and service/elasticloadbalancing in v2")
+	fmt.Println("  - v2 Listener.LoadBalancerPort is an int32 value, InstancePort an *int32; v1 uses *int64 for both")
+	fmt.Println("  - v2 HealthCheck intervals, timeouts and thresholds are *int32 instead of *int64")
+}
+
+func loadBalancerFromV1(desc *elbv1.LoadBalancerDescription) loadBalancer {
+	lb := loadBalancer{
+		Name:    aws.StringValue(desc.LoadBalancerName),
+		DNSName: aws.StringValue(desc.DNSName),
+		Scheme:  aws.StringValue(desc.Scheme),
+	}
+	for _, ld := range desc.ListenerDescriptions {
+		if l := ld.Listener; l != nil {
+			lb.Listeners = append(lb.Listeners, formatListener(
+				aws.StringValue(l.Protocol), aws.Int64Value(l.LoadBalancerPort),
+				aws.StringValue(l.InstanceProtocol), aws.Int64Value(l.InstancePort),
+				aws.StringValue(l.SSLCertificateId)))
+		}
+	}
+	sort.Strings(lb.Listeners)
+	if hc := desc.HealthCheck; hc != nil {
+		lb.HealthCheck = formatHealthCheck(aws.StringValue(hc.Target),
+			aws.Int64Value(hc.Interval), aws.Int64Value(hc.Timeout),
+			aws.Int64Value(hc.HealthyThreshold), aws.Int64Value(hc.UnhealthyThreshold))
+	}
+	return lb
+}
+
+func loadBalancerFromV2(desc elbtypes.LoadBalancerDescription) loadBalancer {
+	var lb loadBalancer
+	if desc.LoadBalancerName != nil {
+		lb.Name = *desc.LoadBalancerName
+	}
+	if desc.DNSName != nil {
+		lb.DNSName = *desc.DNSName
+	}
+	if desc.Scheme != nil {
+		lb.Scheme = *desc.Scheme
+	}
+	for _, ld := range desc.ListenerDescriptions {
+		if l := ld.Listener; l != nil {
+			lb.Listeners = append(lb.Listeners, formatListener(
+				aws.StringValue(l.Protocol), int64(l.LoadBalancerPort),
+				aws.StringValue(l.InstanceProtocol), int64Value(l.InstancePort),
+				aws.StringValue(l.SSLCertificateId)))
+		}
+	}
+	sort.Strings(lb.Listeners)
+	if hc := desc.HealthCheck; hc != nil {
+		lb.HealthCheck = formatHealthCheck(aws.StringValue(hc.Target),
+			int64Value(hc.Interval), int64Value(hc.Timeout),
+			int64Value(hc.HealthyThreshold), int64Value(hc.UnhealthyThreshold))
+	}
+	return lb
+}
+
+// int64Value widens a v2 *int32 to compare it with v1's *int64 values.
+func int64Value(v *int32) int64 {
+	if v == nil {
+		return 0
+	}
+	return int64(*v)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// errEndpointCaptured stops a v2 request once its endpoint is known, so the
+// example never needs to send anything or hold credentials.
+var errEndpointCaptured = errors.New("endpoint captured")
+
+// This example demonstrates reusing a v1 endpoint resolver with SDK v2.
+// A FIPS override for S3 is expressed once, applied to both SDKs, and the
+// host each SDK resolves is compared.
+func main() {
+	fmt.Print("=== Endpoint Resolver Bridge Test ===\n\n")
+
+	region := "us-east-1"
+	expectedHost := "s3-fips.us-east-1.amazonaws.com"
+	ctx := context.Background()
+
+	// A custom v1 resolver that forces the FIPS endpoint for S3 only.
+	fipsResolver := endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		if service == endpoints.S3ServiceID {
+			opts = append(opts, func(o *endpoints.Options) {
+				o.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+			})
+		}
+		return endpoints.DefaultResolver().EndpointFor(service, region, opts...)
+	})
+
+	// The same override expressed as a static endpoint map.
+	static := interop.StaticEndpoints{
+		endpoints.S3ServiceID: "https://" + expectedHost,
+	}
+
+	failures := 0
+
+	fmt.Println("1. Bridging a custom v1 endpoints.Resolver to SDK v2...")
+	failures += compareHosts(ctx, region, expectedHost,
+		fipsResolver, config.WithEndpointResolverWithOptions(interop.EndpointResolverV2FromV1(fipsResolver)))
+
+	fmt.Println("\n2. Translating a static endpoint map for both SDKs...")
+	failures += compareHosts(ctx, region, expectedHost,
+		static.V1(nil), config.WithEndpointResolverWithOptions(static.V2()))
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d endpoint comparisons failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ A v1 endpoint override can be reused as-is with SDK v2")
+	fmt.Println("✓ Both SDKs resolve the same FIPS host for S3")
+	fmt.Println("\nKnown limitations of the translation:")
+	fmt.Println("  - v2 service IDs (\"CloudWatch Logs\") must be mapped to v1 endpoints IDs (\"logs\")")
+	fmt.Println("  - Per-call v2 resolver options are ignored; FIPS/dual-stack must be set on the v1 side")
+	fmt.Println("  - Bridged endpoints use v2's legacy resolution path, bypassing EndpointResolverV2 rules")
+}
+
+// compareHosts builds an S3 client for each SDK with the given resolvers and
+// reports whether both resolve expectedHost. It returns the number of SDKs
+// that did not.
+func compareHosts(ctx context.Context, region, expectedHost string, resolverV1 endpoints.Resolver, resolverV2 func(*config.LoadOptions) error) int {
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:           aws.String(region),
+		EndpointResolver: resolverV1,
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	s3ClientV1 := s3v1.New(sessV1)
+	hostV1 := strings.TrimPrefix(s3ClientV1.Endpoint, "https://")
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region), resolverV2)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	var hostV2 string
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2, func(o *s3v2.Options) {
+		o.APIOptions = append(o.APIOptions, captureHost(&hostV2))
+	})
+	_, err = s3ClientV2.ListBuckets(ctx, &s3v2.ListBucketsInput{})
+	if err != nil && !errors.Is(err, errEndpointCaptured) {
+		log.Printf("   ✗ Failed to resolve endpoint with v2: %v", err)
+		return 1
+	}
+
+	failures := 0
+	for _, r := range []struct{ sdk, host string }{{"v1", hostV1}, {"v2", hostV2}} {
+		if r.host == expectedHost {
+			fmt.Printf("   ✓ SDK %s resolved %s\n", r.sdk, r.host)
+		} else {
+			fmt.Printf("   ✗ SDK %s resolved %s, expected %s\n", r.sdk, r.host, expectedHost)
+			failures++
+		}
+	}
+	return failures
+}
+
+// captureHost records the host a v2 request is about to be sent to, then
+// aborts the request before it is signed.
+func captureHost(host *string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("CaptureHost",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					*host = req.URL.Host
+				}
+				return middleware.FinalizeOutput{}, middleware.Metadata{}, errEndpointCaptured
+			}), middleware.Before)
+	}
+}
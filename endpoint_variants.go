@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+	sqsv1 "github.com/aws/aws-sdk-go/service/sqs"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+	stsv2 "github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// errNotSent stops a v2 request at the transport, once its endpoint has been
+// resolved and recorded.
+var errNotSent = errors.New("request not sent: endpoint recorded")
+
+// exampleCredentials sign v2 requests that are never sent, so that no real
+// credentials are needed.
+var exampleCredentials = credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")
+
+// hostRecorder is a v2 HTTP client that records the host a request would be
+// sent to instead of sending it.
+type hostRecorder struct {
+	host string
+}
+
+func (r *hostRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.host = req.URL.Host
+	return nil, errNotSent
+}
+
+// hostV1 builds req without sending it and returns the host it is addressed
+// to. S3 customizations such as virtual-hosted addressing run during Build.
+func hostV1(req *request.Request) (string, error) {
+	if err := req.Build(); err != nil {
+		return "", err
+	}
+	return req.HTTPRequest.URL.Host, nil
+}
+
+// hostV2 runs call with a hostRecorder as the HTTP client and returns the
+// recorded host, or the error that stopped the request before it reached the
+// transport. Requests are signed with exampleCredentials and never retried.
+func hostV2(call func(rec *hostRecorder) error) (string, error) {
+	rec := &hostRecorder{}
+	err := call(rec)
+	if rec.host != "" {
+		return rec.host, nil
+	}
+	return "", err
+}
+
+// endpointProbe resolves the endpoint of one cheap read-only operation of a
+// service with each SDK.
+type endpointProbe struct {
+	service string
+	v1      func(sess *session.Session) (string, error)
+	v2      func(ctx context.Context, cfg awsv2.Config) (string, error)
+}
+
+var endpointProbes = []endpointProbe{
+	{
+		service: "s3",
+		v1: func(sess *session.Session) (string, error) {
+			req, _ := s3v1.New(sess).ListBucketsRequest(&s3v1.ListBucketsInput{})
+			return hostV1(req)
+		},
+		v2: func(ctx context.Context, cfg awsv2.Config) (string, error) {
+			return hostV2(func(rec *hostRecorder) error {
+				_, err := s3v2.NewFromConfig(cfg).ListBuckets(ctx, &s3v2.ListBucketsInput{}, func(o *s3v2.Options) {
+					o.HTTPClient, o.Credentials, o.RetryMaxAttempts = rec, exampleCredentials, 1
+				})
+				return err
+			})
+		},
+	},
+	{
+		service: "ec2",
+		v1: func(sess *session.Session) (string, error) {
+			req, _ := ec2v1.New(sess).DescribeRegionsRequest(&ec2v1.DescribeRegionsInput{})
+			return hostV1(req)
+		},
+		v2: func(ctx context.Context, cfg awsv2.Config) (string, error) {
+			return hostV2(func(rec *hostRecorder) error {
+				_, err := ec2v2.NewFromConfig(cfg).DescribeRegions(ctx, &ec2v2.DescribeRegionsInput{}, func(o *ec2v2.Options) {
+					o.HTTPClient, o.Credentials, o.RetryMaxAttempts = rec, exampleCredentials, 1
+				})
+				return err
+			})
+		},
+	},
+	{
+		service: "sts",
+		v1: func(sess *session.Session) (string, error) {
+			req, _ := stsv1.New(sess).GetCallerIdentityRequest(&stsv1.GetCallerIdentityInput{})
+			return hostV1(req)
+		},
+		v2: func(ctx context.Context, cfg awsv2.Config) (string, error) {
+			return hostV2(func(rec *hostRecorder) error {
+				_, err := stsv2.NewFromConfig(cfg).GetCallerIdentity(ctx, &stsv2.GetCallerIdentityInput{}, func(o *stsv2.Options) {
+					o.HTTPClient, o.Credentials, o.RetryMaxAttempts = rec, exampleCredentials, 1
+				})
+				return err
+			})
+		},
+	},
+	{
+		service: "dynamodb",
+		v1: func(sess *session.Session) (string, error) {
+			req, _ := dynamodbv1.New(sess).ListTablesRequest(&dynamodbv1.ListTablesInput{})
+			return hostV1(req)
+		},
+		v2: func(ctx context.Context, cfg awsv2.Config) (string, error) {
+			return hostV2(func(rec *hostRecorder) error {
+				_, err := dynamodbv2.NewFromConfig(cfg).ListTables(ctx, &dynamodbv2.ListTablesInput{}, func(o *dynamodbv2.Options) {
+					o.HTTPClient, o.Credentials, o.RetryMaxAttempts = rec, exampleCredentials, 1
+				})
+				return err
+			})
+		},
+	},
+	{
+		service: "sqs",
+		v1: func(sess *session.Session) (string, error) {
+			req, _ := sqsv1.New(sess).ListQueuesRequest(&sqsv1.ListQueuesInput{})
+			return hostV1(req)
+		},
+		v2: func(ctx context.Context, cfg awsv2.Config) (string, error) {
+			return hostV2(func(rec *hostRecorder) error {
+				_, err := sqsv2.NewFromConfig(cfg).ListQueues(ctx, &sqsv2.ListQueuesInput{}, func(o *sqsv2.Options) {
+					o.HTTPClient, o.Credentials, o.RetryMaxAttempts = rec, exampleCredentials, 1
+				})
+				return err
+			})
+		},
+	},
+}
+
+// This example demonstrates selecting dual-stack and FIPS endpoints in both
+// SDKs. With -dualstack and/or -fips, each service's endpoint is resolved
+// with and without the variant by both SDKs, without sending any request or
+// needing credentials, and the resolved hosts are compared.
+func main() {
+	dualStack := flag.Bool("dualstack", false, "use dual-stack (IPv4 and IPv6) endpoints")
+	fips := flag.Bool("fips", false, "use FIPS 140 validated endpoints")
+	region := flag.String("region", "us-east-1", "region to resolve endpoints in")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	fmt.Print("=== Endpoint Variants Interop Test ===\n\n")
+
+	if !*dualStack && !*fips {
+		fmt.Fprintln(os.Stderr, "at least one of -dualstack and -fips is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	var variants []string
+	if *dualStack {
+		variants = append(variants, "dual-stack")
+	}
+	if *fips {
+		variants = append(variants, "FIPS")
+	}
+	variant := strings.Join(variants, " + ")
+	fmt.Printf("Region: %s\nVariant: %s\n\n", *region, variant)
+
+	defaults, err := interop.NewClients(ctx, *region)
+	if err != nil {
+		log.Fatalf("Failed to create clients: %v", err)
+	}
+	selected, err := interop.NewClients(ctx, *region, interop.WithDualStack(*dualStack), interop.WithFIPS(*fips))
+	if err != nil {
+		log.Fatalf("Failed to create %s clients: %v", variant, err)
+	}
+
+	// wantHost reports whether host looks like the requested variant of def.
+	wantHost := func(host, def string) bool {
+		if host == def {
+			return false
+		}
+		if *fips && !strings.Contains(host, "fips") {
+			return false
+		}
+		if *dualStack && !strings.Contains(host, "dualstack") && !strings.HasSuffix(host, ".api.aws") {
+			return false
+		}
+		return true
+	}
+
+	fmt.Println("1. Resolving endpoints with both SDKs...")
+	table := interop.NewTablePrinter("SERVICE", "DEFAULT", "SDK V1", "SDK V2")
+	table.Indent = "   "
+	table.MaxWidth = -1
+	failures, unsupported := 0, 0
+	var notes []string
+	for _, p := range endpointProbes {
+		def, err := p.v2(ctx, defaults.ConfigV2)
+		if err != nil {
+			log.Fatalf("Failed to resolve the default %s endpoint: %v", p.service, err)
+		}
+		hostV1, errV1 := p.v1(selected.SessionV1)
+		hostV2, errV2 := p.v2(ctx, selected.ConfigV2)
+		cellV1, cellV2 := hostV1, hostV2
+		if errV1 != nil {
+			cellV1 = "(none)"
+		}
+		if errV2 != nil {
+			cellV2 = "(none)"
+		}
+		table.AddRow(p.service, def, cellV1, cellV2)
+
+		switch {
+		case errV1 != nil && errV2 != nil:
+			// Not every service offers every variant in every region.
+			notes = append(notes, fmt.Sprintf("- %s has no %s endpoint in %s\n     v1: %v\n     v2: %v", p.service, variant, *region, errV1, errV2))
+			unsupported++
+		case errV2 != nil:
+			// v1 builds a variant host from the partition's template even
+			// where the service does not offer it; v2's rules refuse.
+			notes = append(notes, fmt.Sprintf("- %s has no %s endpoint in %s: v1 assumed %s, v2 reports\n     %v",
+				p.service, variant, *region, hostV1, errV2))
+			unsupported++
+		case errV1 != nil:
+			notes = append(notes, fmt.Sprintf("✗ %s: only SDK v2 resolved a %s endpoint (%s); v1 reports\n     %v",
+				p.service, variant, hostV2, errV1))
+			failures++
+		case hostV1 != hostV2:
+			notes = append(notes, fmt.Sprintf("✗ %s: the SDKs resolved different hosts (v1 %s, v2 %s)", p.service, hostV1, hostV2))
+			failures++
+		case !wantHost(hostV2, def):
+			notes = append(notes, fmt.Sprintf("✗ %s: %s does not look like a %s variant of %s", p.service, hostV2, variant, def))
+			failures++
+		default:
+			notes = append(notes, fmt.Sprintf("✓ %s: both SDKs resolved %s", p.service, hostV2))
+		}
+	}
+	if err := table.Write(interop.Output, interop.OutputText); err != nil {
+		log.Printf("Warning: Failed to print table: %v", err)
+	}
+
+	fmt.Println("\n2. Comparing results...")
+	for _, note := range notes {
+		fmt.Printf("   %s\n", note)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d of %d services resolved %s endpoints inconsistently\n", failures, len(endpointProbes), variant)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs resolve the same %s endpoints (%d of %d services lack one in %s)\n", variant, unsupported, len(endpointProbes), *region)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 selects variants with aws.Config.UseDualStackEndpoint and UseFIPSEndpoint,")
+	fmt.Println("    v2 with config.WithUseDualStackEndpoint and WithUseFIPSEndpoint")
+	fmt.Println("  - v1 resolves from a bundled endpoints.json, v2 from per-service EndpointResolverV2 rules")
+	fmt.Println("  - Where a service lacks the variant, v1 may still build a host from the partition template, v2 fails")
+	fmt.Println("  - The older v1 aws.Config.UseDualStack only affects S3 and has no v2 equivalent")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// mockClient implements the narrow client interfaces of the Exists
+// functions for both SDKs. Every call records the name it was asked about
+// and returns err or, for DescribeInstances, as many reservations as
+// reservations says.
+type mockClient struct {
+	err          error
+	reservations int
+	asked        string
+}
+
+func (m *mockClient) HeadBucketWithContext(ctx aws.Context, in *s3v1.HeadBucketInput, opts ...request.Option) (*s3v1.HeadBucketOutput, error) {
+	m.asked = aws.StringValue(in.Bucket)
+	return &s3v1.HeadBucketOutput{}, m.err
+}
+
+func (m *mockClient) HeadBucket(ctx context.Context, in *s3v2.HeadBucketInput, opts ...func(*s3v2.Options)) (*s3v2.HeadBucketOutput, error) {
+	m.asked = aws.StringValue(in.Bucket)
+	return &s3v2.HeadBucketOutput{}, m.err
+}
+
+func (m *mockClient) DescribeTableWithContext(ctx aws.Context, in *dynamodbv1.DescribeTableInput, opts ...request.Option) (*dynamodbv1.DescribeTableOutput, error) {
+	m.asked = aws.StringValue(in.TableName)
+	return &dynamodbv1.DescribeTableOutput{}, m.err
+}
+
+func (m *mockClient) DescribeTable(ctx context.Context, in *dynamodbv2.DescribeTableInput, opts ...func(*dynamodbv2.Options)) (*dynamodbv2.DescribeTableOutput, error) {
+	m.asked = aws.StringValue(in.TableName)
+	return &dynamodbv2.DescribeTableOutput{}, m.err
+}
+
+func (m *mockClient) DescribeInstancesWithContext(ctx aws.Context, in *ec2v1.DescribeInstancesInput, opts ...request.Option) (*ec2v1.DescribeInstancesOutput, error) {
+	if len(in.InstanceIds) == 1 {
+		m.asked = aws.StringValue(in.InstanceIds[0])
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ec2v1.DescribeInstancesOutput{Reservations: make([]*ec2v1.Reservation, m.reservations)}, nil
+}
+
+func (m *mockClient) DescribeInstances(ctx context.Context, in *ec2v2.DescribeInstancesInput, opts ...func(*ec2v2.Options)) (*ec2v2.DescribeInstancesOutput, error) {
+	if len(in.InstanceIds) == 1 {
+		m.asked = in.InstanceIds[0]
+	}
+	if m.err != nil {
+		return nil, m.err
+	}
+	return &ec2v2.DescribeInstancesOutput{Reservations: make([]ec2types.Reservation, m.reservations)}, nil
+}
+
+// v1Error returns a v1 service error, as the SDK returns it for a response
+// with status and code.
+func v1Error(status int, code string) error {
+	return awserr.NewRequestFailure(awserr.New(code, code, nil), status, "req")
+}
+
+// v2Error returns a v2 service error, wrapped as the SDK wraps it for a
+// response with status and, when not empty, code.
+func v2Error(operation string, status int, code string) error {
+	var err error = &smithy.GenericAPIError{Code: code, Message: code}
+	if code == "" {
+		// S3 HEAD responses have no body, so no error code.
+		err = errors.New(http.StatusText(status))
+	}
+	return &smithy.OperationError{
+		ServiceID:     "Fake",
+		OperationName: operation,
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{Response: &http.Response{StatusCode: status}},
+				Err:      err,
+			},
+			RequestID: "req",
+		},
+	}
+}
+
+// existsCase is one call of an Exists function and its expected result.
+type existsCase struct {
+	name       string
+	client     *mockClient
+	exists     func(ctx context.Context, m *mockClient, name string) (bool, error)
+	wantExists bool
+	wantErr    bool
+}
+
+// This example demonstrates interop.BucketExistsV1/V2, TableExistsV1/V2
+// and InstanceExistsV1/V2 against mock clients of both SDKs that return
+// canned results. Each function must report an existing resource as
+// (true, nil), a not-found error, by code or by a bare 404 status, as
+// (false, nil), and return any other error, such as access denied, as an
+// error. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Exists Functions Test ===\n\n")
+
+	ctx := context.Background()
+
+	bucketV1 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.BucketExistsV1(ctx, m, name)
+	}
+	bucketV2 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.BucketExistsV2(ctx, m, name)
+	}
+	tableV1 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.TableExistsV1(ctx, m, name)
+	}
+	tableV2 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.TableExistsV2(ctx, m, name)
+	}
+	instanceV1 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.InstanceExistsV1(ctx, m, name)
+	}
+	instanceV2 := func(ctx context.Context, m *mockClient, name string) (bool, error) {
+		return interop.InstanceExistsV2(ctx, m, name)
+	}
+
+	groups := []struct {
+		title string
+		cases []existsCase
+	}{
+		{"BucketExistsV1 and BucketExistsV2", []existsCase{
+			{"v1 existing bucket", &mockClient{}, bucketV1, true, false},
+			{"v2 existing bucket", &mockClient{}, bucketV2, true, false},
+			{"v1 NoSuchBucket", &mockClient{err: v1Error(404, "NoSuchBucket")}, bucketV1, false, false},
+			{"v1 bare 404", &mockClient{err: v1Error(404, "BadRequest")}, bucketV1, false, false},
+			{"v2 bare 404", &mockClient{err: v2Error("HeadBucket", 404, "")}, bucketV2, false, false},
+			{"v1 403 Forbidden", &mockClient{err: v1Error(403, "Forbidden")}, bucketV1, false, true},
+			{"v2 403 without a code", &mockClient{err: v2Error("HeadBucket", 403, "")}, bucketV2, false, true},
+		}},
+		{"TableExistsV1 and TableExistsV2", []existsCase{
+			{"v1 existing table", &mockClient{}, tableV1, true, false},
+			{"v2 existing table", &mockClient{}, tableV2, true, false},
+			{"v1 ResourceNotFoundException", &mockClient{err: v1Error(400, "ResourceNotFoundException")}, tableV1, false, false},
+			{"v2 ResourceNotFoundException", &mockClient{err: v2Error("DescribeTable", 400, "ResourceNotFoundException")}, tableV2, false, false},
+			{"v1 AccessDeniedException", &mockClient{err: v1Error(400, "AccessDeniedException")}, tableV1, false, true},
+			{"v2 ThrottlingException", &mockClient{err: v2Error("DescribeTable", 400, "ThrottlingException")}, tableV2, false, true},
+		}},
+		{"InstanceExistsV1 and InstanceExistsV2", []existsCase{
+			{"v1 existing instance", &mockClient{reservations: 1}, instanceV1, true, false},
+			{"v2 existing instance", &mockClient{reservations: 1}, instanceV2, true, false},
+			{"v1 no reservation", &mockClient{}, instanceV1, false, false},
+			{"v2 no reservation", &mockClient{}, instanceV2, false, false},
+			{"v1 InvalidInstanceID.NotFound", &mockClient{err: v1Error(400, "InvalidInstanceID.NotFound")}, instanceV1, false, false},
+			{"v2 InvalidInstanceID.NotFound", &mockClient{err: v2Error("DescribeInstances", 400, "InvalidInstanceID.NotFound")}, instanceV2, false, false},
+			{"v1 UnauthorizedOperation", &mockClient{err: v1Error(403, "UnauthorizedOperation")}, instanceV1, false, true},
+			{"v2 InvalidInstanceID.Malformed", &mockClient{err: v2Error("DescribeInstances", 400, "InvalidInstanceID.Malformed")}, instanceV2, false, true},
+		}},
+	}
+
+	failures, total := 0, 0
+	for i, g := range groups {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("%d. Checking %s...\n", i+1, g.title)
+		for _, c := range g.cases {
+			total++
+			const name = "sdk-migration-test"
+			exists, err := c.exists(ctx, c.client, name)
+			switch {
+			case c.client.asked != name:
+				fmt.Printf("   ✗ %s: the client was asked about %q, want %q\n", c.name, c.client.asked, name)
+				failures++
+			case exists != c.wantExists || (err != nil) != c.wantErr:
+				fmt.Printf("   ✗ %s: got (%t, %v), want exists=%t error=%t\n", c.name, exists, err, c.wantExists, c.wantErr)
+				failures++
+			case err != nil && !errors.Is(err, c.client.err):
+				fmt.Printf("   ✗ %s: got error %v, want the client's error\n", c.name, err)
+				failures++
+			default:
+				// v1 errors span two lines.
+				fmt.Printf("   ✓ %s: (%t, %v)\n", c.name, exists, strings.ReplaceAll(fmt.Sprint(err), "\n\t", " "))
+			}
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d exists checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ All %d calls reported existence, absence or the client's error as expected\n", total)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 returns an awserr.RequestFailure, v2 an *smithy.OperationError wrapping an *awshttp.ResponseError; IsNotFound reads the code or the 404 status from either")
+	fmt.Println("  - Each SDK has its own functions, taking the narrow interface of its client, so passing a client of the wrong SDK fails to compile instead of at run time")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// namesGoldenFile holds the names generated at namesStart below.
+var namesGoldenFile = filepath.Join("testdata", "generated_names", "names.txt")
+
+// namesStart is the time the fake clock is stopped at.
+var namesStart = time.Date(2026, time.January, 2, 3, 4, 5, 0, time.UTC)
+
+// This example demonstrates interop.DefaultClock, the Clock that
+// GenerateName, GenerateBucketName and RunID read the time from. The real
+// clock is replaced with an interop.FakeClock, so the names the programs
+// give their resources and the run ID come out the same on every run and
+// are compared with a golden file in testdata/generated_names; run with
+// -update after an intended change to rewrite it. Nothing is sent to AWS.
+func main() {
+	update := flag.Bool("update", false, "rewrite the golden file with the current names")
+	interop.ParseFlags()
+
+	fmt.Print("=== Generated Names Test ===\n\n")
+
+	clock := interop.NewFakeClock(namesStart)
+	interop.DefaultClock = clock
+	failures := 0
+
+	fmt.Printf("1. Generating names at %s and comparing with %s...\n", namesStart.Format(time.RFC3339), namesGoldenFile)
+	var b strings.Builder
+	fmt.Fprintf(&b, "run ID: %s\n", interop.RunID())
+	for _, kind := range []string{"test", "range", "Replication"} {
+		fmt.Fprintf(&b, "bucket %s: %s\n", kind, interop.GenerateBucketName(kind))
+	}
+	for _, kind := range []string{"streams", "visibility", "signature"} {
+		fmt.Fprintf(&b, "name %s: %s\n", kind, interop.GenerateName(kind))
+	}
+	got := b.String()
+	switch {
+	case *update:
+		if err := os.MkdirAll(filepath.Dir(namesGoldenFile), 0o755); err != nil {
+			log.Fatalf("Failed to create %s: %v", filepath.Dir(namesGoldenFile), err)
+		}
+		if err := os.WriteFile(namesGoldenFile, []byte(got), 0o644); err != nil {
+			log.Fatalf("Failed to update %s: %v", namesGoldenFile, err)
+		}
+		fmt.Printf("   ✓ Updated %s\n", namesGoldenFile)
+	default:
+		want, err := os.ReadFile(namesGoldenFile)
+		if err != nil {
+			log.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+		}
+		if got != string(want) {
+			fmt.Printf("   ✗ Names differ from %s (run with -update if the change is intended)\n", namesGoldenFile)
+			fmt.Printf("       want:\n%s       got:\n%s", want, got)
+			failures++
+		} else {
+			fmt.Println("   ✓ Matches")
+		}
+	}
+
+	fmt.Println("\n2. Advancing the clock by 90 seconds...")
+	before := interop.GenerateName("test")
+	runID := interop.RunID()
+	clock.Advance(90 * time.Second)
+	after := interop.GenerateName("test")
+	want := fmt.Sprintf("sdk-migration-test-%d", namesStart.Add(90*time.Second).Unix())
+	switch {
+	case after != want:
+		fmt.Printf("   ✗ GenerateName returned %s, want %s\n", after, want)
+		failures++
+	case interop.RunID() != runID:
+		fmt.Printf("   ✗ RunID changed from %s to %s\n", runID, interop.RunID())
+		failures++
+	default:
+		fmt.Printf("   ✓ %s became %s; the run ID stays %s\n", before, after, runID)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d generated name checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Names and the run ID depend only on interop.DefaultClock, so a fake clock makes them reproducible")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None: names are generated before either SDK is involved, and both send the same run ID in the User-Agent")
+	fmt.Println("  - Bucket names must be lowercase in both SDKs, so GenerateBucketName lowercases the kind")
+}
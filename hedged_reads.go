@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// hedgeStats holds the hedged samples taken for one comparer.
+type hedgeStats struct {
+	Name      string
+	Wins      map[string]int
+	Failed    int
+	Latencies []time.Duration
+	// Mismatch is set when the SDKs disagreed, in which case trusting the
+	// faster one is not safe.
+	Mismatch string
+	// Unavailable is set when the service does not exist in the region.
+	Unavailable bool
+}
+
+// medianLatency returns the median of samples, or zero when there are none.
+func medianLatency(samples []time.Duration) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// This example demonstrates hedged reads during a migration: each registered
+// comparer's describe call is issued with SDK v1 and SDK v2 at once, the
+// first successful answer is used, normalized to the comparer's shared form,
+// and the slower call is cancelled (interop.Hedge). This is only safe when
+// both SDKs return the same data, so every comparer is first run once with
+// interop.Compare; the hedged samples then report which SDK won how often.
+// Experimental: every hedged read costs two requests.
+func main() {
+	services := flag.String("services", "", "comma-separated comparers to hedge (default: all registered)")
+	samples := flag.Int("samples", 10, "hedged describe calls per comparer")
+	output := interop.OutputFlag(flag.CommandLine, "`format` of the win table: text or csv", interop.OutputText, interop.OutputCSV)
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	if *samples < 1 || *samples > 100 {
+		fmt.Fprintln(os.Stderr, "-samples must be between 1 and 100")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== Hedged Read Experiment ===\n\n")
+
+	selected := interop.Comparers()
+	if *services != "" {
+		selected = nil
+		for _, name := range strings.Split(*services, ",") {
+			sc, ok := interop.LookupComparer(strings.TrimSpace(name))
+			if !ok {
+				log.Fatalf("Unknown comparer %q", name)
+			}
+			selected = append(selected, sc)
+		}
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+	clients, err := interop.NewClients(ctx, region, interop.WithReadOnly(*readOnly))
+	if err != nil {
+		log.Fatalf("Failed to create clients: %v", err)
+	}
+	interop.PrintCredentialSources(ctx, clients.SessionV1, clients.ConfigV2)
+	fmt.Printf("Samples per comparer: %d\n\n", *samples)
+
+	// Compare once, which also warms up both SDKs' connections so that the
+	// first hedged sample is not decided by a TLS handshake.
+	fmt.Println("1. Checking that both SDKs agree before trusting the faster one...")
+	stats := make([]hedgeStats, 0, len(selected))
+	for _, sc := range selected {
+		s := hedgeStats{Name: sc.Name(), Wins: make(map[string]int)}
+		result := interop.Compare(ctx, clients, sc)
+		switch {
+		case result.Unavailable:
+			s.Unavailable = true
+			fmt.Printf("   - %s: service not available in %s\n", sc.Name(), region)
+		case result.Err != nil:
+			s.Mismatch = result.Err.Error()
+			fmt.Printf("   ✗ %s: %v\n", sc.Name(), result.Err)
+		case !result.Match():
+			s.Mismatch = fmt.Sprintf("%d differences, first %s", len(result.Diffs), result.Diffs[0])
+			fmt.Printf("   ✗ %s: %s\n", sc.Name(), s.Mismatch)
+		default:
+			fmt.Printf("   ✓ %s: normalized results match\n", sc.Name())
+		}
+		stats = append(stats, s)
+	}
+
+	fmt.Println("\n2. Hedging each describe call across both SDKs...")
+	for i, sc := range selected {
+		s := &stats[i]
+		if s.Unavailable {
+			continue
+		}
+		if s.Mismatch != "" {
+			fmt.Printf("   - %s: skipped, the SDKs disagree\n", s.Name)
+			continue
+		}
+		var first any
+		for n := 0; n < *samples; n++ {
+			r := interop.Hedge(ctx, clients, sc)
+			if r.Err != nil {
+				interop.Verbosef("%s sample %d failed: %v", s.Name, n+1, r.Err)
+				s.Failed++
+				continue
+			}
+			s.Wins[r.Winner]++
+			s.Latencies = append(s.Latencies, r.Latency)
+			interop.Verbosef("%s sample %d: %s in %v", s.Name, n+1, r.Winner, r.Latency)
+			// Whichever SDK wins, the caller must see the same data.
+			if first == nil {
+				first = r.Value
+			} else if diffs, err := interop.DiffJSON(first, r.Value); err != nil || len(diffs) > 0 {
+				s.Mismatch = fmt.Sprintf("sample %d, won by %s, differs from sample 1", n+1, r.Winner)
+				break
+			}
+		}
+		if s.Mismatch != "" {
+			fmt.Printf("   ✗ %s: %s\n", s.Name, s.Mismatch)
+			continue
+		}
+		fmt.Printf("   ✓ %s: v1 won %d, v2 won %d, %d failed\n", s.Name, s.Wins["v1"], s.Wins["v2"], s.Failed)
+	}
+
+	fmt.Println("\n3. Winners by comparer...")
+	table := interop.NewTablePrinter("COMPARER", "SAMPLES", "V1 WINS", "V2 WINS", "FAILED", "MEDIAN")
+	table.Indent = "   "
+	failures := 0
+	totalV1, totalV2 := 0, 0
+	for _, s := range stats {
+		if s.Unavailable {
+			table.AddRow(s.Name, "(unavailable)")
+			continue
+		}
+		if s.Mismatch != "" {
+			table.AddRow(s.Name, "(SDKs disagree)")
+			failures++
+			continue
+		}
+		if s.Failed > 0 {
+			failures++
+		}
+		totalV1 += s.Wins["v1"]
+		totalV2 += s.Wins["v2"]
+		table.AddRow(s.Name, strconv.Itoa(*samples), strconv.Itoa(s.Wins["v1"]), strconv.Itoa(s.Wins["v2"]),
+			strconv.Itoa(s.Failed), medianLatency(s.Latencies).Round(100*time.Microsecond).String())
+	}
+	if err := table.Write(interop.Output, *output); err != nil {
+		log.Printf("Warning: Failed to print table: %v", err)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d of %d comparers disagreed or had failed samples\n", failures, len(stats))
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Hedged reads returned the same data whichever SDK won (v1 %d, v2 %d)\n", totalV1, totalV2)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 needs the *WithContext variants (and paginators' WithContext forms) for cancellation to reach the request")
+	fmt.Println("  - v2 takes a context on every call, so cancelling the loser is always possible")
+	fmt.Println("  - A cancelled v1 call fails with request.CanceledErrorCode, a v2 call with an error wrapping context.Canceled")
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// Service packages of both SDKs. Operations are methods of their clients,
+// plus v2's paginator and waiter constructors.
+const (
+	servicePathV1 = "github.com/aws/aws-sdk-go/service/"
+	servicePathV2 = "github.com/aws/aws-sdk-go-v2/service/"
+)
+
+// modulePath is the path of this module. The bodies of functions in its
+// packages, such as interop, are followed to find the operations that
+// helpers call on an example's behalf.
+const modulePath = "github.com/sdminonne/aws-sdk-migration-tests"
+
+// waiterOperations maps waiter names, as in v1 WaitUntilTableExists and v2
+// NewTableExistsWaiter, to the operation they poll.
+var waiterOperations = map[string]string{
+	"InstanceExists":     "DescribeInstances",
+	"InstanceRunning":    "DescribeInstances",
+	"InstanceStopped":    "DescribeInstances",
+	"InstanceTerminated": "DescribeInstances",
+	"InstanceStatusOk":   "DescribeInstanceStatus",
+	"BucketExists":       "HeadBucket",
+	"BucketNotExists":    "HeadBucket",
+	"ObjectExists":       "HeadObject",
+	"ObjectNotExists":    "HeadObject",
+	"TableExists":        "DescribeTable",
+	"TableNotExists":     "DescribeTable",
+	"CommandExecuted":    "GetCommandInvocation",
+}
+
+// operationUse is one reference to an SDK operation.
+type operationUse struct {
+	Action   string
+	Call     string
+	Location string
+}
+
+// funcDecl is a function of a helper package, with the type information
+// its body was checked with.
+type funcDecl struct {
+	decl *ast.FuncDecl
+	info *types.Info
+	recv types.Type
+}
+
+// finder walks function bodies for references to SDK operations, following
+// calls into the helper packages.
+type finder struct {
+	fset    *token.FileSet
+	decls   map[string]funcDecl
+	visited map[string]bool
+	uses    []operationUse
+	unknown map[string]bool
+}
+
+// This command prints the minimal IAM policy an example needs. It loads the
+// example's source, finds every operation it calls with either SDK,
+// directly or through the interop package, and maps each one to its IAM
+// action with interop.IAMAction, the mapping interop.AccessDeniedAction also
+// uses. The policy JSON goes to stdout and can be pasted into IAM as is:
+//
+//	./iam_policy ec2_key_pairs > policy.json
+func main() {
+	explain := flag.Bool("explain", false, "also print on stderr which call needs each action")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: iam_policy [-explain] EXAMPLE")
+		fmt.Fprintln(os.Stderr, "EXAMPLE is an example's name or source file, such as s3_cors or s3_cors.go.")
+		flag.PrintDefaults()
+	}
+	interop.ParseFlags()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	src := flag.Arg(0)
+	if !strings.HasSuffix(src, ".go") {
+		src += ".go"
+	}
+	if _, err := os.Stat(src); err != nil {
+		fmt.Fprintf(os.Stderr, "No example source %s: %v\n", src, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps |
+			packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo,
+		Fset:      token.NewFileSet(),
+		ParseFile: parseFile,
+	}
+	example, err := packages.Load(cfg, src)
+	if err != nil {
+		log.Fatalf("Failed to load %s: %v", src, err)
+	}
+	var helpers []*packages.Package
+	packages.Visit(example, nil, func(pkg *packages.Package) {
+		if strings.HasPrefix(pkg.PkgPath, modulePath+"/") {
+			helpers = append(helpers, pkg)
+		}
+	})
+	// Dependencies report errors about the bodies parseFile dropped; only
+	// this module's packages must type check.
+	failed := false
+	for _, pkg := range append(example, helpers...) {
+		for _, e := range pkg.Errors {
+			fmt.Fprintln(os.Stderr, e)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+
+	f := &finder{
+		fset:    cfg.Fset,
+		decls:   make(map[string]funcDecl),
+		visited: make(map[string]bool),
+		unknown: make(map[string]bool),
+	}
+	for _, pkg := range helpers {
+		f.index(pkg)
+	}
+	for _, pkg := range example {
+		for _, file := range pkg.Syntax {
+			f.walk(file, pkg.TypesInfo)
+		}
+	}
+
+	for name := range f.unknown {
+		log.Printf("Warning: %s polls an operation this command does not know; add it to waiterOperations", name)
+	}
+	if len(f.uses) == 0 {
+		fmt.Fprintf(os.Stderr, "%s calls no AWS operations and needs no IAM permissions\n", src)
+		return
+	}
+
+	actions := make([]string, len(f.uses))
+	for i, u := range f.uses {
+		actions[i] = u.Action
+	}
+	policy, err := interop.NewIAMPolicy(actions).JSON()
+	if err != nil {
+		log.Fatalf("Failed to encode policy: %v", err)
+	}
+	fmt.Println(string(policy))
+
+	if *explain {
+		sort.Slice(f.uses, func(i, j int) bool {
+			if f.uses[i].Action != f.uses[j].Action {
+				return f.uses[i].Action < f.uses[j].Action
+			}
+			return f.uses[i].Location < f.uses[j].Location
+		})
+		table := interop.NewTablePrinter("ACTION", "CALL", "LOCATION")
+		for _, u := range f.uses {
+			table.AddRow(u.Action, u.Call, u.Location)
+		}
+		if err := table.Write(os.Stderr, interop.OutputText); err != nil {
+			log.Printf("Warning: Failed to print table: %v", err)
+		}
+	}
+}
+
+// index records pkg and the functions and methods declared in it.
+func (f *finder) index(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, d := range file.Decls {
+			decl, ok := d.(*ast.FuncDecl)
+			if !ok || decl.Body == nil {
+				continue
+			}
+			fn, ok := pkg.TypesInfo.Defs[decl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+			fd := funcDecl{decl: decl, info: pkg.TypesInfo}
+			if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+				fd.recv = recv.Type()
+			}
+			f.decls[fn.FullName()] = fd
+		}
+	}
+}
+
+// walk records the SDK operations node refers to, and walks the helper
+// functions it refers to that have not been walked yet.
+func (f *finder) walk(node ast.Node, info *types.Info) {
+	ast.Inspect(node, func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		fn, ok := info.Uses[id].(*types.Func)
+		if !ok {
+			return true
+		}
+		fn = fn.Origin()
+		if service, op, ok := f.operation(fn); ok {
+			f.uses = append(f.uses, operationUse{
+				Action:   interop.IAMAction(service, op),
+				Call:     fn.Pkg().Name() + "." + fn.Name(),
+				Location: f.location(id),
+			})
+			return true
+		}
+		f.follow(fn)
+		return true
+	})
+}
+
+// follow walks the body of fn if it is a helper function. For a method of
+// an interface, every helper method that may implement it is walked.
+func (f *finder) follow(fn *types.Func) {
+	sig := fn.Type().(*types.Signature)
+	if sig.Recv() != nil && types.IsInterface(sig.Recv().Type()) {
+		iface, ok := sig.Recv().Type().Underlying().(*types.Interface)
+		if !ok {
+			return
+		}
+		for name, fd := range f.decls {
+			if fd.recv != nil && fd.decl.Name.Name == fn.Name() && implements(fd.recv, iface) {
+				f.walkDecl(name, fd)
+			}
+		}
+		return
+	}
+	if fd, ok := f.decls[fn.FullName()]; ok {
+		f.walkDecl(fn.FullName(), fd)
+	}
+}
+
+func (f *finder) walkDecl(name string, fd funcDecl) {
+	if f.visited[name] {
+		return
+	}
+	f.visited[name] = true
+	f.walk(fd.decl.Body, fd.info)
+}
+
+// parseFile parses the files of this module in full, but drops the function
+// bodies of every other package, such as the SDKs: their declarations are all
+// that type checking the module needs, and loading is several times faster.
+func parseFile(fset *token.FileSet, filename string, src []byte) (*ast.File, error) {
+	if wd, err := os.Getwd(); err == nil && strings.HasPrefix(filename, wd+string(filepath.Separator)) {
+		return parser.ParseFile(fset, filename, src, parser.AllErrors|parser.ParseComments)
+	}
+	file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range file.Decls {
+		if decl, ok := d.(*ast.FuncDecl); ok {
+			decl.Body = nil
+		}
+	}
+	return file, nil
+}
+
+// implements reports whether recv, or a pointer to it, implements iface.
+func implements(recv types.Type, iface *types.Interface) bool {
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	return types.Implements(recv, iface) || types.Implements(types.NewPointer(recv), iface)
+}
+
+// operation reports the service and operation fn calls, if it is an SDK
+// client method, a v2 paginator constructor or a waiter of either SDK.
+func (f *finder) operation(fn *types.Func) (service, op string, ok bool) {
+	if fn.Pkg() == nil {
+		return "", "", false
+	}
+	path := fn.Pkg().Path()
+	var v1 bool
+	switch {
+	case strings.HasPrefix(path, servicePathV1):
+		service, v1 = strings.TrimPrefix(path, servicePathV1), true
+	case strings.HasPrefix(path, servicePathV2):
+		service = strings.TrimPrefix(path, servicePathV2)
+	default:
+		return "", "", false
+	}
+	if strings.Contains(service, "/") {
+		// Types and feature packages, such as s3/s3manager and ec2/types.
+		return "", "", false
+	}
+
+	name := fn.Name()
+	isMethod := fn.Type().(*types.Signature).Recv() != nil
+	switch {
+	case v1 && isMethod && strings.HasPrefix(name, "WaitUntil"):
+		op, ok = f.waiter(service, strings.TrimSuffix(strings.TrimPrefix(name, "WaitUntil"), "WithContext"))
+	case v1 && isMethod:
+		op = name
+		for _, suffix := range []string{"PagesWithContext", "WithContext", "Pages", "Request"} {
+			if trimmed, found := strings.CutSuffix(op, suffix); found {
+				op = trimmed
+				break
+			}
+		}
+		ok = true
+	case isMethod:
+		op, ok = name, true
+	case strings.HasPrefix(name, "New") && strings.HasSuffix(name, "Paginator"):
+		op, ok = strings.TrimSuffix(strings.TrimPrefix(name, "New"), "Paginator"), true
+	case strings.HasPrefix(name, "New") && strings.HasSuffix(name, "Waiter"):
+		op, ok = f.waiter(service, strings.TrimSuffix(strings.TrimPrefix(name, "New"), "Waiter"))
+	}
+	// Operations have an input type of the same name; this rules out
+	// client methods such as v2's Options.
+	if !ok || fn.Pkg().Scope().Lookup(op+"Input") == nil {
+		return "", "", false
+	}
+	return service, op, true
+}
+
+func (f *finder) waiter(service, name string) (string, bool) {
+	op, ok := waiterOperations[name]
+	if !ok {
+		f.unknown[service+" waiter "+name] = true
+	}
+	return op, ok
+}
+
+// location returns where id appears, relative to the working directory.
+func (f *finder) location(id *ast.Ident) string {
+	pos := f.fset.Position(id.Pos())
+	name := pos.Filename
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, name); err == nil {
+			name = rel
+		}
+	}
+	return fmt.Sprintf("%s:%d", name, pos.Line)
+}
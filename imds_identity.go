@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"syscall"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/ec2metadata"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// instanceIdentity is an SDK-neutral view of the fields of the instance
+// identity document that both SDKs are compared on.
+type instanceIdentity struct {
+	InstanceID       string
+	Region           string
+	AvailabilityZone string
+	AccountID        string
+	InstanceType     string
+	ImageID          string
+}
+
+func (d instanceIdentity) String() string {
+	return fmt.Sprintf("instance=%s region=%s az=%s account=%s type=%s image=%s",
+		d.InstanceID, d.Region, d.AvailabilityZone, d.AccountID, d.InstanceType, d.ImageID)
+}
+
+// This example demonstrates reading the instance metadata service (IMDS)
+// with both SDKs: v1 aws/ec2metadata and v2 feature/ec2/imds. It must run on
+// an EC2 instance; elsewhere IMDS does not answer and the example exits
+// without failing. With -imdsv2-only neither SDK may fall back to IMDSv1
+// when the session token cannot be fetched.
+func main() {
+	timeout := flag.Duration("timeout", 2*time.Second, "how long to wait for IMDS before deciding this is not an EC2 instance")
+	imdsv2Only := flag.Bool("imdsv2-only", false, "fail instead of falling back to IMDSv1 when no session token can be fetched")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== IMDS Instance Identity Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+
+	cfgV1 := &aws.Config{
+		Region: aws.String(region),
+	}
+	if *imdsv2Only {
+		cfgV1.EC2MetadataEnableFallback = aws.Bool(false)
+	}
+	sessV1, err := session.NewSession(cfgV1)
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	imdsClientV1 := ec2metadata.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	imdsClientV2 := imds.NewFromConfig(cfgV2, func(o *imds.Options) {
+		if *imdsv2Only {
+			o.EnableFallback = awsv2.FalseTernary
+		}
+	})
+
+	// Off EC2 the link-local address 169.254.169.254 usually does not answer
+	// at all, so the probe is bounded by -timeout rather than by the SDK's
+	// own retries.
+	fmt.Println("1. Probing IMDS with SDK v2...")
+	probeCtx, cancel := context.WithTimeout(ctx, *timeout)
+	_, err = imdsClientV2.GetMetadata(probeCtx, &imds.GetMetadataInput{Path: "instance-id"})
+	cancel()
+	if err != nil {
+		if imdsUnreachable(err) {
+			fmt.Printf("   IMDS did not answer within %s: this does not look like an EC2 instance.\n", *timeout)
+			fmt.Println("   Run this example on EC2 to compare the SDKs; nothing was tested.")
+			return
+		}
+		log.Fatalf("Failed to reach IMDS with v2: %v", err)
+	}
+	fmt.Println("   ✓ IMDS answered")
+
+	// Use v1 to read the identity document and region
+	fmt.Println("\n2. Using SDK v1 to read the instance identity document...")
+	docV1, err := imdsClientV1.GetInstanceIdentityDocumentWithContext(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get instance identity document with v1: %v", err)
+	}
+	identityV1 := instanceIdentity{
+		InstanceID:       docV1.InstanceID,
+		Region:           docV1.Region,
+		AvailabilityZone: docV1.AvailabilityZone,
+		AccountID:        docV1.AccountID,
+		InstanceType:     docV1.InstanceType,
+		ImageID:          docV1.ImageID,
+	}
+	regionV1, err := imdsClientV1.RegionWithContext(ctx)
+	if err != nil {
+		log.Fatalf("Failed to get region with v1: %v", err)
+	}
+	fmt.Printf("   ✓ %s\n", identityV1)
+	fmt.Printf("   ✓ Region(): %s\n", regionV1)
+
+	// Use v2 to read the identity document and region
+	fmt.Println("\n3. Using SDK v2 to read the instance identity document...")
+	docV2, err := imdsClientV2.GetInstanceIdentityDocument(ctx, &imds.GetInstanceIdentityDocumentInput{})
+	if err != nil {
+		log.Fatalf("Failed to get instance identity document with v2: %v", err)
+	}
+	identityV2 := instanceIdentity{
+		InstanceID:       docV2.InstanceID,
+		Region:           docV2.Region,
+		AvailabilityZone: docV2.AvailabilityZone,
+		AccountID:        docV2.AccountID,
+		InstanceType:     docV2.InstanceType,
+		ImageID:          docV2.ImageID,
+	}
+	regionV2, err := imdsClientV2.GetRegion(ctx, &imds.GetRegionInput{})
+	if err != nil {
+		log.Fatalf("Failed to get region with v2: %v", err)
+	}
+	fmt.Printf("   ✓ %s\n", identityV2)
+	fmt.Printf("   ✓ GetRegion(): %s\n", regionV2.Region)
+
+	// Compare
+	fmt.Println("\n4. Comparing results...")
+	mismatches := 0
+	compare := func(field, v1, v2 string) {
+		if v1 != v2 {
+			fmt.Printf("   ✗ %s differs: v1 %q, v2 %q\n", field, v1, v2)
+			mismatches++
+			return
+		}
+		fmt.Printf("   ✓ %s matches: %s\n", field, v1)
+	}
+	compare("Instance ID", identityV1.InstanceID, identityV2.InstanceID)
+	compare("Region", identityV1.Region, identityV2.Region)
+	compare("Region helper", regionV1, regionV2.Region)
+	if identityV1 != identityV2 {
+		fmt.Printf("   ✗ Identity documents differ\n     v1: %s\n     v2: %s\n", identityV1, identityV2)
+		mismatches++
+	} else {
+		fmt.Println("   ✓ Identity documents match")
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d IMDS checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs read the same instance identity and region from IMDS")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 ec2metadata hangs off a session, v2 imds is its own client built from aws.Config")
+	fmt.Println("  - Both fetch an IMDSv2 session token first and fall back to IMDSv1 unless told not to:")
+	fmt.Println("    v1 with aws.Config.EC2MetadataEnableFallback, v2 with imds.Options.EnableFallback")
+	fmt.Println("  - v1 Region() returns a string, v2 GetRegion returns an output struct")
+	fmt.Println("  - v1 caps IMDS calls at a 1 second HTTP timeout by default, v2 relies on the caller's context")
+}
+
+// imdsUnreachable reports whether err means IMDS did not answer at all, as
+// opposed to answering with an error.
+func imdsUnreachable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EHOSTUNREACH) ||
+		errors.Is(err, syscall.ENETUNREACH) ||
+		(errors.As(err, &netErr) && netErr.Timeout())
+}
@@ -0,0 +1,68 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrBudgetExhausted is wrapped by the error Budget.Step returns when no time
+// is left for the step.
+var ErrBudgetExhausted = errors.New("time budget exhausted")
+
+// Budget splits one total timeout across a planned number of sequential
+// steps. Each step gets an equal share of the time that is left, so time a
+// fast step does not use carries over to the steps after it, while a slow
+// step is canceled at the end of its share instead of starving them. A nil
+// *Budget imposes no deadline.
+type Budget struct {
+	total    time.Duration
+	deadline time.Time
+	steps    int
+}
+
+// NewBudget returns a budget of total for steps steps, starting now.
+func NewBudget(total time.Duration, steps int) *Budget {
+	return &Budget{total: total, deadline: time.Now().Add(total), steps: steps}
+}
+
+// Step returns a child of ctx whose deadline is the next step's share, and
+// its cancel function. name identifies the step in messages; if the step
+// overruns its share, context.Cause of the returned context says so. Steps
+// beyond the planned number get whatever time is left.
+//
+// When the budget is exhausted, Step returns an error wrapping
+// ErrBudgetExhausted and no context.
+func (b *Budget) Step(ctx context.Context, name string) (context.Context, context.CancelFunc, error) {
+	if b == nil {
+		ctx, cancel := context.WithCancel(ctx)
+		return ctx, cancel, nil
+	}
+	left := max(b.steps, 1)
+	b.steps = max(b.steps-1, 0)
+
+	remaining := time.Until(b.deadline)
+	if remaining <= 0 {
+		return nil, nil, fmt.Errorf("%w: no time left for step %q of the %s budget", ErrBudgetExhausted, name, b.total)
+	}
+	share := remaining / time.Duration(left)
+	Verbosef("Budget: %s left for %d steps, %s for step %q", remaining.Round(time.Millisecond), left, share.Round(time.Millisecond), name)
+
+	cause := fmt.Errorf("step %q exceeded its %s share of the %s budget", name, share.Round(time.Millisecond), b.total)
+	ctx, cancel := context.WithDeadlineCause(ctx, time.Now().Add(share), cause)
+	return ctx, cancel, nil
+}
+
+// StepError adds to err the reason ctx ended, if it did. Errors from SDK
+// calls only say that the context deadline was exceeded, not which step's
+// share ran out.
+func StepError(ctx context.Context, err error) error {
+	if err == nil || ctx.Err() == nil {
+		return err
+	}
+	if cause := context.Cause(ctx); cause != ctx.Err() {
+		return fmt.Errorf("%v: %w", cause, err)
+	}
+	return err
+}
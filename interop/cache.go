@@ -0,0 +1,202 @@
+package interop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awsutil"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// cacheablePrefixes are the operation name prefixes a ResultCache serves:
+// the describe and list calls, which only read and which comparisons repeat.
+var cacheablePrefixes = []string{"Describe", "List"}
+
+// cacheKey identifies the result of one call: the SDK and region, the
+// service and operation, and the Go type and a hash of the input.
+type cacheKey struct {
+	sdk, region, service, operation, input, hash string
+}
+
+func (k cacheKey) String() string {
+	return fmt.Sprintf("%s %s %s.%s (input %s)", k.sdk, k.region, k.service, k.operation, k.hash[:12])
+}
+
+// cacheContextKey is the context key the cacheKey of a v1 call that missed
+// is stored under, from the Validate handler to the Complete handler that
+// stores its result.
+type cacheContextKey struct{}
+
+// CacheStats counts the calls a ResultCache served and those it sent.
+type CacheStats struct {
+	Hits, Misses int
+}
+
+func (s CacheStats) String() string {
+	return fmt.Sprintf("hits=%d misses=%d", s.Hits, s.Misses)
+}
+
+// ResultCache remembers the results of describe and list calls for the rest
+// of a run, so that checks needing the same listing make one call between
+// them. Results are keyed by SDK, region, operation and input, so a v1
+// result never answers a v2 call, nor one region's result another's, and
+// each entry keeps the Go type of its output. Only successful calls are
+// cached, and a result is copied on the way in and out, so that callers may
+// modify what they get. Two identical calls made concurrently may both miss.
+//
+// A cache is per run and per account: nothing invalidates an entry, so a
+// program that changes resources must not read them back through a cache,
+// and clients of different accounts must not share one.
+type ResultCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]any
+	stats   CacheStats
+}
+
+// NewResultCache returns an empty ResultCache.
+func NewResultCache() *ResultCache {
+	return &ResultCache{entries: make(map[cacheKey]any)}
+}
+
+// Stats returns the hits and misses of the cache so far.
+func (c *ResultCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// InstallV1 serves the describe and list calls of every client created from
+// sess afterwards from the cache. A call is looked up in the Validate
+// handlers; on a hit the request's Build, Sign, Send and Unmarshal handlers
+// are cleared, so nothing is sent, and its Complete handlers still run.
+func (c *ResultCache) InstallV1(sess *session.Session) {
+	sess.Handlers.Validate.PushBackNamed(request.NamedHandler{
+		Name: "interop.ResultCache.Lookup",
+		Fn: func(r *request.Request) {
+			if r.Error != nil || !cacheable(r.Operation.Name) {
+				return
+			}
+			key, err := c.key("v1", aws.StringValue(r.Config.Region), r.ClientInfo.ServiceName, r.Operation.Name, r.Params)
+			if err != nil {
+				Verbosef("%v", err)
+				return
+			}
+			if cached, ok := c.lookup(key, reflect.TypeOf(r.Data)); ok {
+				awsutil.Copy(r.Data, cached)
+				for _, list := range []*request.HandlerList{&r.Handlers.Build, &r.Handlers.Sign, &r.Handlers.Send,
+					&r.Handlers.UnmarshalMeta, &r.Handlers.ValidateResponse, &r.Handlers.Unmarshal} {
+					list.Clear()
+				}
+				return
+			}
+			r.SetContext(context.WithValue(r.Context(), cacheContextKey{}, key))
+		},
+	})
+	sess.Handlers.Complete.PushFrontNamed(request.NamedHandler{
+		Name: "interop.ResultCache.Store",
+		Fn: func(r *request.Request) {
+			if key, ok := r.Context().Value(cacheContextKey{}).(cacheKey); ok && r.Error == nil {
+				c.store(key, r.Data)
+			}
+		},
+	})
+}
+
+// InstallV2 is InstallV1 for every client created from cfg afterwards. An
+// Initialize middleware, right after the service, operation and region are
+// registered, returns a hit without calling the rest of the stack.
+func (c *ResultCache) InstallV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		if _, ok := stack.Initialize.Get("RegisterServiceMetadata"); !ok {
+			return nil
+		}
+		return stack.Initialize.Insert(middleware.InitializeMiddlewareFunc("interop.ResultCache",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				operation := awsmiddleware.GetOperationName(ctx)
+				if !cacheable(operation) {
+					return next.HandleInitialize(ctx, in)
+				}
+				key, err := c.key("v2", awsmiddleware.GetRegion(ctx), awsmiddleware.GetServiceID(ctx), operation, in.Parameters)
+				if err != nil {
+					Verbosef("%v", err)
+					return next.HandleInitialize(ctx, in)
+				}
+				if cached, ok := c.lookup(key, nil); ok {
+					return middleware.InitializeOutput{Result: awsutil.CopyOf(cached)}, middleware.Metadata{}, nil
+				}
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				if err == nil {
+					c.store(key, out.Result)
+				}
+				return out, metadata, err
+			}), "RegisterServiceMetadata", middleware.After)
+	})
+}
+
+// cacheable reports whether a ResultCache serves the named operation.
+func cacheable(operation string) bool {
+	for _, prefix := range cacheablePrefixes {
+		if strings.HasPrefix(operation, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// key returns the key of a call of operation with params.
+func (c *ResultCache) key(sdk, region, service, operation string, params any) (cacheKey, error) {
+	input, err := json.Marshal(params)
+	if err != nil {
+		return cacheKey{}, fmt.Errorf("cache: encoding %s input: %w", operation, err)
+	}
+	sum := sha256.Sum256(input)
+	return cacheKey{
+		sdk:       sdk,
+		region:    region,
+		service:   strings.ToLower(strings.ReplaceAll(service, " ", "")),
+		operation: operation,
+		input:     reflect.TypeOf(params).String(),
+		hash:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// lookup returns the result stored under key and counts a hit, or counts a
+// miss. When want is not nil, an entry of any other type is a miss.
+func (c *ResultCache) lookup(key cacheKey, want reflect.Type) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.entries[key]
+	if ok && want != nil && reflect.TypeOf(cached) != want {
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	Verbosef("cache hit: %s", key)
+	return cached, true
+}
+
+// store copies result, an output pointer, into the cache under key.
+func (c *ResultCache) store(key cacheKey, result any) {
+	if v := reflect.ValueOf(result); v.Kind() != reflect.Pointer || v.IsNil() {
+		return
+	}
+	result = awsutil.CopyOf(result)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = result
+}
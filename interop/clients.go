@@ -0,0 +1,230 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// Clients holds a v1 session and a v2 config for the same region, together
+// with the clients most comparisons need. Clients for other services can be
+// created from SessionV1 and ConfigV2.
+type Clients struct {
+	Region    string
+	SessionV1 *session.Session
+	ConfigV2  awsv2.Config
+
+	EC2V1 *ec2v1.EC2
+	EC2V2 *ec2v2.Client
+	S3V1  *s3v1.S3
+	S3V2  *s3v2.Client
+}
+
+// ClientOption customizes NewClients.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	readOnly   bool
+	maxRetries *int
+	profile    string
+	dualStack  bool
+	fips       bool
+	trace      *Trace
+	cache      *ResultCache
+
+	allowRegionMismatch bool
+}
+
+// WithReadOnly installs the ReadOnly filter on both SDKs when enabled is true.
+func WithReadOnly(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.readOnly = enabled
+	}
+}
+
+// WithProfile loads credentials and settings from the named shared config
+// profile, in both SDKs, instead of the default profile. The shared config
+// file is always read for v1, as v2 does, so that profiles defined only in
+// ~/.aws/config (such as role_arn profiles) work too.
+func WithProfile(name string) ClientOption {
+	return func(o *clientOptions) {
+		o.profile = name
+	}
+}
+
+// WithDualStack makes both SDKs use dual-stack (IPv4 and IPv6) endpoints
+// when enabled is true: v1 through aws.Config.UseDualStackEndpoint and v2
+// through config.WithUseDualStackEndpoint. Services or regions without a
+// dual-stack endpoint then fail to resolve one, rather than silently using
+// the IPv4-only endpoint.
+func WithDualStack(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.dualStack = enabled
+	}
+}
+
+// WithFIPS makes both SDKs use FIPS 140 validated endpoints when enabled is
+// true, the same way WithDualStack selects dual-stack endpoints.
+func WithFIPS(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.fips = enabled
+	}
+}
+
+// WithTrace installs t on both SDKs, to capture the responses of every call
+// or replay them. When t replays, both SDKs also get static placeholder
+// credentials, so that replays need no AWS account.
+func WithTrace(t *Trace) ClientOption {
+	return func(o *clientOptions) {
+		o.trace = t
+	}
+}
+
+// WithCache serves the describe and list calls of both SDKs from c, for
+// programs that repeat them within a run. One cache can be shared by the
+// clients of several regions, since its entries are keyed by region. A nil
+// c leaves caching off.
+func WithCache(c *ResultCache) ClientOption {
+	return func(o *clientOptions) {
+		o.cache = c
+	}
+}
+
+// WithRegionMismatch makes NewClients return the clients even when the v1
+// session and the v2 config resolve different regions, for programs that
+// mean to compare regions. Clients.Region is then the v2 region.
+func WithRegionMismatch(allowed bool) ClientOption {
+	return func(o *clientOptions) {
+		o.allowRegionMismatch = allowed
+	}
+}
+
+// WithMaxRetries makes both SDKs retry a failed request at most maxRetries
+// times: it sets v1 MaxRetries to maxRetries and v2 RetryMaxAttempts to
+// RetryMaxAttempts(maxRetries). A negative value keeps the SDK defaults.
+func WithMaxRetries(maxRetries int) ClientOption {
+	return func(o *clientOptions) {
+		if maxRetries < 0 {
+			o.maxRetries = nil
+			return
+		}
+		o.maxRetries = &maxRetries
+	}
+}
+
+// NewClients creates a v1 session and loads a v2 config for region from the
+// default credential chain, then builds the service clients from them.
+// Options are applied to the session and config before any client is
+// created, since v1 clients copy the session handlers when they are built.
+// An empty region leaves it to each SDK's defaults; unless WithRegionMismatch
+// allows it, NewClients fails with a *RegionMismatchError when the two
+// resolve different regions.
+func NewClients(ctx context.Context, region string, opts ...ClientOption) (*Clients, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var cfgV1 aws.Config
+	if region != "" {
+		cfgV1.Region = aws.String(region)
+	}
+	loadOpts := []func(*config.LoadOptions) error{config.WithRegion(region)}
+	if o.maxRetries != nil {
+		cfgV1.MaxRetries = aws.Int(*o.maxRetries)
+		loadOpts = append(loadOpts, config.WithRetryMaxAttempts(RetryMaxAttempts(*o.maxRetries)))
+	}
+
+	if o.dualStack {
+		cfgV1.UseDualStackEndpoint = endpoints.DualStackEndpointStateEnabled
+		loadOpts = append(loadOpts, config.WithUseDualStackEndpoint(awsv2.DualStackEndpointStateEnabled))
+	}
+	if o.fips {
+		cfgV1.UseFIPSEndpoint = endpoints.FIPSEndpointStateEnabled
+		loadOpts = append(loadOpts, config.WithUseFIPSEndpoint(awsv2.FIPSEndpointStateEnabled))
+	}
+
+	if o.trace != nil && o.trace.Mode() == TraceReplay {
+		cfgV1.Credentials = credentials.NewStaticCredentials("AKIDREPLAY", "replay", "")
+		loadOpts = append(loadOpts, config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDREPLAY", "replay", "")))
+	}
+
+	sessOpts := session.Options{Config: cfgV1}
+	if o.profile != "" {
+		sessOpts.Profile = o.profile
+		sessOpts.SharedConfigState = session.SharedConfigEnable
+		loadOpts = append(loadOpts, config.WithSharedConfigProfile(o.profile))
+	}
+
+	sess, err := session.NewSessionWithOptions(sessOpts)
+	if err != nil {
+		return nil, fmt.Errorf("creating v1 session: %w", err)
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, loadOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("loading v2 config: %w", err)
+	}
+	if err := CheckRegions(sess, cfg); err != nil && !o.allowRegionMismatch {
+		return nil, err
+	}
+	if o.readOnly {
+		ReadOnly.InstallV1(sess)
+		ReadOnly.InstallV2(&cfg)
+	}
+	if o.trace != nil {
+		o.trace.InstallV1(sess)
+		o.trace.InstallV2(&cfg)
+	}
+	if o.cache != nil {
+		o.cache.InstallV1(sess)
+		o.cache.InstallV2(&cfg)
+	}
+	InstallOperationSummaryV1(sess)
+	InstallOperationSummaryV2(&cfg)
+	InstallUserAgentV1(sess)
+	InstallUserAgentV2(&cfg)
+
+	return &Clients{
+		Region:    cfg.Region,
+		SessionV1: sess,
+		ConfigV2:  cfg,
+		EC2V1:     ec2v1.New(sess),
+		EC2V2:     ec2v2.NewFromConfig(cfg),
+		S3V1:      s3v1.New(sess),
+		S3V2:      s3v2.NewFromConfig(cfg),
+	}, nil
+}
+
+// RegionMismatchError is returned by CheckRegions when a v1 session and a v2
+// config are set up for different regions.
+type RegionMismatchError struct {
+	V1, V2 string
+}
+
+func (e *RegionMismatchError) Error() string {
+	return fmt.Sprintf("the v1 session is for region %q but the v2 config for %q; "+
+		"resources in one would look missing in the other", e.V1, e.V2)
+}
+
+// CheckRegions returns a *RegionMismatchError if sess and cfg resolve
+// different regions. They differ most often when the region is left to the
+// environment: v1 only reads AWS_DEFAULT_REGION and the region of a shared
+// config profile when AWS_SDK_LOAD_CONFIG is set, while v2 always does.
+func CheckRegions(sess *session.Session, cfg awsv2.Config) error {
+	if v1 := aws.StringValue(sess.Config.Region); v1 != cfg.Region {
+		return &RegionMismatchError{V1: v1, V2: cfg.Region}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package interop
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clock tells the current time. The helpers that put the time into names
+// and identifiers read it from DefaultClock, so that a program can swap in
+// a FakeClock and get the same output on every run.
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock is the Clock of the system.
+type RealClock struct{}
+
+// Now returns time.Now().
+func (RealClock) Now() time.Time { return time.Now() }
+
+// FakeClock is a Clock that only moves when told to. It is safe for
+// concurrent use.
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a FakeClock stopped at t.
+func NewFakeClock(t time.Time) *FakeClock {
+	return &FakeClock{now: t}
+}
+
+// Now returns the time the clock is stopped at.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Set stops the clock at t.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	c.now = t
+	c.mu.Unlock()
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+// DefaultClock is read by GenerateName, GenerateBucketName and RunID, and
+// when checking the validity of SNS signing certificates. Set it before
+// any of them is first called; RunID keeps the first value it computes.
+var DefaultClock Clock = RealClock{}
+
+// GenerateName returns "sdk-migration-<kind>-<unix seconds>", the name
+// programs give the resources they create, so that leftovers can be told
+// apart by kind and age.
+func GenerateName(kind string) string {
+	return fmt.Sprintf("sdk-migration-%s-%d", kind, DefaultClock.Now().Unix())
+}
+
+// GenerateBucketName is GenerateName for S3 buckets, whose names must be
+// lowercase.
+func GenerateBucketName(kind string) string {
+	return GenerateName(strings.ToLower(kind))
+}
+
+var runID = sync.OnceValue(func() string {
+	return fmt.Sprintf("%d", DefaultClock.Now().Unix())
+})
+
+// RunID identifies one run of a program: the Unix second DefaultClock read
+// when RunID was first called. A program reads it when sending its first
+// request at the latest, so it is close to the start time and near the
+// timestamps GenerateName puts into the names of the run's resources.
+func RunID() string {
+	return runID()
+}
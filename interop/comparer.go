@@ -0,0 +1,173 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// ServiceComparer describes the same resources with both SDKs and maps each
+// result onto a shared form. Normalize receives whatever DescribeV1 or
+// DescribeV2 returned and must produce values that DiffJSON can compare.
+type ServiceComparer interface {
+	Name() string
+	DescribeV1(ctx context.Context, c *Clients) (any, error)
+	DescribeV2(ctx context.Context, c *Clients) (any, error)
+	Normalize(any) any
+}
+
+// KeyedComparer is a ServiceComparer whose normalized result is a list of
+// resources, each identified by the JSON field KeyField names. Comparisons
+// between accounts use it to match resources by identity rather than by
+// position, so that one extra resource does not shift every later one.
+type KeyedComparer interface {
+	ServiceComparer
+	KeyField() string
+}
+
+var comparers = map[string]ServiceComparer{}
+
+// RegisterComparer makes sc available to Comparers and LookupComparer. It
+// panics if a comparer with the same name is already registered.
+func RegisterComparer(sc ServiceComparer) {
+	if _, dup := comparers[sc.Name()]; dup {
+		panic(fmt.Sprintf("interop: comparer %q registered twice", sc.Name()))
+	}
+	comparers[sc.Name()] = sc
+}
+
+// LookupComparer returns the registered comparer with the given name.
+func LookupComparer(name string) (ServiceComparer, bool) {
+	sc, ok := comparers[name]
+	return sc, ok
+}
+
+// Comparers returns every registered comparer, sorted by name.
+func Comparers() []ServiceComparer {
+	all := make([]ServiceComparer, 0, len(comparers))
+	for _, sc := range comparers {
+		all = append(all, sc)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name() < all[j].Name() })
+	return all
+}
+
+// ComparisonResult is the outcome of running one comparer. Err is set when
+// either describe call or the diff itself failed; otherwise Diffs lists the
+// differences between the normalized v1 and v2 results. Unavailable is set
+// along with Err when a describe call failed because the service does not
+// exist in the clients' region, which callers report without counting it as
+// a failure.
+type ComparisonResult struct {
+	Name        string
+	Diffs       []string
+	Err         error
+	Unavailable bool
+}
+
+// fail records err, returned while doing what, as the result's error.
+func (r *ComparisonResult) fail(region, what string, err error) {
+	if NormalizeError(err).Kind == ErrorKindUnavailable {
+		r.Unavailable = true
+		r.Err = fmt.Errorf("service not available in %s (%s: %w)", region, what, err)
+		return
+	}
+	r.Err = fmt.Errorf("%s: %w", what, err)
+}
+
+// Match reports whether the comparison ran and found no differences.
+func (r ComparisonResult) Match() bool {
+	return r.Err == nil && len(r.Diffs) == 0
+}
+
+// Compare describes the comparer's resources with both SDKs, normalizes both
+// results and diffs them with DiffJSON.
+func Compare(ctx context.Context, c *Clients, sc ServiceComparer) ComparisonResult {
+	result := ComparisonResult{Name: sc.Name()}
+	outV1, err := sc.DescribeV1(ctx, c)
+	if err != nil {
+		result.fail(c.Region, "describing with v1", err)
+		return result
+	}
+	outV2, err := sc.DescribeV2(ctx, c)
+	if err != nil {
+		result.fail(c.Region, "describing with v2", err)
+		return result
+	}
+	result.Diffs, result.Err = DiffJSON(sc.Normalize(outV1), sc.Normalize(outV2))
+	return result
+}
+
+// CompareAccounts describes the comparer's resources with SDK v2 in two
+// accounts at once, a and b being clients for different profiles, and diffs
+// the normalized results. For a KeyedComparer each difference names the
+// resource it belongs to; "first" in a diff refers to a and "second" to b.
+func CompareAccounts(ctx context.Context, a, b *Clients, sc ServiceComparer) ComparisonResult {
+	result := ComparisonResult{Name: sc.Name()}
+	var outA, outB any
+	var errA, errB error
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		outA, errA = sc.DescribeV2(ctx, a)
+	}()
+	go func() {
+		defer wg.Done()
+		outB, errB = sc.DescribeV2(ctx, b)
+	}()
+	wg.Wait()
+	if errA != nil {
+		result.fail(a.Region, "describing first account", errA)
+		return result
+	}
+	if errB != nil {
+		result.fail(b.Region, "describing second account", errB)
+		return result
+	}
+
+	docA, docB := sc.Normalize(outA), sc.Normalize(outB)
+	if kc, ok := sc.(KeyedComparer); ok {
+		var err error
+		if docA, err = indexByField(docA, kc.KeyField()); err != nil {
+			result.Err = fmt.Errorf("indexing first account: %w", err)
+			return result
+		}
+		if docB, err = indexByField(docB, kc.KeyField()); err != nil {
+			result.Err = fmt.Errorf("indexing second account: %w", err)
+			return result
+		}
+	}
+	result.Diffs, result.Err = DiffJSON(docA, docB)
+	return result
+}
+
+// indexByField turns a list of JSON objects into a map keyed by each
+// object's field value.
+func indexByField(list any, field string) (map[string]interface{}, error) {
+	doc, err := jsonDocument(list)
+	if err != nil {
+		return nil, err
+	}
+	indexed := make(map[string]interface{})
+	if doc == nil {
+		return indexed, nil
+	}
+	items, ok := doc.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("normalized result is not a list")
+	}
+	for i, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("item %d is not an object", i)
+		}
+		key, ok := obj[field].(string)
+		if !ok {
+			return nil, fmt.Errorf("item %d has no string %q field", i, field)
+		}
+		indexed[key] = obj
+	}
+	return indexed, nil
+}
@@ -0,0 +1,222 @@
+package interop
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func init() {
+	RegisterComparer(ec2InstancesComparer{})
+	RegisterComparer(s3BucketsComparer{})
+}
+
+// ec2InstancesComparer compares DescribeInstances across SDKs.
+type ec2InstancesComparer struct{}
+
+// normalizedInstance is the shared form of an instance. Tags leaves out
+// AWS-managed tags, which differ between accounts and stacks for otherwise
+// identical instances.
+type normalizedInstance struct {
+	ID         string            `json:"id"`
+	Type       string            `json:"type"`
+	State      string            `json:"state"`
+	LaunchTime string            `json:"launchTime,omitempty"`
+	Tags       map[string]string `json:"tags,omitempty"`
+}
+
+func (ec2InstancesComparer) Name() string { return "ec2" }
+
+func (ec2InstancesComparer) KeyField() string { return "id" }
+
+func (ec2InstancesComparer) DescribeV1(ctx context.Context, c *Clients) (any, error) {
+	var instances []*ec2v1.Instance
+	err := c.EC2V1.DescribeInstancesPagesWithContext(ctx, &ec2v1.DescribeInstancesInput{},
+		func(page *ec2v1.DescribeInstancesOutput, lastPage bool) bool {
+			for _, reservation := range page.Reservations {
+				instances = append(instances, reservation.Instances...)
+			}
+			return true
+		})
+	return instances, err
+}
+
+func (ec2InstancesComparer) DescribeV2(ctx context.Context, c *Clients) (any, error) {
+	var instances []ec2types.Instance
+	paginator := ec2v2.NewDescribeInstancesPaginator(c.EC2V2, &ec2v2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			instances = append(instances, reservation.Instances...)
+		}
+	}
+	return instances, nil
+}
+
+// Normalize sorts the instances by SortInstancesBy, with the same ordering
+// for both SDKs, so that the results are compared and printed in a stable
+// order.
+func (ec2InstancesComparer) Normalize(v any) any {
+	var out []normalizedInstance
+	switch instances := v.(type) {
+	case []*ec2v1.Instance:
+		instances = slices.Clone(instances)
+		SortInstancesV1(instances, SortInstancesBy)
+		for _, inst := range instances {
+			out = append(out, normalizeInstanceV1(inst))
+		}
+	case []ec2types.Instance:
+		instances = slices.Clone(instances)
+		SortInstancesV2(instances, SortInstancesBy)
+		for _, inst := range instances {
+			out = append(out, normalizeInstanceV2(inst))
+		}
+	default:
+		return v
+	}
+	return out
+}
+
+func normalizeInstanceV1(inst *ec2v1.Instance) normalizedInstance {
+	n := normalizedInstance{
+		ID:         aws.StringValue(inst.InstanceId),
+		Type:       aws.StringValue(inst.InstanceType),
+		LaunchTime: FormatTime(inst.LaunchTime),
+	}
+	if inst.State != nil {
+		n.State = aws.StringValue(inst.State.Name)
+	}
+	for _, tag := range inst.Tags {
+		if n.Tags == nil {
+			n.Tags = make(map[string]string)
+		}
+		n.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	n.Tags = withoutTags(n.Tags, []string{ManagedTagPrefix})
+	return n
+}
+
+func normalizeInstanceV2(inst ec2types.Instance) normalizedInstance {
+	n := normalizedInstance{
+		ID:         aws.StringValue(inst.InstanceId),
+		Type:       string(inst.InstanceType),
+		LaunchTime: FormatTime(inst.LaunchTime),
+		Tags:       withoutTags(tagsFromV2(inst.Tags), []string{ManagedTagPrefix}),
+	}
+	if inst.State != nil {
+		n.State = string(inst.State.Name)
+	}
+	return n
+}
+
+// tagsFromV2 returns tags as a map, or nil when there are none.
+func tagsFromV2(tags []ec2types.Tag) map[string]string {
+	var m map[string]string
+	for _, tag := range tags {
+		if m == nil {
+			m = make(map[string]string)
+		}
+		m[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+	return m
+}
+
+// s3BucketsComparer compares ListBuckets across SDKs.
+type s3BucketsComparer struct{}
+
+type normalizedBucket struct {
+	Name         string `json:"name"`
+	CreationDate string `json:"creationDate"`
+}
+
+func (s3BucketsComparer) Name() string { return "s3" }
+
+func (s3BucketsComparer) KeyField() string { return "name" }
+
+// listBucketsPageV1 is a ListBuckets request and response with the
+// pagination fields that S3 added after v1 was last generated: without
+// them, v1 sends no continuation token and gets only the first page of an
+// account with more buckets than S3 returns at once.
+type listBucketsPageV1 struct {
+	_ struct{} `type:"structure"`
+
+	ContinuationToken *string `location:"querystring" locationName:"continuation-token" type:"string"`
+}
+
+type listBucketsOutputV1 struct {
+	_ struct{} `type:"structure"`
+
+	Buckets           []*s3v1.Bucket `locationNameList:"Bucket" type:"list"`
+	ContinuationToken *string        `type:"string"`
+}
+
+func (s3BucketsComparer) DescribeV1(ctx context.Context, c *Clients) (any, error) {
+	var buckets []*s3v1.Bucket
+	var token *string
+	for {
+		// The request comes from ListBucketsRequest for the S3
+		// customizations; only its input and output are replaced.
+		req, _ := c.S3V1.ListBucketsRequest(&s3v1.ListBucketsInput{})
+		out := &listBucketsOutputV1{}
+		req.Params = &listBucketsPageV1{ContinuationToken: token}
+		req.Data = out
+		req.SetContext(ctx)
+		if err := req.Send(); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, out.Buckets...)
+		if aws.StringValue(out.ContinuationToken) == "" {
+			return buckets, nil
+		}
+		token = out.ContinuationToken
+	}
+}
+
+func (s3BucketsComparer) DescribeV2(ctx context.Context, c *Clients) (any, error) {
+	var buckets []s3types.Bucket
+	paginator := s3v2.NewListBucketsPaginator(c.S3V2, &s3v2.ListBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, page.Buckets...)
+	}
+	return buckets, nil
+}
+
+func (s3BucketsComparer) Normalize(v any) any {
+	var out []normalizedBucket
+	switch buckets := v.(type) {
+	case []*s3v1.Bucket:
+		for _, b := range buckets {
+			out = append(out, normalizedBucket{
+				Name:         aws.StringValue(b.Name),
+				CreationDate: FormatTime(b.CreationDate),
+			})
+		}
+	case []s3types.Bucket:
+		for _, b := range buckets {
+			out = append(out, normalizedBucket{
+				Name:         aws.StringValue(b.Name),
+				CreationDate: FormatTime(b.CreationDate),
+			})
+		}
+	default:
+		return v
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
@@ -0,0 +1,238 @@
+package interop
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Environment variables that configure the programs, for CI jobs that would
+// rather not repeat flags on every command line. Each one supplies the
+// value of the flag in its comment, when the program has that flag and it
+// is not given on the command line.
+const (
+	RegionEnv    = "SDKMT_REGION"     // -region
+	ProfileEnv   = "SDKMT_PROFILE"    // shared config profile; no program has a flag for it
+	OutputEnv    = "SDKMT_OUTPUT"     // -output
+	VerboseEnv   = "SDKMT_VERBOSE"    // -verbose
+	ReadOnlyEnv  = "SDKMT_READ_ONLY"  // -read-only
+	AssumeYesEnv = "SDKMT_YES"        // -yes
+	UserAgentEnv = "SDKMT_USER_AGENT" // -user-agent
+	ConfigEnv    = "SDKMT_CONFIG"     // -config, the ConfigFile to read
+)
+
+// DefaultRegion is the region programs run in when neither -region nor
+// SDKMT_REGION is given and the program has no default of its own.
+const DefaultRegion = "us-east-1"
+
+// Config is the configuration shared by the programs, resolved once by
+// ParseFlags. Each field comes from, in order of precedence, its flag when
+// given on the command line, its SDKMT_* environment variable when set to
+// anything but "", the config file when one is given with -config or
+// SDKMT_CONFIG, and otherwise the flag's default, or the package default
+// when the program has no such flag.
+//
+// Regions lists the regions to check: those of the config file's regions
+// key when the region does not come from a flag or the environment, and
+// otherwise just Region, which is always Regions[0]. Services and
+// Options come from -services or the config file; Services is empty when
+// neither names any, meaning every registered comparer.
+//
+// Warnings lists the values ResolveConfig ignored: an output format from
+// SDKMT_OUTPUT or the config file that the program's OutputFlag does not
+// accept, since programs with different formats share both.
+type Config struct {
+	Region    string
+	Profile   string
+	Output    string
+	Verbose   bool
+	ReadOnly  bool
+	AssumeYes bool
+	UserAgent string
+	File      string
+	Regions   []string
+	Services  []string
+	Options   map[string]ServiceOptions
+	Warnings  []string
+}
+
+// outputValue is the flag.Value of an OutputFlag.
+type outputValue struct {
+	value   *string
+	formats []string
+}
+
+func (o *outputValue) String() string {
+	if o == nil || o.value == nil {
+		return ""
+	}
+	return *o.value
+}
+
+func (o *outputValue) Set(value string) error {
+	if !slices.Contains(o.formats, value) {
+		return fmt.Errorf("want %s", strings.Join(o.formats, " or "))
+	}
+	*o.value = value
+	return nil
+}
+
+// OutputFlag defines an -output flag in fs that accepts only formats, the
+// first of which is its default, and returns the variable holding its
+// value. An unsupported format on the command line is a usage error; from
+// SDKMT_OUTPUT or the config file it is ignored with a warning.
+func OutputFlag(fs *flag.FlagSet, usage string, formats ...string) *string {
+	output := formats[0]
+	fs.Var(&outputValue{value: &output, formats: formats}, "output", usage)
+	return &output
+}
+
+// setting ties a Config field to its flag and environment variable. A
+// setting with no environment variable is read from its flag and the
+// config file only.
+type setting struct {
+	flag, env, def string
+	set            func(c *Config, value string) error
+}
+
+// configSettings returns the settings of every Config field. The defaults
+// are read when it is called, after programs may have changed them.
+func configSettings() []setting {
+	return []setting{
+		{"region", RegionEnv, DefaultRegion, func(c *Config, v string) error { c.Region = v; return nil }},
+		{"profile", ProfileEnv, "", func(c *Config, v string) error { c.Profile = v; return nil }},
+		{"output", OutputEnv, OutputText, func(c *Config, v string) error { c.Output = v; return nil }},
+		{"verbose", VerboseEnv, "false", boolSetting(func(c *Config) *bool { return &c.Verbose })},
+		{"read-only", ReadOnlyEnv, "false", boolSetting(func(c *Config) *bool { return &c.ReadOnly })},
+		{"yes", AssumeYesEnv, "false", boolSetting(func(c *Config) *bool { return &c.AssumeYes })},
+		{"user-agent", UserAgentEnv, UserAgentSuffix, func(c *Config, v string) error { c.UserAgent = v; return nil }},
+		{"services", "", "", func(c *Config, v string) error { c.Services = splitList(v); return nil }},
+	}
+}
+
+// boolSetting returns a setting's set function for the bool field returned
+// by field.
+func boolSetting(field func(c *Config) *bool) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		*field(c) = b
+		return nil
+	}
+}
+
+// splitList splits a comma-separated list, dropping empty elements.
+func splitList(s string) []string {
+	var out []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// ResolveConfig resolves the Config of a program whose flags fs has already
+// parsed, looking environment variables up with lookupEnv, which is
+// os.LookupEnv outside of tests. The config file is read first, from -config
+// or SDKMT_CONFIG, whether or not the program has a -config flag. A flag
+// that fs defines but the command line did not set is set from its
+// environment variable or the config file, so that the variables the
+// program bound to its flags see the resolved value too. It returns an
+// error naming the variable when a value is not valid for its flag, and the
+// error of LoadConfigFile when the config file is not valid.
+func ResolveConfig(fs *flag.FlagSet, lookupEnv func(string) (string, bool)) (Config, error) {
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	var cfg Config
+	if f := fs.Lookup("config"); f != nil {
+		cfg.File = f.Value.String()
+	}
+	if env, ok := lookupEnv(ConfigEnv); ok && env != "" && !explicit["config"] {
+		cfg.File = env
+	}
+	var file *ConfigFile
+	if cfg.File != "" {
+		var err error
+		if file, err = LoadConfigFile(cfg.File); err != nil {
+			return Config{}, err
+		}
+		cfg.Options = file.Options
+	}
+
+	// from records where each setting's value came from, by flag name.
+	from := make(map[string]string)
+	for _, s := range configSettings() {
+		value, name := s.def, s.env
+		if name == "" {
+			name = "-" + s.flag
+		}
+		f := fs.Lookup(s.flag)
+		if f != nil {
+			value = f.Value.String()
+		}
+		if v, ok := file.value(s.flag); ok && !explicit[s.flag] {
+			value, name = v, cfg.File
+		}
+		if env, ok := lookupEnv(s.env); s.env != "" && ok && env != "" && !explicit[s.flag] {
+			value, name = env, s.env
+		}
+		if o, ok := flagValue(f).(*outputValue); ok && !slices.Contains(o.formats, value) {
+			cfg.Warnings = append(cfg.Warnings, fmt.Sprintf("ignoring output %q from %s: this program's -output must be %s",
+				value, name, strings.Join(o.formats, " or ")))
+			value, name = o.String(), "-"+s.flag
+		}
+		from[s.flag] = name
+		if err := s.set(&cfg, value); err != nil {
+			return Config{}, fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+		}
+		if f != nil && value != f.Value.String() {
+			if err := fs.Set(s.flag, value); err != nil {
+				return Config{}, fmt.Errorf("invalid value %q for %s: %w", value, name, err)
+			}
+		}
+	}
+	cfg.Regions = []string{cfg.Region}
+	if file != nil && from["region"] == cfg.File && len(file.Regions) > 0 {
+		cfg.Regions = file.Regions
+	}
+	return cfg, nil
+}
+
+// flagValue returns the Value of f, or nil when f is nil.
+func flagValue(f *flag.Flag) flag.Value {
+	if f == nil {
+		return nil
+	}
+	return f.Value
+}
+
+// ParseFlags parses the command line, resolves the Config from it, the
+// environment and the config file, and returns it; programs call it in
+// place of flag.Parse. An invalid environment variable or config file is
+// reported like a bad flag, with the usage and exit status 2, and the
+// Config's warnings are printed to stderr. A profile from SDKMT_PROFILE is
+// exported as AWS_PROFILE, so that both SDKs load it as if it had been set
+// there.
+func ParseFlags() Config {
+	flag.Parse()
+	cfg, err := ResolveConfig(flag.CommandLine, os.LookupEnv)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, warning := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+	}
+	if cfg.Profile != "" {
+		os.Setenv("AWS_PROFILE", cfg.Profile)
+	}
+	return cfg
+}
@@ -0,0 +1,191 @@
+package interop
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the content of a -config file: the services and regions a
+// migration-validation suite checks, and options for some services, kept
+// in version control instead of on command lines. It is JSON or YAML,
+// depending on the file's extension, every key is optional, and an
+// unknown key is an error rather than ignored, so that a misspelt key does
+// not silently check less than intended. Keys are case-sensitive in YAML;
+// in JSON, as encoding/json decodes it, they match in any case.
+type ConfigFile struct {
+	Region    string                    `json:"region" yaml:"region"`
+	Regions   []string                  `json:"regions" yaml:"regions"`
+	Profile   string                    `json:"profile" yaml:"profile"`
+	Output    string                    `json:"output" yaml:"output"`
+	Verbose   *bool                     `json:"verbose" yaml:"verbose"`
+	ReadOnly  *bool                     `json:"readOnly" yaml:"readOnly"`
+	AssumeYes *bool                     `json:"yes" yaml:"yes"`
+	UserAgent *string                   `json:"userAgent" yaml:"userAgent"`
+	Services  []string                  `json:"services" yaml:"services"`
+	Options   map[string]ServiceOptions `json:"options" yaml:"options"`
+}
+
+// ServiceOptions are the options of one service in a ConfigFile, keyed by
+// the name of its comparer. Regions, when set, replaces the regions of the
+// file for that service. SortBy is the InstanceOrder of the ec2 comparer,
+// and is not accepted for other services.
+type ServiceOptions struct {
+	Regions []string `json:"regions" yaml:"regions"`
+	SortBy  string   `json:"sortBy" yaml:"sortBy"`
+}
+
+// value returns the file's value for the setting of flag name, as the flag
+// would be given it, and whether the file sets it.
+func (f *ConfigFile) value(name string) (string, bool) {
+	if f == nil {
+		return "", false
+	}
+	optionalBool := func(b *bool) (string, bool) {
+		if b == nil {
+			return "", false
+		}
+		return strconv.FormatBool(*b), true
+	}
+	switch name {
+	case "region":
+		if len(f.Regions) > 0 {
+			return f.Regions[0], true
+		}
+		return f.Region, f.Region != ""
+	case "profile":
+		return f.Profile, f.Profile != ""
+	case "output":
+		return f.Output, f.Output != ""
+	case "verbose":
+		return optionalBool(f.Verbose)
+	case "read-only":
+		return optionalBool(f.ReadOnly)
+	case "yes":
+		return optionalBool(f.AssumeYes)
+	case "user-agent":
+		if f.UserAgent == nil {
+			return "", false
+		}
+		return *f.UserAgent, true
+	case "services":
+		return strings.Join(f.Services, ","), len(f.Services) > 0
+	}
+	return "", false
+}
+
+// yamlUnknownField matches the lines of a yaml.v3 error about a key that
+// KnownFields rejected.
+var yamlUnknownField = regexp.MustCompile(`line (\d+): field (\S+) not found in type \S+`)
+
+// LoadConfigFile reads and validates the config file at path. Files ending
+// in .json are read as JSON, and files ending in .yaml or .yml as YAML.
+// Errors name the file, and for an unknown key, the key.
+func LoadConfigFile(path string) (*ConfigFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f ConfigFile
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&f); err != nil && err != io.EOF {
+			if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+				return nil, fmt.Errorf("%s: unknown key %s", path, field)
+			}
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("%s: unexpected data after the top-level object", path)
+		}
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&f); err != nil && err != io.EOF {
+			var typeErr *yaml.TypeError
+			if errors.As(err, &typeErr) {
+				msgs := make([]string, len(typeErr.Errors))
+				for i, msg := range typeErr.Errors {
+					msgs[i] = yamlUnknownField.ReplaceAllString(msg, `line $1: unknown key "$2"`)
+				}
+				return nil, fmt.Errorf("%s: %s", path, strings.Join(msgs, "; "))
+			}
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("%s: unsupported config file extension %q (want .json, .yaml or .yml)", path, ext)
+	}
+	if err := f.validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &f, nil
+}
+
+// validate checks what decoding alone does not: that services and options
+// name registered comparers, and that each option applies to its service.
+func (f *ConfigFile) validate() error {
+	if f.Region != "" && len(f.Regions) > 0 {
+		return errors.New("region and regions cannot both be set")
+	}
+	if err := validateRegions("regions", f.Regions); err != nil {
+		return err
+	}
+	for _, name := range f.Services {
+		if err := validateComparer("services", name); err != nil {
+			return err
+		}
+	}
+	for name, opts := range f.Options {
+		if err := validateComparer("options", name); err != nil {
+			return err
+		}
+		if err := validateRegions("options."+name+".regions", opts.Regions); err != nil {
+			return err
+		}
+		if opts.SortBy == "" {
+			continue
+		}
+		if name != "ec2" {
+			return fmt.Errorf("options.%s.sortBy: only the ec2 comparer has a sort order", name)
+		}
+		if _, err := ParseInstanceOrder(opts.SortBy); err != nil {
+			return fmt.Errorf("options.%s.sortBy: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateComparer returns an error listing the registered comparers when
+// name, found under key, is not one of them.
+func validateComparer(key, name string) error {
+	if _, ok := LookupComparer(name); ok {
+		return nil
+	}
+	all := Comparers()
+	names := make([]string, len(all))
+	for i, sc := range all {
+		names[i] = sc.Name()
+	}
+	return fmt.Errorf("%s: unknown comparer %q (want %s)", key, name, strings.Join(names, ", "))
+}
+
+// validateRegions rejects an empty region name, found under key.
+func validateRegions(key string, regions []string) error {
+	for _, r := range regions {
+		if r == "" {
+			return fmt.Errorf("%s: empty region name", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package interop
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// AssumeYes approves every destructive action without prompting. Programs
+// bind it to a -yes flag; it is required when stdin is not a terminal.
+var AssumeYes bool
+
+// stdinIsTerminal reports whether stdin is an interactive terminal.
+func stdinIsTerminal() bool {
+	fi, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// ConfirmDestructive reports whether the program may delete resource. With
+// AssumeYes set it always may. Otherwise it prompts on an interactive
+// terminal, and refuses outright when stdin is not a terminal (as in CI), so
+// that a demo pointed at a real account never deletes anything unattended.
+func ConfirmDestructive(resource string) bool {
+	if AssumeYes {
+		return true
+	}
+	if !stdinIsTerminal() {
+		fmt.Fprintf(Output, "Refusing to delete %s: stdin is not a terminal, pass -yes to confirm\n", resource)
+		return false
+	}
+
+	fmt.Fprintf(Output, "Delete %s? [y/N]: ", resource)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	fmt.Fprintf(Output, "Not deleting %s\n", resource)
+	return false
+}
@@ -0,0 +1,197 @@
+package interop
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// maxFillDepth bounds how deep fillValue follows nested pointers, so that
+// recursive types terminate.
+const maxFillDepth = 4
+
+// ConverterCoverage is the result of checking which exported fields of a v2
+// struct type a converter reads.
+type ConverterCoverage struct {
+	Name string
+	// Mapped fields change the converter's output when cleared.
+	Mapped []string
+	// Ignored fields are deliberately not mapped.
+	Ignored []string
+	// Unmapped fields are neither mapped nor ignored: typically fields added
+	// by a newer SDK release that the converter has not caught up with.
+	Unmapped []string
+	// Stale entries of the ignore list are mapped after all, or are no
+	// longer fields of the type.
+	Stale []string
+}
+
+// OK reports whether every field is accounted for.
+func (c ConverterCoverage) OK() bool {
+	return len(c.Unmapped) == 0 && len(c.Stale) == 0
+}
+
+// CheckConverterCoverage finds the exported top-level fields of T that
+// convert ignores. It fills every field of a T with distinct non-zero values,
+// then clears the fields one at a time: a field is mapped if clearing it
+// changes the JSON encoding of convert's result. Fields listed in ignored
+// are expected to be unmapped. Nested structs count as mapped as soon as the
+// converter reads any part of them. Interface-typed fields, such as unions,
+// cannot be filled and so always appear unmapped unless ignored.
+func CheckConverterCoverage[T any](name string, convert func(T) any, ignored ...string) (ConverterCoverage, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() != reflect.Struct {
+		return ConverterCoverage{}, fmt.Errorf("%s: %s is not a struct type", name, typ)
+	}
+
+	full := reflect.New(typ).Elem()
+	seed := 0
+	fillValue(full, &seed, 0)
+	want, err := json.Marshal(convert(full.Interface().(T)))
+	if err != nil {
+		return ConverterCoverage{}, fmt.Errorf("%s: encoding result: %w", name, err)
+	}
+
+	isIgnored := make(map[string]bool, len(ignored))
+	for _, field := range ignored {
+		isIgnored[field] = true
+	}
+	cov := ConverterCoverage{Name: name}
+	seen := make(map[string]bool)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		seen[field.Name] = true
+
+		cleared := reflect.New(typ).Elem()
+		cleared.Set(full)
+		cleared.Field(i).SetZero()
+		got, err := json.Marshal(convert(cleared.Interface().(T)))
+		if err != nil {
+			return ConverterCoverage{}, fmt.Errorf("%s: encoding result without %s: %w", name, field.Name, err)
+		}
+		mapped := !bytes.Equal(got, want)
+		switch {
+		case mapped && isIgnored[field.Name]:
+			cov.Mapped = append(cov.Mapped, field.Name)
+			cov.Stale = append(cov.Stale, field.Name)
+		case mapped:
+			cov.Mapped = append(cov.Mapped, field.Name)
+		case isIgnored[field.Name]:
+			cov.Ignored = append(cov.Ignored, field.Name)
+		default:
+			cov.Unmapped = append(cov.Unmapped, field.Name)
+		}
+	}
+	for _, field := range ignored {
+		if !seen[field] {
+			cov.Stale = append(cov.Stale, field)
+		}
+	}
+	sort.Strings(cov.Stale)
+	return cov, nil
+}
+
+// fillValue sets v, and everything reachable from it up to maxFillDepth,
+// to non-zero values. Each scalar gets a value derived from *seed, which is
+// incremented, so that no two fields hold the same value.
+func fillValue(v reflect.Value, seed *int, depth int) {
+	*seed++
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(fmt.Sprintf("value-%d", *seed))
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*seed % 100))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(uint64(*seed % 100))
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(*seed) + 0.5)
+	case reflect.Pointer:
+		if depth >= maxFillDepth {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		fillValue(p.Elem(), seed, depth+1)
+		v.Set(p)
+	case reflect.Slice:
+		if depth >= maxFillDepth {
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fillValue(s.Index(0), seed, depth+1)
+		v.Set(s)
+	case reflect.Map:
+		if depth >= maxFillDepth {
+			return
+		}
+		m := reflect.MakeMapWithSize(v.Type(), 1)
+		key := reflect.New(v.Type().Key()).Elem()
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillValue(key, seed, depth+1)
+		fillValue(elem, seed, depth+1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeFor[time.Time]() {
+			v.Set(reflect.ValueOf(time.Unix(1_700_000_000+int64(*seed), 0).UTC()))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillValue(v.Field(i), seed, depth)
+			}
+		}
+	}
+}
+
+// ConverterCoverageChecks checks the package's v2 converters against the
+// fields of the SDK types they convert, as vendored in go.mod. After an SDK
+// upgrade, any new field shows up as Unmapped until it is either mapped or
+// added to the converter's ignore list.
+func ConverterCoverageChecks() ([]ConverterCoverage, error) {
+	checks := []func() (ConverterCoverage, error){
+		func() (ConverterCoverage, error) {
+			return CheckConverterCoverage("ec2 Tag", func(tag ec2types.Tag) any {
+				return tagsFromV2([]ec2types.Tag{tag})
+			})
+		},
+		func() (ConverterCoverage, error) {
+			// The comparer only normalizes identity, type, state, launch
+			// time and tags.
+			return CheckConverterCoverage("ec2 Instance", func(inst ec2types.Instance) any {
+				return normalizeInstanceV2(inst)
+			},
+				"AmiLaunchIndex", "Architecture", "BlockDeviceMappings", "BootMode", "CapacityBlockId",
+				"CapacityReservationId", "CapacityReservationSpecification", "ClientToken", "CpuOptions",
+				"CurrentInstanceBootMode", "EbsOptimized", "ElasticGpuAssociations",
+				"ElasticInferenceAcceleratorAssociations", "EnaSupport", "EnclaveOptions", "HibernationOptions",
+				"Hypervisor", "IamInstanceProfile", "ImageId", "InstanceLifecycle", "Ipv6Address", "KernelId",
+				"KeyName", "Licenses", "MaintenanceOptions", "MetadataOptions", "Monitoring",
+				"NetworkInterfaces", "NetworkPerformanceOptions", "Operator", "OutpostArn", "Placement",
+				"Platform", "PlatformDetails", "PrivateDnsName", "PrivateDnsNameOptions", "PrivateIpAddress",
+				"ProductCodes", "PublicDnsName", "PublicIpAddress", "RamdiskId", "RootDeviceName",
+				"RootDeviceType", "SecurityGroups", "SourceDestCheck", "SpotInstanceRequestId",
+				"SriovNetSupport", "StateReason", "StateTransitionReason", "SubnetId", "TpmSupport",
+				"UsageOperation", "UsageOperationUpdateTime", "VirtualizationType", "VpcId",
+			)
+		},
+	}
+	var out []ConverterCoverage
+	for _, check := range checks {
+		cov, err := check()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cov)
+	}
+	return out, nil
+}
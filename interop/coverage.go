@@ -0,0 +1,110 @@
+package interop
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+)
+
+// FieldCoverage describes one field name of a v1 and v2 result for the same
+// resource: whether each SDK's struct declares it and whether it is set.
+type FieldCoverage struct {
+	Field        string
+	InV1, InV2   bool
+	SetV1, SetV2 bool
+}
+
+// Dropped reports whether the field is populated in one SDK's result but is
+// missing or unset in the other's.
+func (f FieldCoverage) Dropped() bool {
+	return f.SetV1 != f.SetV2
+}
+
+// CompareFieldCoverage compares the exported top-level fields of v1 and v2,
+// which are typically the same resource described by each SDK (for example
+// *ec2.Instance and types.Instance). Pointers are dereferenced first. A field
+// counts as set when it is not its type's zero value, so a nil *string in v1
+// and an empty enum in v2 are both unset. Rows are sorted by field name.
+func CompareFieldCoverage(v1, v2 interface{}) ([]FieldCoverage, error) {
+	fieldsV1, err := structFields(v1)
+	if err != nil {
+		return nil, fmt.Errorf("v1 value: %w", err)
+	}
+	fieldsV2, err := structFields(v2)
+	if err != nil {
+		return nil, fmt.Errorf("v2 value: %w", err)
+	}
+
+	rows := make(map[string]*FieldCoverage)
+	for name, set := range fieldsV1 {
+		rows[name] = &FieldCoverage{Field: name, InV1: true, SetV1: set}
+	}
+	for name, set := range fieldsV2 {
+		row, ok := rows[name]
+		if !ok {
+			row = &FieldCoverage{Field: name}
+			rows[name] = row
+		}
+		row.InV2, row.SetV2 = true, set
+	}
+
+	out := make([]FieldCoverage, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, *row)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out, nil
+}
+
+// structFields returns the exported fields of v, after dereferencing
+// pointers, mapped to whether each is set.
+func structFields(v interface{}) (map[string]bool, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%s is not a struct", rv.Type())
+	}
+	fields := make(map[string]bool)
+	for i := 0; i < rv.NumField(); i++ {
+		f := rv.Type().Field(i)
+		// v1 structs carry an unexported "_ struct{}" and v2 ones an
+		// embedded noSmithyDocumentSerde; neither is a response field.
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		fields[f.Name] = !rv.Field(i).IsZero()
+	}
+	return fields, nil
+}
+
+// WriteFieldCoverage writes rows as an aligned table, marking fields that are
+// set in only one SDK's result.
+func WriteFieldCoverage(w io.Writer, rows []FieldCoverage) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tV1\tV2\t")
+	for _, row := range rows {
+		marker := ""
+		if row.Dropped() {
+			marker = "<- differs"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", row.Field, coverageCell(row.InV1, row.SetV1), coverageCell(row.InV2, row.SetV2), marker)
+	}
+	return tw.Flush()
+}
+
+func coverageCell(declared, set bool) string {
+	switch {
+	case !declared:
+		return "absent"
+	case set:
+		return "set"
+	}
+	return "unset"
+}
@@ -0,0 +1,96 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// credentialKinds maps provider names reported by either SDK, or their
+// prefixes, to a short kind that is comparable across versions.
+var credentialKinds = []struct {
+	prefix, kind string
+}{
+	{"EnvConfigCredentials", "env"},
+	{"EnvProvider", "env"},
+	{"SharedConfigCredentials", "profile"},
+	{"SharedCredentialsProvider", "profile"},
+	{"EC2RoleProvider", "imds"},
+	{"AssumeRoleProvider", "assume-role"},
+	{"WebIdentityCredentials", "web-identity"},
+	{"SSOProvider", "sso"},
+	{"ProcessProvider", "process"},
+	{"CredentialsEndpoint", "container"},
+	{"StaticProvider", "static"},
+	{"StaticCredentials", "static"},
+}
+
+// CredentialSourceKind classifies a provider name, such as v1's
+// credentials.Value.ProviderName or v2's aws.Credentials.Source, as env,
+// profile, imds, assume-role, web-identity, sso, process, container or
+// static. Unrecognized providers are returned unchanged.
+func CredentialSourceKind(provider string) string {
+	for _, k := range credentialKinds {
+		if strings.HasPrefix(provider, k.prefix) {
+			return k.kind
+		}
+	}
+	return provider
+}
+
+// CredentialSource describes where one SDK obtained its credentials.
+type CredentialSource struct {
+	Provider string
+	Kind     string
+}
+
+func (c CredentialSource) String() string {
+	return fmt.Sprintf("%s (%s)", c.Kind, c.Provider)
+}
+
+// CredentialSourceV1 reports which provider supplies the session's
+// credentials. Get retrieves them first if that has not happened yet, so this
+// may make a network call (for example to IMDS or STS).
+func CredentialSourceV1(sess *session.Session) (CredentialSource, error) {
+	value, err := sess.Config.Credentials.Get()
+	if err != nil {
+		return CredentialSource{}, fmt.Errorf("retrieving v1 credentials: %w", err)
+	}
+	return CredentialSource{Provider: value.ProviderName, Kind: CredentialSourceKind(value.ProviderName)}, nil
+}
+
+// CredentialSourceV2 reports which provider supplies the config's
+// credentials, retrieving them through the credential cache if needed.
+func CredentialSourceV2(ctx context.Context, cfg awsv2.Config) (CredentialSource, error) {
+	if cfg.Credentials == nil {
+		return CredentialSource{}, fmt.Errorf("retrieving v2 credentials: no credential provider configured")
+	}
+	creds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return CredentialSource{}, fmt.Errorf("retrieving v2 credentials: %w", err)
+	}
+	return CredentialSource{Provider: creds.Source, Kind: CredentialSourceKind(creds.Source)}, nil
+}
+
+// PrintCredentialSources prints, in verbose mode, the credential source each
+// SDK resolved. Retrieval failures are printed rather than returned, since
+// the subsequent calls will report them anyway.
+func PrintCredentialSources(ctx context.Context, sess *session.Session, cfg awsv2.Config) {
+	if !Verbose {
+		return
+	}
+	if src, err := CredentialSourceV1(sess); err != nil {
+		Verbosef("SDK v1 credentials: %v", err)
+	} else {
+		Verbosef("SDK v1 credentials: %s", src)
+	}
+	if src, err := CredentialSourceV2(ctx, cfg); err != nil {
+		Verbosef("SDK v2 credentials: %v", err)
+	} else {
+		Verbosef("SDK v2 credentials: %s", src)
+	}
+}
@@ -0,0 +1,95 @@
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// DiffJSON compares a and b by their JSON encoding and returns one line per
+// difference, each naming the JSON path where the values diverge. An empty
+// result means both encode to the same document. Marshalling through JSON
+// makes field names, not Go types, the unit of comparison, so values built
+// from v1 and v2 shapes can be diffed once they share a normalized form.
+func DiffJSON(a, b interface{}) ([]string, error) {
+	docA, err := jsonDocument(a)
+	if err != nil {
+		return nil, fmt.Errorf("encoding first value: %w", err)
+	}
+	docB, err := jsonDocument(b)
+	if err != nil {
+		return nil, fmt.Errorf("encoding second value: %w", err)
+	}
+	var diffs []string
+	diffJSONValues("$", docA, docB, &diffs)
+	return diffs, nil
+}
+
+func jsonDocument(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func diffJSONValues(path string, a, b interface{}, diffs *[]string) {
+	switch va := a.(type) {
+	case map[string]interface{}:
+		vb, ok := b.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(va)+len(vb))
+		for k := range va {
+			keys = append(keys, k)
+		}
+		for k := range vb {
+			if _, ok := va[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childA, inA := va[k]
+			childB, inB := vb[k]
+			childPath := path + "." + k
+			switch {
+			case !inA:
+				*diffs = append(*diffs, fmt.Sprintf("%s: only in second (%s)", childPath, jsonString(childB)))
+			case !inB:
+				*diffs = append(*diffs, fmt.Sprintf("%s: only in first (%s)", childPath, jsonString(childA)))
+			default:
+				diffJSONValues(childPath, childA, childB, diffs)
+			}
+		}
+		return
+	case []interface{}:
+		vb, ok := b.([]interface{})
+		if !ok {
+			break
+		}
+		if len(va) != len(vb) {
+			*diffs = append(*diffs, fmt.Sprintf("%s: length %d != %d", path, len(va), len(vb)))
+		}
+		for i := 0; i < len(va) && i < len(vb); i++ {
+			diffJSONValues(fmt.Sprintf("%s[%d]", path, i), va[i], vb[i], diffs)
+		}
+		return
+	}
+	if jsonString(a) != jsonString(b) {
+		*diffs = append(*diffs, fmt.Sprintf("%s: %s != %s", path, jsonString(a), jsonString(b)))
+	}
+}
+
+func jsonString(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
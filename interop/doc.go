@@ -0,0 +1,4 @@
+// Package interop contains helpers shared by the example programs for
+// bridging the AWS SDK for Go v1 and v2, so that the same configuration and
+// data can be used with either SDK during a migration.
+package interop
@@ -0,0 +1,208 @@
+package interop
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	streamstypes "github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+)
+
+// ConvertAttributeValueV1ToV2 converts a v1 AttributeValue, a struct with one
+// pointer field per type, to the v2 union member holding the same value.
+// Lists and maps are converted recursively. A value with no field set is
+// rejected, as DynamoDB would reject it.
+func ConvertAttributeValueV1ToV2(av *dynamodbv1.AttributeValue) (ddbtypes.AttributeValue, error) {
+	if av == nil {
+		return nil, fmt.Errorf("nil attribute value")
+	}
+	switch {
+	case av.S != nil:
+		return &ddbtypes.AttributeValueMemberS{Value: *av.S}, nil
+	case av.N != nil:
+		return &ddbtypes.AttributeValueMemberN{Value: *av.N}, nil
+	case av.B != nil:
+		return &ddbtypes.AttributeValueMemberB{Value: copyBytes(av.B)}, nil
+	case av.BOOL != nil:
+		return &ddbtypes.AttributeValueMemberBOOL{Value: *av.BOOL}, nil
+	case av.NULL != nil:
+		return &ddbtypes.AttributeValueMemberNULL{Value: *av.NULL}, nil
+	case av.SS != nil:
+		return &ddbtypes.AttributeValueMemberSS{Value: aws.StringValueSlice(av.SS)}, nil
+	case av.NS != nil:
+		return &ddbtypes.AttributeValueMemberNS{Value: aws.StringValueSlice(av.NS)}, nil
+	case av.BS != nil:
+		bs := make([][]byte, len(av.BS))
+		for i, b := range av.BS {
+			bs[i] = copyBytes(b)
+		}
+		return &ddbtypes.AttributeValueMemberBS{Value: bs}, nil
+	case av.L != nil:
+		l := make([]ddbtypes.AttributeValue, len(av.L))
+		for i, v := range av.L {
+			converted, err := ConvertAttributeValueV1ToV2(v)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			l[i] = converted
+		}
+		return &ddbtypes.AttributeValueMemberL{Value: l}, nil
+	case av.M != nil:
+		m, err := ConvertAttributeValuesV1ToV2(av.M)
+		if err != nil {
+			return nil, err
+		}
+		return &ddbtypes.AttributeValueMemberM{Value: m}, nil
+	}
+	return nil, fmt.Errorf("attribute value has no type set")
+}
+
+// ConvertAttributeValuesV1ToV2 converts a v1 item or key to its v2 form.
+func ConvertAttributeValuesV1ToV2(item map[string]*dynamodbv1.AttributeValue) (map[string]ddbtypes.AttributeValue, error) {
+	if item == nil {
+		return nil, nil
+	}
+	out := make(map[string]ddbtypes.AttributeValue, len(item))
+	for name, av := range item {
+		converted, err := ConvertAttributeValueV1ToV2(av)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		out[name] = converted
+	}
+	return out, nil
+}
+
+// ConvertAttributeValueV2ToV1 is the inverse of ConvertAttributeValueV1ToV2.
+func ConvertAttributeValueV2ToV1(av ddbtypes.AttributeValue) (*dynamodbv1.AttributeValue, error) {
+	switch v := av.(type) {
+	case *ddbtypes.AttributeValueMemberS:
+		return &dynamodbv1.AttributeValue{S: aws.String(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberN:
+		return &dynamodbv1.AttributeValue{N: aws.String(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberB:
+		// A nil Value is an empty binary in v2 but would leave v1's B unset,
+		// and with it the type, so it becomes an empty slice.
+		return &dynamodbv1.AttributeValue{B: append([]byte{}, v.Value...)}, nil
+	case *ddbtypes.AttributeValueMemberBOOL:
+		return &dynamodbv1.AttributeValue{BOOL: aws.Bool(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberNULL:
+		return &dynamodbv1.AttributeValue{NULL: aws.Bool(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberSS:
+		return &dynamodbv1.AttributeValue{SS: aws.StringSlice(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberNS:
+		return &dynamodbv1.AttributeValue{NS: aws.StringSlice(v.Value)}, nil
+	case *ddbtypes.AttributeValueMemberBS:
+		bs := make([][]byte, len(v.Value))
+		for i, b := range v.Value {
+			bs[i] = copyBytes(b)
+		}
+		return &dynamodbv1.AttributeValue{BS: bs}, nil
+	case *ddbtypes.AttributeValueMemberL:
+		l := make([]*dynamodbv1.AttributeValue, len(v.Value))
+		for i, elem := range v.Value {
+			converted, err := ConvertAttributeValueV2ToV1(elem)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			l[i] = converted
+		}
+		return &dynamodbv1.AttributeValue{L: l}, nil
+	case *ddbtypes.AttributeValueMemberM:
+		m, err := ConvertAttributeValuesV2ToV1(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		if m == nil {
+			// Likewise, a nil map is an empty map attribute.
+			m = map[string]*dynamodbv1.AttributeValue{}
+		}
+		return &dynamodbv1.AttributeValue{M: m}, nil
+	case nil:
+		return nil, fmt.Errorf("nil attribute value")
+	}
+	return nil, fmt.Errorf("unsupported attribute value type %T", av)
+}
+
+// ConvertAttributeValuesV2ToV1 converts a v2 item or key to its v1 form.
+func ConvertAttributeValuesV2ToV1(item map[string]ddbtypes.AttributeValue) (map[string]*dynamodbv1.AttributeValue, error) {
+	if item == nil {
+		return nil, nil
+	}
+	out := make(map[string]*dynamodbv1.AttributeValue, len(item))
+	for name, av := range item {
+		converted, err := ConvertAttributeValueV2ToV1(av)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		out[name] = converted
+	}
+	return out, nil
+}
+
+// ConvertStreamAttributeValueToV2 converts an attribute value from a v2
+// DynamoDB Streams record to the v2 DynamoDB type. The two services declare
+// identical but distinct AttributeValue unions, so stream images cannot be
+// passed to DynamoDB helpers such as attributevalue.UnmarshalMap directly.
+func ConvertStreamAttributeValueToV2(av streamstypes.AttributeValue) (ddbtypes.AttributeValue, error) {
+	switch v := av.(type) {
+	case *streamstypes.AttributeValueMemberS:
+		return &ddbtypes.AttributeValueMemberS{Value: v.Value}, nil
+	case *streamstypes.AttributeValueMemberN:
+		return &ddbtypes.AttributeValueMemberN{Value: v.Value}, nil
+	case *streamstypes.AttributeValueMemberB:
+		return &ddbtypes.AttributeValueMemberB{Value: copyBytes(v.Value)}, nil
+	case *streamstypes.AttributeValueMemberBOOL:
+		return &ddbtypes.AttributeValueMemberBOOL{Value: v.Value}, nil
+	case *streamstypes.AttributeValueMemberNULL:
+		return &ddbtypes.AttributeValueMemberNULL{Value: v.Value}, nil
+	case *streamstypes.AttributeValueMemberSS:
+		return &ddbtypes.AttributeValueMemberSS{Value: append([]string(nil), v.Value...)}, nil
+	case *streamstypes.AttributeValueMemberNS:
+		return &ddbtypes.AttributeValueMemberNS{Value: append([]string(nil), v.Value...)}, nil
+	case *streamstypes.AttributeValueMemberBS:
+		bs := make([][]byte, len(v.Value))
+		for i, b := range v.Value {
+			bs[i] = copyBytes(b)
+		}
+		return &ddbtypes.AttributeValueMemberBS{Value: bs}, nil
+	case *streamstypes.AttributeValueMemberL:
+		l := make([]ddbtypes.AttributeValue, len(v.Value))
+		for i, elem := range v.Value {
+			converted, err := ConvertStreamAttributeValueToV2(elem)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			l[i] = converted
+		}
+		return &ddbtypes.AttributeValueMemberL{Value: l}, nil
+	case *streamstypes.AttributeValueMemberM:
+		m, err := ConvertStreamAttributeValuesToV2(v.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &ddbtypes.AttributeValueMemberM{Value: m}, nil
+	case nil:
+		return nil, fmt.Errorf("nil attribute value")
+	}
+	return nil, fmt.Errorf("unsupported attribute value type %T", av)
+}
+
+// ConvertStreamAttributeValuesToV2 converts a stream record image, such as
+// StreamRecord.NewImage, to a v2 DynamoDB item.
+func ConvertStreamAttributeValuesToV2(image map[string]streamstypes.AttributeValue) (map[string]ddbtypes.AttributeValue, error) {
+	if image == nil {
+		return nil, nil
+	}
+	out := make(map[string]ddbtypes.AttributeValue, len(image))
+	for name, av := range image {
+		converted, err := ConvertStreamAttributeValueToV2(av)
+		if err != nil {
+			return nil, fmt.Errorf("attribute %q: %w", name, err)
+		}
+		out[name] = converted
+	}
+	return out, nil
+}
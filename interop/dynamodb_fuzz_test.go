@@ -0,0 +1,138 @@
+package interop
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	ddbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// FuzzConvertAttributeValues converts items between both SDKs' attribute
+// values. Each input is an item in DynamoDB JSON, such as
+// {"id":{"S":"a"},"n":{"N":"1.50"}}, decoded into v1 values; inputs that
+// are not valid items are skipped. A v1 item must come back from v2
+// unchanged, and its v2 form, with every empty collection also left nil as
+// v2 allows, must convert back to the same v1 item. The seed corpus is in
+// testdata/fuzz/FuzzConvertAttributeValues; run
+//
+//	go test ./interop -run '^$' -fuzz FuzzConvertAttributeValues
+//
+// to look for more.
+func FuzzConvertAttributeValues(f *testing.F) {
+	f.Fuzz(func(t *testing.T, data string) {
+		var item map[string]*dynamodbv1.AttributeValue
+		if err := json.Unmarshal([]byte(data), &item); err != nil || item == nil || !validItemV1(item) {
+			t.Skip()
+		}
+
+		v2, err := ConvertAttributeValuesV1ToV2(item)
+		if err != nil {
+			t.Fatalf("v1 to v2: %v", err)
+		}
+		back, err := ConvertAttributeValuesV2ToV1(v2)
+		if err != nil {
+			t.Fatalf("v2 to v1: %v", err)
+		}
+		if !reflect.DeepEqual(back, item) {
+			t.Fatalf("v1 round trip returned\n  %s\nwant\n  %s", jsonString(back), jsonString(item))
+		}
+
+		// v2 may leave an empty collection nil, which v1 cannot: a v1
+		// collection is typed by being set. The converter must turn it
+		// into an empty one.
+		fromNil, err := ConvertAttributeValuesV2ToV1(nilEmptyItemV2(v2))
+		if err != nil {
+			t.Fatalf("v2 with nil collections to v1: %v", err)
+		}
+		if !reflect.DeepEqual(fromNil, item) {
+			t.Fatalf("v2 with nil collections returned\n  %s\nwant\n  %s", jsonString(fromNil), jsonString(item))
+		}
+	})
+}
+
+// validItemV1 reports whether every value of item has exactly one type set
+// and no nil elements, as DynamoDB requires.
+func validItemV1(item map[string]*dynamodbv1.AttributeValue) bool {
+	for _, av := range item {
+		if !validValueV1(av) {
+			return false
+		}
+	}
+	return true
+}
+
+func validValueV1(av *dynamodbv1.AttributeValue) bool {
+	if av == nil {
+		return false
+	}
+	set := 0
+	for _, isSet := range []bool{av.S != nil, av.N != nil, av.B != nil, av.BOOL != nil, av.NULL != nil,
+		av.SS != nil, av.NS != nil, av.BS != nil, av.L != nil, av.M != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return false
+	}
+	for _, s := range append(av.SS, av.NS...) {
+		if s == nil {
+			return false
+		}
+	}
+	for _, elem := range av.L {
+		if !validValueV1(elem) {
+			return false
+		}
+	}
+	return av.M == nil || validItemV1(av.M)
+}
+
+// nilEmptyItemV2 returns a copy of item in which every empty binary, set,
+// list and map is nil.
+func nilEmptyItemV2(item map[string]ddbtypes.AttributeValue) map[string]ddbtypes.AttributeValue {
+	out := make(map[string]ddbtypes.AttributeValue, len(item))
+	for name, av := range item {
+		out[name] = nilEmptyValueV2(av)
+	}
+	return out
+}
+
+func nilEmptyValueV2(av ddbtypes.AttributeValue) ddbtypes.AttributeValue {
+	switch v := av.(type) {
+	case *ddbtypes.AttributeValueMemberB:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberB{}
+		}
+	case *ddbtypes.AttributeValueMemberSS:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberSS{}
+		}
+	case *ddbtypes.AttributeValueMemberNS:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberNS{}
+		}
+	case *ddbtypes.AttributeValueMemberBS:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberBS{}
+		}
+	case *ddbtypes.AttributeValueMemberL:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberL{}
+		}
+		l := make([]ddbtypes.AttributeValue, len(v.Value))
+		for i, elem := range v.Value {
+			l[i] = nilEmptyValueV2(elem)
+		}
+		return &ddbtypes.AttributeValueMemberL{Value: l}
+	case *ddbtypes.AttributeValueMemberM:
+		if len(v.Value) == 0 {
+			return &ddbtypes.AttributeValueMemberM{}
+		}
+		return &ddbtypes.AttributeValueMemberM{Value: nilEmptyItemV2(v.Value)}
+	}
+	return av
+}
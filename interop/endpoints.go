@@ -0,0 +1,114 @@
+package interop
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// v2ServiceIDToV1 maps v2 service IDs, as passed to legacy endpoint
+// resolvers, to the v1 endpoints IDs where the two cannot be derived from
+// each other by lowercasing.
+var v2ServiceIDToV1 = map[string]string{
+	"CloudWatch":             "monitoring",
+	"CloudWatch Logs":        "logs",
+	"DynamoDB Streams":       "streams.dynamodb",
+	"ECR":                    "api.ecr",
+	"Elastic Load Balancing": "elasticloadbalancing",
+	"S3 Control":             "s3-control",
+	"SFN":                    "states",
+}
+
+// V1EndpointsID returns the v1 endpoints ID for a v2 service ID, for example
+// "logs" for "CloudWatch Logs". Unknown services are lowercased with spaces
+// removed, which matches most services.
+func V1EndpointsID(serviceID string) string {
+	if id, ok := v2ServiceIDToV1[serviceID]; ok {
+		return id
+	}
+	return strings.ToLower(strings.ReplaceAll(serviceID, " ", ""))
+}
+
+// EndpointResolverV2FromV1 adapts a v1 endpoints.Resolver so that the same
+// overrides can be passed to v2 with config.WithEndpointResolverWithOptions.
+// The opts are applied to every resolution, which is how FIPS or dual-stack
+// selection is carried over.
+//
+// The translation has known limitations:
+//   - v2 service IDs are mapped to v1 endpoints IDs with V1EndpointsID, so
+//     services missing from its table may resolve to the wrong ID.
+//   - Per-call options that v2 passes to legacy resolvers, such as the
+//     service's EndpointResolverOptions, are ignored; FIPS and dual-stack
+//     must be set through opts instead.
+//   - Endpoints returned this way take v2's legacy resolution path, which
+//     bypasses EndpointResolverV2 rules such as S3 Express or access-point
+//     routing.
+//   - SigningNameDerived has no v2 equivalent and is dropped.
+//
+// When the v1 resolver does not know the service or region, an
+// aws.EndpointNotFoundError is returned so that v2 falls back to its own
+// default resolution.
+func EndpointResolverV2FromV1(resolver endpoints.Resolver, opts ...func(*endpoints.Options)) awsv2.EndpointResolverWithOptions {
+	return awsv2.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (awsv2.Endpoint, error) {
+		resolved, err := resolver.EndpointFor(V1EndpointsID(service), region, opts...)
+		if err != nil {
+			var notFound endpoints.EndpointNotFoundError
+			var unknown endpoints.UnknownServiceError
+			if errors.As(err, &notFound) || errors.As(err, &unknown) {
+				return awsv2.Endpoint{}, &awsv2.EndpointNotFoundError{Err: err}
+			}
+			return awsv2.Endpoint{}, err
+		}
+		return awsv2.Endpoint{
+			URL:           resolved.URL,
+			PartitionID:   resolved.PartitionID,
+			SigningName:   resolved.SigningName,
+			SigningRegion: resolved.SigningRegion,
+			SigningMethod: resolved.SigningMethod,
+			Source:        awsv2.EndpointSourceCustom,
+		}, nil
+	})
+}
+
+// StaticEndpoints is a fixed map of v1 endpoints IDs (for example "s3") to
+// endpoint URLs. It produces matching resolvers for both SDKs, so that the
+// same overrides are applied consistently. Signing names are left empty so
+// that each SDK uses the service's default.
+type StaticEndpoints map[string]string
+
+// V1 returns a v1 resolver that serves the static endpoints and delegates
+// everything else to fallback. A nil fallback uses endpoints.DefaultResolver.
+func (s StaticEndpoints) V1(fallback endpoints.Resolver) endpoints.Resolver {
+	if fallback == nil {
+		fallback = endpoints.DefaultResolver()
+	}
+	return endpoints.ResolverFunc(func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+		url, ok := s[service]
+		if !ok {
+			return fallback.EndpointFor(service, region, opts...)
+		}
+		return endpoints.ResolvedEndpoint{
+			URL:           url,
+			SigningRegion: region,
+		}, nil
+	})
+}
+
+// V2 returns a v2 resolver that serves the static endpoints and lets v2 fall
+// back to its default resolution for every other service.
+func (s StaticEndpoints) V2() awsv2.EndpointResolverWithOptions {
+	return awsv2.EndpointResolverWithOptionsFunc(func(service, region string, _ ...interface{}) (awsv2.Endpoint, error) {
+		url, ok := s[V1EndpointsID(service)]
+		if !ok {
+			return awsv2.Endpoint{}, &awsv2.EndpointNotFoundError{}
+		}
+		return awsv2.Endpoint{
+			URL:           url,
+			SigningRegion: region,
+			Source:        awsv2.EndpointSourceCustom,
+		}, nil
+	})
+}
@@ -0,0 +1,75 @@
+package interop
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// notFoundCodes lists error codes that services use to say a resource does
+// not exist. Both SDKs report the same codes; only the error types differ.
+var notFoundCodes = map[string]bool{
+	"NotFound":                                true,
+	"NoSuchBucket":                            true,
+	"NoSuchKey":                               true,
+	"ResourceNotFoundException":               true,
+	"InvalidInstanceID.NotFound":              true,
+	"InvalidVpcID.NotFound":                   true,
+	"InvalidSubnetID.NotFound":                true,
+	"InvalidGroup.NotFound":                   true,
+	"InvalidKeyPair.NotFound":                 true,
+	"InvalidVolume.NotFound":                  true,
+	"InvalidAllocationID.NotFound":            true,
+	"LoadBalancerNotFound":                    true,
+	"NoSuchEntity":                            true,
+	"AWS.SimpleQueueService.NonExistentQueue": true,
+}
+
+// IsNotFound reports whether err, returned by either SDK, means the requested
+// resource does not exist. It recognizes v1 awserr.Error and v2
+// smithy.APIError codes, and falls back to a 404 status for responses without
+// a body, such as S3 HeadBucket and HeadObject.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	// SDK v1
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		if notFoundCodes[aerr.Code()] {
+			return true
+		}
+		var reqErr awserr.RequestFailure
+		if errors.As(err, &reqErr) && reqErr.StatusCode() == http.StatusNotFound {
+			return true
+		}
+		return false
+	}
+
+	// SDK v2
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) && notFoundCodes[apiErr.ErrorCode()] {
+		return true
+	}
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
+
+// ErrorCode returns the service error code of err, returned by either SDK,
+// or "" when err carries none, as with network errors.
+func ErrorCode(err error) string {
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		return aerr.Code()
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCode()
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package interop
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// OutputJSONL selects a stream of JSON lines, one per event, for programs
+// whose -output flag supports it.
+const OutputJSONL = "jsonl"
+
+// Event types, in the "event" field of each line.
+const (
+	EventStep    = "step"
+	EventSummary = "summary"
+)
+
+// StepEvent reports the outcome of one step of a program. DurationMS is how
+// long the step took, in milliseconds.
+type StepEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Step       string    `json:"step"`
+	Status     string    `json:"status"`
+	Resource   string    `json:"resource,omitempty"`
+	DurationMS int64     `json:"duration_ms"`
+	Detail     string    `json:"detail,omitempty"`
+}
+
+// SummaryEvent is the last line of a stream. Status is the outcome of the
+// whole run and DurationMS its total duration.
+type SummaryEvent struct {
+	Event      string    `json:"event"`
+	Time       time.Time `json:"time"`
+	Status     string    `json:"status"`
+	Steps      int       `json:"steps"`
+	Passed     int       `json:"passed"`
+	Warned     int       `json:"warned"`
+	Failed     int       `json:"failed"`
+	DurationMS int64     `json:"duration_ms"`
+}
+
+// EventStream writes events as JSON lines, so that automation can follow a
+// long run as it progresses instead of waiting for a single document at the
+// end. Each line is written with one Write call and flushed before Emit or
+// Summary returns. It is safe for concurrent use.
+type EventStream struct {
+	mu    sync.Mutex
+	w     io.Writer
+	start time.Time
+}
+
+// NewEventStream returns a stream writing to w. Durations in the summary
+// count from this call.
+func NewEventStream(w io.Writer) *EventStream {
+	return &EventStream{w: w, start: time.Now()}
+}
+
+// Emit writes a step event. Event and, if zero, Time are filled in.
+func (s *EventStream) Emit(e StepEvent) error {
+	e.Event = EventStep
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	return s.writeLine(e)
+}
+
+// Summary writes the final summary line. Event, Time and DurationMS are
+// filled in.
+func (s *EventStream) Summary(e SummaryEvent) error {
+	e.Event = EventSummary
+	e.Time = time.Now()
+	e.DurationMS = e.Time.Sub(s.start).Milliseconds()
+	return s.writeLine(e)
+}
+
+func (s *EventStream) writeLine(v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.w.Write(line); err != nil {
+		return err
+	}
+	// Buffered writers, such as a bufio.Writer around a pipe, would
+	// otherwise hold lines back until they fill up.
+	if f, ok := s.w.(interface{ Flush() error }); ok {
+		return f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package interop
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// HeadBucketAPIV1 is the part of the v1 S3 client used by BucketExistsV1.
+type HeadBucketAPIV1 interface {
+	HeadBucketWithContext(aws.Context, *s3v1.HeadBucketInput, ...request.Option) (*s3v1.HeadBucketOutput, error)
+}
+
+// HeadBucketAPIV2 is the part of the v2 S3 client used by BucketExistsV2.
+type HeadBucketAPIV2 interface {
+	HeadBucket(context.Context, *s3v2.HeadBucketInput, ...func(*s3v2.Options)) (*s3v2.HeadBucketOutput, error)
+}
+
+// DescribeTableAPIV1 is the part of the v1 DynamoDB client used by TableExistsV1.
+type DescribeTableAPIV1 interface {
+	DescribeTableWithContext(aws.Context, *dynamodbv1.DescribeTableInput, ...request.Option) (*dynamodbv1.DescribeTableOutput, error)
+}
+
+// DescribeTableAPIV2 is the part of the v2 DynamoDB client used by TableExistsV2.
+type DescribeTableAPIV2 interface {
+	DescribeTable(context.Context, *dynamodbv2.DescribeTableInput, ...func(*dynamodbv2.Options)) (*dynamodbv2.DescribeTableOutput, error)
+}
+
+// DescribeInstancesAPIV1 is the part of the v1 EC2 client used by InstanceExistsV1.
+type DescribeInstancesAPIV1 interface {
+	DescribeInstancesWithContext(aws.Context, *ec2v1.DescribeInstancesInput, ...request.Option) (*ec2v1.DescribeInstancesOutput, error)
+}
+
+// DescribeInstancesAPIV2 is the part of the v2 EC2 client used by InstanceExistsV2.
+type DescribeInstancesAPIV2 interface {
+	DescribeInstances(context.Context, *ec2v2.DescribeInstancesInput, ...func(*ec2v2.Options)) (*ec2v2.DescribeInstancesOutput, error)
+}
+
+// BucketExistsV1 reports whether bucket exists and is accessible. A
+// not-found response is reported as (false, nil); any other failure,
+// including access denied, is returned as an error.
+func BucketExistsV1(ctx context.Context, client HeadBucketAPIV1, bucket string) (bool, error) {
+	_, err := client.HeadBucketWithContext(ctx, &s3v1.HeadBucketInput{Bucket: aws.String(bucket)})
+	return existsResult(err)
+}
+
+// BucketExistsV2 is BucketExistsV1 for a v2 client.
+func BucketExistsV2(ctx context.Context, client HeadBucketAPIV2, bucket string) (bool, error) {
+	_, err := client.HeadBucket(ctx, &s3v2.HeadBucketInput{Bucket: aws.String(bucket)})
+	return existsResult(err)
+}
+
+// TableExistsV1 reports whether the DynamoDB table exists, as BucketExistsV1
+// does for buckets. A table that is still being created or deleted counts as
+// existing.
+func TableExistsV1(ctx context.Context, client DescribeTableAPIV1, table string) (bool, error) {
+	_, err := client.DescribeTableWithContext(ctx, &dynamodbv1.DescribeTableInput{TableName: aws.String(table)})
+	return existsResult(err)
+}
+
+// TableExistsV2 is TableExistsV1 for a v2 client.
+func TableExistsV2(ctx context.Context, client DescribeTableAPIV2, table string) (bool, error) {
+	_, err := client.DescribeTable(ctx, &dynamodbv2.DescribeTableInput{TableName: aws.String(table)})
+	return existsResult(err)
+}
+
+// InstanceExistsV1 reports whether the EC2 instance exists, as
+// BucketExistsV1 does for buckets. Recently terminated instances are still
+// returned by EC2 and so count as existing.
+func InstanceExistsV1(ctx context.Context, client DescribeInstancesAPIV1, instanceID string) (bool, error) {
+	out, err := client.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{
+		InstanceIds: aws.StringSlice([]string{instanceID}),
+	})
+	if err != nil {
+		return existsResult(err)
+	}
+	return len(out.Reservations) > 0, nil
+}
+
+// InstanceExistsV2 is InstanceExistsV1 for a v2 client.
+func InstanceExistsV2(ctx context.Context, client DescribeInstancesAPIV2, instanceID string) (bool, error) {
+	out, err := client.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return existsResult(err)
+	}
+	return len(out.Reservations) > 0, nil
+}
+
+// existsResult maps the error of a describe or head call onto the result of
+// an Exists function: no error means the resource exists, a not-found error
+// that it does not, and any other error is returned.
+func existsResult(err error) (bool, error) {
+	switch {
+	case err == nil:
+		return true, nil
+	case IsNotFound(err):
+		return false, nil
+	default:
+		return false, err
+	}
+}
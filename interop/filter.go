@@ -0,0 +1,90 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrOperationBlocked is wrapped by the error returned for operations that
+// an OperationFilter rejects.
+var ErrOperationBlocked = errors.New("operation blocked")
+
+// OperationFilter rejects operations by name before any request is sent.
+// Deny entries are exact operation names, or prefixes when they end in "*"
+// (for example "Delete*").
+type OperationFilter struct {
+	Deny []string
+}
+
+// ReadOnly blocks every operation that creates, changes or removes resources,
+// so that examples can be pointed at a production account safely. Batch*,
+// Execute* and Transact* are blocked whole, reads included, since their
+// operations mix reads and writes (BatchGetItem and BatchWriteItem,
+// ExecuteStatement with SELECT or DELETE).
+var ReadOnly = OperationFilter{
+	Deny: []string{
+		"Abort*", "Allocate*", "Associate*", "Attach*", "Authorize*",
+		"Batch*", "Cancel*", "Change*", "Complete*", "Copy*",
+		"Create*", "Delete*", "Deregister*", "Detach*", "Disable*",
+		"Disassociate*", "Enable*", "Execute*", "Import*", "Modify*",
+		"Publish*", "Purge*", "Put*", "Reboot*", "Register*",
+		"Release*", "Remove*", "Replace*", "Reset*", "Restore*",
+		"Revoke*", "Run*", "Send*", "Set*", "Start*", "Stop*", "Tag*",
+		"Terminate*", "Transact*", "Untag*", "Update*", "Upload*",
+	},
+}
+
+// Blocked reports whether the filter rejects the named operation.
+func (f OperationFilter) Blocked(operation string) bool {
+	for _, pattern := range f.Deny {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(operation, prefix) {
+				return true
+			}
+		} else if operation == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+func (f OperationFilter) blockedError(service, operation string) error {
+	return fmt.Errorf("%w: %s.%s is not allowed in read-only mode", ErrOperationBlocked, service, operation)
+}
+
+// InstallV1 adds the filter to the Validate handlers of sess, so that every
+// client created from it fails blocked operations before they are sent.
+func (f OperationFilter) InstallV1(sess *session.Session) {
+	sess.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "interop.OperationFilter",
+		Fn: func(r *request.Request) {
+			if f.Blocked(r.Operation.Name) {
+				r.Error = f.blockedError(r.ClientInfo.ServiceName, r.Operation.Name)
+			}
+		},
+	})
+}
+
+// InstallV2 adds the filter as an Initialize middleware to every client
+// created from cfg, so that blocked operations fail before serialization.
+func (f OperationFilter) InstallV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("interop.OperationFilter",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				operation := awsmiddleware.GetOperationName(ctx)
+				if f.Blocked(operation) {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, f.blockedError(awsmiddleware.GetServiceID(ctx), operation)
+				}
+				return next.HandleInitialize(ctx, in)
+			}), middleware.After)
+	})
+}
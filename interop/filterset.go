@@ -0,0 +1,80 @@
+package interop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// FilterSet is an SDK-neutral set of EC2 Describe* filters, mapping each
+// filter name to the values it accepts. A resource matches when it matches
+// every filter, and a filter when it matches any of its values. Build it once
+// and pass V1() or V2() to whichever SDK makes the call.
+type FilterSet map[string][]string
+
+// Add appends values to the named filter and returns the set, so that calls
+// can be chained.
+func (f FilterSet) Add(name string, values ...string) FilterSet {
+	f[name] = append(f[name], values...)
+	return f
+}
+
+// Tag adds a "tag:key" filter matching resources whose tag key has one of
+// values.
+func (f FilterSet) Tag(key string, values ...string) FilterSet {
+	return f.Add("tag:"+key, values...)
+}
+
+// names returns the filter names in sorted order, so that both SDKs send
+// the filters in the same order.
+func (f FilterSet) names() []string {
+	names := make([]string, 0, len(f))
+	for name := range f {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// V1 returns the filters as v1 expects them.
+func (f FilterSet) V1() []*ec2v1.Filter {
+	if len(f) == 0 {
+		return nil
+	}
+	out := make([]*ec2v1.Filter, 0, len(f))
+	for _, name := range f.names() {
+		out = append(out, &ec2v1.Filter{
+			Name:   aws.String(name),
+			Values: aws.StringSlice(f[name]),
+		})
+	}
+	return out
+}
+
+// V2 returns the filters as v2 expects them.
+func (f FilterSet) V2() []ec2types.Filter {
+	if len(f) == 0 {
+		return nil
+	}
+	out := make([]ec2types.Filter, 0, len(f))
+	for _, name := range f.names() {
+		out = append(out, ec2types.Filter{
+			Name:   aws.String(name),
+			Values: append([]string(nil), f[name]...),
+		})
+	}
+	return out
+}
+
+func (f FilterSet) String() string {
+	parts := make([]string, 0, len(f))
+	for _, name := range f.names() {
+		parts = append(parts, fmt.Sprintf("%s=%s", name, strings.Join(f[name], ",")))
+	}
+	return strings.Join(parts, " ")
+}
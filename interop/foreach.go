@@ -0,0 +1,37 @@
+package interop
+
+import "sync"
+
+// DefaultWorkers is the number of concurrent calls cleanups make: enough to
+// speed up accounts with many resources, few enough to stay clear of the
+// request rate limits of S3 and EC2.
+const DefaultWorkers = 8
+
+// ForEachConcurrently calls fn for every item, with at most workers calls
+// running at once, and returns once every call has returned. The returned
+// slice has one error per item, in the order of items: errs[i] is what
+// fn(items[i]) returned, nil when it succeeded. A failed call does not stop
+// the others; errors.Join(errs...) is nil when every call succeeded.
+// Workers below 1 run the calls one at a time.
+func ForEachConcurrently[T any](items []T, workers int, fn func(T) error) []error {
+	errs := make([]error, len(items))
+	workers = max(1, min(workers, len(items)))
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				errs[i] = fn(items[i])
+			}
+		}()
+	}
+	for i := range items {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return errs
+}
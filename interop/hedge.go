@@ -0,0 +1,73 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// HedgeResult is the outcome of one hedged describe. Winner is "v1" or "v2",
+// whichever SDK answered first without an error, and Value its normalized
+// result. Err is set only when both SDKs failed.
+type HedgeResult struct {
+	Name    string
+	Winner  string
+	Value   any
+	Latency time.Duration
+	Err     error
+}
+
+// Hedge issues the comparer's describe call with both SDKs at once and
+// returns the first successful answer, normalized, so that a read is as fast
+// as the faster SDK. The other call's context is cancelled as soon as a
+// winner is known, and Hedge waits for it to return so that no request
+// outlives the call. When the first answer is an error the other SDK still
+// gets the chance to win.
+func Hedge(ctx context.Context, c *Clients, sc ServiceComparer) HedgeResult {
+	type answer struct {
+		sdk     string
+		out     any
+		err     error
+		latency time.Duration
+	}
+	ctxV1, cancelV1 := context.WithCancel(ctx)
+	defer cancelV1()
+	ctxV2, cancelV2 := context.WithCancel(ctx)
+	defer cancelV2()
+
+	start := time.Now()
+	answers := make(chan answer, 2)
+	go func() {
+		out, err := sc.DescribeV1(ctxV1, c)
+		answers <- answer{"v1", out, err, time.Since(start)}
+	}()
+	go func() {
+		out, err := sc.DescribeV2(ctxV2, c)
+		answers <- answer{"v2", out, err, time.Since(start)}
+	}()
+
+	result := HedgeResult{Name: sc.Name()}
+	var errs []error
+	for range 2 {
+		a := <-answers
+		if result.Winner != "" {
+			// The loser, usually returning a cancellation error.
+			continue
+		}
+		if a.err != nil {
+			errs = append(errs, fmt.Errorf("describing with %s: %w", a.sdk, a.err))
+			continue
+		}
+		result.Winner, result.Value, result.Latency = a.sdk, sc.Normalize(a.out), a.latency
+		if a.sdk == "v1" {
+			cancelV2()
+		} else {
+			cancelV1()
+		}
+	}
+	if result.Winner == "" {
+		result.Err = errors.Join(errs...)
+	}
+	return result
+}
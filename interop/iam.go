@@ -0,0 +1,132 @@
+package interop
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aws/smithy-go"
+)
+
+// iamPrefixes maps SDK service names to IAM service prefixes where the two
+// differ. Service names are SDK package names, or v2 service IDs lowercased
+// without spaces, which are the same for every service listed here.
+var iamPrefixes = map[string]string{
+	"cloudwatchlogs":         "logs",
+	"dynamodbstreams":        "dynamodb",
+	"elb":                    "elasticloadbalancing",
+	"elbv2":                  "elasticloadbalancing",
+	"elasticloadbalancingv2": "elasticloadbalancing",
+	"sfn":                    "states",
+	"sesv2":                  "ses",
+}
+
+// iamActions maps operations, as prefix:Operation, to the IAM action that
+// authorizes them where the names differ. Every other operation is
+// authorized by the action of the same name.
+var iamActions = map[string]string{
+	"s3:ListBuckets":                        "s3:ListAllMyBuckets",
+	"s3:HeadBucket":                         "s3:ListBucket",
+	"s3:ListObjects":                        "s3:ListBucket",
+	"s3:ListObjectsV2":                      "s3:ListBucket",
+	"s3:ListObjectVersions":                 "s3:ListBucketVersions",
+	"s3:ListMultipartUploads":               "s3:ListBucketMultipartUploads",
+	"s3:HeadObject":                         "s3:GetObject",
+	"s3:SelectObjectContent":                "s3:GetObject",
+	"s3:DeleteObjects":                      "s3:DeleteObject",
+	"s3:CreateMultipartUpload":              "s3:PutObject",
+	"s3:UploadPart":                         "s3:PutObject",
+	"s3:CompleteMultipartUpload":            "s3:PutObject",
+	"s3:GetBucketCors":                      "s3:GetBucketCORS",
+	"s3:PutBucketCors":                      "s3:PutBucketCORS",
+	"s3:DeleteBucketCors":                   "s3:PutBucketCORS",
+	"s3:GetBucketLifecycleConfiguration":    "s3:GetLifecycleConfiguration",
+	"s3:PutBucketLifecycleConfiguration":    "s3:PutLifecycleConfiguration",
+	"s3:DeleteBucketLifecycle":              "s3:PutLifecycleConfiguration",
+	"s3:GetBucketNotificationConfiguration": "s3:GetBucketNotification",
+	"s3:PutBucketNotificationConfiguration": "s3:PutBucketNotification",
+	"s3:GetObjectLockConfiguration":         "s3:GetBucketObjectLockConfiguration",
+	"s3:PutObjectLockConfiguration":         "s3:PutBucketObjectLockConfiguration",
+	"s3:DeleteBucketOwnershipControls":      "s3:PutBucketOwnershipControls",
+	"s3:GetBucketEncryption":                "s3:GetEncryptionConfiguration",
+	"s3:PutBucketEncryption":                "s3:PutEncryptionConfiguration",
+}
+
+// IAMAction returns the IAM action that authorizes operation of service,
+// such as "s3:ListAllMyBuckets" for S3 ListBuckets. service is an SDK
+// package name such as "s3" or "cloudwatchlogs", or a v2 service ID such as
+// "CloudWatch Logs".
+func IAMAction(service, operation string) string {
+	prefix := strings.ToLower(strings.ReplaceAll(service, " ", ""))
+	if p, ok := iamPrefixes[prefix]; ok {
+		prefix = p
+	}
+	action := prefix + ":" + operation
+	if a, ok := iamActions[action]; ok {
+		return a
+	}
+	return action
+}
+
+// notAuthorizedRE matches the action named in AccessDenied messages such as
+// "User: arn:aws:iam::123456789012:user/dev is not authorized to perform:
+// ec2:DescribeKeyPairs".
+var notAuthorizedRE = regexp.MustCompile(`not authorized to perform:? ([a-z0-9-]+:[A-Za-z0-9]+)`)
+
+// AccessDeniedAction returns the IAM action that err, returned by either
+// SDK, was denied. The action is taken from the error message when the
+// service names it, and otherwise derived from the operation recorded in v2
+// errors; v1 errors do not record it, so ok is false for v1 errors whose
+// message does not name the action, as with S3.
+func AccessDeniedAction(err error) (action string, ok bool) {
+	n := NormalizeError(err)
+	if n == nil || n.Kind != ErrorKindAccessDenied {
+		return "", false
+	}
+	if m := notAuthorizedRE.FindStringSubmatch(n.Message); m != nil {
+		return m[1], true
+	}
+	var opErr *smithy.OperationError
+	if errors.As(err, &opErr) {
+		return IAMAction(opErr.Service(), opErr.Operation()), true
+	}
+	return "", false
+}
+
+// IAMPolicy is an identity-based IAM policy document.
+type IAMPolicy struct {
+	Version   string
+	Statement []IAMStatement
+}
+
+// IAMStatement is one statement of an IAMPolicy.
+type IAMStatement struct {
+	Effect   string
+	Action   []string
+	Resource string
+}
+
+// NewIAMPolicy returns a policy allowing actions on every resource, with
+// duplicate actions removed and the rest sorted.
+func NewIAMPolicy(actions []string) IAMPolicy {
+	seen := make(map[string]bool, len(actions))
+	var sorted []string
+	for _, a := range actions {
+		if !seen[a] {
+			seen[a] = true
+			sorted = append(sorted, a)
+		}
+	}
+	sort.Strings(sorted)
+	return IAMPolicy{
+		Version:   "2012-10-17",
+		Statement: []IAMStatement{{Effect: "Allow", Action: sorted, Resource: "*"}},
+	}
+}
+
+// JSON returns the policy as indented JSON that IAM accepts as is.
+func (p IAMPolicy) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
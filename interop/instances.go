@@ -0,0 +1,99 @@
+package interop
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// InstanceOrder is the order EC2 instances are sorted in before they are
+// compared or printed. DescribeInstances returns them in no particular
+// order, and not always in the same one, so results are sorted to make
+// diffs and golden output stable.
+type InstanceOrder string
+
+const (
+	// InstanceOrderID sorts instances by instance ID.
+	InstanceOrderID InstanceOrder = "id"
+	// InstanceOrderLaunchTime sorts instances from the oldest launch.
+	InstanceOrderLaunchTime InstanceOrder = "launch-time"
+	// InstanceOrderType sorts instances by instance type name.
+	InstanceOrderType InstanceOrder = "type"
+)
+
+// InstanceOrders lists the valid InstanceOrder values.
+var InstanceOrders = []InstanceOrder{InstanceOrderID, InstanceOrderLaunchTime, InstanceOrderType}
+
+// SortInstancesBy is the order the ec2 comparer sorts instances in.
+var SortInstancesBy = InstanceOrderID
+
+// ParseInstanceOrder returns the InstanceOrder named s, as accepted by the
+// -sort-by flags.
+func ParseInstanceOrder(s string) (InstanceOrder, error) {
+	for _, o := range InstanceOrders {
+		if string(o) == s {
+			return o, nil
+		}
+	}
+	names := make([]string, len(InstanceOrders))
+	for i, o := range InstanceOrders {
+		names[i] = string(o)
+	}
+	return "", fmt.Errorf("unknown instance order %q (want %s)", s, strings.Join(names, ", "))
+}
+
+// instanceSortKey holds the fields instances are sorted on, so that both
+// SDKs share one ordering.
+type instanceSortKey struct {
+	id, instanceType string
+	launchTime       time.Time
+}
+
+// less orders a before b by o. Ties, such as two instances of one type,
+// are broken by instance ID so that the order is total.
+func (o InstanceOrder) less(a, b instanceSortKey) bool {
+	switch o {
+	case InstanceOrderLaunchTime:
+		if !a.launchTime.Equal(b.launchTime) {
+			return a.launchTime.Before(b.launchTime)
+		}
+	case InstanceOrderType:
+		if a.instanceType != b.instanceType {
+			return a.instanceType < b.instanceType
+		}
+	}
+	return a.id < b.id
+}
+
+// SortInstancesV1 sorts v1 instances in place by o.
+func SortInstancesV1(instances []*ec2v1.Instance, o InstanceOrder) {
+	key := func(i int) instanceSortKey {
+		inst := instances[i]
+		return instanceSortKey{
+			id:           aws.StringValue(inst.InstanceId),
+			instanceType: aws.StringValue(inst.InstanceType),
+			launchTime:   truncateTime(inst.LaunchTime),
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return o.less(key(i), key(j)) })
+}
+
+// SortInstancesV2 sorts v2 instances in place by o, in the same order
+// SortInstancesV1 sorts the same instances described with v1.
+func SortInstancesV2(instances []ec2types.Instance, o InstanceOrder) {
+	key := func(i int) instanceSortKey {
+		inst := instances[i]
+		return instanceSortKey{
+			id:           aws.StringValue(inst.InstanceId),
+			instanceType: string(inst.InstanceType),
+			launchTime:   truncateTime(inst.LaunchTime),
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return o.less(key(i), key(j)) })
+}
@@ -0,0 +1,102 @@
+package interop
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ComparisonResults aggregates the results of several comparers, one per
+// resource type, so they can be summarized together.
+type ComparisonResults []ComparisonResult
+
+// Mismatched returns how many results did not match, because they found
+// differences or failed. Services unavailable in the region are not counted.
+func (rs ComparisonResults) Mismatched() int {
+	n := 0
+	for _, r := range rs {
+		if !r.Match() && !r.Unavailable {
+			n++
+		}
+	}
+	return n
+}
+
+// Unavailable returns how many results are for services unavailable in the
+// region.
+func (rs ComparisonResults) Unavailable() int {
+	n := 0
+	for _, r := range rs {
+		if r.Unavailable {
+			n++
+		}
+	}
+	return n
+}
+
+// RenderMarkdown returns a GitHub-flavored Markdown summary of the results,
+// meant to be pasted into a pull request: a table with one ✓ or ✗ row per
+// resource and its number of differences, followed by a collapsed section
+// listing the differences or error of every resource that did not match.
+// Services unavailable in the region get a row of their own but no section.
+// Resources appear in the order of rs.
+func (rs ComparisonResults) RenderMarkdown() string {
+	var b strings.Builder
+	b.WriteString("| Resource | Result | Differences |\n")
+	b.WriteString("|----------|:------:|------------:|\n")
+	for _, r := range rs {
+		switch {
+		case r.Unavailable:
+			fmt.Fprintf(&b, "| %s | – | unavailable |\n", markdownCell(r.Name))
+		case r.Err != nil:
+			fmt.Fprintf(&b, "| %s | ✗ | error |\n", markdownCell(r.Name))
+		case r.Match():
+			fmt.Fprintf(&b, "| %s | ✓ | 0 |\n", markdownCell(r.Name))
+		default:
+			fmt.Fprintf(&b, "| %s | ✗ | %d |\n", markdownCell(r.Name), len(r.Diffs))
+		}
+	}
+
+	mismatched, unavailable := rs.Mismatched(), rs.Unavailable()
+	switch {
+	case mismatched == 0 && unavailable == 0:
+		fmt.Fprintf(&b, "\n**✓ All %d resources match.**\n", len(rs))
+		return b.String()
+	case mismatched == 0:
+		fmt.Fprintf(&b, "\n**✓ All %d available resources match; %d are not available in the region.**\n", len(rs)-unavailable, unavailable)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "\n**✗ %d of %d resources do not match.**\n", mismatched, len(rs))
+	for _, r := range rs {
+		if r.Match() || r.Unavailable {
+			continue
+		}
+		summary := fmt.Sprintf("%s: %d differences", r.Name, len(r.Diffs))
+		if len(r.Diffs) == 1 {
+			summary = r.Name + ": 1 difference"
+		}
+		lines := r.Diffs
+		if r.Err != nil {
+			summary = r.Name + ": error"
+			lines = []string{r.Err.Error()}
+		}
+		fmt.Fprintf(&b, "\n<details>\n<summary>%s</summary>\n\n```\n", markdownHTML(summary))
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n\n</details>\n")
+	}
+	return b.String()
+}
+
+// markdownCell escapes s for a table cell, where a pipe would end the cell
+// and a newline the row.
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// markdownHTML escapes s for use inside an HTML element such as <summary>.
+func markdownHTML(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
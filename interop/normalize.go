@@ -0,0 +1,165 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+)
+
+// ErrorKind is the broad category of an error, independent of the SDK that
+// returned it.
+type ErrorKind string
+
+const (
+	ErrorKindThrottling   ErrorKind = "throttling"
+	ErrorKindServer       ErrorKind = "server"
+	ErrorKindNotFound     ErrorKind = "not-found"
+	ErrorKindAccessDenied ErrorKind = "access-denied"
+	ErrorKindClient       ErrorKind = "client"
+	ErrorKindCanceled     ErrorKind = "canceled"
+	ErrorKindUnavailable  ErrorKind = "unavailable"
+	ErrorKindUnknown      ErrorKind = "unknown"
+)
+
+// throttlingCodes lists error codes that services use to say a request was
+// throttled. Services that report throttling with a 400 status rely on the
+// code alone.
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottled":                       true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// unknownEndpointCode is the code of the error v1 returns when strict
+// endpoint matching finds no endpoint for a service in a region.
+const unknownEndpointCode = "UnknownEndpointError"
+
+// accessDeniedCodes lists error codes for requests the caller may not make.
+var accessDeniedCodes = map[string]bool{
+	"AccessDenied":          true,
+	"AccessDeniedException": true,
+	"UnauthorizedOperation": true,
+	"Forbidden":             true,
+}
+
+// NormalizedError is an SDK-neutral view of an error returned by either SDK.
+// Fields the error does not carry are left empty: network errors, for
+// instance, have no Code or StatusCode.
+type NormalizedError struct {
+	Kind       ErrorKind
+	Code       string
+	Message    string
+	StatusCode int
+	RequestID  string
+	// Err is the original error.
+	Err error
+}
+
+func (e *NormalizedError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("%s: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%s: %s (status %d): %s", e.Kind, e.Code, e.StatusCode, e.Message)
+}
+
+func (e *NormalizedError) Unwrap() error {
+	return e.Err
+}
+
+// NormalizeError maps err, returned by either SDK, onto a NormalizedError,
+// or returns nil if err is nil. It sees through the wrappers both SDKs add,
+// such as v2's retry.MaxAttemptsError once retries are exhausted, so that
+// the same failure gets the same Kind and Code from both.
+func NormalizeError(err error) *NormalizedError {
+	if err == nil {
+		return nil
+	}
+	n := &NormalizedError{Err: err, Code: ErrorCode(err)}
+
+	// SDK v1
+	var aerr awserr.Error
+	if errors.As(err, &aerr) {
+		n.Message = aerr.Message()
+	}
+	var reqErr awserr.RequestFailure
+	if errors.As(err, &reqErr) {
+		n.StatusCode = reqErr.StatusCode()
+		n.RequestID = reqErr.RequestID()
+	}
+
+	// SDK v2
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		n.Message = apiErr.ErrorMessage()
+	}
+	var respErr *awshttp.ResponseError
+	if errors.As(err, &respErr) {
+		n.StatusCode = respErr.HTTPStatusCode()
+		n.RequestID = respErr.ServiceRequestID()
+	}
+
+	n.Kind = errorKind(err, n)
+	return n
+}
+
+func errorKind(err error, n *NormalizedError) ErrorKind {
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded),
+		n.Code == request.CanceledErrorCode:
+		// v1 reports a canceled context as a RequestCanceled awserr.Error
+		// that does not wrap the context's error.
+		return ErrorKindCanceled
+	case n.Code == unknownEndpointCode, hostNotFound(err):
+		// Neither SDK knows which services exist in which regions: both
+		// build the host from the partition's template, and the lookup of
+		// a service that is absent from the region finds no such host.
+		return ErrorKindUnavailable
+	case throttlingCodes[n.Code], n.StatusCode == http.StatusTooManyRequests:
+		return ErrorKindThrottling
+	case IsNotFound(err):
+		return ErrorKindNotFound
+	case accessDeniedCodes[n.Code], n.StatusCode == http.StatusForbidden:
+		return ErrorKindAccessDenied
+	case n.StatusCode >= 500:
+		return ErrorKindServer
+	case n.StatusCode >= 400:
+		return ErrorKindClient
+	}
+	return ErrorKindUnknown
+}
+
+// hostNotFound reports whether err was caused by a DNS lookup of a host that
+// does not exist. v1 errors hold their cause in OrigErr rather than wrapping
+// it, so their chain is followed too.
+func hostNotFound(err error) bool {
+	for err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) {
+			return dnsErr.IsNotFound
+		}
+		var aerr awserr.Error
+		if !errors.As(err, &aerr) {
+			return false
+		}
+		err = aerr.OrigErr()
+	}
+	return false
+}
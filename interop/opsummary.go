@@ -0,0 +1,115 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// InstallOperationSummaryV1 makes every client created from sess afterwards
+// print one line per call to Output while Verbose is set:
+//
+//	[v1] S3.ListBuckets 48.2ms 200
+//
+// The duration runs from when the request was created until its Complete
+// handlers run, so it covers building, signing and every retry.
+func InstallOperationSummaryV1(sess *session.Session) {
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "interop.OperationSummary",
+		Fn: func(r *request.Request) {
+			if !Verbose {
+				return
+			}
+			var code string
+			var aerr awserr.Error
+			if errors.As(r.Error, &aerr) {
+				code = aerr.Code()
+			}
+			status := 0
+			if r.HTTPResponse != nil {
+				status = r.HTTPResponse.StatusCode
+			}
+			printOperationSummary("v1", r.ClientInfo.ServiceID, r.Operation.Name, time.Since(r.Time), status, code, r.Error, r.RetryCount)
+		},
+	})
+}
+
+// InstallOperationSummaryV2 is InstallOperationSummaryV1 for every client
+// created from cfg afterwards. The middleware runs in the Initialize step,
+// right after the service and operation names are registered, so its
+// duration covers serialization, signing and every retry, like v1's.
+func InstallOperationSummaryV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// Clients without service metadata, such as the IMDS client, have
+		// no names to print and are left out.
+		if _, ok := stack.Initialize.Get("RegisterServiceMetadata"); !ok {
+			return nil
+		}
+		return stack.Initialize.Insert(middleware.InitializeMiddlewareFunc("interop.OperationSummary",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (
+				middleware.InitializeOutput, middleware.Metadata, error,
+			) {
+				if !Verbose {
+					return next.HandleInitialize(ctx, in)
+				}
+				start := time.Now()
+				out, metadata, err := next.HandleInitialize(ctx, in)
+				elapsed := time.Since(start)
+
+				status := 0
+				if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok {
+					status = resp.StatusCode
+				}
+				var respErr *awshttp.ResponseError
+				if errors.As(err, &respErr) {
+					status = respErr.HTTPStatusCode()
+				}
+				var code string
+				var apiErr smithy.APIError
+				if errors.As(err, &apiErr) {
+					code = apiErr.ErrorCode()
+				}
+				retries := 0
+				if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 1 {
+					retries = len(results.Results) - 1
+				}
+				printOperationSummary("v2", awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), elapsed, status, code, err, retries)
+				return out, metadata, err
+			}), "RegisterServiceMetadata", middleware.After)
+	})
+}
+
+// printOperationSummary writes one summary line. The status is the HTTP
+// status code of the last attempt, followed by the error code when the call
+// failed, "failed" and the error when no response was received, or
+// "cached" when a ResultCache answered without sending anything.
+func printOperationSummary(sdk, service, operation string, elapsed time.Duration, status int, code string, err error, retries int) {
+	line := fmt.Sprintf("[%s] %s.%s %s ", sdk, service, operation, elapsed.Round(100*time.Microsecond))
+	switch {
+	case status == 0 && err != nil:
+		line += fmt.Sprintf("failed (%v)", err)
+	case status == 0:
+		line += "cached"
+	case code != "":
+		line += fmt.Sprintf("%d %s", status, code)
+	default:
+		line += fmt.Sprint(status)
+	}
+	if retries > 0 {
+		line += fmt.Sprintf(" after %d retries", retries)
+	}
+	fmt.Fprintln(Output, line)
+}
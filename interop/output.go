@@ -0,0 +1,20 @@
+package interop
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// Output is where the package's report and diagnostic output goes, and where
+// programs built on it write their reports. It defaults to os.Stdout.
+var Output io.Writer = os.Stdout
+
+// SetOutput redirects Output and the standard logger to w, so that a test can
+// capture everything a program prints, or a user can send a report to a
+// file. Without a call, output goes to stdout and log output to stderr as
+// before.
+func SetOutput(w io.Writer) {
+	Output = w
+	log.SetOutput(w)
+}
@@ -0,0 +1,74 @@
+package interop
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TB is the part of testing.TB that AssertSDKParity reports through, so
+// that *testing.T and *testing.B can be passed to it directly. Programs
+// pass a *ParityReport instead.
+type TB interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertSDKParity runs v1fn, a code path written against SDK v1, and v2fn,
+// its rewrite against SDK v2, and reports through t unless both agree. Both
+// must return the same SDK-neutral form, such as a slice of structs built
+// from the output shapes: the results are compared with DiffJSON, and each
+// differing JSON path is reported. When both calls fail, they agree if
+// their errors have the same NormalizedError Kind and Code, so that a
+// rewrite which turns a NotFound into a success, or an AccessDenied into a
+// throttle, is caught as well. name labels the report, and is usually the
+// operation being migrated. AssertSDKParity returns whether both agreed.
+func AssertSDKParity(t TB, name string, v1fn, v2fn func() (any, error)) bool {
+	t.Helper()
+	outV1, errV1 := v1fn()
+	outV2, errV2 := v2fn()
+
+	switch {
+	case errV1 != nil && errV2 != nil:
+		n1, n2 := NormalizeError(errV1), NormalizeError(errV2)
+		if n1.Kind != n2.Kind || n1.Code != n2.Code {
+			t.Errorf("%s: v1 and v2 fail differently:\n  v1: %v\n  v2: %v", name, n1, n2)
+			return false
+		}
+		return true
+	case errV1 != nil:
+		t.Errorf("%s: v1 failed but v2 succeeded: %v", name, errV1)
+		return false
+	case errV2 != nil:
+		t.Errorf("%s: v2 failed but v1 succeeded: %v", name, errV2)
+		return false
+	}
+
+	diffs, err := DiffJSON(outV1, outV2)
+	if err != nil {
+		t.Errorf("%s: comparing results: %v", name, err)
+		return false
+	}
+	if len(diffs) > 0 {
+		// "first" and "second" in the diffs are v1 and v2.
+		t.Errorf("%s: v1 and v2 results differ:\n  %s", name, strings.Join(diffs, "\n  "))
+		return false
+	}
+	return true
+}
+
+// ParityReport is a TB for programs: it prints every report as a failed
+// step and counts them.
+type ParityReport struct {
+	Failures int
+}
+
+// Helper does nothing; it is there to satisfy TB.
+func (r *ParityReport) Helper() {}
+
+// Errorf prints the report to Output, indented under the current step, and
+// counts it.
+func (r *ParityReport) Errorf(format string, args ...any) {
+	r.Failures++
+	msg := strings.ReplaceAll(fmt.Sprintf(format, args...), "\n", "\n     ")
+	fmt.Fprintf(Output, "   ✗ %s\n", msg)
+}
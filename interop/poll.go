@@ -0,0 +1,41 @@
+package interop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPollTimeout is wrapped by the error Poll returns when the condition is
+// still not met after the timeout.
+var ErrPollTimeout = errors.New("timed out waiting for condition")
+
+// Poll calls check every interval until it reports done, returns an error,
+// or timeout elapses. The first check runs immediately. Cancelling parent stops
+// polling with parent's error.
+func Poll(parent context.Context, interval, timeout time.Duration, check func(ctx context.Context) (bool, error)) error {
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		done, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			if err := parent.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("%w after %s", ErrPollTimeout, timeout)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+)
+
+// regionNotOptedIn is the OptInStatus of an opt-in region the account has
+// not enabled. Such regions are listed by DescribeRegions with AllRegions
+// set, but every other call to them fails.
+const regionNotOptedIn = "not-opted-in"
+
+// EnabledRegions returns the sorted names of the regions enabled for the
+// account, as reported by EC2 DescribeRegions with both SDKs. Opt-in regions
+// the account has not opted in to are left out. If the SDKs disagree, a
+// warning names the differing regions and only those both SDKs report are
+// returned, so that a multi-region sweep never visits a region one SDK
+// cannot reach.
+func EnabledRegions(ctx context.Context, c *Clients) ([]string, error) {
+	outV1, err := c.EC2V1.DescribeRegionsWithContext(ctx, &ec2v1.DescribeRegionsInput{
+		AllRegions: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describing regions with v1: %w", err)
+	}
+	enabledV1 := make(map[string]bool)
+	for _, r := range outV1.Regions {
+		name, status := aws.StringValue(r.RegionName), aws.StringValue(r.OptInStatus)
+		if status == regionNotOptedIn {
+			Verbosef("skipping %s: %s", name, status)
+			continue
+		}
+		enabledV1[name] = true
+	}
+
+	outV2, err := c.EC2V2.DescribeRegions(ctx, &ec2v2.DescribeRegionsInput{
+		AllRegions: aws.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describing regions with v2: %w", err)
+	}
+	enabledV2 := make(map[string]bool)
+	for _, r := range outV2.Regions {
+		if aws.StringValue(r.OptInStatus) == regionNotOptedIn {
+			continue
+		}
+		enabledV2[aws.StringValue(r.RegionName)] = true
+	}
+
+	var both, onlyV1, onlyV2 []string
+	for name := range enabledV1 {
+		if enabledV2[name] {
+			both = append(both, name)
+		} else {
+			onlyV1 = append(onlyV1, name)
+		}
+	}
+	for name := range enabledV2 {
+		if !enabledV1[name] {
+			onlyV2 = append(onlyV2, name)
+		}
+	}
+	sort.Strings(both)
+	if len(onlyV1) > 0 || len(onlyV2) > 0 {
+		sort.Strings(onlyV1)
+		sort.Strings(onlyV2)
+		log.Printf("Warning: SDKs disagree on enabled regions (only v1: [%s], only v2: [%s]); using the %d regions both report",
+			strings.Join(onlyV1, " "), strings.Join(onlyV2, " "), len(both))
+	}
+	return both, nil
+}
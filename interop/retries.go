@@ -0,0 +1,155 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// RetryMaxAttempts converts a v1 MaxRetries value to the v2 RetryMaxAttempts
+// that allows the same number of requests. v1 counts the retries after the
+// first attempt, v2 counts every attempt including the first, so three
+// retries in v1 are four attempts in v2. Zero retries is one attempt.
+func RetryMaxAttempts(maxRetries int) int {
+	return maxRetries + 1
+}
+
+// RetryStats summarizes how many attempts one call of an operation took and
+// how long the SDK waited between them.
+type RetryStats struct {
+	Attempts   int
+	RetryDelay time.Duration
+}
+
+func (s RetryStats) String() string {
+	return fmt.Sprintf("%d attempts, %s retry delay", s.Attempts, s.RetryDelay.Round(time.Millisecond))
+}
+
+// RetryComparison pairs the stats both SDKs recorded for one operation.
+type RetryComparison struct {
+	Operation string
+	V1, V2    RetryStats
+}
+
+// Match reports whether both SDKs made the same number of attempts. Delays
+// are jittered by both SDKs, so they are reported but not compared.
+func (c RetryComparison) Match() bool {
+	return c.V1.Attempts == c.V2.Attempts
+}
+
+func (c RetryComparison) String() string {
+	return fmt.Sprintf("%s: v1 %s, v2 %s", c.Operation, c.V1, c.V2)
+}
+
+// RetryRecorder records RetryStats for the last call of each operation made
+// through the sessions and configs it is installed on.
+type RetryRecorder struct {
+	mu      sync.Mutex
+	v1, v2  map[string]RetryStats
+	pending map[*request.Request]time.Duration
+}
+
+// NewRetryRecorder returns an empty RetryRecorder.
+func NewRetryRecorder() *RetryRecorder {
+	return &RetryRecorder{
+		v1:      make(map[string]RetryStats),
+		v2:      make(map[string]RetryStats),
+		pending: make(map[*request.Request]time.Duration),
+	}
+}
+
+// InstallV1 records stats for every client created from sess afterwards.
+// v1 keeps the retry count on request.Request but only the most recent
+// delay, so delays are summed from the AfterRetry handlers.
+func (rr *RetryRecorder) InstallV1(sess *session.Session) {
+	sess.Handlers.AfterRetry.PushBackNamed(request.NamedHandler{
+		Name: "interop.RetryRecorder.AfterRetry",
+		Fn: func(r *request.Request) {
+			// core.AfterRetryHandler clears r.Error once it has slept
+			// and decided to retry.
+			if r.Error == nil {
+				rr.mu.Lock()
+				rr.pending[r] += r.RetryDelay
+				rr.mu.Unlock()
+			}
+		},
+	})
+	sess.Handlers.Complete.PushBackNamed(request.NamedHandler{
+		Name: "interop.RetryRecorder.Complete",
+		Fn: func(r *request.Request) {
+			rr.mu.Lock()
+			defer rr.mu.Unlock()
+			rr.v1[r.Operation.Name] = RetryStats{Attempts: r.RetryCount + 1, RetryDelay: rr.pending[r]}
+			delete(rr.pending, r)
+		},
+	})
+}
+
+type attemptTimesKey struct{}
+
+// attemptTimes holds the start and end of each attempt of one v2 call.
+type attemptTimes struct {
+	starts, ends []time.Time
+}
+
+// InstallV2 records stats for every client created from cfg afterwards.
+// Attempts come from the retry metadata v2 attaches to the result; delays
+// are measured between attempts by a middleware that runs once per attempt.
+func (rr *RetryRecorder) InstallV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		err := stack.Finalize.Add(middleware.FinalizeMiddlewareFunc("interop.RetryRecorder.Call",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+				middleware.FinalizeOutput, middleware.Metadata, error,
+			) {
+				times := &attemptTimes{}
+				out, metadata, err := next.HandleFinalize(context.WithValue(ctx, attemptTimesKey{}, times), in)
+
+				stats := RetryStats{Attempts: len(times.starts)}
+				if results, ok := retry.GetAttemptResults(metadata); ok {
+					stats.Attempts = len(results.Results)
+				}
+				for i := 1; i < len(times.starts) && i <= len(times.ends); i++ {
+					stats.RetryDelay += times.starts[i].Sub(times.ends[i-1])
+				}
+				rr.mu.Lock()
+				rr.v2[awsmiddleware.GetOperationName(ctx)] = stats
+				rr.mu.Unlock()
+				return out, metadata, err
+			}), middleware.Before)
+		if err != nil {
+			return err
+		}
+		return stack.Finalize.Insert(middleware.FinalizeMiddlewareFunc("interop.RetryRecorder.Attempt",
+			func(ctx context.Context, in middleware.FinalizeInput, next middleware.FinalizeHandler) (
+				middleware.FinalizeOutput, middleware.Metadata, error,
+			) {
+				times, _ := ctx.Value(attemptTimesKey{}).(*attemptTimes)
+				if times == nil {
+					return next.HandleFinalize(ctx, in)
+				}
+				times.starts = append(times.starts, time.Now())
+				out, metadata, err := next.HandleFinalize(ctx, in)
+				times.ends = append(times.ends, time.Now())
+				return out, metadata, err
+			}), "Retry", middleware.After)
+	})
+}
+
+// Compare returns the stats both SDKs recorded for operation. ok is false if
+// either SDK has not completed a call of it yet.
+func (rr *RetryRecorder) Compare(operation string) (c RetryComparison, ok bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+	v1, okV1 := rr.v1[operation]
+	v2, okV2 := rr.v2[operation]
+	return RetryComparison{Operation: operation, V1: v1, V2: v2}, okV1 && okV2
+}
@@ -0,0 +1,182 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// NormalizeBucketLocation maps the LocationConstraint returned by
+// GetBucketLocation in either SDK to a canonical region name. S3 reports
+// buckets in us-east-1 with an empty constraint, and buckets created with the
+// legacy "EU" constraint as "EU" rather than eu-west-1.
+func NormalizeBucketLocation(constraint string) string {
+	switch constraint {
+	case "":
+		return "us-east-1"
+	case "EU":
+		return "eu-west-1"
+	}
+	return constraint
+}
+
+// BucketLocationConstraint returns the LocationConstraint to create a bucket
+// in region with: none for us-east-1, where S3 rejects an explicit
+// constraint, and the region itself everywhere else, where S3 rejects a
+// request without one. It is the inverse of NormalizeBucketLocation.
+func BucketLocationConstraint(region string) string {
+	if region == "us-east-1" {
+		return ""
+	}
+	return region
+}
+
+// CreateBucketInRegionV1 creates bucket in region with SDK v1, sending a
+// CreateBucketConfiguration only when BucketLocationConstraint asks for one.
+// The client must be configured for the same region. Each of opts may set
+// other fields of the input, such as ObjectLockEnabledForBucket.
+func CreateBucketInRegionV1(ctx context.Context, client *s3v1.S3, bucket, region string, opts ...func(*s3v1.CreateBucketInput)) error {
+	input := &s3v1.CreateBucketInput{Bucket: aws.String(bucket)}
+	for _, opt := range opts {
+		opt(input)
+	}
+	if constraint := BucketLocationConstraint(region); constraint != "" {
+		input.CreateBucketConfiguration = &s3v1.CreateBucketConfiguration{
+			LocationConstraint: aws.String(constraint),
+		}
+	}
+	_, err := client.CreateBucketWithContext(ctx, input)
+	return err
+}
+
+// CreateBucketInRegionV2 is CreateBucketInRegionV1 with SDK v2.
+func CreateBucketInRegionV2(ctx context.Context, client *s3v2.Client, bucket, region string, opts ...func(*s3v2.CreateBucketInput)) error {
+	input := &s3v2.CreateBucketInput{Bucket: aws.String(bucket)}
+	for _, opt := range opts {
+		opt(input)
+	}
+	if constraint := BucketLocationConstraint(region); constraint != "" {
+		input.CreateBucketConfiguration = &s3types.CreateBucketConfiguration{
+			LocationConstraint: s3types.BucketLocationConstraint(constraint),
+		}
+	}
+	_, err := client.CreateBucket(ctx, input)
+	return err
+}
+
+// DrainBucketV2 deletes every object version and delete marker in bucket
+// with SDK v2, so that the bucket can be deleted, making up to workers
+// DeleteObject calls at once. Unversioned buckets list each object once,
+// with the version ID "null". With bypassGovernance, versions under
+// GOVERNANCE Object Lock retention are deleted too, which needs the
+// s3:BypassGovernanceRetention permission. It returns the number of
+// versions and markers deleted, and an error for each one that could not
+// be, or for the listing.
+func DrainBucketV2(ctx context.Context, client *s3v2.Client, bucket string, workers int, bypassGovernance bool) (int, []error) {
+	var targets []s3types.ObjectIdentifier
+	paginator := s3v2.NewListObjectVersionsPaginator(client, &s3v2.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return 0, []error{fmt.Errorf("listing object versions: %w", err)}
+		}
+		for _, v := range page.Versions {
+			targets = append(targets, s3types.ObjectIdentifier{Key: v.Key, VersionId: v.VersionId})
+		}
+		for _, m := range page.DeleteMarkers {
+			targets = append(targets, s3types.ObjectIdentifier{Key: m.Key, VersionId: m.VersionId})
+		}
+	}
+
+	var bypass *bool
+	if bypassGovernance {
+		bypass = aws.Bool(true)
+	}
+	errs := ForEachConcurrently(targets, workers, func(t s3types.ObjectIdentifier) error {
+		_, err := client.DeleteObject(ctx, &s3v2.DeleteObjectInput{
+			Bucket:                    aws.String(bucket),
+			Key:                       t.Key,
+			VersionId:                 t.VersionId,
+			BypassGovernanceRetention: bypass,
+		})
+		return err
+	})
+	var failed []error
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Errorf("deleting %s (version %s): %w",
+				aws.StringValue(targets[i].Key), aws.StringValue(targets[i].VersionId), err))
+		}
+	}
+	return len(targets) - len(failed), failed
+}
+
+// contentTypes maps the extensions of common S3 objects to their content
+// types. It is fixed, unlike mime.TypeByExtension, which also reads the
+// host's MIME tables, so both SDKs and every host upload the same type.
+var contentTypes = map[string]string{
+	".css":  "text/css; charset=utf-8",
+	".csv":  "text/csv; charset=utf-8",
+	".gif":  "image/gif",
+	".gz":   "application/gzip",
+	".htm":  "text/html; charset=utf-8",
+	".html": "text/html; charset=utf-8",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".js":   "text/javascript; charset=utf-8",
+	".json": "application/json",
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".svg":  "image/svg+xml",
+	".txt":  "text/plain; charset=utf-8",
+	".xml":  "application/xml",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".zip":  "application/zip",
+}
+
+// DetectContentType returns the content type to upload an object with:
+// the type of key's extension if it is a common one, and otherwise the type
+// http.DetectContentType sniffs from body, which is
+// "application/octet-stream" for binary data. Without a type, v1 PutObject
+// sends none and S3 stores binary/octet-stream, while v2 sends
+// application/octet-stream, so the same upload differs between SDKs.
+func DetectContentType(key string, body []byte) string {
+	if t, ok := contentTypes[strings.ToLower(path.Ext(key))]; ok {
+		return t
+	}
+	return http.DetectContentType(body)
+}
+
+// UserMetadataV1 returns the user metadata of a v1 HeadObject or GetObject
+// response with its keys lowercased, as S3 stores them and v2 returns them.
+// v1 canonicalizes keys like HTTP header names ("Releasenotes" for a key
+// put as "ReleaseNotes"), unless the session sets LowerCaseHeaderMaps.
+func UserMetadataV1(metadata map[string]*string) map[string]string {
+	out := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		out[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return out
+}
+
+// UserMetadataV2 is UserMetadataV1 for v2, whose keys are already
+// lowercase; it returns a copy, so that both SDKs' results can be compared
+// and changed alike.
+func UserMetadataV2(metadata map[string]string) map[string]string {
+	out := make(map[string]string, len(metadata))
+	for k, v := range metadata {
+		out[strings.ToLower(k)] = v
+	}
+	return out
+}
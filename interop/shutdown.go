@@ -0,0 +1,51 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// ShutdownContext returns a copy of parent that is canceled on the first
+// SIGINT or SIGTERM, so that in-flight calls, waiters and polls return early
+// and the program can run its cleanup instead of leaving resources behind.
+// A notice is printed when that happens, and a second signal terminates the
+// program immediately.
+//
+// Cleanup code runs after ctx is canceled, so it should make its calls with
+// context.WithoutCancel(ctx). Call stop once the program is done.
+func ShutdownContext(parent context.Context) (ctx context.Context, stop context.CancelFunc) {
+	signaled, stopNotify := signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+	// The returned context is canceled only after the notice is printed,
+	// so the notice comes before any output about the canceled calls.
+	ctx, cancel := context.WithCancel(parent)
+	stopped := make(chan struct{})
+	var once sync.Once
+	go func() {
+		select {
+		case <-signaled.Done():
+		case <-stopped:
+			return
+		}
+		select {
+		case <-stopped:
+			// stop also cancels signaled; that is not an interrupt.
+			return
+		default:
+		}
+		if parent.Err() == nil {
+			// Restore the default behavior so that a second signal exits.
+			stopNotify()
+			fmt.Fprintln(os.Stderr, "\nInterrupted: cleaning up, please wait (interrupt again to exit immediately)...")
+		}
+		cancel()
+	}()
+	return ctx, func() {
+		once.Do(func() { close(stopped) })
+		stopNotify()
+		cancel()
+	}
+}
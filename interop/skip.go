@@ -0,0 +1,76 @@
+package interop
+
+import (
+	"context"
+	"fmt"
+	"os"
+)
+
+// ForceRunEnv names the environment variable that turns off SkipIfDenied:
+// when it is set to anything but "", a program whose probe is denied runs
+// anyway and fails on its own calls. CI jobs whose account is meant to have
+// every permission set it, so that a lost permission fails the job instead
+// of skipping programs quietly.
+const ForceRunEnv = "SDKMT_FORCE_RUN"
+
+// SkipIfDenied runs probe, a cheap read that needs the permissions the
+// program depends on, and reports whether the program should be skipped.
+// When probe fails with an access denied error, it prints which action was
+// denied and returns true, so that the program can exit with status 0 and a
+// restricted account still runs every program it is allowed to. Any other
+// outcome returns false: a not found error proves the call was authorized,
+// and other failures are left to the program's own calls to report.
+func SkipIfDenied(ctx context.Context, action string, probe func(ctx context.Context) error) bool {
+	action, err := probeDenied(ctx, action, probe)
+	if err == nil {
+		return false
+	}
+	if os.Getenv(ForceRunEnv) != "" {
+		fmt.Fprintf(Output, "Warning: %s is denied; running anyway because %s is set\n\n", action, ForceRunEnv)
+		return false
+	}
+	fmt.Fprintf(Output, "Skipped: the caller is not allowed to perform %s\n", action)
+	fmt.Fprintf(Output, "Set %s=1 to run anyway.\n", ForceRunEnv)
+	Verbosef("probe error: %v", err)
+	return true
+}
+
+// SkipTB is the part of testing.TB that SkipTestIfDenied reports through,
+// so that *testing.T and *testing.B can be passed to it directly.
+type SkipTB interface {
+	Helper()
+	Logf(format string, args ...any)
+	Skip(args ...any)
+}
+
+// SkipTestIfDenied is SkipIfDenied for tests: when probe fails with an
+// access denied error, it skips t with a message naming the denied action,
+// and otherwise returns, leaving other failures to the test's own calls.
+// Like SkipIfDenied it runs the test anyway, logging the denial, when
+// ForceRunEnv is set.
+func SkipTestIfDenied(t SkipTB, ctx context.Context, action string, probe func(ctx context.Context) error) {
+	t.Helper()
+	action, err := probeDenied(ctx, action, probe)
+	if err == nil {
+		return
+	}
+	if os.Getenv(ForceRunEnv) != "" {
+		t.Logf("%s is denied; running anyway because %s is set", action, ForceRunEnv)
+		return
+	}
+	t.Skip(fmt.Sprintf("the caller is not allowed to perform %s (set %s=1 to run anyway): %v", action, ForceRunEnv, err))
+}
+
+// probeDenied runs probe and returns its error when it is an access denied
+// error, along with the denied action the error names, or action when it
+// names none. It returns a nil error for any other outcome.
+func probeDenied(ctx context.Context, action string, probe func(ctx context.Context) error) (string, error) {
+	err := probe(ctx)
+	if err == nil || NormalizeError(err).Kind != ErrorKindAccessDenied {
+		return action, nil
+	}
+	if denied, ok := AccessDeniedAction(err); ok {
+		action = denied
+	}
+	return action, err
+}
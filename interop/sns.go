@@ -0,0 +1,211 @@
+package interop
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	snsv1 "github.com/aws/aws-sdk-go/service/sns"
+
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+)
+
+// SNS message attribute data types. Custom types such as "Number.float" or
+// "Binary.gif" are classified by the part before the first dot.
+const (
+	snsDataTypeString      = "String"
+	snsDataTypeStringArray = "String.Array"
+	snsDataTypeNumber      = "Number"
+	snsDataTypeBinary      = "Binary"
+)
+
+// ConvertSNSAttributesV1ToV2 converts v1 message attributes, a map of
+// pointers, to the map of values v2 expects. Binary attributes carry their
+// data in BinaryValue while all other types use StringValue; values placed
+// in the wrong field are rejected rather than silently dropped.
+func ConvertSNSAttributesV1ToV2(attrs map[string]*snsv1.MessageAttributeValue) (map[string]snstypes.MessageAttributeValue, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	out := make(map[string]snstypes.MessageAttributeValue, len(attrs))
+	for name, attr := range attrs {
+		if attr == nil {
+			return nil, fmt.Errorf("message attribute %q: nil value", name)
+		}
+		if err := validateSNSAttribute(attr.DataType, attr.StringValue, attr.BinaryValue); err != nil {
+			return nil, fmt.Errorf("message attribute %q: %w", name, err)
+		}
+		out[name] = snstypes.MessageAttributeValue{
+			DataType:    copyString(attr.DataType),
+			StringValue: copyString(attr.StringValue),
+			BinaryValue: copyBytes(attr.BinaryValue),
+		}
+	}
+	return out, nil
+}
+
+// ConvertSNSAttributesV2ToV1 is the inverse of ConvertSNSAttributesV1ToV2.
+func ConvertSNSAttributesV2ToV1(attrs map[string]snstypes.MessageAttributeValue) (map[string]*snsv1.MessageAttributeValue, error) {
+	if attrs == nil {
+		return nil, nil
+	}
+	out := make(map[string]*snsv1.MessageAttributeValue, len(attrs))
+	for name, attr := range attrs {
+		if err := validateSNSAttribute(attr.DataType, attr.StringValue, attr.BinaryValue); err != nil {
+			return nil, fmt.Errorf("message attribute %q: %w", name, err)
+		}
+		out[name] = &snsv1.MessageAttributeValue{
+			DataType:    copyString(attr.DataType),
+			StringValue: copyString(attr.StringValue),
+			BinaryValue: copyBytes(attr.BinaryValue),
+		}
+	}
+	return out, nil
+}
+
+// validateSNSAttribute checks that the value is held in the field matching
+// its data type, and that String.Array values are JSON arrays.
+func validateSNSAttribute(dataType, stringValue *string, binaryValue []byte) error {
+	if dataType == nil || *dataType == "" {
+		return fmt.Errorf("missing data type")
+	}
+	switch *dataType {
+	case snsDataTypeStringArray:
+		if stringValue == nil {
+			return fmt.Errorf("%s value must be set in StringValue", *dataType)
+		}
+		var values []interface{}
+		if err := json.Unmarshal([]byte(*stringValue), &values); err != nil {
+			return fmt.Errorf("%s value is not a JSON array: %w", *dataType, err)
+		}
+		return nil
+	}
+
+	base, _, _ := strings.Cut(*dataType, ".")
+	switch base {
+	case snsDataTypeBinary:
+		if binaryValue == nil {
+			return fmt.Errorf("%s value must be set in BinaryValue", *dataType)
+		}
+		if stringValue != nil {
+			return fmt.Errorf("%s attribute must not set StringValue", *dataType)
+		}
+	case snsDataTypeString, snsDataTypeNumber:
+		if stringValue == nil {
+			return fmt.Errorf("%s value must be set in StringValue", *dataType)
+		}
+		if binaryValue != nil {
+			return fmt.Errorf("%s attribute must not set BinaryValue", *dataType)
+		}
+	default:
+		return fmt.Errorf("unsupported data type %q", *dataType)
+	}
+	return nil
+}
+
+// copyString returns a pointer to a copy of *s, so converted values never
+// alias the input.
+func copyString(s *string) *string {
+	if s == nil {
+		return nil
+	}
+	v := *s
+	return &v
+}
+
+func copyBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// SNSMessageStructureJSON is the Publish MessageStructure that makes Message
+// a JSON object of per-protocol messages.
+const SNSMessageStructureJSON = "json"
+
+// snsMessageProtocols are the keys a message structure may hold, mapped to
+// whether the protocol's message is itself a JSON payload. Those payloads
+// must still be given as strings holding JSON, not as nested objects.
+var snsMessageProtocols = map[string]bool{
+	"default":           false,
+	"email":             false,
+	"email-json":        false,
+	"sms":               false,
+	"sqs":               false,
+	"lambda":            false,
+	"http":              false,
+	"https":             false,
+	"firehose":          false,
+	"MPNS":              false,
+	"WNS":               false,
+	"APNS":              true,
+	"APNS_SANDBOX":      true,
+	"APNS_VOIP":         true,
+	"APNS_VOIP_SANDBOX": true,
+	"MACOS":             true,
+	"MACOS_SANDBOX":     true,
+	"GCM":               true,
+	"ADM":               true,
+	"BAIDU":             true,
+}
+
+// BuildSNSMessageStructure encodes per-protocol messages as the Message of
+// a Publish with MessageStructure SNSMessageStructureJSON. The map needs a
+// "default" message, and the payloads of mobile push protocols such as APNS
+// and GCM must be JSON objects; both SDKs send whatever string they are
+// given, so these mistakes would otherwise only surface as a service error
+// or an undelivered notification.
+func BuildSNSMessageStructure(messages map[string]string) (string, error) {
+	if err := validateSNSMessageStructure(messages); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ParseSNSMessageStructure decodes and validates the Message of a Publish
+// with MessageStructure SNSMessageStructureJSON. Every value must be a
+// string: a push payload nested as an object, a common mistake when
+// building the message from structs, is rejected.
+func ParseSNSMessageStructure(message string) (map[string]string, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(message), &raw); err != nil {
+		return nil, fmt.Errorf("message structure is not a JSON object: %w", err)
+	}
+	messages := make(map[string]string, len(raw))
+	for protocol, value := range raw {
+		var s string
+		if err := json.Unmarshal(value, &s); err != nil {
+			return nil, fmt.Errorf("message for %q must be a string, holding JSON for push payloads: %w", protocol, err)
+		}
+		messages[protocol] = s
+	}
+	if err := validateSNSMessageStructure(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func validateSNSMessageStructure(messages map[string]string) error {
+	if _, ok := messages["default"]; !ok {
+		return fmt.Errorf("message structure has no default message")
+	}
+	for protocol, message := range messages {
+		payload, ok := snsMessageProtocols[protocol]
+		if !ok {
+			return fmt.Errorf("message structure has unknown protocol %q", protocol)
+		}
+		if !payload {
+			continue
+		}
+		var obj map[string]interface{}
+		if err := json.Unmarshal([]byte(message), &obj); err != nil {
+			return fmt.Errorf("%s payload is not a JSON object: %w", protocol, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+package interop
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNS message types, as found in the Type field of a delivered message.
+const (
+	SNSTypeNotification             = "Notification"
+	SNSTypeSubscriptionConfirmation = "SubscriptionConfirmation"
+	SNSTypeUnsubscribeConfirmation  = "UnsubscribeConfirmation"
+)
+
+// SNSMessage is the JSON document SNS delivers to HTTP/S endpoints, and to
+// SQS queues subscribed without raw message delivery. The same document is
+// delivered whichever SDK published the message. Optional fields that are
+// absent are empty.
+type SNSMessage struct {
+	Type             string
+	MessageId        string
+	Token            string `json:",omitempty"`
+	TopicArn         string
+	Subject          string `json:",omitempty"`
+	Message          string
+	Timestamp        string
+	SignatureVersion string
+	Signature        string
+	SigningCertURL   string
+	SubscribeURL     string `json:",omitempty"`
+	UnsubscribeURL   string `json:",omitempty"`
+}
+
+// ParseSNSMessage decodes a delivered SNS message.
+func ParseSNSMessage(data []byte) (*SNSMessage, error) {
+	var msg SNSMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return nil, fmt.Errorf("decoding SNS message: %w", err)
+	}
+	if msg.Type == "" || msg.Signature == "" {
+		return nil, fmt.Errorf("not an SNS message: Type and Signature are required")
+	}
+	return &msg, nil
+}
+
+// SNSStringToSign returns the canonical string SNS signs for msg: selected
+// fields as "name\nvalue\n" pairs in a fixed order that depends on the
+// message type. Subject is only included when the message has one.
+func SNSStringToSign(msg *SNSMessage) (string, error) {
+	type field struct{ name, value string }
+	var fields []field
+	switch msg.Type {
+	case SNSTypeNotification:
+		fields = []field{{"Message", msg.Message}, {"MessageId", msg.MessageId}}
+		if msg.Subject != "" {
+			fields = append(fields, field{"Subject", msg.Subject})
+		}
+		fields = append(fields, field{"Timestamp", msg.Timestamp}, field{"TopicArn", msg.TopicArn}, field{"Type", msg.Type})
+	case SNSTypeSubscriptionConfirmation, SNSTypeUnsubscribeConfirmation:
+		fields = []field{
+			{"Message", msg.Message}, {"MessageId", msg.MessageId}, {"SubscribeURL", msg.SubscribeURL},
+			{"Timestamp", msg.Timestamp}, {"Token", msg.Token}, {"TopicArn", msg.TopicArn}, {"Type", msg.Type},
+		}
+	default:
+		return "", fmt.Errorf("unknown SNS message type %q", msg.Type)
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteString(f.name)
+		b.WriteByte('\n')
+		b.WriteString(f.value)
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
+
+// snsSignatureHash returns the hash for a SignatureVersion: SHA1 for "1",
+// the default for topics, and SHA256 for "2".
+func snsSignatureHash(version string) (crypto.Hash, error) {
+	switch version {
+	case "1":
+		return crypto.SHA1, nil
+	case "2":
+		return crypto.SHA256, nil
+	default:
+		return 0, fmt.Errorf("unsupported SNS SignatureVersion %q", version)
+	}
+}
+
+// snsCertHost matches the hosts SNS serves signing certificates from, in
+// the aws and aws-cn partitions.
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// CheckSNSSigningCertURL reports an error unless rawURL is an HTTPS URL of a
+// PEM file on an SNS host. Without this check, anyone could sign a forged
+// message with their own key and point SigningCertURL at its certificate.
+func CheckSNSSigningCertURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid SigningCertURL: %w", err)
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("SigningCertURL %q is not an HTTPS URL", rawURL)
+	}
+	if !snsCertHost.MatchString(u.Host) {
+		return fmt.Errorf("SigningCertURL host %q is not an SNS host", u.Host)
+	}
+	if !strings.HasSuffix(u.Path, ".pem") {
+		return fmt.Errorf("SigningCertURL %q does not name a PEM file", rawURL)
+	}
+	return nil
+}
+
+// SNSVerifier verifies SNS message signatures, caching the signing
+// certificates it fetches. The zero value is ready to use.
+type SNSVerifier struct {
+	// FetchCertificate returns the certificate at a SigningCertURL that
+	// has passed CheckSNSSigningCertURL. If nil, it is downloaded over
+	// HTTPS. Replacing it allows verifying messages offline.
+	FetchCertificate func(ctx context.Context, certURL string) (*x509.Certificate, error)
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// Verify checks that msg was signed by SNS. It does not check the age of
+// Timestamp; callers that need replay protection must do so themselves.
+func (v *SNSVerifier) Verify(ctx context.Context, msg *SNSMessage) error {
+	hash, err := snsSignatureHash(msg.SignatureVersion)
+	if err != nil {
+		return err
+	}
+	stringToSign, err := SNSStringToSign(msg)
+	if err != nil {
+		return err
+	}
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("decoding Signature: %w", err)
+	}
+	if err := CheckSNSSigningCertURL(msg.SigningCertURL); err != nil {
+		return err
+	}
+	cert, err := v.certificate(ctx, msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("signing certificate has a %T key, want RSA", cert.PublicKey)
+	}
+
+	var digest []byte
+	if hash == crypto.SHA1 {
+		sum := sha1.Sum([]byte(stringToSign))
+		digest = sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		digest = sum[:]
+	}
+	if err := rsa.VerifyPKCS1v15(pub, hash, digest, signature); err != nil {
+		return fmt.Errorf("SNS message %s: signature version %s does not verify: %w", msg.MessageId, msg.SignatureVersion, err)
+	}
+	return nil
+}
+
+func (v *SNSVerifier) certificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	v.mu.Lock()
+	cert, ok := v.certs[certURL]
+	v.mu.Unlock()
+	if ok {
+		return cert, nil
+	}
+
+	fetch := v.FetchCertificate
+	if fetch == nil {
+		fetch = fetchSNSCertificate
+	}
+	cert, err := fetch(ctx, certURL)
+	if err != nil {
+		return nil, fmt.Errorf("fetching signing certificate: %w", err)
+	}
+	now := DefaultClock.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, fmt.Errorf("signing certificate %s is not valid at %s", certURL, now.UTC().Format(time.RFC3339))
+	}
+
+	v.mu.Lock()
+	if v.certs == nil {
+		v.certs = make(map[string]*x509.Certificate)
+	}
+	v.certs[certURL] = cert
+	v.mu.Unlock()
+	return cert, nil
+}
+
+// fetchSNSCertificate downloads and parses the PEM certificate at certURL.
+// The HTTPS connection, to a host CheckSNSSigningCertURL accepted, is what
+// vouches for the certificate; SNS does not publish a chain to check.
+func fetchSNSCertificate(ctx context.Context, certURL string) (*x509.Certificate, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, certURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", certURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s is not a PEM certificate", certURL)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// VerifySNSMessage checks msg's signature with a shared SNSVerifier that
+// downloads signing certificates.
+func VerifySNSMessage(ctx context.Context, msg *SNSMessage) error {
+	return defaultSNSVerifier.Verify(ctx, msg)
+}
+
+var defaultSNSVerifier SNSVerifier
@@ -0,0 +1,136 @@
+package interop
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// ErrNoQueueAttribute is wrapped by the errors QueueAttributes accessors
+// return when the attribute is not set.
+var ErrNoQueueAttribute = errors.New("queue attribute not set")
+
+// QueueAttributes holds SQS queue attributes in v2's form, keyed by attribute
+// name. SQS sends every attribute as a string, numbers and JSON documents
+// included; the accessors parse the common ones.
+type QueueAttributes map[string]string
+
+// ConvertSQSQueueAttributesV1ToV2 converts v1 queue attributes, a map of
+// pointers, to v2's map of values. Nil values are dropped: v1 never returns
+// them, and SQS would reject them in a request.
+func ConvertSQSQueueAttributesV1ToV2(attrs map[string]*string) QueueAttributes {
+	if attrs == nil {
+		return nil
+	}
+	out := make(QueueAttributes, len(attrs))
+	for name, value := range attrs {
+		if value != nil {
+			out[name] = *value
+		}
+	}
+	return out
+}
+
+// ConvertSQSQueueAttributesV2ToV1 is the inverse of
+// ConvertSQSQueueAttributesV1ToV2.
+func ConvertSQSQueueAttributesV2ToV1(attrs map[string]string) map[string]*string {
+	if attrs == nil {
+		return nil
+	}
+	out := make(map[string]*string, len(attrs))
+	for name, value := range attrs {
+		v := value
+		out[name] = &v
+	}
+	return out
+}
+
+// Int parses the attribute name as a whole number, as SQS sends
+// VisibilityTimeout, DelaySeconds and the approximate message counts.
+func (a QueueAttributes) Int(name sqstypes.QueueAttributeName) (int, error) {
+	value, ok := a[string(name)]
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", name, ErrNoQueueAttribute)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %q is not a whole number", name, value)
+	}
+	return n, nil
+}
+
+// SetInt sets the attribute name to n, formatted as SQS expects.
+func (a QueueAttributes) SetInt(name sqstypes.QueueAttributeName, n int) {
+	a[string(name)] = strconv.Itoa(n)
+}
+
+// VisibilityTimeout returns the queue's visibility timeout in seconds.
+func (a QueueAttributes) VisibilityTimeout() (int, error) {
+	return a.Int(sqstypes.QueueAttributeNameVisibilityTimeout)
+}
+
+// QueueArn returns the queue's ARN, or "" if it was not requested.
+func (a QueueAttributes) QueueArn() string {
+	return a[string(sqstypes.QueueAttributeNameQueueArn)]
+}
+
+// RedrivePolicy is the parsed RedrivePolicy attribute, which sends messages
+// received more than MaxReceiveCount times to a dead-letter queue.
+type RedrivePolicy struct {
+	DeadLetterTargetArn string `json:"deadLetterTargetArn"`
+	MaxReceiveCount     int    `json:"maxReceiveCount"`
+}
+
+// UnmarshalJSON accepts maxReceiveCount as a number, as SQS returns it, or
+// as a string, as the SQS documentation and many templates write it.
+func (p *RedrivePolicy) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		DeadLetterTargetArn string          `json:"deadLetterTargetArn"`
+		MaxReceiveCount     json.RawMessage `json:"maxReceiveCount"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw.DeadLetterTargetArn == "" {
+		return fmt.Errorf("deadLetterTargetArn is missing")
+	}
+	count := strings.Trim(string(raw.MaxReceiveCount), `"`)
+	if count == "" {
+		return fmt.Errorf("maxReceiveCount is missing")
+	}
+	n, err := strconv.Atoi(count)
+	if err != nil || n < 1 {
+		return fmt.Errorf("maxReceiveCount %s is not a positive whole number", raw.MaxReceiveCount)
+	}
+	*p = RedrivePolicy{DeadLetterTargetArn: raw.DeadLetterTargetArn, MaxReceiveCount: n}
+	return nil
+}
+
+// RedrivePolicy parses the queue's RedrivePolicy attribute. It returns an
+// error wrapping ErrNoQueueAttribute if the queue has no dead-letter queue.
+func (a QueueAttributes) RedrivePolicy() (RedrivePolicy, error) {
+	name := sqstypes.QueueAttributeNameRedrivePolicy
+	value, ok := a[string(name)]
+	if !ok || value == "" {
+		return RedrivePolicy{}, fmt.Errorf("%s: %w", name, ErrNoQueueAttribute)
+	}
+	var p RedrivePolicy
+	if err := json.Unmarshal([]byte(value), &p); err != nil {
+		return RedrivePolicy{}, fmt.Errorf("%s: %w", name, err)
+	}
+	return p, nil
+}
+
+// SetRedrivePolicy sets the RedrivePolicy attribute to p as JSON.
+func (a QueueAttributes) SetRedrivePolicy(p RedrivePolicy) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	a[string(sqstypes.QueueAttributeNameRedrivePolicy)] = string(data)
+	return nil
+}
@@ -0,0 +1,148 @@
+package interop
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Output formats accepted by TablePrinter.Write, for programs to bind to an
+// -output flag.
+const (
+	OutputText = "text"
+	OutputCSV  = "csv"
+)
+
+// defaultMaxWidth is the widest a text column may be before values are
+// truncated.
+const defaultMaxWidth = 40
+
+// TablePrinter collects rows under a fixed set of headers and writes them as
+// aligned text columns or as CSV.
+type TablePrinter struct {
+	// Indent is written before every text line.
+	Indent string
+	// MaxWidth caps the width of text columns; longer values are cut and
+	// end with an ellipsis. Zero means defaultMaxWidth, negative no limit.
+	// CSV output is never truncated.
+	MaxWidth int
+	// MaxRows caps the rows of text output, which then ends with a line
+	// counting the rows left out; zero or negative means all of them. CSV
+	// output always has every row.
+	MaxRows int
+
+	headers []string
+	rows    [][]string
+}
+
+// NewTablePrinter returns an empty table with the given column headers.
+func NewTablePrinter(headers ...string) *TablePrinter {
+	return &TablePrinter{headers: headers}
+}
+
+// AddRow appends a row. Missing trailing values are left empty and extra
+// values are dropped.
+func (t *TablePrinter) AddRow(values ...string) {
+	row := make([]string, len(t.headers))
+	copy(row, values)
+	t.rows = append(t.rows, row)
+}
+
+// Len returns the number of rows added.
+func (t *TablePrinter) Len() int {
+	return len(t.rows)
+}
+
+// Write writes the table to w in format, OutputText or OutputCSV.
+func (t *TablePrinter) Write(w io.Writer, format string) error {
+	switch format {
+	case OutputText, "":
+		return t.WriteText(w)
+	case OutputCSV:
+		return t.WriteCSV(w)
+	}
+	return fmt.Errorf("unknown output format %q (want %s or %s)", format, OutputText, OutputCSV)
+}
+
+// WriteText writes the headers and rows as space-aligned columns.
+func (t *TablePrinter) WriteText(w io.Writer) error {
+	maxWidth := t.MaxWidth
+	if maxWidth == 0 {
+		maxWidth = defaultMaxWidth
+	}
+	rows, omitted := t.shownRows()
+	lines := make([][]string, 0, len(rows)+1)
+	lines = append(lines, t.headers)
+	for _, row := range rows {
+		line := make([]string, len(row))
+		for i, v := range row {
+			line[i] = truncate(v, maxWidth)
+		}
+		lines = append(lines, line)
+	}
+
+	widths := make([]int, len(t.headers))
+	for _, line := range lines {
+		for i, v := range line {
+			widths[i] = max(widths[i], utf8.RuneCountInString(v))
+		}
+	}
+	for _, line := range lines {
+		var b strings.Builder
+		b.WriteString(t.Indent)
+		for i, v := range line {
+			if i == len(line)-1 {
+				b.WriteString(v)
+				break
+			}
+			b.WriteString(v)
+			b.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(v)+2))
+		}
+		// Empty trailing values would otherwise leave padding behind.
+		if _, err := io.WriteString(w, strings.TrimRight(b.String(), " ")+"\n"); err != nil {
+			return err
+		}
+	}
+	if omitted > 0 {
+		if _, err := fmt.Fprintf(w, "%s... and %d more\n", t.Indent, omitted); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteCSV writes the headers and every row as CSV, without truncating
+// values.
+func (t *TablePrinter) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// shownRows returns the rows MaxRows lets through and how many it leaves
+// out.
+func (t *TablePrinter) shownRows() (rows [][]string, omitted int) {
+	if t.MaxRows <= 0 || len(t.rows) <= t.MaxRows {
+		return t.rows, 0
+	}
+	return t.rows[:t.MaxRows], len(t.rows) - t.MaxRows
+}
+
+// truncate shortens s to at most width runes, ending it with an ellipsis
+// when anything was cut. A negative width disables truncation.
+func truncate(s string, width int) string {
+	if width < 0 || utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return "…"
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
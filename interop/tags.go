@@ -0,0 +1,60 @@
+package interop
+
+import "strings"
+
+// ManagedTagPrefix starts the keys of tags AWS adds itself, such as
+// aws:cloudformation:stack-name or aws:autoscaling:groupName. They cannot be
+// set or removed by callers and differ between accounts and stacks, so tag
+// comparisons ignore them by default.
+const ManagedTagPrefix = "aws:"
+
+// TagsEqual reports whether a and b hold the same tags, ignoring every tag
+// whose key starts with one of ignorePrefixes, or with ManagedTagPrefix when
+// none are given. Passing prefixes replaces the default rather than adding
+// to it. A nil map and an empty one are equal.
+func TagsEqual(a, b map[string]string, ignorePrefixes ...string) bool {
+	if len(ignorePrefixes) == 0 {
+		ignorePrefixes = []string{ManagedTagPrefix}
+	}
+	a, b = withoutTags(a, ignorePrefixes), withoutTags(b, ignorePrefixes)
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if w, ok := b[k]; !ok || w != v {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutTags returns tags without the keys starting with any of prefixes.
+// tags itself is returned when nothing is removed, and nil when nothing is
+// left.
+func withoutTags(tags map[string]string, prefixes []string) map[string]string {
+	var out map[string]string
+	removed := false
+	for k, v := range tags {
+		if hasAnyPrefix(k, prefixes) {
+			removed = true
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string, len(tags))
+		}
+		out[k] = v
+	}
+	if !removed {
+		return tags
+	}
+	return out
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
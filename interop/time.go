@@ -0,0 +1,29 @@
+package interop
+
+import "time"
+
+// TimeEqual reports whether a and b, taken from v1 and v2 results, are the
+// same instant to the second. Services return whole seconds but the SDKs
+// parse some fields with sub-second precision, and a nil pointer and a
+// pointer to the zero time both mean the field is unset.
+func TimeEqual(a, b *time.Time) bool {
+	return truncateTime(a).Equal(truncateTime(b))
+}
+
+// FormatTime formats t as an RFC 3339 UTC timestamp truncated to the second,
+// or "" when t is nil or zero, so that values compare consistently as
+// strings.
+func FormatTime(t *time.Time) string {
+	tt := truncateTime(t)
+	if tt.IsZero() {
+		return ""
+	}
+	return tt.Format(time.RFC3339)
+}
+
+func truncateTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.UTC().Truncate(time.Second)
+}
@@ -0,0 +1,34 @@
+package interop
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// NewClientToken returns a random version 4 UUID for use as an idempotency
+// token. Repeating a create call with the same token returns the resource
+// created by the first call instead of creating another one, which makes
+// application-level retries safe.
+//
+// Both SDKs fill an empty token with a fresh UUID, but only once per call:
+// their own retries reuse it, a second call does not. Operations that accept
+// a token include:
+//
+//   - EC2 RunInstances, CreateFleet, CreateLaunchTemplate, CreateNatGateway,
+//     CreateVpcEndpoint and CreateCapacityReservation (ClientToken)
+//   - DynamoDB TransactWriteItems (ClientRequestToken)
+//   - CloudFormation CreateStack and UpdateStack (ClientRequestToken)
+//   - ECS RunTask (ClientToken)
+//
+// S3 and standard SQS queues have no client tokens; FIFO queues deduplicate
+// on MessageDeduplicationId instead.
+func NewClientToken() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand.Read does not fail on supported platforms.
+		panic(fmt.Sprintf("interop: reading random bytes: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,350 @@
+package interop
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// ErrNotCaptured is wrapped by the errors a replaying Trace returns for calls
+// it has no captured response for.
+var ErrNotCaptured = errors.New("no captured response")
+
+// TraceMode selects whether a Trace records responses or serves them.
+type TraceMode string
+
+const (
+	// TraceCapture sends every request and records its response.
+	TraceCapture TraceMode = "capture"
+	// TraceReplay answers every request from the recorded responses,
+	// without sending anything.
+	TraceReplay TraceMode = "replay"
+)
+
+// traceResponse is one recorded HTTP response. Bodies are stored as text,
+// so that captures of XML and JSON APIs can be reviewed like any other
+// fixture, unless they are binary.
+type traceResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 []byte      `json:"bodyBase64,omitempty"`
+}
+
+func newTraceResponse(resp *http.Response, body []byte) traceResponse {
+	r := traceResponse{StatusCode: resp.StatusCode, Header: resp.Header.Clone()}
+	if utf8.Valid(body) {
+		r.Body = string(body)
+	} else {
+		r.BodyBase64 = body
+	}
+	return r
+}
+
+func (r traceResponse) body() []byte {
+	if r.BodyBase64 != nil {
+		return r.BodyBase64
+	}
+	return []byte(r.Body)
+}
+
+// traceEntry holds the responses to every call of one operation with one
+// input, in the order they were received: retries and repeated calls such
+// as polls get one response each.
+type traceEntry struct {
+	SDK       string          `json:"sdk"`
+	Service   string          `json:"service"`
+	Operation string          `json:"operation"`
+	Input     json.RawMessage `json:"input"`
+	Responses []traceResponse `json:"responses"`
+}
+
+// traceKey identifies the entry of one call: the SDK, service and operation,
+// and a hash of the input.
+type traceKey struct {
+	sdk, service, operation, hash string
+}
+
+func (k traceKey) String() string {
+	return fmt.Sprintf("%s %s %s (input %s)", k.sdk, k.service, k.operation, k.hash)
+}
+
+// traceContextKey is the context key a call's traceKey is stored under,
+// from where it is keyed to where its response is captured or replayed.
+type traceContextKey struct{}
+
+// Trace captures the HTTP responses of API calls made with either SDK into
+// a directory, one JSON file per operation and input, and replays them
+// later without credentials or network access. Captures make reproducible
+// fixtures for migration comparisons: record once against a real account,
+// commit the directory, and replay in CI.
+//
+// Calls are keyed by operation and a hash of their input, so only examples
+// whose inputs are the same on every run, such as listings, replay; inputs
+// with timestamps or random names never match a capture.
+type Trace struct {
+	dir  string
+	mode TraceMode
+
+	mu      sync.Mutex
+	entries map[traceKey]*traceEntry
+	served  map[traceKey]int
+}
+
+// NewTrace returns a Trace that captures into or replays from dir. The
+// directory is created for capture, and must exist for replay.
+func NewTrace(dir string, mode TraceMode) (*Trace, error) {
+	switch mode {
+	case TraceCapture:
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	case TraceReplay:
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	default:
+		return nil, fmt.Errorf("unknown trace mode %q", mode)
+	}
+	return &Trace{
+		dir:     dir,
+		mode:    mode,
+		entries: make(map[traceKey]*traceEntry),
+		served:  make(map[traceKey]int),
+	}, nil
+}
+
+// Mode returns whether t captures or replays.
+func (t *Trace) Mode() TraceMode {
+	return t.mode
+}
+
+// InstallV1 captures or replays the calls of every client created from sess
+// afterwards. The call is keyed in the Validate handlers, before anything
+// fills in the input, and replay replaces core.SendHandler, so requests are
+// still built and signed as usual.
+func (t *Trace) InstallV1(sess *session.Session) {
+	sess.Handlers.Validate.PushFrontNamed(request.NamedHandler{
+		Name: "interop.Trace.Key",
+		Fn: func(r *request.Request) {
+			key, input, err := t.key("v1", r.ClientInfo.ServiceName, r.Operation.Name, r.Params)
+			if err == nil && t.mode == TraceReplay {
+				err = t.load(key)
+			}
+			if err != nil {
+				r.Error = err
+				return
+			}
+			t.remember(key, input)
+			r.SetContext(context.WithValue(r.Context(), traceContextKey{}, key))
+		},
+	})
+	key := func(r *request.Request) traceKey {
+		k, _ := r.Context().Value(traceContextKey{}).(traceKey)
+		return k
+	}
+	if t.mode == TraceReplay {
+		sess.Handlers.Send.Swap(corehandlers.SendHandler.Name, request.NamedHandler{
+			Name: "interop.Trace.Replay",
+			Fn: func(r *request.Request) {
+				r.HTTPResponse, r.Error = t.replay(key(r), r.HTTPRequest)
+			},
+		})
+		return
+	}
+	sess.Handlers.Send.PushBackNamed(request.NamedHandler{
+		Name: "interop.Trace.Capture",
+		Fn: func(r *request.Request) {
+			if r.Error == nil && r.HTTPResponse != nil {
+				r.Error = t.capture(key(r), r.HTTPResponse)
+			}
+		},
+	})
+}
+
+// InstallV2 captures or replays the calls of every client created from cfg
+// afterwards. An Initialize middleware keys the call before idempotency
+// tokens are filled in, and cfg.HTTPClient is wrapped to record or serve
+// each attempt.
+func (t *Trace) InstallV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		// Right after RegisterServiceMetadata, which names the service and
+		// operation, and before the idempotency token middleware.
+		return stack.Initialize.Insert(middleware.InitializeMiddlewareFunc("interop.Trace",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				key, input, err := t.key("v2", awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx), in.Parameters)
+				if err == nil && t.mode == TraceReplay {
+					// Failing here, rather than in the HTTP client,
+					// keeps the retryer from retrying a missing capture.
+					err = t.load(key)
+				}
+				if err != nil {
+					return middleware.InitializeOutput{}, middleware.Metadata{}, err
+				}
+				t.remember(key, input)
+				return next.HandleInitialize(context.WithValue(ctx, traceContextKey{}, key), in)
+			}), "RegisterServiceMetadata", middleware.After)
+	})
+	cfg.HTTPClient = &traceClient{trace: t, next: cfg.HTTPClient}
+}
+
+// traceClient captures or replays the requests of v2 calls keyed by the
+// Trace middleware, and sends any others unchanged.
+type traceClient struct {
+	trace *Trace
+	next  awsv2.HTTPClient
+}
+
+func (c *traceClient) Do(req *http.Request) (*http.Response, error) {
+	key, ok := req.Context().Value(traceContextKey{}).(traceKey)
+	if !ok {
+		return c.next.Do(req)
+	}
+	if c.trace.mode == TraceReplay {
+		return c.trace.replay(key, req)
+	}
+	resp, err := c.next.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.trace.capture(key, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// key returns the key of a call of operation with params, and params as
+// JSON for the capture file.
+func (t *Trace) key(sdk, service, operation string, params interface{}) (traceKey, json.RawMessage, error) {
+	input, err := json.Marshal(params)
+	if err != nil {
+		return traceKey{}, nil, fmt.Errorf("trace: encoding %s input: %w", operation, err)
+	}
+	sum := sha256.Sum256(input)
+	return traceKey{
+		sdk:       sdk,
+		service:   strings.ToLower(strings.ReplaceAll(service, " ", "")),
+		operation: operation,
+		hash:      hex.EncodeToString(sum[:6]),
+	}, input, nil
+}
+
+// path returns the capture file of key, such as v1/s3/ListBuckets-<hash>.json.
+func (t *Trace) path(key traceKey) string {
+	return filepath.Join(t.dir, key.sdk, key.service, key.operation+"-"+key.hash+".json")
+}
+
+// remember records the input of key for capture.
+func (t *Trace) remember(key traceKey, input json.RawMessage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; !ok && t.mode == TraceCapture {
+		t.entries[key] = &traceEntry{SDK: key.sdk, Service: key.service, Operation: key.operation, Input: input}
+	}
+}
+
+// load reads the capture file of key for replay, if it was not read yet.
+func (t *Trace) load(key traceKey) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.entries[key]; ok {
+		return nil
+	}
+	data, err := os.ReadFile(t.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w for %s in %s", ErrNotCaptured, key, t.dir)
+	}
+	if err != nil {
+		return err
+	}
+	var entry traceEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return fmt.Errorf("trace: reading %s: %w", t.path(key), err)
+	}
+	if len(entry.Responses) == 0 {
+		return fmt.Errorf("%w for %s in %s: the capture has no responses", ErrNotCaptured, key, t.dir)
+	}
+	t.entries[key] = &entry
+	return nil
+}
+
+// replay returns the next captured response of key, or the last one again
+// once all have been served, so that polls ending in a captured state
+// settle there.
+func (t *Trace) replay(key traceKey, req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil, fmt.Errorf("%w for %s in %s", ErrNotCaptured, key, t.dir)
+	}
+	i := min(t.served[key], len(entry.Responses)-1)
+	t.served[key]++
+	r := entry.Responses[i]
+	body := r.body()
+	Verbosef("Trace: replaying %s response %d of %d", key, i+1, len(entry.Responses))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Header:        r.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// capture reads the body of resp, replaces it with a copy for the SDK to
+// decode, and appends the response to the capture file of key.
+func (t *Trace) capture(key traceKey, resp *http.Response) error {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return fmt.Errorf("trace: reading %s response: %w", key, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[key]
+	if !ok {
+		return nil
+	}
+	entry.Responses = append(entry.Responses, newTraceResponse(resp, body))
+	// Without HTML escaping, XML bodies stay readable.
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(entry); err != nil {
+		return err
+	}
+	path := t.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	Verbosef("Trace: captured %s response %d", key, len(entry.Responses))
+	return os.WriteFile(path, data.Bytes(), 0o644)
+}
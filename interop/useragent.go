@@ -0,0 +1,82 @@
+package interop
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+)
+
+// UserAgentSuffix is added to the User-Agent header of every request sent by
+// clients set up with InstallUserAgentV1 or InstallUserAgentV2, so that the
+// calls a program made can be picked out of CloudTrail, which records the
+// header as userAgent. It is a product/version pair or a single token, in
+// which "{run-id}" stands for RunID, and defaults to
+// "sdk-migration-test/{run-id}"; programs bind it to a -user-agent flag.
+// Empty adds nothing. It is read for every call, so it can be set after the
+// clients are created.
+var UserAgentSuffix = "sdk-migration-test/{run-id}"
+
+// userAgentSuffix returns UserAgentSuffix with "{run-id}" replaced.
+func userAgentSuffix() string {
+	if !strings.Contains(UserAgentSuffix, "{run-id}") {
+		return UserAgentSuffix
+	}
+	return strings.ReplaceAll(UserAgentSuffix, "{run-id}", RunID())
+}
+
+// InstallUserAgentV1 makes every client created from sess afterwards append
+// UserAgentSuffix to the User-Agent header. The handler runs after v1's own
+// Build handlers, which write the SDK and Go versions.
+func InstallUserAgentV1(sess *session.Session) {
+	sess.Handlers.Build.PushBackNamed(request.NamedHandler{
+		Name: "interop.UserAgent",
+		Fn: func(r *request.Request) {
+			suffix := userAgentSuffix()
+			if suffix == "" {
+				return
+			}
+			product, version, ok := strings.Cut(suffix, "/")
+			suffix = userAgentToken(product)
+			if ok {
+				suffix += "/" + userAgentToken(version)
+			}
+			request.AddToUserAgent(r, suffix)
+		},
+	})
+}
+
+// InstallUserAgentV2 is InstallUserAgentV1 for every client created from cfg
+// afterwards, using v2's own user-agent middleware.
+func InstallUserAgentV2(cfg *awsv2.Config) {
+	cfg.APIOptions = append(cfg.APIOptions, func(stack *middleware.Stack) error {
+		suffix := userAgentSuffix()
+		if suffix == "" {
+			return nil
+		}
+		// v2 would turn the "/" of a single key into "-", so the pair is
+		// passed as a key and a value.
+		product, version, ok := strings.Cut(suffix, "/")
+		if !ok {
+			return awsmiddleware.AddUserAgentKey(product)(stack)
+		}
+		return awsmiddleware.AddUserAgentKeyValue(product, version)(stack)
+	})
+}
+
+// userAgentToken replaces every rune that is not allowed in a User-Agent
+// token with "-", the way v2 sanitizes keys and values, so that both SDKs
+// send the same suffix.
+func userAgentToken(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("!#$%&'*+-.^_`|~", r)) {
+			return r
+		}
+		return '-'
+	}, s)
+}
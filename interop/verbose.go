@@ -0,0 +1,13 @@
+package interop
+
+import "fmt"
+
+// Verbose enables diagnostic output. Programs bind it to a -verbose flag.
+var Verbose bool
+
+// Verbosef writes a diagnostic line to Output when Verbose is set.
+func Verbosef(format string, args ...interface{}) {
+	if Verbose {
+		fmt.Fprintf(Output, "[verbose] "+format+"\n", args...)
+	}
+}
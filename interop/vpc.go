@@ -0,0 +1,182 @@
+package interop
+
+import (
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+// VPCCIDRs is the set of CIDR blocks associated with a VPC: the primary IPv4
+// block, any secondary IPv4 blocks and any IPv6 blocks. Blocks that are
+// still being associated, or are being or have been disassociated, are left
+// out. Prefixes is sorted, IPv4 before IPv6, and holds no duplicates.
+type VPCCIDRs struct {
+	VpcID    string
+	Prefixes []netip.Prefix
+}
+
+// VPCCIDRsV1 parses the CIDR blocks of a v1 VPC.
+func VPCCIDRsV1(vpc *ec2v1.Vpc) (VPCCIDRs, error) {
+	c := VPCCIDRs{VpcID: aws.StringValue(vpc.VpcId)}
+	blocks := []string{aws.StringValue(vpc.CidrBlock)}
+	for _, assoc := range vpc.CidrBlockAssociationSet {
+		if assoc.CidrBlockState == nil || cidrAssociated(aws.StringValue(assoc.CidrBlockState.State)) {
+			blocks = append(blocks, aws.StringValue(assoc.CidrBlock))
+		}
+	}
+	for _, assoc := range vpc.Ipv6CidrBlockAssociationSet {
+		if assoc.Ipv6CidrBlockState == nil || cidrAssociated(aws.StringValue(assoc.Ipv6CidrBlockState.State)) {
+			blocks = append(blocks, aws.StringValue(assoc.Ipv6CidrBlock))
+		}
+	}
+	err := c.add(blocks)
+	return c, err
+}
+
+// VPCCIDRsV2 parses the CIDR blocks of a v2 VPC.
+func VPCCIDRsV2(vpc ec2types.Vpc) (VPCCIDRs, error) {
+	c := VPCCIDRs{VpcID: aws.StringValue(vpc.VpcId)}
+	blocks := []string{aws.StringValue(vpc.CidrBlock)}
+	for _, assoc := range vpc.CidrBlockAssociationSet {
+		if assoc.CidrBlockState == nil || cidrAssociated(string(assoc.CidrBlockState.State)) {
+			blocks = append(blocks, aws.StringValue(assoc.CidrBlock))
+		}
+	}
+	for _, assoc := range vpc.Ipv6CidrBlockAssociationSet {
+		if assoc.Ipv6CidrBlockState == nil || cidrAssociated(string(assoc.Ipv6CidrBlockState.State)) {
+			blocks = append(blocks, aws.StringValue(assoc.Ipv6CidrBlock))
+		}
+	}
+	err := c.add(blocks)
+	return c, err
+}
+
+// cidrAssociated reports whether a VpcCidrBlockStateCode means the block is
+// in use by the VPC.
+func cidrAssociated(state string) bool {
+	return state == "" || state == string(ec2types.VpcCidrBlockStateCodeAssociated)
+}
+
+// add parses blocks into c.Prefixes, skipping empty strings, and sorts the
+// result. Every block that does not parse is reported.
+func (c *VPCCIDRs) add(blocks []string) error {
+	var bad []string
+	for _, block := range blocks {
+		if block == "" {
+			continue
+		}
+		p, err := netip.ParsePrefix(block)
+		if err != nil {
+			bad = append(bad, block)
+			continue
+		}
+		if !slices.Contains(c.Prefixes, p) {
+			c.Prefixes = append(c.Prefixes, p)
+		}
+	}
+	slices.SortFunc(c.Prefixes, comparePrefixes)
+	if len(bad) > 0 {
+		return fmt.Errorf("VPC %s: invalid CIDR blocks %s", c.VpcID, strings.Join(bad, ", "))
+	}
+	return nil
+}
+
+// comparePrefixes orders prefixes by address, IPv4 before IPv6, then by
+// length.
+func comparePrefixes(a, b netip.Prefix) int {
+	if c := a.Addr().Compare(b.Addr()); c != 0 {
+		return c
+	}
+	return a.Bits() - b.Bits()
+}
+
+// CIDRMismatch is a VPC whose CIDR set differs between the SDKs. OnlyV1 and
+// OnlyV2 hold the blocks only one SDK reported; a VPC only one SDK returned
+// has all of its blocks on that side.
+type CIDRMismatch struct {
+	VpcID  string
+	OnlyV1 []netip.Prefix
+	OnlyV2 []netip.Prefix
+}
+
+func (m CIDRMismatch) String() string {
+	return fmt.Sprintf("%s: only v1 %v, only v2 %v", m.VpcID, m.OnlyV1, m.OnlyV2)
+}
+
+// DiffVPCCIDRs matches the VPCs of both SDKs by ID and returns those whose
+// CIDR sets differ, sorted by VPC ID.
+func DiffVPCCIDRs(v1, v2 []VPCCIDRs) []CIDRMismatch {
+	byID := make(map[string]*CIDRMismatch)
+	var ids []string
+	entry := func(id string) *CIDRMismatch {
+		m, ok := byID[id]
+		if !ok {
+			m = &CIDRMismatch{VpcID: id}
+			byID[id] = m
+			ids = append(ids, id)
+		}
+		return m
+	}
+	setsV2 := make(map[string][]netip.Prefix, len(v2))
+	for _, c := range v2 {
+		setsV2[c.VpcID] = c.Prefixes
+	}
+	setsV1 := make(map[string][]netip.Prefix, len(v1))
+	for _, c := range v1 {
+		setsV1[c.VpcID] = c.Prefixes
+		for _, p := range c.Prefixes {
+			if !slices.Contains(setsV2[c.VpcID], p) {
+				entry(c.VpcID).OnlyV1 = append(entry(c.VpcID).OnlyV1, p)
+			}
+		}
+	}
+	for _, c := range v2 {
+		for _, p := range c.Prefixes {
+			if !slices.Contains(setsV1[c.VpcID], p) {
+				entry(c.VpcID).OnlyV2 = append(entry(c.VpcID).OnlyV2, p)
+			}
+		}
+	}
+	slices.Sort(ids)
+	mismatches := make([]CIDRMismatch, 0, len(ids))
+	for _, id := range ids {
+		mismatches = append(mismatches, *byID[id])
+	}
+	return mismatches
+}
+
+// CIDROverlap is a pair of CIDR blocks of two different VPCs whose address
+// ranges overlap, which keeps the VPCs from being peered or attached to the
+// same transit gateway route table.
+type CIDROverlap struct {
+	VpcA, VpcB       string
+	PrefixA, PrefixB netip.Prefix
+}
+
+func (o CIDROverlap) String() string {
+	return fmt.Sprintf("%s %s overlaps %s %s", o.VpcA, o.PrefixA, o.VpcB, o.PrefixB)
+}
+
+// VPCCIDROverlaps returns every pair of overlapping blocks that belong to
+// different VPCs, in the order of vpcs and of their prefixes.
+func VPCCIDROverlaps(vpcs []VPCCIDRs) []CIDROverlap {
+	var overlaps []CIDROverlap
+	for i, a := range vpcs {
+		for _, b := range vpcs[i+1:] {
+			for _, pa := range a.Prefixes {
+				for _, pb := range b.Prefixes {
+					if pa.Overlaps(pb) {
+						overlaps = append(overlaps, CIDROverlap{VpcA: a.VpcID, VpcB: b.VpcID, PrefixA: pa, PrefixB: pb})
+					}
+				}
+			}
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// goldenDir holds the expected Markdown of every report below.
+const goldenDir = "testdata/markdown_report"
+
+// goldenReport is a fixed set of comparison results and the golden file its
+// Markdown summary must equal.
+type goldenReport struct {
+	name    string
+	file    string
+	results interop.ComparisonResults
+}
+
+// This example demonstrates ComparisonResults.RenderMarkdown, which turns the
+// results of compare_services into a GitHub-flavored summary table for pull
+// requests. Fixed results are rendered and compared with golden files in
+// testdata/markdown_report, so any change to the format shows up as a diff;
+// run with -update after an intended change to rewrite them. Nothing is sent
+// to AWS.
+func main() {
+	update := flag.Bool("update", false, "rewrite the golden files with the current rendering")
+	interop.ParseFlags()
+
+	fmt.Print("=== Markdown Report Test ===\n\n")
+
+	reports := []goldenReport{
+		{
+			name: "all resources match",
+			file: "match.md",
+			results: interop.ComparisonResults{
+				{Name: "ec2"},
+				{Name: "s3"},
+				{Name: "sqs", Diffs: []string{}},
+			},
+		},
+		{
+			name: "differences and errors",
+			file: "mismatch.md",
+			results: interop.ComparisonResults{
+				{Name: "dynamodb"},
+				{Name: "ec2", Diffs: []string{
+					`.i-0123456789abcdef0.State: "running" != "stopped"`,
+					`.i-0fedcba9876543210: only in second ({"ID":"i-0fedcba9876543210","State":"running","Type":"t3.micro"})`,
+				}},
+				{Name: "iam", Err: errors.New("describing with v2: operation error IAM: ListRoles, AccessDenied")},
+				{Name: "s3", Diffs: []string{`[2].Name: "logs" != "logs-eu"`}},
+				// A pipe in a name would otherwise end the table cell.
+				{Name: "sns|topics", Diffs: []string{`[0].DisplayName: "orders" != ""`}},
+			},
+		},
+	}
+
+	failures := 0
+	for i, r := range reports {
+		path := filepath.Join(goldenDir, r.file)
+		fmt.Printf("%d. Rendering %s and comparing with %s...\n", i+1, r.name, path)
+		got := r.results.RenderMarkdown()
+		if *update {
+			if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+				log.Fatalf("Failed to create %s: %v", goldenDir, err)
+			}
+			if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+				log.Fatalf("Failed to update %s: %v", path, err)
+			}
+			fmt.Printf("   ✓ Updated %s\n", path)
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatalf("Failed to read golden file (run with -update to create it): %v", err)
+		}
+		if got != string(want) {
+			fmt.Printf("   ✗ Rendering differs from %s\n", path)
+			printLineDiff(string(want), got)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ Matches (%d results, %d mismatched)\n", len(r.results), r.results.Mismatched())
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d renderings differ from their golden files (run with -update if the change is intended)\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ RenderMarkdown produces the expected GitHub-flavored summary")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None in the summary itself: results are diffed after normalization, so the table is SDK-neutral")
+	fmt.Println("  - A ✗ row means the SDKs' normalized results differ, or that one of them failed to describe the resource")
+}
+
+// printLineDiff prints the first line where got and want differ, with the
+// line number, so a format change can be located without a diff tool.
+func printLineDiff(want, got string) {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			fmt.Printf("       line %d\n       want: %q\n       got:  %q\n", i+1, w, g)
+			return
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/migrationlint"
+)
+
+// This command reports AWS SDK v1 usage in a Go module that will need changes
+// to migrate to SDK v2, printing each finding as file:line:col with the v2
+// equivalent. It accepts package patterns like go vet:
+//
+//	./migration_lint ./...
+func main() {
+	singlechecker.Main(migrationlint.Analyzer)
+}
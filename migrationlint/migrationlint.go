@@ -0,0 +1,112 @@
+// Package migrationlint provides a go/analysis analyzer that flags AWS SDK v1
+// usage which will not carry over unchanged to SDK v2, together with the v2
+// equivalent to move to.
+package migrationlint
+
+import (
+	"go/ast"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const (
+	v1Module     = "github.com/aws/aws-sdk-go"
+	v1AWSPackage = v1Module + "/aws"
+	v1Services   = v1Module + "/service/"
+)
+
+// Analyzer reports v1 pointer helpers, Describe calls without a context,
+// session.Must and imports of v1 packages that have no direct v2
+// counterpart.
+var Analyzer = &analysis.Analyzer{
+	Name:     "migrationlint",
+	Doc:      "report AWS SDK v1 usage that needs changes to migrate to SDK v2",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// v1OnlyPackages maps v1 packages without a same-named v2 counterpart to
+// what replaces them in v2.
+var v1OnlyPackages = map[string]string{
+	v1Module + "/aws/session":                        "github.com/aws/aws-sdk-go-v2/config (LoadDefaultConfig)",
+	v1Module + "/aws/request":                        "smithy-go middleware on the client's APIOptions",
+	v1Module + "/aws/awserr":                         "errors.As with smithy.APIError or the service's types.*Exception",
+	v1Module + "/aws/awsutil":                        "no equivalent; compare normalized values instead",
+	v1Module + "/aws/endpoints":                      "per-service EndpointResolverV2 or BaseEndpoint",
+	v1Module + "/aws/credentials/stscreds":           "github.com/aws/aws-sdk-go-v2/credentials/stscreds",
+	v1Module + "/aws/ec2metadata":                    "github.com/aws/aws-sdk-go-v2/feature/ec2/imds",
+	v1Module + "/service/s3/s3manager":               "github.com/aws/aws-sdk-go-v2/feature/s3/manager",
+	v1Module + "/service/dynamodb/dynamodbattribute": "github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue",
+	v1Module + "/service/dynamodb/expression":        "github.com/aws/aws-sdk-go-v2/feature/dynamodb/expression",
+}
+
+// pointerHelpers are the v1 aws package conversions whose v2 target fields
+// often change type (for example *int64 to *int32 or a plain value).
+var pointerHelpers = map[string]bool{
+	"String": true, "StringValue": true, "StringSlice": true, "StringValueSlice": true,
+	"Int64": true, "Int64Value": true, "Int64Slice": true,
+	"Int": true, "IntValue": true,
+	"Bool": true, "BoolValue": true,
+	"Float64": true, "Float64Value": true,
+	"Time": true, "TimeValue": true,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	for _, file := range pass.Files {
+		for _, spec := range file.Imports {
+			path, err := strconv.Unquote(spec.Path.Value)
+			if err != nil {
+				continue
+			}
+			if hint, ok := v1OnlyPackages[path]; ok {
+				pass.Reportf(spec.Pos(), "v1-only package %s; in v2 use %s", path, hint)
+			} else if strings.HasSuffix(path, "iface") && strings.HasPrefix(path, v1Services) {
+				pass.Reportf(spec.Pos(), "v1 interface package %s has no v2 counterpart; declare a small interface with only the methods you call", path)
+			}
+		}
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return
+		}
+		if fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func); ok {
+			checkCall(pass, call, fn)
+		}
+	})
+	return nil, nil
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, fn *types.Func) {
+	if fn.Pkg() == nil {
+		return
+	}
+	pkg := fn.Pkg().Path()
+	name := fn.Name()
+	sig, _ := fn.Type().(*types.Signature)
+
+	switch {
+	case pkg == v1AWSPackage && pointerHelpers[name] && sig != nil && sig.Recv() == nil:
+		pass.Reportf(call.Pos(), "v1 aws.%s; v2 has the same helper in github.com/aws/aws-sdk-go-v2/aws, but check the v2 field type (many *int64 fields become *int32 or plain values, enums become named types)", name)
+
+	case pkg == v1Module+"/aws/session" && name == "Must":
+		pass.Reportf(call.Pos(), "session.Must panics on configuration errors; in v2 call config.LoadDefaultConfig(ctx) and handle the returned error")
+
+	case strings.HasPrefix(pkg, v1Services) && sig != nil && sig.Recv() != nil &&
+		strings.HasPrefix(name, "Describe") && !strings.HasSuffix(name, "WithContext") && !strings.HasSuffix(name, "Request"):
+		op := strings.TrimSuffix(name, "Pages")
+		if strings.HasSuffix(name, "Pages") {
+			pass.Reportf(call.Pos(), "%s does not take a context; use %sPagesWithContext, or in v2 New%sPaginator(client, input).NextPage(ctx)", name, op, op)
+			return
+		}
+		pass.Reportf(call.Pos(), "%s does not take a context; use %sWithContext, or in v2 client.%s(ctx, input)", name, op, op)
+	}
+}
@@ -0,0 +1,16 @@
+package migrationlint_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/migrationlint"
+)
+
+// TestAnalyzer runs the analyzer over the fixture module in testdata, which
+// has one instance of every finding, and checks each against the want
+// comment on its line.
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), migrationlint.Analyzer, "fixture")
+}
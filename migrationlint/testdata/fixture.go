@@ -0,0 +1,42 @@
+// Package fixture contains one instance of every pattern migrationlint
+// reports, each followed by a want comment giving the expected finding,
+// which go test ./migrationlint checks. It is a module of its own, so that
+// analysistest can load it; run the linter over it from this directory
+// with:
+//
+//	../../migration_lint .
+package fixture
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session" // want `v1-only package github.com/aws/aws-sdk-go/aws/session`
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/ec2/ec2iface" // want `v1 interface package`
+	"github.com/aws/aws-sdk-go/service/s3/s3manager" // want `v1-only package github.com/aws/aws-sdk-go/service/s3/s3manager`
+)
+
+func describe(ctx context.Context) error {
+	sess := session.Must(session.NewSession()) // want `session.Must panics`
+
+	var client ec2iface.EC2API = ec2.New(sess)
+	_, err := client.DescribeInstances(&ec2.DescribeInstancesInput{ // want `DescribeInstances does not take a context`
+		MaxResults: aws.Int64(5), // want `v1 aws.Int64`
+	})
+	if err != nil {
+		return err
+	}
+
+	err = ec2.New(sess).DescribeVpcsPages(&ec2.DescribeVpcsInput{}, // want `DescribeVpcsPages does not take a context`
+		func(page *ec2.DescribeVpcsOutput, lastPage bool) bool { return true })
+	if err != nil {
+		return err
+	}
+
+	// Not reported: the context-aware variant.
+	_, err = ec2.New(sess).DescribeRegionsWithContext(ctx, &ec2.DescribeRegionsInput{})
+
+	_ = s3manager.NewUploader(sess)
+	return err
+}
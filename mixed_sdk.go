@@ -2,8 +2,14 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
+	"io"
 	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	// AWS SDK v1
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,271 +19,375 @@ import (
 	// AWS SDK v2
 	"github.com/aws/aws-sdk-go-v2/config"
 	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
 )
 
 // This example demonstrates using both SDK v1 and v2 in the same application.
 // We'll use v1 for EC2 operations and v2 for the same EC2 operations to compare.
 func main() {
-	fmt.Println("=== Mixed SDK Test: EC2 with v1 and v2 ===\n")
+	output := interop.OutputFlag(flag.CommandLine, "`format` of the resource listings: text or csv", interop.OutputText, interop.OutputCSV)
+	sortBy := flag.String("sort-by", string(interop.InstanceOrderID), "order EC2 instances are listed in: id, launch-time or type")
+	sample := flag.Int("sample", 3, "entries of each resource type to print in text output, or 0 for all of them; csv output has every entry")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+	tables := interop.Output
+	if *output == interop.OutputCSV {
+		// Keep stdout for the CSV rows; everything else goes to stderr.
+		interop.SetOutput(os.Stderr)
+	}
+	w := interop.Output
+
+	fmt.Fprint(w, "=== Mixed SDK Test: EC2 with v1 and v2 ===\n\n")
+
+	if *sample < 0 {
+		fmt.Fprintln(os.Stderr, "-sample must not be negative")
+		flag.Usage()
+		os.Exit(2)
+	}
+	order, err := interop.ParseInstanceOrder(*sortBy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid -sort-by: %v\n", err)
+		flag.Usage()
+		os.Exit(2)
+	}
 
 	// Initialize SDK v1 for EC2
-	fmt.Println("1. Initializing AWS SDK v1 for EC2...")
+	fmt.Fprintln(w, "1. Initializing AWS SDK v1 for EC2...")
 	sessV1, err := session.NewSession(&aws.Config{
-		Region: aws.String("us-east-1"),
+		Region: aws.String(cfg.Region),
 	})
 	if err != nil {
 		log.Fatalf("Failed to create v1 session: %v", err)
 	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
 	ec2ClientV1 := ec2.New(sessV1)
-	fmt.Println("   ✓ SDK v1 session and EC2 client created")
+	fmt.Fprintln(w, "   ✓ SDK v1 session and EC2 client created")
 
 	// Initialize SDK v2 for EC2
-	fmt.Println("\n2. Initializing AWS SDK v2 for EC2...")
+	fmt.Fprintln(w, "\n2. Initializing AWS SDK v2 for EC2...")
 	ctx := context.Background()
-	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion("us-east-1"))
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(cfg.Region))
 	if err != nil {
 		log.Fatalf("Failed to load v2 config: %v", err)
 	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
 	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
-	fmt.Println("   ✓ SDK v2 config and EC2 client created")
+	fmt.Fprintln(w, "   ✓ SDK v2 config and EC2 client created")
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// A dry run is authorized without listing anything: EC2 answers
+	// DryRunOperation when the call would have succeeded.
+	if interop.SkipIfDenied(ctx, "ec2:DescribeInstances", func(ctx context.Context) error {
+		_, err := ec2ClientV1.DescribeInstancesWithContext(ctx, &ec2.DescribeInstancesInput{DryRun: aws.Bool(true)})
+		return err
+	}) {
+		return
+	}
 
 	// Use v1 to list EC2 instances
-	fmt.Println("\n3. Using SDK v1 to list EC2 instances...")
+	fmt.Fprintln(w, "\n3. Using SDK v1 to list EC2 instances...")
 	instancesV1, err := ec2ClientV1.DescribeInstances(&ec2.DescribeInstancesInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list instances with v1: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list instances with v1: %v\n", err)
 	} else {
-		count := 0
+		var instances []*ec2.Instance
 		for _, reservation := range instancesV1.Reservations {
-			count += len(reservation.Instances)
+			instances = append(instances, reservation.Instances...)
 		}
-		fmt.Printf("   ✓ Found %d EC2 instances using SDK v1\n", count)
-		shown := 0
-		for _, reservation := range instancesV1.Reservations {
-			for _, instance := range reservation.Instances {
-				if shown < 3 {
-					name := "N/A"
-					for _, tag := range instance.Tags {
-						if aws.StringValue(tag.Key) == "Name" {
-							name = aws.StringValue(tag.Value)
-							break
-						}
-					}
-					fmt.Printf("     - %s (State: %s, Type: %s)\n",
-						aws.StringValue(instance.InstanceId),
-						aws.StringValue(instance.State.Name),
-						aws.StringValue(instance.InstanceType))
-					if name != "N/A" {
-						fmt.Printf("       Name: %s\n", name)
-					}
-					shown++
-				}
+		interop.SortInstancesV1(instances, order)
+		table := newTable("ID", "NAME", "STATE", "TYPE")
+		for _, instance := range instances {
+			state := ""
+			if instance.State != nil {
+				state = aws.StringValue(instance.State.Name)
 			}
+			table.AddRow(aws.StringValue(instance.InstanceId), nameTagV1(instance.Tags), state,
+				aws.StringValue(instance.InstanceType))
 		}
-		if count > 3 {
-			fmt.Printf("     ... and %d more\n", count-3)
-		}
+		fmt.Fprintf(w, "   ✓ Found %d EC2 instances using SDK v1\n", table.Len())
+		printTable(tables, table, *output, *sample)
 	}
 
 	// Use v1 to list VPCs
-	fmt.Println("\n4. Using SDK v1 to list VPCs...")
+	fmt.Fprintln(w, "\n4. Using SDK v1 to list VPCs...")
 	vpcsV1, err := ec2ClientV1.DescribeVpcs(&ec2.DescribeVpcsInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list VPCs with v1: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list VPCs with v1: %v\n", err)
 	} else {
-		fmt.Printf("   ✓ Found %d VPCs using SDK v1\n", len(vpcsV1.Vpcs))
-		for i, vpc := range vpcsV1.Vpcs {
-			if i < 3 {
-				name := "N/A"
-				for _, tag := range vpc.Tags {
-					if aws.StringValue(tag.Key) == "Name" {
-						name = aws.StringValue(tag.Value)
-						break
-					}
-				}
-				fmt.Printf("     - %s (CIDR: %s, Default: %v)\n",
-					aws.StringValue(vpc.VpcId),
-					aws.StringValue(vpc.CidrBlock),
-					aws.BoolValue(vpc.IsDefault))
-				if name != "N/A" {
-					fmt.Printf("       Name: %s\n", name)
-				}
-			}
-		}
-		if len(vpcsV1.Vpcs) > 3 {
-			fmt.Printf("     ... and %d more\n", len(vpcsV1.Vpcs)-3)
+		table := newTable("ID", "NAME", "CIDR", "DEFAULT")
+		for _, vpc := range vpcsV1.Vpcs {
+			table.AddRow(aws.StringValue(vpc.VpcId), nameTagV1(vpc.Tags), aws.StringValue(vpc.CidrBlock),
+				strconv.FormatBool(aws.BoolValue(vpc.IsDefault)))
 		}
+		fmt.Fprintf(w, "   ✓ Found %d VPCs using SDK v1\n", table.Len())
+		printTable(tables, table, *output, *sample)
 	}
 
 	// Use v1 to list Subnets
-	fmt.Println("\n5. Using SDK v1 to list Subnets...")
+	fmt.Fprintln(w, "\n5. Using SDK v1 to list Subnets...")
 	subnetsV1, err := ec2ClientV1.DescribeSubnets(&ec2.DescribeSubnetsInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list subnets with v1: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list subnets with v1: %v\n", err)
 	} else {
-		fmt.Printf("   ✓ Found %d Subnets using SDK v1\n", len(subnetsV1.Subnets))
-		for i, subnet := range subnetsV1.Subnets {
-			if i < 3 {
-				name := "N/A"
-				for _, tag := range subnet.Tags {
-					if aws.StringValue(tag.Key) == "Name" {
-						name = aws.StringValue(tag.Value)
-						break
-					}
-				}
-				fmt.Printf("     - %s (VPC: %s, CIDR: %s, AZ: %s)\n",
-					aws.StringValue(subnet.SubnetId),
-					aws.StringValue(subnet.VpcId),
-					aws.StringValue(subnet.CidrBlock),
-					aws.StringValue(subnet.AvailabilityZone))
-				if name != "N/A" {
-					fmt.Printf("       Name: %s\n", name)
-				}
-			}
-		}
-		if len(subnetsV1.Subnets) > 3 {
-			fmt.Printf("     ... and %d more\n", len(subnetsV1.Subnets)-3)
+		table := newTable("ID", "NAME", "VPC", "CIDR", "AZ")
+		for _, subnet := range subnetsV1.Subnets {
+			table.AddRow(aws.StringValue(subnet.SubnetId), nameTagV1(subnet.Tags), aws.StringValue(subnet.VpcId),
+				aws.StringValue(subnet.CidrBlock), aws.StringValue(subnet.AvailabilityZone))
 		}
+		fmt.Fprintf(w, "   ✓ Found %d Subnets using SDK v1\n", table.Len())
+		printTable(tables, table, *output, *sample)
 	}
 
 	// Use v2 to list EC2 instances
-	fmt.Println("\n6. Using SDK v2 to list EC2 instances...")
+	fmt.Fprintln(w, "\n6. Using SDK v2 to list EC2 instances...")
 	instancesV2, err := ec2ClientV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list instances with v2: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list instances with v2: %v\n", err)
 	} else {
-		count := 0
+		var instances []ec2types.Instance
 		for _, reservation := range instancesV2.Reservations {
-			count += len(reservation.Instances)
+			instances = append(instances, reservation.Instances...)
 		}
-		fmt.Printf("   ✓ Found %d EC2 instances using SDK v2\n", count)
-		shown := 0
-		for _, reservation := range instancesV2.Reservations {
-			for _, instance := range reservation.Instances {
-				if shown < 3 {
-					name := "N/A"
-					for _, tag := range instance.Tags {
-						if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
-							name = *tag.Value
-							break
-						}
-					}
-					stateStr := "unknown"
-					if instance.State != nil && instance.State.Name != "" {
-						stateStr = string(instance.State.Name)
-					}
-					typeStr := "unknown"
-					if instance.InstanceType != "" {
-						typeStr = string(instance.InstanceType)
-					}
-					instanceID := "N/A"
-					if instance.InstanceId != nil {
-						instanceID = *instance.InstanceId
-					}
-					fmt.Printf("     - %s (State: %s, Type: %s)\n", instanceID, stateStr, typeStr)
-					if name != "N/A" {
-						fmt.Printf("       Name: %s\n", name)
-					}
-					shown++
-				}
+		interop.SortInstancesV2(instances, order)
+		table := newTable("ID", "NAME", "STATE", "TYPE")
+		for _, instance := range instances {
+			state := ""
+			if instance.State != nil {
+				state = string(instance.State.Name)
 			}
+			table.AddRow(aws.StringValue(instance.InstanceId), nameTagV2(instance.Tags), state,
+				string(instance.InstanceType))
 		}
-		if count > 3 {
-			fmt.Printf("     ... and %d more\n", count-3)
-		}
+		fmt.Fprintf(w, "   ✓ Found %d EC2 instances using SDK v2\n", table.Len())
+		printTable(tables, table, *output, *sample)
 	}
 
 	// Use v2 to list VPCs
-	fmt.Println("\n7. Using SDK v2 to list VPCs...")
+	fmt.Fprintln(w, "\n7. Using SDK v2 to list VPCs...")
 	vpcsV2, err := ec2ClientV2.DescribeVpcs(ctx, &ec2v2.DescribeVpcsInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list VPCs with v2: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list VPCs with v2: %v\n", err)
 	} else {
-		fmt.Printf("   ✓ Found %d VPCs using SDK v2\n", len(vpcsV2.Vpcs))
-		for i, vpc := range vpcsV2.Vpcs {
-			if i < 3 {
-				name := "N/A"
-				for _, tag := range vpc.Tags {
-					if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
-						name = *tag.Value
-						break
-					}
-				}
-				vpcID := "N/A"
-				if vpc.VpcId != nil {
-					vpcID = *vpc.VpcId
-				}
-				cidr := "N/A"
-				if vpc.CidrBlock != nil {
-					cidr = *vpc.CidrBlock
-				}
-				isDefault := false
-				if vpc.IsDefault != nil {
-					isDefault = *vpc.IsDefault
-				}
-				fmt.Printf("     - %s (CIDR: %s, Default: %v)\n", vpcID, cidr, isDefault)
-				if name != "N/A" {
-					fmt.Printf("       Name: %s\n", name)
-				}
+		table := newTable("ID", "NAME", "CIDR", "DEFAULT")
+		for _, vpc := range vpcsV2.Vpcs {
+			isDefault := false
+			if vpc.IsDefault != nil {
+				isDefault = *vpc.IsDefault
 			}
+			table.AddRow(aws.StringValue(vpc.VpcId), nameTagV2(vpc.Tags), aws.StringValue(vpc.CidrBlock),
+				strconv.FormatBool(isDefault))
 		}
-		if len(vpcsV2.Vpcs) > 3 {
-			fmt.Printf("     ... and %d more\n", len(vpcsV2.Vpcs)-3)
-		}
+		fmt.Fprintf(w, "   ✓ Found %d VPCs using SDK v2\n", table.Len())
+		printTable(tables, table, *output, *sample)
 	}
 
 	// Use v2 to list Subnets
-	fmt.Println("\n8. Using SDK v2 to list Subnets...")
+	fmt.Fprintln(w, "\n8. Using SDK v2 to list Subnets...")
 	subnetsV2, err := ec2ClientV2.DescribeSubnets(ctx, &ec2v2.DescribeSubnetsInput{})
 	if err != nil {
-		log.Printf("   ✗ Failed to list subnets with v2: %v", err)
+		fmt.Fprintf(w, "   ✗ Failed to list subnets with v2: %v\n", err)
 	} else {
-		fmt.Printf("   ✓ Found %d Subnets using SDK v2\n", len(subnetsV2.Subnets))
-		for i, subnet := range subnetsV2.Subnets {
-			if i < 3 {
-				name := "N/A"
-				for _, tag := range subnet.Tags {
-					if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
-						name = *tag.Value
-						break
-					}
-				}
-				subnetID := "N/A"
-				if subnet.SubnetId != nil {
-					subnetID = *subnet.SubnetId
-				}
-				vpcID := "N/A"
-				if subnet.VpcId != nil {
-					vpcID = *subnet.VpcId
-				}
-				cidr := "N/A"
-				if subnet.CidrBlock != nil {
-					cidr = *subnet.CidrBlock
-				}
-				az := "N/A"
-				if subnet.AvailabilityZone != nil {
-					az = *subnet.AvailabilityZone
-				}
-				fmt.Printf("     - %s (VPC: %s, CIDR: %s, AZ: %s)\n", subnetID, vpcID, cidr, az)
-				if name != "N/A" {
-					fmt.Printf("       Name: %s\n", name)
-				}
+		table := newTable("ID", "NAME", "VPC", "CIDR", "AZ")
+		for _, subnet := range subnetsV2.Subnets {
+			table.AddRow(aws.StringValue(subnet.SubnetId), nameTagV2(subnet.Tags), aws.StringValue(subnet.VpcId),
+				aws.StringValue(subnet.CidrBlock), aws.StringValue(subnet.AvailabilityZone))
+		}
+		fmt.Fprintf(w, "   ✓ Found %d Subnets using SDK v2\n", table.Len())
+		printTable(tables, table, *output, *sample)
+	}
+
+	// Compare how both SDKs group subnets by availability zone
+	if subnetsV1 != nil && subnetsV2 != nil {
+		fmt.Fprintln(w, "\n9. Comparing subnets grouped by availability zone...")
+		byAZV1 := subnetsByAZV1(subnetsV1.Subnets)
+		byAZV2 := subnetsByAZV2(subnetsV2.Subnets)
+
+		azs := make([]string, 0, len(byAZV1))
+		for az := range byAZV1 {
+			azs = append(azs, az)
+		}
+		for az := range byAZV2 {
+			if _, ok := byAZV1[az]; !ok {
+				azs = append(azs, az)
+			}
+		}
+		sort.Strings(azs)
+
+		differing := 0
+		for _, az := range azs {
+			v1 := strings.Join(byAZV1[az], ", ")
+			v2 := strings.Join(byAZV2[az], ", ")
+			if v1 != v2 {
+				differing++
+				fmt.Fprintf(w, "   ✗ %s differs\n       v1: [%s]\n       v2: [%s]\n", az, v1, v2)
+				continue
 			}
+			fmt.Fprintf(w, "   ✓ %s: %d subnets\n", az, len(byAZV1[az]))
+		}
+		if differing == 0 {
+			fmt.Fprintf(w, "   ✓ Both SDKs produce the same AZ→subnet mapping across %d AZs\n", len(azs))
+		} else {
+			fmt.Fprintf(w, "   ✗ %d of %d AZs have a different subnet set\n", differing, len(azs))
+		}
+	}
+
+	// Compare the full CIDR set of each VPC, not only the primary block
+	if vpcsV1 != nil && vpcsV2 != nil {
+		fmt.Fprintln(w, "\n10. Comparing the CIDR blocks associated with each VPC...")
+		compareVPCCIDRs(vpcsV1.Vpcs, vpcsV2.Vpcs)
+	}
+
+	fmt.Fprintln(w, "\n=== Conclusion ===")
+	fmt.Fprintln(w, "✓ Both SDKs work independently in the same application")
+	fmt.Fprintln(w, "✓ Each SDK maintains its own session/config")
+	fmt.Fprintln(w, "✓ Both SDKs can authenticate using the same AWS credentials")
+	fmt.Fprintln(w, "\nKey differences between v1 and v2:")
+	fmt.Fprintln(w, "  - v1 uses pointers extensively (aws.String, aws.StringValue)")
+	fmt.Fprintln(w, "  - v2 uses native types and requires explicit nil checks")
+	fmt.Fprintln(w, "  - v2 requires context.Context for all operations")
+	fmt.Fprintln(w, "  - v2 uses strongly-typed enums instead of string pointers")
+	fmt.Fprintln(w, "\nThis demonstrates that you can gradually migrate services")
+	fmt.Fprintln(w, "from v1 to v2 without having to migrate everything at once.")
+}
+
+// newTable returns a table for one resource listing, indented to line up
+// with the step output.
+func newTable(headers ...string) *interop.TablePrinter {
+	table := interop.NewTablePrinter(headers...)
+	table.Indent = "     "
+	return table
+}
+
+// printTable writes table to w in the -output format: in text, its first
+// sample rows, or all of them when sample is 0, and in CSV every row.
+func printTable(w io.Writer, table *interop.TablePrinter, format string, sample int) {
+	if table.Len() == 0 {
+		return
+	}
+	table.MaxRows = sample
+	if err := table.Write(w, format); err != nil {
+		log.Printf("Warning: Failed to print table: %v", err)
+	}
+}
+
+// nameTagV1 returns the value of the Name tag, or "" if there is none.
+func nameTagV1(tags []*ec2.Tag) string {
+	for _, tag := range tags {
+		if aws.StringValue(tag.Key) == "Name" {
+			return aws.StringValue(tag.Value)
+		}
+	}
+	return ""
+}
+
+// nameTagV2 is the v2 counterpart of nameTagV1.
+func nameTagV2(tags []ec2types.Tag) string {
+	for _, tag := range tags {
+		if tag.Key != nil && *tag.Key == "Name" && tag.Value != nil {
+			return *tag.Value
+		}
+	}
+	return ""
+}
+
+// subnetAZKey returns the key subnets are grouped under: the AZ name, or the
+// AZ ID when the name is not set.
+func subnetAZKey(az, azID string) string {
+	if az != "" {
+		return az
+	}
+	if azID != "" {
+		return "id:" + azID
+	}
+	return "unknown"
+}
+
+// subnetsByAZV1 groups v1 subnets by availability zone, each with a sorted
+// list of subnet IDs.
+func subnetsByAZV1(subnets []*ec2.Subnet) map[string][]string {
+	byAZ := make(map[string][]string)
+	for _, subnet := range subnets {
+		key := subnetAZKey(aws.StringValue(subnet.AvailabilityZone), aws.StringValue(subnet.AvailabilityZoneId))
+		byAZ[key] = append(byAZ[key], aws.StringValue(subnet.SubnetId))
+	}
+	for _, ids := range byAZ {
+		sort.Strings(ids)
+	}
+	return byAZ
+}
+
+// subnetsByAZV2 is the v2 counterpart of subnetsByAZV1.
+func subnetsByAZV2(subnets []ec2types.Subnet) map[string][]string {
+	byAZ := make(map[string][]string)
+	for _, subnet := range subnets {
+		az, azID, subnetID := "", "", ""
+		if subnet.AvailabilityZone != nil {
+			az = *subnet.AvailabilityZone
+		}
+		if subnet.AvailabilityZoneId != nil {
+			azID = *subnet.AvailabilityZoneId
+		}
+		if subnet.SubnetId != nil {
+			subnetID = *subnet.SubnetId
+		}
+		key := subnetAZKey(az, azID)
+		byAZ[key] = append(byAZ[key], subnetID)
+	}
+	for _, ids := range byAZ {
+		sort.Strings(ids)
+	}
+	return byAZ
+}
+
+// compareVPCCIDRs parses the CIDR blocks each SDK reports for every VPC,
+// secondary and IPv6 blocks included, and prints the VPCs whose sets differ
+// and the blocks that overlap between VPCs to interop.Output.
+func compareVPCCIDRs(vpcsV1 []*ec2.Vpc, vpcsV2 []ec2types.Vpc) {
+	w := interop.Output
+	var cidrsV1, cidrsV2 []interop.VPCCIDRs
+	for _, vpc := range vpcsV1 {
+		c, err := interop.VPCCIDRsV1(vpc)
+		if err != nil {
+			fmt.Fprintf(w, "   ✗ v1 %v\n", err)
 		}
-		if len(subnetsV2.Subnets) > 3 {
-			fmt.Printf("     ... and %d more\n", len(subnetsV2.Subnets)-3)
+		cidrsV1 = append(cidrsV1, c)
+	}
+	for _, vpc := range vpcsV2 {
+		c, err := interop.VPCCIDRsV2(vpc)
+		if err != nil {
+			fmt.Fprintf(w, "   ✗ v2 %v\n", err)
 		}
+		cidrsV2 = append(cidrsV2, c)
 	}
 
-	fmt.Println("\n=== Conclusion ===")
-	fmt.Println("✓ Both SDKs work independently in the same application")
-	fmt.Println("✓ Each SDK maintains its own session/config")
-	fmt.Println("✓ Both SDKs can authenticate using the same AWS credentials")
-	fmt.Println("\nKey differences between v1 and v2:")
-	fmt.Println("  - v1 uses pointers extensively (aws.String, aws.StringValue)")
-	fmt.Println("  - v2 uses native types and requires explicit nil checks")
-	fmt.Println("  - v2 requires context.Context for all operations")
-	fmt.Println("  - v2 uses strongly-typed enums instead of string pointers")
-	fmt.Println("\nThis demonstrates that you can gradually migrate services")
-	fmt.Println("from v1 to v2 without having to migrate everything at once.")
+	mismatches := interop.DiffVPCCIDRs(cidrsV1, cidrsV2)
+	for _, m := range mismatches {
+		fmt.Fprintf(w, "   ✗ %s\n", m)
+	}
+	if len(mismatches) == 0 {
+		blocks := 0
+		for _, c := range cidrsV1 {
+			blocks += len(c.Prefixes)
+			interop.Verbosef("%s: %v", c.VpcID, c.Prefixes)
+		}
+		fmt.Fprintf(w, "   ✓ Both SDKs report the same %d CIDR blocks across %d VPCs\n", blocks, len(cidrsV1))
+	} else {
+		fmt.Fprintf(w, "   ✗ %d VPCs have a different CIDR set\n", len(mismatches))
+	}
+
+	for _, o := range interop.VPCCIDROverlaps(cidrsV1) {
+		fmt.Fprintf(w, "   - %s\n", o)
+	}
 }
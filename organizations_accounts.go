@@ -0,0 +1,191 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	orgsv1 "github.com/aws/aws-sdk-go/service/organizations"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	orgsv2 "github.com/aws/aws-sdk-go-v2/service/organizations"
+	orgstypes "github.com/aws/aws-sdk-go-v2/service/organizations/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// account is an SDK-neutral view of an organization member account.
+type account struct {
+	ID     string
+	Name   string
+	Status string
+}
+
+func (a account) String() string {
+	return fmt.Sprintf("%s (%s) %s", a.ID, a.Name, a.Status)
+}
+
+// This example demonstrates listing the accounts of an AWS Organization with
+// both SDKs. Organizations only answers the management account (or a
+// delegated administrator), so it explains access-denied errors and skips
+// when the caller's account is not in an organization.
+func main() {
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== Organizations Accounts Interop Test ===\n\n")
+
+	// Organizations is a global service served from us-east-1.
+	region := cfg.Region
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	orgsClientV1 := orgsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	orgsClientV2 := orgsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// skipOn explains the errors that mean this account cannot list the
+	// organization, and reports whether err was one of them.
+	skipOn := func(err error) bool {
+		switch interop.ErrorCode(err) {
+		case orgsv1.ErrCodeAWSOrganizationsNotInUseException:
+			fmt.Println("   This account is not a member of an organization; nothing to compare.")
+			return true
+		case orgsv1.ErrCodeAccessDeniedException:
+			fmt.Println("   Access denied: ListAccounts must be run from the organization's management")
+			fmt.Println("   account or a delegated administrator, with organizations:ListAccounts allowed.")
+			return true
+		}
+		return false
+	}
+
+	// Use v1 to list accounts
+	fmt.Println("1. Using SDK v1 to list accounts (all pages)...")
+	accountsV1 := make(map[string]account)
+	err = orgsClientV1.ListAccountsPagesWithContext(ctx, &orgsv1.ListAccountsInput{},
+		func(page *orgsv1.ListAccountsOutput, lastPage bool) bool {
+			for _, a := range page.Accounts {
+				acct := accountFromV1(a)
+				accountsV1[acct.ID] = acct
+			}
+			return true
+		})
+	if err != nil {
+		if skipOn(err) {
+			return
+		}
+		log.Fatalf("Failed to list accounts with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d accounts using SDK v1\n", len(accountsV1))
+
+	// Use v2 to list accounts
+	fmt.Println("\n2. Using SDK v2 to list accounts (all pages)...")
+	accountsV2 := make(map[string]account)
+	paginator := orgsv2.NewListAccountsPaginator(orgsClientV2, &orgsv2.ListAccountsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			if skipOn(err) {
+				return
+			}
+			log.Fatalf("Failed to list accounts with v2: %v", err)
+		}
+		for _, a := range page.Accounts {
+			acct := accountFromV2(a)
+			accountsV2[acct.ID] = acct
+		}
+	}
+	fmt.Printf("   ✓ Found %d accounts using SDK v2\n", len(accountsV2))
+
+	// Compare
+	fmt.Println("\n3. Comparing accounts...")
+	ids := make([]string, 0, len(accountsV1))
+	for id := range accountsV1 {
+		ids = append(ids, id)
+	}
+	for id := range accountsV2 {
+		if _, ok := accountsV1[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	mismatches := 0
+	for _, id := range ids {
+		v1, inV1 := accountsV1[id]
+		v2, inV2 := accountsV2[id]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", v2)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", v1)
+			mismatches++
+		case v1 != v2:
+			fmt.Printf("   ✗ Account %s differs\n       v1: %s\n       v2: %s\n", id, v1, v2)
+			mismatches++
+		default:
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d accounts did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs list the same %d accounts\n", len(ids))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Status is a *string, v2 uses the types.AccountStatus enum")
+	fmt.Println("  - v2 also returns the newer State field, which v1's frozen models do not have")
+	fmt.Println("  - v1 paginates with ListAccountsPages, v2 with NewListAccountsPaginator")
+}
+
+func accountFromV1(a *orgsv1.Account) account {
+	return account{
+		ID:     aws.StringValue(a.Id),
+		Name:   aws.StringValue(a.Name),
+		Status: aws.StringValue(a.Status),
+	}
+}
+
+func accountFromV2(a orgstypes.Account) account {
+	acct := account{Status: string(a.Status)}
+	if a.Id != nil {
+		acct.ID = *a.Id
+	}
+	if a.Name != nil {
+		acct.Name = *a.Name
+	}
+	return acct
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	ecrv1 "github.com/aws/aws-sdk-go/service/ecr"
+	route53v1 "github.com/aws/aws-sdk-go/service/route53"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+	sqsv1 "github.com/aws/aws-sdk-go/service/sqs"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ecrv2 "github.com/aws/aws-sdk-go-v2/service/ecr"
+	ecrtypes "github.com/aws/aws-sdk-go-v2/service/ecr/types"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// countingTransport answers every request with an empty 200 response and
+// counts them, so that a call the filter blocked can be told from one that
+// reached the network.
+type countingTransport struct {
+	mu    sync.Mutex
+	sends int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.sends++
+	t.mu.Unlock()
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/xml"}},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+func (t *countingTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sends
+}
+
+// blockedCall is one mutating operation that interop.ReadOnly must reject
+// before it reaches the transport.
+type blockedCall struct {
+	name string
+	call func(ctx context.Context) error
+}
+
+// This example demonstrates interop.ReadOnly, the filter behind -read-only.
+// Both SDKs send every request through an in-process transport that counts
+// them, and a mutating operation of each family the filter denies, from
+// TerminateInstances to multipart uploads, batch writes and PartiQL, is
+// called through the filter: each must fail with ErrOperationBlocked
+// without a single request reaching the transport. A read is then sent to
+// check that the transport does count. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Read-Only Filter Test ===\n\n")
+
+	ctx := context.Background()
+	transport := &countingTransport{}
+	httpClient := &http.Client{Transport: transport}
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	// Both SDKs get the transport after loading their configuration, as a CA
+	// bundle from AWS_CA_BUNDLE can only be loaded into an *http.Transport.
+	sessV1.Config.HTTPClient = httpClient
+	interop.ReadOnly.InstallV1(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithRetryMaxAttempts(1),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	cfgV2.HTTPClient = httpClient
+	interop.ReadOnly.InstallV2(&cfgV2)
+
+	ec2ClientV1, s3ClientV1 := ec2v1.New(sessV1), s3v1.New(sessV1)
+	dynamodbClientV1, sqsClientV1 := dynamodbv1.New(sessV1), sqsv1.New(sessV1)
+	ecrClientV1, route53ClientV1 := ecrv1.New(sessV1), route53v1.New(sessV1)
+	ec2ClientV2, s3ClientV2 := ec2v2.NewFromConfig(cfgV2), s3v2.NewFromConfig(cfgV2)
+	dynamodbClientV2, sqsClientV2 := dynamodbv2.NewFromConfig(cfgV2), sqsv2.NewFromConfig(cfgV2)
+	ecrClientV2 := ecrv2.NewFromConfig(cfgV2)
+
+	bucket, key, uploadID := aws.String("sdk-migration-test"), aws.String("object"), aws.String("upload")
+	table, queueURL := aws.String("sdk-migration-test"), aws.String("https://sqs.us-east-1.amazonaws.com/123456789012/sdk-migration-test")
+	item := map[string]dynamodbtypes.AttributeValue{"id": &dynamodbtypes.AttributeValueMemberS{Value: "1"}}
+
+	callsV1 := []blockedCall{
+		{"EC2 TerminateInstances", func(ctx context.Context) error {
+			_, err := ec2ClientV1.TerminateInstancesWithContext(ctx, &ec2v1.TerminateInstancesInput{InstanceIds: []*string{aws.String("i-0123456789abcdef0")}})
+			return err
+		}},
+		{"EC2 ResetImageAttribute", func(ctx context.Context) error {
+			_, err := ec2ClientV1.ResetImageAttributeWithContext(ctx, &ec2v1.ResetImageAttributeInput{ImageId: aws.String("ami-0123456789abcdef0"), Attribute: aws.String("launchPermission")})
+			return err
+		}},
+		{"S3 DeleteBucket", func(ctx context.Context) error {
+			_, err := s3ClientV1.DeleteBucketWithContext(ctx, &s3v1.DeleteBucketInput{Bucket: bucket})
+			return err
+		}},
+		{"S3 UploadPart", func(ctx context.Context) error {
+			_, err := s3ClientV1.UploadPartWithContext(ctx, &s3v1.UploadPartInput{Bucket: bucket, Key: key, UploadId: uploadID, PartNumber: aws.Int64(1)})
+			return err
+		}},
+		{"S3 CompleteMultipartUpload", func(ctx context.Context) error {
+			_, err := s3ClientV1.CompleteMultipartUploadWithContext(ctx, &s3v1.CompleteMultipartUploadInput{Bucket: bucket, Key: key, UploadId: uploadID})
+			return err
+		}},
+		{"S3 AbortMultipartUpload", func(ctx context.Context) error {
+			_, err := s3ClientV1.AbortMultipartUploadWithContext(ctx, &s3v1.AbortMultipartUploadInput{Bucket: bucket, Key: key, UploadId: uploadID})
+			return err
+		}},
+		{"DynamoDB BatchWriteItem", func(ctx context.Context) error {
+			_, err := dynamodbClientV1.BatchWriteItemWithContext(ctx, &dynamodbv1.BatchWriteItemInput{RequestItems: map[string][]*dynamodbv1.WriteRequest{
+				*table: {{DeleteRequest: &dynamodbv1.DeleteRequest{Key: map[string]*dynamodbv1.AttributeValue{"id": {S: aws.String("1")}}}}},
+			}})
+			return err
+		}},
+		{"DynamoDB TransactWriteItems", func(ctx context.Context) error {
+			_, err := dynamodbClientV1.TransactWriteItemsWithContext(ctx, &dynamodbv1.TransactWriteItemsInput{TransactItems: []*dynamodbv1.TransactWriteItem{
+				{Delete: &dynamodbv1.Delete{TableName: table, Key: map[string]*dynamodbv1.AttributeValue{"id": {S: aws.String("1")}}}},
+			}})
+			return err
+		}},
+		{"DynamoDB ExecuteStatement", func(ctx context.Context) error {
+			_, err := dynamodbClientV1.ExecuteStatementWithContext(ctx, &dynamodbv1.ExecuteStatementInput{Statement: aws.String(`DELETE FROM "sdk-migration-test" WHERE id = '1'`)})
+			return err
+		}},
+		{"SQS PurgeQueue", func(ctx context.Context) error {
+			_, err := sqsClientV1.PurgeQueueWithContext(ctx, &sqsv1.PurgeQueueInput{QueueUrl: queueURL})
+			return err
+		}},
+		{"ECR BatchDeleteImage", func(ctx context.Context) error {
+			_, err := ecrClientV1.BatchDeleteImageWithContext(ctx, &ecrv1.BatchDeleteImageInput{RepositoryName: aws.String("sdk-migration-test"), ImageIds: []*ecrv1.ImageIdentifier{{ImageTag: aws.String("latest")}}})
+			return err
+		}},
+		{"Route 53 ChangeResourceRecordSets", func(ctx context.Context) error {
+			_, err := route53ClientV1.ChangeResourceRecordSetsWithContext(ctx, &route53v1.ChangeResourceRecordSetsInput{HostedZoneId: aws.String("Z0123456789"), ChangeBatch: &route53v1.ChangeBatch{}})
+			return err
+		}},
+	}
+
+	// v2 validates required parameters before the filter runs, so every
+	// input is complete. There is no v2 Route 53 client in this module.
+	callsV2 := []blockedCall{
+		{"EC2 TerminateInstances", func(ctx context.Context) error {
+			_, err := ec2ClientV2.TerminateInstances(ctx, &ec2v2.TerminateInstancesInput{InstanceIds: []string{"i-0123456789abcdef0"}})
+			return err
+		}},
+		{"S3 DeleteBucket", func(ctx context.Context) error {
+			_, err := s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{Bucket: bucket})
+			return err
+		}},
+		{"S3 UploadPart", func(ctx context.Context) error {
+			_, err := s3ClientV2.UploadPart(ctx, &s3v2.UploadPartInput{Bucket: bucket, Key: key, UploadId: uploadID, PartNumber: aws.Int32(1)})
+			return err
+		}},
+		{"S3 CompleteMultipartUpload", func(ctx context.Context) error {
+			_, err := s3ClientV2.CompleteMultipartUpload(ctx, &s3v2.CompleteMultipartUploadInput{Bucket: bucket, Key: key, UploadId: uploadID})
+			return err
+		}},
+		{"S3 AbortMultipartUpload", func(ctx context.Context) error {
+			_, err := s3ClientV2.AbortMultipartUpload(ctx, &s3v2.AbortMultipartUploadInput{Bucket: bucket, Key: key, UploadId: uploadID})
+			return err
+		}},
+		{"DynamoDB BatchWriteItem", func(ctx context.Context) error {
+			_, err := dynamodbClientV2.BatchWriteItem(ctx, &dynamodbv2.BatchWriteItemInput{RequestItems: map[string][]dynamodbtypes.WriteRequest{
+				*table: {{DeleteRequest: &dynamodbtypes.DeleteRequest{Key: item}}},
+			}})
+			return err
+		}},
+		{"DynamoDB TransactWriteItems", func(ctx context.Context) error {
+			_, err := dynamodbClientV2.TransactWriteItems(ctx, &dynamodbv2.TransactWriteItemsInput{TransactItems: []dynamodbtypes.TransactWriteItem{
+				{Delete: &dynamodbtypes.Delete{TableName: table, Key: item}},
+			}})
+			return err
+		}},
+		{"DynamoDB ExecuteStatement", func(ctx context.Context) error {
+			_, err := dynamodbClientV2.ExecuteStatement(ctx, &dynamodbv2.ExecuteStatementInput{Statement: aws.String(`DELETE FROM "sdk-migration-test" WHERE id = '1'`)})
+			return err
+		}},
+		{"SQS PurgeQueue", func(ctx context.Context) error {
+			_, err := sqsClientV2.PurgeQueue(ctx, &sqsv2.PurgeQueueInput{QueueUrl: queueURL})
+			return err
+		}},
+		{"ECR BatchDeleteImage", func(ctx context.Context) error {
+			_, err := ecrClientV2.BatchDeleteImage(ctx, &ecrv2.BatchDeleteImageInput{RepositoryName: aws.String("sdk-migration-test"), ImageIds: []ecrtypes.ImageIdentifier{{ImageTag: aws.String("latest")}}})
+			return err
+		}},
+	}
+
+	failures := 0
+	runBlocked := func(calls []blockedCall) {
+		for _, c := range calls {
+			before := transport.count()
+			err := c.call(ctx)
+			sent := transport.count() - before
+			switch {
+			case !errors.Is(err, interop.ErrOperationBlocked):
+				fmt.Printf("   ✗ %s: got %v, want ErrOperationBlocked\n", c.name, err)
+				failures++
+			case sent != 0:
+				fmt.Printf("   ✗ %s: blocked, but %d requests reached the transport\n", c.name, sent)
+				failures++
+			default:
+				fmt.Printf("   ✓ %s blocked, 0 requests sent\n", c.name)
+			}
+		}
+	}
+
+	fmt.Printf("1. Calling %d mutating operations with SDK v1 in read-only mode...\n", len(callsV1))
+	runBlocked(callsV1)
+
+	fmt.Printf("\n2. Calling %d mutating operations with SDK v2 in read-only mode...\n", len(callsV2))
+	runBlocked(callsV2)
+
+	// Reads must still go through, or the checks above would pass for a
+	// transport that never counts anything. Their empty responses may not
+	// decode; only the request matters here.
+	fmt.Println("\n3. Checking that reads still reach the transport...")
+	before := transport.count()
+	_, errV1 := s3ClientV1.ListBucketsWithContext(ctx, &s3v1.ListBucketsInput{})
+	_, errV2 := s3ClientV2.ListBuckets(ctx, &s3v2.ListBucketsInput{})
+	if sent := transport.count() - before; sent != 2 || errors.Is(errV1, interop.ErrOperationBlocked) || errors.Is(errV2, interop.ErrOperationBlocked) {
+		fmt.Printf("   ✗ ListBuckets sent %d requests with both SDKs, want 2 (v1: %v, v2: %v)\n", sent, errV1, errV2)
+		failures++
+	} else {
+		fmt.Println("   ✓ ListBuckets sent 1 request with each SDK")
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d read-only checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ All %d mutating calls were blocked before the transport, in both SDKs, and reads still went through\n", len(callsV1)+len(callsV2))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 blocks in the Validate handlers, before parameter validation; v2 at the end of the Initialize step, after it, so v2 inputs must be valid to reach the filter")
+	fmt.Println("  - v1 returns the filter's error as is, v2 wraps it in a *smithy.OperationError; errors.Is finds ErrOperationBlocked in both")
+}
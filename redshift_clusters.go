@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	redshiftv1 "github.com/aws/aws-sdk-go/service/redshift"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	redshiftv2 "github.com/aws/aws-sdk-go-v2/service/redshift"
+	redshifttypes "github.com/aws/aws-sdk-go-v2/service/redshift/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// clusterPageSize is the MaxRecords of each DescribeClusters call, the
+// smallest Redshift accepts, so that pagination is exercised early.
+const clusterPageSize = 20
+
+// changingStatuses are the cluster statuses during which the node type,
+// size, endpoint or parameter groups may change between the v1 and the v2
+// listing.
+var changingStatuses = map[string]bool{
+	"available, prep-for-resize": true,
+	"available, resize-cleanup":  true,
+	"cancelling-resize":          true,
+	"creating":                   true,
+	"deleting":                   true,
+	"final-snapshot":             true,
+	"modifying":                  true,
+	"rebooting":                  true,
+	"renaming":                   true,
+	"resizing":                   true,
+	"rotating-keys":              true,
+	"updating-hsm":               true,
+}
+
+// cluster is an SDK-neutral view of a Redshift cluster. Endpoint is
+// address:port, or "-" while the cluster has none, as when it is being
+// created. ParameterGroups lists the parameter group names and
+// ParameterStatus their apply status, in the same order. Pending is the
+// node type and number of nodes a resize is moving to, or "" when none is
+// pending.
+type cluster struct {
+	ID              string
+	NodeType        string
+	Nodes           int64
+	Status          string
+	DBName          string
+	Endpoint        string
+	ParameterGroups string
+	ParameterStatus string
+	Pending         string
+}
+
+func (c cluster) String() string {
+	s := fmt.Sprintf("%s %s x%d %s db=%s endpoint=%s params=%s(%s)",
+		c.ID, c.NodeType, c.Nodes, c.Status, c.DBName, c.Endpoint, c.ParameterGroups, c.ParameterStatus)
+	if c.Pending != "" {
+		s += " pending=" + c.Pending
+	}
+	return s
+}
+
+// changing reports whether the cluster is in one of changingStatuses.
+func (c cluster) changing() bool {
+	return changingStatuses[c.Status]
+}
+
+// stable returns c without the fields that change while the cluster is
+// being modified, for comparing a cluster that may have moved on between
+// the two listings.
+func (c cluster) stable() cluster {
+	c.NodeType, c.Nodes, c.Status, c.Endpoint, c.ParameterStatus, c.Pending = "", 0, "", "", "", ""
+	return c
+}
+
+// This example demonstrates describing Redshift clusters with both SDKs.
+// Every page is read with each SDK's paginator, and the identifiers, node
+// types, sizes, statuses, endpoints and parameter groups are compared. The
+// endpoint and the parameter group status are nested structs, and the
+// endpoint is nil while a cluster is being created, so they exercise
+// converters that assume both are set. Clusters in a changing status, such
+// as modifying or resizing, are only compared on the fields that do not
+// move during the change. An account with no clusters is reported, not
+// treated as a failure.
+func main() {
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	region := flag.String("region", "us-east-1", "region to describe clusters in")
+	interop.ParseFlags()
+
+	fmt.Print("=== Redshift Clusters Interop Test ===\n\n")
+
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(*region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	redshiftClientV1 := redshiftv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	redshiftClientV2 := redshiftv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Use v1 to describe clusters
+	fmt.Printf("1. Using SDK v1 to describe clusters in %s (all pages)...\n", *region)
+	clustersV1 := make(map[string]cluster)
+	pagesV1 := 0
+	err = redshiftClientV1.DescribeClustersPagesWithContext(ctx, &redshiftv1.DescribeClustersInput{
+		MaxRecords: aws.Int64(clusterPageSize),
+	}, func(page *redshiftv1.DescribeClustersOutput, lastPage bool) bool {
+		pagesV1++
+		for _, c := range page.Clusters {
+			clustersV1[aws.StringValue(c.ClusterIdentifier)] = clusterFromV1(c)
+		}
+		return true
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe clusters with v1: %v", err)
+	}
+	fmt.Printf("   ✓ Found %d clusters in %d pages using SDK v1\n", len(clustersV1), pagesV1)
+
+	// Use v2 to describe clusters
+	fmt.Printf("\n2. Using SDK v2 to describe clusters in %s (all pages)...\n", *region)
+	clustersV2 := make(map[string]cluster)
+	pagesV2 := 0
+	paginator := redshiftv2.NewDescribeClustersPaginator(redshiftClientV2, &redshiftv2.DescribeClustersInput{
+		MaxRecords: aws.Int32(clusterPageSize),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			log.Fatalf("Failed to describe clusters with v2: %v", err)
+		}
+		pagesV2++
+		for _, c := range page.Clusters {
+			clustersV2[aws.StringValue(c.ClusterIdentifier)] = clusterFromV2(c)
+		}
+	}
+	fmt.Printf("   ✓ Found %d clusters in %d pages using SDK v2\n", len(clustersV2), pagesV2)
+
+	if len(clustersV1) == 0 && len(clustersV2) == 0 {
+		fmt.Printf("\nNo clusters in %s; both SDKs agree on the empty list.\n", *region)
+		return
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing clusters...")
+	ids := make([]string, 0, len(clustersV1))
+	for id := range clustersV1 {
+		ids = append(ids, id)
+	}
+	for id := range clustersV2 {
+		if _, ok := clustersV1[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	mismatches := 0
+	changing := 0
+	for _, id := range ids {
+		v1, inV1 := clustersV1[id]
+		v2, inV2 := clustersV2[id]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", v2)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", v1)
+			mismatches++
+		case v1 == v2:
+			if v1.changing() {
+				changing++
+			}
+			fmt.Printf("   ✓ %s\n", v1)
+		case (v1.changing() || v2.changing()) && v1.stable() == v2.stable():
+			// The cluster moved on between the two listings, which says
+			// nothing about the SDKs.
+			changing++
+			fmt.Printf("   ✓ %s (changing: v1 saw %s, v2 saw %s; compared without size, status and endpoint)\n", v2, v1.Status, v2.Status)
+		default:
+			fmt.Printf("   ✗ Cluster %s differs\n       v1: %s\n       v2: %s\n", id, v1, v2)
+			mismatches++
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d clusters did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs describe the same %d clusters (%d in a changing status)\n", len(ids), changing)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - NumberOfNodes, Endpoint.Port and MaxRecords are *int64 in v1 and *int32 in v2")
+	fmt.Println("  - ClusterStatus and NodeType are *string in both; SnapshotScheduleState is *string in v1 and the types.ScheduleState enum in v2")
+	fmt.Println("  - Endpoint is a *Endpoint in both and nil while the cluster is created; ClusterParameterGroups is []*ClusterParameterGroupStatus in v1, []types.ClusterParameterGroupStatus in v2")
+	fmt.Println("  - v1 paginates with DescribeClustersPages, v2 with NewDescribeClustersPaginator")
+}
+
+// formatEndpoint formats an endpoint as address:port, or "-" when the
+// cluster has none yet.
+func formatEndpoint(address *string, port int64) string {
+	if address == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%s:%d", aws.StringValue(address), port)
+}
+
+// formatPending formats the node type and number of nodes a resize is
+// moving to, or "" when neither is pending.
+func formatPending(nodeType *string, nodes *int64) string {
+	var parts []string
+	if nodeType != nil {
+		parts = append(parts, aws.StringValue(nodeType))
+	}
+	if nodes != nil {
+		parts = append(parts, fmt.Sprintf("x%d", *nodes))
+	}
+	return strings.Join(parts, " ")
+}
+
+func clusterFromV1(c *redshiftv1.Cluster) cluster {
+	out := cluster{
+		ID:       aws.StringValue(c.ClusterIdentifier),
+		NodeType: aws.StringValue(c.NodeType),
+		Nodes:    aws.Int64Value(c.NumberOfNodes),
+		Status:   aws.StringValue(c.ClusterStatus),
+		DBName:   aws.StringValue(c.DBName),
+		Endpoint: "-",
+	}
+	if c.Endpoint != nil {
+		out.Endpoint = formatEndpoint(c.Endpoint.Address, aws.Int64Value(c.Endpoint.Port))
+	}
+	var names, statuses []string
+	for _, pg := range c.ClusterParameterGroups {
+		names = append(names, aws.StringValue(pg.ParameterGroupName))
+		statuses = append(statuses, aws.StringValue(pg.ParameterApplyStatus))
+	}
+	out.ParameterGroups, out.ParameterStatus = strings.Join(names, ","), strings.Join(statuses, ",")
+	if p := c.PendingModifiedValues; p != nil {
+		out.Pending = formatPending(p.NodeType, p.NumberOfNodes)
+	}
+	return out
+}
+
+func clusterFromV2(c redshifttypes.Cluster) cluster {
+	out := cluster{
+		ID:       aws.StringValue(c.ClusterIdentifier),
+		NodeType: aws.StringValue(c.NodeType),
+		Status:   aws.StringValue(c.ClusterStatus),
+		DBName:   aws.StringValue(c.DBName),
+		Endpoint: "-",
+	}
+	if c.NumberOfNodes != nil {
+		out.Nodes = int64(*c.NumberOfNodes)
+	}
+	if c.Endpoint != nil {
+		var port int64
+		if c.Endpoint.Port != nil {
+			port = int64(*c.Endpoint.Port)
+		}
+		out.Endpoint = formatEndpoint(c.Endpoint.Address, port)
+	}
+	var names, statuses []string
+	for _, pg := range c.ClusterParameterGroups {
+		names = append(names, aws.StringValue(pg.ParameterGroupName))
+		statuses = append(statuses, aws.StringValue(pg.ParameterApplyStatus))
+	}
+	out.ParameterGroups, out.ParameterStatus = strings.Join(names, ","), strings.Join(statuses, ",")
+	if p := c.PendingModifiedValues; p != nil {
+		var nodes *int64
+		if p.NumberOfNodes != nil {
+			nodes = aws.Int64(int64(*p.NumberOfNodes))
+		}
+		out.Pending = formatPending(p.NodeType, nodes)
+	}
+	return out
+}
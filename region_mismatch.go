@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// regionConfig is the shared config file the cases below run with: the
+// default profile sets a region, which v1 ignores unless it loads the
+// shared config.
+const regionConfig = `[default]
+region = eu-central-1
+
+[profile west]
+region = us-west-2
+`
+
+// regionCase is one way of choosing the region, and the outcome NewClients
+// must have.
+type regionCase struct {
+	name   string
+	env    map[string]string
+	region string
+	opts   []interop.ClientOption
+	// want is the region of the clients, or "" when NewClients must fail
+	// with a *interop.RegionMismatchError of wantV1 and wantV2.
+	want           string
+	wantV1, wantV2 string
+}
+
+// This example demonstrates the region check of interop.NewClients. A v1
+// session and a v2 config that resolve different regions make every
+// resource look missing in one of the SDKs, so NewClients fails with a
+// *interop.RegionMismatchError unless interop.WithRegionMismatch allows it.
+// The environment is replaced with a shared config file of its own, and the
+// region is chosen explicitly, through AWS_REGION, AWS_DEFAULT_REGION, the
+// default profile and a named profile. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Region Mismatch Test ===\n\n")
+
+	dir, err := os.MkdirTemp("", "region-mismatch")
+	if err != nil {
+		log.Fatalf("Failed to create temporary directory: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	configFile := filepath.Join(dir, "config")
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(configFile, []byte(regionConfig), 0o600); err != nil {
+		log.Fatalf("Failed to write %s: %v", configFile, err)
+	}
+	if err := os.WriteFile(credentialsFile, nil, 0o600); err != nil {
+		log.Fatalf("Failed to write %s: %v", credentialsFile, err)
+	}
+	// Only the files above may decide the region.
+	for _, name := range []string{"AWS_REGION", "AWS_DEFAULT_REGION", "AWS_PROFILE", "AWS_DEFAULT_PROFILE", "AWS_SDK_LOAD_CONFIG", "AWS_CA_BUNDLE"} {
+		os.Unsetenv(name)
+	}
+	os.Setenv("AWS_CONFIG_FILE", configFile)
+	os.Setenv("AWS_SHARED_CREDENTIALS_FILE", credentialsFile)
+
+	cases := []regionCase{
+		{name: "explicit region", region: "eu-west-1", want: "eu-west-1"},
+		{name: "AWS_REGION", env: map[string]string{"AWS_REGION": "ap-south-1"}, want: "ap-south-1"},
+		{name: "AWS_REGION and AWS_SDK_LOAD_CONFIG", env: map[string]string{"AWS_REGION": "ap-south-1", "AWS_SDK_LOAD_CONFIG": "1"}, want: "ap-south-1"},
+		{name: "AWS_DEFAULT_REGION", env: map[string]string{"AWS_DEFAULT_REGION": "sa-east-1"}, wantV1: "", wantV2: "sa-east-1"},
+		{name: "default profile region", wantV1: "", wantV2: "eu-central-1"},
+		{name: "default profile region with AWS_SDK_LOAD_CONFIG", env: map[string]string{"AWS_SDK_LOAD_CONFIG": "1"}, want: "eu-central-1"},
+		{name: "named profile region", opts: []interop.ClientOption{interop.WithProfile("west")}, want: "us-west-2"},
+		{name: "AWS_PROFILE region", env: map[string]string{"AWS_PROFILE": "west"}, wantV1: "", wantV2: "us-west-2"},
+		{name: "default profile region, allowed", opts: []interop.ClientOption{interop.WithRegionMismatch(true)}, want: "eu-central-1"},
+	}
+
+	ctx := context.Background()
+	failures := 0
+	fmt.Printf("1. Creating clients for %d ways of choosing the region...\n", len(cases))
+	for _, c := range cases {
+		for k, v := range c.env {
+			os.Setenv(k, v)
+		}
+		clients, err := interop.NewClients(ctx, c.region, c.opts...)
+		for k := range c.env {
+			os.Unsetenv(k)
+		}
+
+		var mismatch *interop.RegionMismatchError
+		switch {
+		case c.want == "" && errors.As(err, &mismatch):
+			if mismatch.V1 != c.wantV1 || mismatch.V2 != c.wantV2 {
+				fmt.Printf("   ✗ %s: mismatch v1 %q, v2 %q, want v1 %q, v2 %q\n", c.name, mismatch.V1, mismatch.V2, c.wantV1, c.wantV2)
+				failures++
+				continue
+			}
+			fmt.Printf("   ✓ %s: rejected, v1 %q and v2 %q\n", c.name, mismatch.V1, mismatch.V2)
+		case c.want == "":
+			fmt.Printf("   ✗ %s: want a region mismatch error, got %v\n", c.name, err)
+			failures++
+		case err != nil:
+			fmt.Printf("   ✗ %s: %v\n", c.name, err)
+			failures++
+		case clients.Region != c.want:
+			fmt.Printf("   ✗ %s: clients are for %q, want %q\n", c.name, clients.Region, c.want)
+			failures++
+		case interop.CheckRegions(clients.SessionV1, clients.ConfigV2) != nil:
+			fmt.Printf("   ✓ %s: created anyway, for the v2 region %s\n", c.name, clients.Region)
+		default:
+			fmt.Printf("   ✓ %s: both SDKs use %s\n", c.name, clients.Region)
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d region checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ NewClients refuses a v1 session and a v2 config for different regions unless told otherwise")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 only reads the region of a shared config profile with AWS_SDK_LOAD_CONFIG or SharedConfigEnable; v2 always does")
+	fmt.Println("  - v2 falls back to AWS_DEFAULT_REGION when AWS_REGION is unset; v1 does not without AWS_SDK_LOAD_CONFIG")
+	fmt.Println("  - An explicit region or AWS_REGION is read the same way by both SDKs")
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// missingInstance is the instance ID the fake EC2 answers with
+// InvalidInstanceID.NotFound.
+const missingInstance = "i-0000000000000000f"
+
+// fakeEC2 is an in-process EC2 that answers every call with one instance
+// named after the region in the request's signature, or with no instance
+// when the call has a filter, and counts the requests it receives.
+type fakeEC2 struct {
+	mu       sync.Mutex
+	requests int
+}
+
+func (f *fakeEC2) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	f.mu.Lock()
+	f.requests++
+	f.mu.Unlock()
+
+	// The credential scope is AKID/date/region/service/aws4_request.
+	region := "unknown"
+	if _, scope, ok := strings.Cut(r.Header.Get("Authorization"), "Credential="); ok {
+		if parts := strings.Split(scope, "/"); len(parts) > 2 {
+			region = parts[2]
+		}
+	}
+	w.Header().Set("Content-Type", "text/xml")
+	if strings.Contains(string(body), missingInstance) {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `<Response><Errors><Error><Code>InvalidInstanceID.NotFound</Code><Message>The instance ID '%s' does not exist</Message></Error></Errors><RequestID>req</RequestID></Response>`, missingInstance)
+		return
+	}
+	instances := "<item><instanceId>i-" + region + "</instanceId></item>"
+	if strings.Contains(string(body), "Filter.1") {
+		instances = ""
+	}
+	fmt.Fprintf(w, `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>req</requestId>`+
+		`<reservationSet><item><reservationId>r-1</reservationId><instancesSet>%s</instancesSet></item></reservationSet></DescribeInstancesResponse>`, instances)
+}
+
+// count returns the number of requests received so far.
+func (f *fakeEC2) count() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests
+}
+
+// instanceIDsV1 returns the instance IDs of a v1 DescribeInstances result.
+func instanceIDsV1(out *ec2v1.DescribeInstancesOutput) []string {
+	var ids []string
+	for _, reservation := range out.Reservations {
+		for _, inst := range reservation.Instances {
+			ids = append(ids, aws.StringValue(inst.InstanceId))
+		}
+	}
+	return ids
+}
+
+// instanceIDsV2 returns the instance IDs of a v2 DescribeInstances result.
+func instanceIDsV2(out *ec2v2.DescribeInstancesOutput) []string {
+	var ids []string
+	for _, reservation := range out.Reservations {
+		for _, inst := range reservation.Instances {
+			ids = append(ids, aws.StringValue(inst.InstanceId))
+		}
+	}
+	return ids
+}
+
+// This example demonstrates interop.ResultCache, which serves repeated
+// describe and list calls of both SDKs from the results of the first one
+// for the rest of a run. Both SDKs describe instances against an
+// in-process EC2 through one cache, and each step checks the hits, the
+// misses and the requests that reached the server: a repeated call is a
+// hit, while a call from the other SDK, with another input, in another
+// region, of an operation that is not a describe or list call, or one that
+// failed is a miss. Nothing is sent to AWS.
+func main() {
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as the cache hits")
+	interop.ParseFlags()
+
+	fmt.Print("=== Result Cache Test ===\n\n")
+
+	ctx := context.Background()
+	server := &fakeEC2{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	cache := interop.NewResultCache()
+	clientsFor := func(region string) (*ec2v1.EC2, *ec2v2.Client) {
+		sessV1, err := session.NewSession(&aws.Config{
+			Region:      aws.String(region),
+			Endpoint:    aws.String(httpServer.URL),
+			Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			MaxRetries:  aws.Int(0),
+		})
+		if err != nil {
+			log.Fatalf("Failed to create v1 session: %v", err)
+		}
+		cache.InstallV1(sessV1)
+		interop.InstallOperationSummaryV1(sessV1)
+
+		cfgV2, err := config.LoadDefaultConfig(ctx,
+			config.WithRegion(region),
+			config.WithBaseEndpoint(httpServer.URL),
+			config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+			config.WithRetryMaxAttempts(1),
+		)
+		if err != nil {
+			log.Fatalf("Failed to load v2 config: %v", err)
+		}
+		cache.InstallV2(&cfgV2)
+		interop.InstallOperationSummaryV2(&cfgV2)
+		return ec2v1.New(sessV1), ec2v2.NewFromConfig(cfgV2)
+	}
+	eastV1, eastV2 := clientsFor("us-east-1")
+	westV1, westV2 := clientsFor("eu-west-1")
+
+	failures := 0
+	step := 0
+	requests, stats := 0, interop.CacheStats{}
+	// check reports a step: the requests the server received and the cache
+	// hits and misses since the previous step, and that every call of the
+	// step saw the instances in want.
+	check := func(name string, wantRequests int, wantDelta interop.CacheStats, results [][]string, want []string) {
+		step++
+		fmt.Printf("%d. %s...\n", step, name)
+		gotRequests, gotStats := server.count(), cache.Stats()
+		delta := interop.CacheStats{Hits: gotStats.Hits - stats.Hits, Misses: gotStats.Misses - stats.Misses}
+		ok := true
+		if gotRequests-requests != wantRequests || delta != wantDelta {
+			fmt.Printf("   ✗ Got %s requests=%d, want %s requests=%d\n", delta, gotRequests-requests, wantDelta, wantRequests)
+			ok = false
+		}
+		for i, ids := range results {
+			if !slices.Equal(ids, want) {
+				fmt.Printf("   ✗ Call %d saw instances %v, want %v\n", i+1, ids, want)
+				ok = false
+			}
+		}
+		if ok {
+			fmt.Printf("   ✓ %s requests=%d\n", delta, wantRequests)
+		} else {
+			failures++
+		}
+		requests, stats = gotRequests, gotStats
+	}
+
+	describeV1 := func(client *ec2v1.EC2, input *ec2v1.DescribeInstancesInput) []string {
+		out, err := client.DescribeInstancesWithContext(ctx, input)
+		if err != nil {
+			log.Fatalf("Failed to describe instances with v1: %v", err)
+		}
+		return instanceIDsV1(out)
+	}
+	describeV2 := func(client *ec2v2.Client, input *ec2v2.DescribeInstancesInput) []string {
+		out, err := client.DescribeInstances(ctx, input)
+		if err != nil {
+			log.Fatalf("Failed to describe instances with v2: %v", err)
+		}
+		return instanceIDsV2(out)
+	}
+
+	first := describeV1(eastV1, &ec2v1.DescribeInstancesInput{})
+	second := describeV1(eastV1, &ec2v1.DescribeInstancesInput{})
+	check("Describing instances twice with SDK v1", 1, interop.CacheStats{Hits: 1, Misses: 1},
+		[][]string{first, second}, []string{"i-us-east-1"})
+
+	// A caller changing its result must not change what the next caller
+	// is served.
+	out, err := eastV1.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{})
+	if err != nil {
+		log.Fatalf("Failed to describe instances with v1: %v", err)
+	}
+	out.Reservations[0].Instances[0].InstanceId = aws.String("i-changed")
+	check("Changing a cached result and describing again", 0, interop.CacheStats{Hits: 2},
+		[][]string{describeV1(eastV1, &ec2v1.DescribeInstancesInput{})}, []string{"i-us-east-1"})
+
+	check("Describing instances twice with SDK v2", 1, interop.CacheStats{Hits: 1, Misses: 1},
+		[][]string{describeV2(eastV2, &ec2v2.DescribeInstancesInput{}), describeV2(eastV2, &ec2v2.DescribeInstancesInput{})},
+		[]string{"i-us-east-1"})
+
+	check("Describing with a filter in both SDKs", 2, interop.CacheStats{Misses: 2},
+		[][]string{
+			describeV1(eastV1, &ec2v1.DescribeInstancesInput{Filters: []*ec2v1.Filter{{Name: aws.String("instance-state-name"), Values: []*string{aws.String("running")}}}}),
+			describeV2(eastV2, &ec2v2.DescribeInstancesInput{Filters: []ec2types.Filter{{Name: aws.String("instance-state-name"), Values: []string{"running"}}}}),
+		}, nil)
+
+	check("Describing instances in eu-west-1 through the same cache", 2, interop.CacheStats{Hits: 2, Misses: 2},
+		[][]string{
+			describeV1(westV1, &ec2v1.DescribeInstancesInput{}), describeV1(westV1, &ec2v1.DescribeInstancesInput{}),
+			describeV2(westV2, &ec2v2.DescribeInstancesInput{}), describeV2(westV2, &ec2v2.DescribeInstancesInput{}),
+		}, []string{"i-eu-west-1"})
+
+	// Operations other than describe and list calls are never cached, nor
+	// are they counted.
+	for range 2 {
+		if _, err := eastV1.GetConsoleOutputWithContext(ctx, &ec2v1.GetConsoleOutputInput{InstanceId: aws.String("i-us-east-1")}); err != nil {
+			log.Fatalf("Failed to get the console output with v1: %v", err)
+		}
+	}
+	check("Getting the console output twice with SDK v1", 2, interop.CacheStats{}, nil, nil)
+
+	for range 2 {
+		if _, err := eastV1.DescribeInstancesWithContext(ctx, &ec2v1.DescribeInstancesInput{InstanceIds: []*string{aws.String(missingInstance)}}); err == nil {
+			log.Fatalf("Describing %s with v1 succeeded", missingInstance)
+		}
+		if _, err := eastV2.DescribeInstances(ctx, &ec2v2.DescribeInstancesInput{InstanceIds: []string{missingInstance}}); err == nil {
+			log.Fatalf("Describing %s with v2 succeeded", missingInstance)
+		}
+	}
+	check("Describing a missing instance twice in both SDKs", 4, interop.CacheStats{Misses: 4}, nil, nil)
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d cache checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ %d of %d describe calls were served from the cache, and only repeats of a successful call with the same SDK, region and input\n",
+		stats.Hits, stats.Hits+stats.Misses)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 serves a hit from the Validate handlers by clearing the request's Build, Sign, Send and Unmarshal handlers; v2 from an Initialize middleware that does not call the rest of the stack")
+	fmt.Println("  - Both SDKs' outputs are pointers to generated structs that awsutil.CopyOf can copy deeply, so neither shares a cached result with its caller")
+}
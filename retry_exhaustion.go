@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/ratelimit"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// unavailableBody is the DynamoDB error every request gets.
+const unavailableBody = `{"__type":"com.amazon.coral.availability#ServiceUnavailableException","message":"The service is temporarily unavailable."}`
+
+// elapsedSlack is added to the worst-case backoff when bounding how long a
+// call may take, for the attempts themselves and scheduling delays.
+const elapsedSlack = 500 * time.Millisecond
+
+// unavailableTransport answers every request with a retryable 503 and counts
+// the requests it saw.
+type unavailableTransport struct {
+	mu    sync.Mutex
+	calls int
+}
+
+func (t *unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.calls++
+	t.mu.Unlock()
+
+	// Both SDKs verify DynamoDB responses against X-Amz-Crc32.
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header: http.Header{
+			"Content-Type": []string{"application/x-amz-json-1.0"},
+			"X-Amz-Crc32":  []string{strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(unavailableBody))), 10)},
+		},
+		Body:    io.NopCloser(strings.NewReader(unavailableBody)),
+		Request: req,
+	}, nil
+}
+
+func (t *unavailableTransport) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls
+}
+
+// exhaustion is what one SDK's call left behind once it gave up.
+type exhaustion struct {
+	err        error
+	normalized *interop.NormalizedError
+	calls      int
+	elapsed    time.Duration
+}
+
+// This example demonstrates that both SDKs give up the same way when a
+// service never recovers. Every request gets a retryable 503; with the same
+// -max-retries and -max-backoff, both SDKs must make the same number of
+// attempts, finish within the time the backoff allows, and return errors
+// that interop.NormalizeError classifies identically, although v2 wraps the
+// last error in a retry.MaxAttemptsError.
+func main() {
+	maxRetries := flag.Int("max-retries", 3, "retries per request in both SDKs (0-10); v2 gets one more attempt")
+	maxBackoff := flag.Duration("max-backoff", 200*time.Millisecond, "longest delay between attempts in both SDKs")
+	interop.ParseFlags()
+
+	fmt.Print("=== Retry Exhaustion Test ===\n\n")
+
+	if *maxRetries < 0 || *maxRetries > 10 {
+		fmt.Fprintln(os.Stderr, "-max-retries must be between 0 and 10")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *maxBackoff < 10*time.Millisecond {
+		fmt.Fprintln(os.Stderr, "-max-backoff must be at least 10ms")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	region := "us-east-1"
+	attempts := interop.RetryMaxAttempts(*maxRetries)
+	// Neither SDK sleeps longer than the maximum backoff between attempts.
+	bound := time.Duration(*maxRetries)*(*maxBackoff) + elapsedSlack
+	fmt.Printf("v1 MaxRetries: %d, v2 RetryMaxAttempts: %d, max backoff: %s\n", *maxRetries, attempts, *maxBackoff)
+	fmt.Printf("Each call must give up within %s\n\n", bound)
+
+	// Configure both SDKs
+	fmt.Println("1. Configuring both SDKs against a transport that always answers 503...")
+	transportV1 := &unavailableTransport{}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transportV1},
+		Retryer: client.DefaultRetryer{
+			NumMaxRetries:    *maxRetries,
+			MinRetryDelay:    *maxBackoff / 8,
+			MaxRetryDelay:    *maxBackoff,
+			MinThrottleDelay: *maxBackoff / 8,
+			MaxThrottleDelay: *maxBackoff,
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	dynamoClientV1 := dynamodbv1.New(sessV1)
+
+	transportV2 := &unavailableTransport{}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transportV2}),
+		config.WithRetryer(func() awsv2.Retryer {
+			return retry.NewStandard(func(o *retry.StandardOptions) {
+				o.MaxAttempts = attempts
+				o.MaxBackoff = *maxBackoff
+				// The client-side retry quota would otherwise stop
+				// retries early once enough calls have failed.
+				o.RateLimiter = ratelimit.None
+			})
+		}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	dynamoClientV2 := dynamodbv2.NewFromConfig(cfgV2)
+	fmt.Println("   ✓ Both SDKs configured")
+
+	// Call with v1
+	fmt.Println("\n2. Calling ListTables with SDK v1...")
+	start := time.Now()
+	_, err = dynamoClientV1.ListTablesWithContext(ctx, &dynamodbv1.ListTablesInput{})
+	v1 := exhaustion{err: err, normalized: interop.NormalizeError(err), calls: transportV1.count(), elapsed: time.Since(start)}
+	fmt.Printf("   SDK v1 gave up after %d transport calls in %s\n", v1.calls, v1.elapsed.Round(time.Millisecond))
+
+	// Call with v2
+	fmt.Println("\n3. Calling ListTables with SDK v2...")
+	start = time.Now()
+	_, err = dynamoClientV2.ListTables(ctx, &dynamodbv2.ListTablesInput{})
+	v2 := exhaustion{err: err, normalized: interop.NormalizeError(err), calls: transportV2.count(), elapsed: time.Since(start)}
+	fmt.Printf("   SDK v2 gave up after %d transport calls in %s\n", v2.calls, v2.elapsed.Round(time.Millisecond))
+
+	// Check
+	fmt.Println("\n4. Checking how both SDKs gave up...")
+	failures := 0
+	for _, c := range []struct {
+		sdk string
+		ex  exhaustion
+	}{{"v1", v1}, {"v2", v2}} {
+		failures += checkExhaustion(c.sdk, c.ex, attempts, bound)
+	}
+
+	var maxErr *retry.MaxAttemptsError
+	switch {
+	case !errors.As(v2.err, &maxErr):
+		fmt.Printf("   ✗ SDK v2 error is not a retry.MaxAttemptsError: %v\n", v2.err)
+		failures++
+	case maxErr.Attempt != attempts:
+		fmt.Printf("   ✗ SDK v2 MaxAttemptsError reports %d attempts, expected %d\n", maxErr.Attempt, attempts)
+		failures++
+	default:
+		fmt.Printf("   ✓ SDK v2 wraps the last error in a retry.MaxAttemptsError after %d attempts\n", maxErr.Attempt)
+	}
+
+	if v1.normalized != nil && v2.normalized != nil {
+		if v1.normalized.Kind != v2.normalized.Kind || v1.normalized.Code != v2.normalized.Code ||
+			v1.normalized.StatusCode != v2.normalized.StatusCode {
+			fmt.Printf("   ✗ The SDKs' errors normalize differently\n       v1: %s\n       v2: %s\n", v1.normalized, v2.normalized)
+			failures++
+		} else {
+			fmt.Printf("   ✓ Both errors normalize to %s\n", v1.normalized)
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d retry exhaustion checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs give up after %d attempts and within the backoff bound\n", attempts)
+	fmt.Println("✓ interop.NormalizeError classifies the exhausted errors of both SDKs identically")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 returns the last attempt's awserr.RequestFailure; v2 wraps it in a retry.MaxAttemptsError")
+	fmt.Println("  - v1 bounds backoff with DefaultRetryer.MaxRetryDelay, v2 with StandardOptions.MaxBackoff")
+	fmt.Println("  - v2 also has a client-side retry quota that can stop retrying before MaxAttempts")
+}
+
+// checkExhaustion checks that one SDK's call failed with a server or
+// throttling error after the expected number of attempts and within bound,
+// and returns the number of failed checks.
+func checkExhaustion(sdk string, ex exhaustion, attempts int, bound time.Duration) int {
+	failures := 0
+	if ex.err == nil {
+		fmt.Printf("   ✗ SDK %s succeeded although every request failed\n", sdk)
+		return 1
+	}
+	if ex.calls != attempts {
+		fmt.Printf("   ✗ SDK %s made %d attempts, expected %d\n", sdk, ex.calls, attempts)
+		failures++
+	} else {
+		fmt.Printf("   ✓ SDK %s made %d attempts\n", sdk, ex.calls)
+	}
+	if ex.elapsed > bound {
+		fmt.Printf("   ✗ SDK %s took %s, more than the %s the backoff allows\n", sdk, ex.elapsed.Round(time.Millisecond), bound)
+		failures++
+	} else {
+		fmt.Printf("   ✓ SDK %s gave up within %s\n", sdk, bound)
+	}
+	switch kind := ex.normalized.Kind; kind {
+	case interop.ErrorKindServer, interop.ErrorKindThrottling:
+		fmt.Printf("   ✓ SDK %s error is a %s error with status %d\n", sdk, kind, ex.normalized.StatusCode)
+	default:
+		fmt.Printf("   ✗ SDK %s error normalizes to %s, expected a server or throttling error: %v\n", sdk, kind, ex.err)
+		failures++
+	}
+	return failures
+}
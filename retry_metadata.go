@@ -0,0 +1,189 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// throttlingTransport answers the first throttles requests with a DynamoDB
+// ThrottlingException and every later one with an empty ListTables result.
+type throttlingTransport struct {
+	mu        sync.Mutex
+	throttles int
+	calls     int
+}
+
+func (t *throttlingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	t.calls++
+	throttled := t.calls <= t.throttles
+	t.mu.Unlock()
+
+	status, body := http.StatusOK, `{"TableNames":[]}`
+	if throttled {
+		status, body = http.StatusBadRequest, `{"__type":"com.amazonaws.dynamodb.v20120810#ThrottlingException","message":"Rate of requests exceeds the allowed throughput."}`
+	}
+	// Both SDKs verify DynamoDB responses against X-Amz-Crc32.
+	return &http.Response{
+		StatusCode: status,
+		Header: http.Header{
+			"Content-Type": []string{"application/x-amz-json-1.0"},
+			"X-Amz-Crc32":  []string{strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(body))), 10)},
+		},
+		Body:    io.NopCloser(strings.NewReader(body)),
+		Request: req,
+	}, nil
+}
+
+// This example demonstrates comparing retry behavior across SDKs with one
+// -max-retries setting. Both clients talk to a transport that throttles
+// requests, and an interop.RetryRecorder reports the attempts and retry delay
+// of each call: first when a call recovers after a few throttles, then when
+// every request is throttled and both SDKs must give up after the same
+// number of attempts.
+func main() {
+	maxRetries := flag.Int("max-retries", 3, "retries per request in both SDKs (0-10); v2 gets one more attempt")
+	interop.ParseFlags()
+
+	fmt.Print("=== Retry Metadata Comparison Test ===\n\n")
+
+	if *maxRetries < 0 || *maxRetries > 10 {
+		fmt.Fprintln(os.Stderr, "-max-retries must be between 0 and 10")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx := context.Background()
+	fmt.Printf("v1 MaxRetries: %d, v2 RetryMaxAttempts: %d\n\n", *maxRetries, interop.RetryMaxAttempts(*maxRetries))
+
+	failures := 0
+
+	// A call that is throttled fewer times than it may retry succeeds.
+	throttles := 2
+	if throttles > *maxRetries {
+		throttles = *maxRetries
+	}
+	fmt.Printf("1. Throttling the first %d requests of each call...\n", throttles)
+	failures += runScenario(ctx, *maxRetries, throttles, true, throttles+1)
+
+	// A call that is always throttled fails after the last allowed attempt.
+	fmt.Println("\n2. Throttling every request...")
+	failures += runScenario(ctx, *maxRetries, math.MaxInt, false, interop.RetryMaxAttempts(*maxRetries))
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d retry checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Retry counts carry over when MaxRetries is translated to RetryMaxAttempts")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 MaxRetries counts retries, v2 RetryMaxAttempts counts attempts (retries + 1)")
+	fmt.Println("  - v1 exposes RetryCount and the last RetryDelay on request.Request")
+	fmt.Println("  - v2 attaches per-attempt results to the output's ResultMetadata (retry.GetAttemptResults)")
+	fmt.Println("  - Backoff is jittered differently, so total delays are not expected to match")
+}
+
+// runScenario calls ListTables once with each SDK, configured for maxRetries
+// retries, against transports that throttle the first throttles requests. It
+// checks whether the calls succeeded and that both made wantAttempts
+// attempts, and returns the number of failed checks.
+func runScenario(ctx context.Context, maxRetries, throttles int, wantSuccess bool, wantAttempts int) int {
+	region := "us-east-1"
+	recorder := interop.NewRetryRecorder()
+
+	transportV1 := &throttlingTransport{throttles: throttles}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transportV1},
+		MaxRetries:  aws.Int(maxRetries),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	recorder.InstallV1(sessV1)
+	dynamoClientV1 := dynamodbv1.New(sessV1)
+
+	transportV2 := &throttlingTransport{throttles: throttles}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transportV2}),
+		config.WithRetryMaxAttempts(interop.RetryMaxAttempts(maxRetries)),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	recorder.InstallV2(&cfgV2)
+	dynamoClientV2 := dynamodbv2.NewFromConfig(cfgV2)
+
+	failures := 0
+	checkOutcome := func(sdk string, err error, calls int) {
+		switch {
+		case wantSuccess && err != nil:
+			fmt.Printf("   ✗ SDK %s failed after %d transport calls: %v\n", sdk, calls, err)
+			failures++
+		case !wantSuccess && err == nil:
+			fmt.Printf("   ✗ SDK %s succeeded although every request was throttled\n", sdk)
+			failures++
+		case wantSuccess:
+			fmt.Printf("   ✓ SDK %s succeeded after %d transport calls\n", sdk, calls)
+		default:
+			fmt.Printf("   ✓ SDK %s gave up after %d transport calls\n", sdk, calls)
+		}
+	}
+	_, err = dynamoClientV1.ListTablesWithContext(ctx, &dynamodbv1.ListTablesInput{})
+	checkOutcome("v1", err, transportV1.calls)
+	_, err = dynamoClientV2.ListTables(ctx, &dynamodbv2.ListTablesInput{})
+	checkOutcome("v2", err, transportV2.calls)
+
+	comparison, ok := recorder.Compare("ListTables")
+	if !ok {
+		log.Fatalf("No retry metadata recorded for ListTables")
+	}
+	fmt.Printf("   %s\n", comparison)
+
+	switch {
+	case !comparison.Match():
+		fmt.Println("   ✗ The SDKs made a different number of attempts")
+		failures++
+	case comparison.V1.Attempts != wantAttempts:
+		fmt.Printf("   ✗ Expected %d attempts, got %d\n", wantAttempts, comparison.V1.Attempts)
+		failures++
+	default:
+		fmt.Printf("   ✓ Both SDKs made %d attempts\n", wantAttempts)
+	}
+	if wantAttempts > 1 {
+		if comparison.V1.RetryDelay <= 0 || comparison.V2.RetryDelay <= 0 {
+			fmt.Println("   ✗ Expected both SDKs to back off between attempts")
+			failures++
+		} else {
+			fmt.Println("   ✓ Both SDKs backed off between attempts")
+		}
+	}
+	return failures
+}
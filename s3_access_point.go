@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3control"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates S3 access points across SDKs. The access point
+// is managed through the separate S3 Control API with SDK v1, and objects are
+// then written and read through its ARN and alias with both SDKs. With
+// -vpc-id the access point only accepts requests from that VPC, so the
+// object steps are skipped unless the program runs inside it.
+func main() {
+	vpcID := flag.String("vpc-id", "", "create a VPC-only access point for this VPC instead of an internet one")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 Access Point Interop Test ===\n\n")
+
+	suffix := interop.DefaultClock.Now().Unix()
+	bucketName := fmt.Sprintf("sdk-migration-ap-%d", suffix)
+	accessPointName := fmt.Sprintf("sdk-migration-ap-%d", suffix)
+	objectKey := "access-point/test.txt"
+	content := "written through an access point"
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test bucket name: %s\n", bucketName)
+	fmt.Printf("Test access point name: %s\n\n", accessPointName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+	controlClientV1 := s3control.New(sessV1)
+	stsClientV1 := stsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// S3 Control operations are addressed by account, not by bucket.
+	identity, err := stsClientV1.GetCallerIdentityWithContext(ctx, &stsv1.GetCallerIdentityInput{})
+	if err != nil {
+		log.Fatalf("Failed to get caller identity with v1: %v", err)
+	}
+	accountID := aws.StringValue(identity.Account)
+
+	// ===== PHASE 1: Create bucket and access point with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket and access point using SDK v1")
+	fmt.Println("--------------------------------------------------------")
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	var accessPointCreated, objectWritten bool
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing object, access point and bucket")
+		fmt.Println("---------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s' and access point '%s'", bucketName, accessPointName)) {
+			if accessPointCreated {
+				fmt.Printf("\nPlease manually delete access point: %s\n", accessPointName)
+			}
+			fmt.Printf("Please manually delete bucket: %s\n", bucketName)
+			return
+		}
+		if objectWritten {
+			_, err := s3ClientV2.DeleteObject(ctx, &s3v2.DeleteObjectInput{
+				Bucket: aws.String(bucketName),
+				Key:    aws.String(objectKey),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete object: %v", err)
+			} else {
+				fmt.Println("✓ Object deleted successfully with SDK v2")
+			}
+		}
+		if accessPointCreated {
+			_, err := controlClientV1.DeleteAccessPointWithContext(ctx, &s3control.DeleteAccessPointInput{
+				AccountId: aws.String(accountID),
+				Name:      aws.String(accessPointName),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete access point: %v", err)
+				fmt.Printf("\nPlease manually delete access point: %s\n", accessPointName)
+			} else {
+				fmt.Println("✓ Access point deleted successfully with SDK v1 (S3 Control)")
+			}
+		}
+		_, err := s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	input := &s3control.CreateAccessPointInput{
+		AccountId: aws.String(accountID),
+		Bucket:    aws.String(bucketName),
+		Name:      aws.String(accessPointName),
+	}
+	wantOrigin := s3control.NetworkOriginInternet
+	if *vpcID != "" {
+		input.VpcConfiguration = &s3control.VpcConfiguration{VpcId: aws.String(*vpcID)}
+		wantOrigin = s3control.NetworkOriginVpc
+	}
+	created, err := controlClientV1.CreateAccessPointWithContext(ctx, input)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to create access point with v1: %v", err)
+	}
+	accessPointCreated = true
+	accessPointArn := aws.StringValue(created.AccessPointArn)
+	fmt.Printf("✓ Access point created with SDK v1: %s\n", accessPointArn)
+
+	ap, err := controlClientV1.GetAccessPointWithContext(ctx, &s3control.GetAccessPointInput{
+		AccountId: aws.String(accountID),
+		Name:      aws.String(accessPointName),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get access point with v1: %v", err)
+	}
+	alias := aws.StringValue(ap.Alias)
+	mismatches := 0
+	if origin := aws.StringValue(ap.NetworkOrigin); origin != wantOrigin {
+		fmt.Printf("✗ Network origin is %s, want %s\n", origin, wantOrigin)
+		mismatches++
+	} else {
+		fmt.Printf("✓ Network origin: %s, alias: %s\n", origin, alias)
+	}
+
+	if *vpcID != "" {
+		fmt.Printf("\nThe access point only accepts requests from %s; skipping the object steps.\n", *vpcID)
+	} else {
+		// ===== PHASE 2: Write through the access point ARN with SDK v2 =====
+		fmt.Println("\n\nPHASE 2: Writing an object through the access point ARN using SDK v2")
+		fmt.Println("----------------------------------------------------------------------")
+
+		// A new access point can take a few seconds to accept requests.
+		err = interop.Poll(ctx, 2*time.Second, 60*time.Second, func(ctx context.Context) (bool, error) {
+			_, err := s3ClientV2.PutObject(ctx, &s3v2.PutObjectInput{
+				Bucket: aws.String(accessPointArn),
+				Key:    aws.String(objectKey),
+				Body:   strings.NewReader(content),
+			})
+			if err != nil {
+				fmt.Printf("  %v, retrying...\n", err)
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to put object through the access point with v2: %v", err)
+		}
+		objectWritten = true
+		fmt.Printf("✓ Object '%s' written with SDK v2 via %s\n", objectKey, accessPointArn)
+
+		// ===== PHASE 3: Read it back with both SDKs =====
+		fmt.Println("\n\nPHASE 3: Reading the object back")
+		fmt.Println("----------------------------------")
+
+		reads := []struct {
+			via  string
+			read func() (string, error)
+		}{
+			{"SDK v1 via the access point ARN", func() (string, error) {
+				out, err := s3ClientV1.GetObjectWithContext(ctx, &s3v1.GetObjectInput{
+					Bucket: aws.String(accessPointArn),
+					Key:    aws.String(objectKey),
+				})
+				if err != nil {
+					return "", err
+				}
+				defer out.Body.Close()
+				b, err := io.ReadAll(out.Body)
+				return string(b), err
+			}},
+			{"SDK v2 via the access point alias", func() (string, error) {
+				out, err := s3ClientV2.GetObject(ctx, &s3v2.GetObjectInput{
+					Bucket: aws.String(alias),
+					Key:    aws.String(objectKey),
+				})
+				if err != nil {
+					return "", err
+				}
+				defer out.Body.Close()
+				b, err := io.ReadAll(out.Body)
+				return string(b), err
+			}},
+			{"SDK v2 via the bucket name", func() (string, error) {
+				out, err := s3ClientV2.GetObject(ctx, &s3v2.GetObjectInput{
+					Bucket: aws.String(bucketName),
+					Key:    aws.String(objectKey),
+				})
+				if err != nil {
+					return "", err
+				}
+				defer out.Body.Close()
+				b, err := io.ReadAll(out.Body)
+				return string(b), err
+			}},
+		}
+		for _, r := range reads {
+			got, err := r.read()
+			switch {
+			case err != nil:
+				fmt.Printf("✗ Read with %s failed: %v\n", r.via, err)
+				mismatches++
+			case got != content:
+				fmt.Printf("✗ Read with %s returned %q, want %q\n", r.via, got, content)
+				mismatches++
+			default:
+				fmt.Printf("✓ Read with %s: %q\n", r.via, got)
+			}
+		}
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d access point checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ An access point created with SDK v1 works as a bucket stand-in for both SDKs")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Access points are managed by the S3 Control client, which needs the account ID, in both SDKs")
+	fmt.Println("  - Both SDKs accept an access point ARN or alias wherever a bucket name goes")
+	fmt.Println("  - v1 needs S3UseARNRegion to use an ARN from another region, v2 uses the UseARNRegion option")
+}
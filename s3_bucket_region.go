@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// createBucketRequest is what a CreateBucket call sent.
+type createBucketRequest struct {
+	Host string
+	// Constraint is the LocationConstraint of the body, and HasBody whether
+	// the request had a CreateBucketConfiguration body at all.
+	Constraint string
+	HasBody    bool
+}
+
+// createBucketTransport records the last CreateBucket request and answers it
+// the way S3 does when the constraint matches the region: with 200 and an
+// empty body.
+type createBucketTransport struct {
+	mu   sync.Mutex
+	last createBucketRequest
+	err  error
+}
+
+func (t *createBucketTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.last = createBucketRequest{Host: req.URL.Host, HasBody: len(bytes.TrimSpace(body)) > 0}
+	t.err = nil
+	if t.last.HasBody {
+		var cfg struct {
+			LocationConstraint string
+		}
+		t.err = xml.Unmarshal(body, &cfg)
+		t.last.Constraint = cfg.LocationConstraint
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// regionCase is a region and the LocationConstraint a bucket created there
+// must be sent with.
+type regionCase struct {
+	region     string
+	constraint string
+}
+
+var regionCases = []regionCase{
+	{"us-east-1", ""},
+	{"us-west-2", "us-west-2"},
+	{"eu-west-1", "eu-west-1"},
+	{"eu-central-1", "eu-central-1"},
+	{"ap-southeast-2", "ap-southeast-2"},
+	{"sa-east-1", "sa-east-1"},
+}
+
+// This example demonstrates interop.CreateBucketInRegionV1 and
+// interop.CreateBucketInRegionV2, which create a bucket in the client's
+// region. S3 rejects an explicit LocationConstraint in us-east-1 and
+// requires one everywhere else, and neither SDK fills it in from the
+// client's region, so code that omits it only works in us-east-1. Each
+// region's CreateBucket request is captured from both SDKs and its
+// CreateBucketConfiguration body checked. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== S3 Bucket Region Test ===\n\n")
+
+	bucket := "sdk-migration-test"
+	ctx := context.Background()
+	failures := 0
+	transport := &createBucketTransport{}
+
+	fmt.Printf("1. Choosing the LocationConstraint for %d regions...\n", len(regionCases))
+	for _, c := range regionCases {
+		got := interop.BucketLocationConstraint(c.region)
+		// GetBucketLocation reports the constraint, which must map back to
+		// the region the bucket was created in.
+		back := interop.NormalizeBucketLocation(got)
+		if got != c.constraint || back != c.region {
+			fmt.Printf("   ✗ %s: constraint %q (reported as %s), want %q\n", c.region, got, back, c.constraint)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ %-14s %q\n", c.region, got)
+	}
+
+	fmt.Println("\n2. Creating a bucket in each region with both SDKs...")
+	for _, c := range regionCases {
+		sessV1, err := session.NewSession(&aws.Config{
+			Region:      aws.String(c.region),
+			Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+			HTTPClient:  &http.Client{Transport: transport},
+		})
+		if err != nil {
+			log.Fatalf("Failed to create v1 session: %v", err)
+		}
+		s3ClientV1 := s3v1.New(sessV1)
+
+		cfgV2, err := config.LoadDefaultConfig(ctx,
+			config.WithRegion(c.region),
+			config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+			config.WithHTTPClient(&http.Client{Transport: transport}),
+		)
+		if err != nil {
+			log.Fatalf("Failed to load v2 config: %v", err)
+		}
+		s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+
+		for _, sdk := range []struct {
+			name   string
+			create func() error
+		}{
+			{"v1", func() error { return interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucket, c.region) }},
+			{"v2", func() error { return interop.CreateBucketInRegionV2(ctx, s3ClientV2, bucket, c.region) }},
+		} {
+			if err := sdk.create(); err != nil {
+				fmt.Printf("   ✗ %s with SDK %s: %v\n", c.region, sdk.name, err)
+				failures++
+				continue
+			}
+			transport.mu.Lock()
+			got, parseErr := transport.last, transport.err
+			transport.mu.Unlock()
+			switch {
+			case parseErr != nil:
+				fmt.Printf("   ✗ %s with SDK %s: unreadable CreateBucketConfiguration: %v\n", c.region, sdk.name, parseErr)
+				failures++
+			case c.constraint == "" && got.HasBody:
+				fmt.Printf("   ✗ %s with SDK %s: sent a CreateBucketConfiguration, which us-east-1 rejects\n", c.region, sdk.name)
+				failures++
+			case c.constraint != "" && got.Constraint != c.constraint:
+				fmt.Printf("   ✗ %s with SDK %s: LocationConstraint %q, want %q\n", c.region, sdk.name, got.Constraint, c.constraint)
+				failures++
+			case c.constraint == "":
+				fmt.Printf("   ✓ %s with SDK %s: no body, sent to %s\n", c.region, sdk.name, got.Host)
+			default:
+				fmt.Printf("   ✓ %s with SDK %s: LocationConstraint %s, sent to %s\n", c.region, sdk.name, got.Constraint, got.Host)
+			}
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d bucket region checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs send a LocationConstraint in the %d regions that need one, and none in us-east-1\n", len(regionCases)-1)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 LocationConstraint is a *string, v2 the s3types.BucketLocationConstraint enum")
+	fmt.Println("  - Neither SDK derives it from the client's region; CreateBucket outside us-east-1 fails without it")
+	fmt.Println("  - v1 sends us-east-1 requests to the global s3.amazonaws.com host, v2 to s3.us-east-1.amazonaws.com")
+}
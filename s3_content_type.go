@@ -0,0 +1,245 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// contentTypeTransport remembers the Content-Type each object was put with
+// and returns it from HeadObject, the way S3 stores it.
+type contentTypeTransport struct {
+	mu    sync.Mutex
+	types map[string]string
+}
+
+func (t *contentTypeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	header := http.Header{}
+	status := http.StatusOK
+	switch req.Method {
+	case http.MethodPut:
+		t.types[req.URL.Path] = req.Header.Get("Content-Type")
+	case http.MethodHead:
+		contentType, ok := t.types[req.URL.Path]
+		switch {
+		case !ok:
+			status = http.StatusNotFound
+		case contentType == "":
+			// What S3 stores when PutObject sets no type.
+			header.Set("Content-Type", "binary/octet-stream")
+		default:
+			header.Set("Content-Type", contentType)
+		}
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     header,
+		Body:       io.NopCloser(bytes.NewReader(nil)),
+		Request:    req,
+	}, nil
+}
+
+// contentTypeCase is an object key and body and the content type
+// interop.DetectContentType must pick for them.
+type contentTypeCase struct {
+	key  string
+	body []byte
+	want string
+}
+
+var contentTypeCases = []contentTypeCase{
+	{"notes.txt", []byte("plain text"), "text/plain; charset=utf-8"},
+	{"index.html", []byte("<!doctype html>"), "text/html; charset=utf-8"},
+	{"data.json", []byte(`{"a":1}`), "application/json"},
+	{"report.CSV", []byte("a,b\n1,2\n"), "text/csv; charset=utf-8"},
+	{"photo.jpg", []byte{0xff, 0xd8, 0xff}, "image/jpeg"},
+	{"archive.tar.gz", []byte{0x1f, 0x8b}, "application/gzip"},
+	{"README", []byte("no extension, sniffed as text\n"), "text/plain; charset=utf-8"},
+	{"logo", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png"},
+	{"blob.bin", []byte{0x00, 0x01, 0x02, 0xfe, 0xff}, "application/octet-stream"},
+	{"empty", nil, "text/plain; charset=utf-8"},
+}
+
+// This example demonstrates interop.DetectContentType, which picks the
+// content type to upload an S3 object with: from the key's extension when
+// it is a common one, sniffed from the content otherwise, with
+// application/octet-stream for binary data. It then puts objects with the
+// detected type through each SDK and heads them with the other, against an
+// in-process S3, to show that both store and return the same type. Without
+// one the SDKs differ: v1 sends no Content-Type, which S3 stores as
+// binary/octet-stream, while v2 sends application/octet-stream.
+func main() {
+	fmt.Print("=== S3 Content Type Detection Test ===\n\n")
+
+	region := "us-east-1"
+	bucket := "sdk-migration-test"
+	ctx := context.Background()
+	failures := 0
+	transport := &contentTypeTransport{types: make(map[string]string)}
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transport},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+
+	// Detection
+	fmt.Println("1. Detecting content types...")
+	for _, c := range contentTypeCases {
+		got := interop.DetectContentType(c.key, c.body)
+		if got != c.want {
+			fmt.Printf("   ✗ %s: %s, want %s\n", c.key, got, c.want)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ %-16s %s\n", c.key, got)
+	}
+
+	putV1 := func(key, contentType string) error {
+		input := &s3v1.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+			Body:   bytes.NewReader([]byte("content")),
+		}
+		if contentType != "" {
+			input.ContentType = aws.String(contentType)
+		}
+		_, err := s3ClientV1.PutObjectWithContext(ctx, input)
+		return err
+	}
+	putV2 := func(key, contentType string) error {
+		input := &s3v2.PutObjectInput{
+			Bucket: awsv2.String(bucket),
+			Key:    awsv2.String(key),
+			Body:   bytes.NewReader([]byte("content")),
+		}
+		if contentType != "" {
+			input.ContentType = awsv2.String(contentType)
+		}
+		_, err := s3ClientV2.PutObject(ctx, input)
+		return err
+	}
+	headV1 := func(key string) (string, error) {
+		out, err := s3ClientV1.HeadObjectWithContext(ctx, &s3v1.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err != nil {
+			return "", err
+		}
+		return aws.StringValue(out.ContentType), nil
+	}
+	headV2 := func(key string) (string, error) {
+		out, err := s3ClientV2.HeadObject(ctx, &s3v2.HeadObjectInput{Bucket: awsv2.String(bucket), Key: awsv2.String(key)})
+		if err != nil {
+			return "", err
+		}
+		if out.ContentType == nil {
+			return "", nil
+		}
+		return *out.ContentType, nil
+	}
+
+	roundTrip := func(direction string, put func(key, contentType string) error, head func(key string) (string, error)) {
+		for _, c := range contentTypeCases {
+			key := direction + "/" + c.key
+			want := interop.DetectContentType(c.key, c.body)
+			if err := put(key, want); err != nil {
+				log.Fatalf("Failed to put %s: %v", key, err)
+			}
+			got, err := head(key)
+			switch {
+			case err != nil:
+				fmt.Printf("   ✗ %s: %v\n", key, err)
+				failures++
+			case got != want:
+				fmt.Printf("   ✗ %s: returned %s, uploaded as %s\n", key, got, want)
+				failures++
+			}
+		}
+	}
+
+	// Put with v1, head with v2
+	fmt.Println("\n2. Putting objects with SDK v1 and heading them with SDK v2...")
+	before := failures
+	roundTrip("v1-to-v2", putV1, headV2)
+	if failures == before {
+		fmt.Printf("   ✓ %d content types returned as uploaded\n", len(contentTypeCases))
+	}
+
+	// Put with v2, head with v1
+	fmt.Println("\n3. Putting objects with SDK v2 and heading them with SDK v1...")
+	before = failures
+	roundTrip("v2-to-v1", putV2, headV1)
+	if failures == before {
+		fmt.Printf("   ✓ %d content types returned as uploaded\n", len(contentTypeCases))
+	}
+
+	// Without a content type
+	fmt.Println("\n4. Putting an object without a content type with each SDK...")
+	for _, sdk := range []struct {
+		name string
+		put  func(key, contentType string) error
+		want string
+	}{{"v1", putV1, "binary/octet-stream"}, {"v2", putV2, "application/octet-stream"}} {
+		key := sdk.name + "/untyped.txt"
+		if err := sdk.put(key, ""); err != nil {
+			log.Fatalf("Failed to put %s: %v", key, err)
+		}
+		got, err := headV2(key)
+		if err != nil || got != sdk.want {
+			fmt.Printf("   ✗ SDK %s: returned %q (%v), want %s\n", sdk.name, got, err, sdk.want)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ SDK %s: %s comes back as %s\n", sdk.name, key, got)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d content type checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs store and return the content type detected at upload")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Neither SDK derives ContentType from the key or the body; set it on PutObject")
+	fmt.Println("  - Without one, v1 sends no Content-Type (stored as binary/octet-stream) and v2 sends application/octet-stream")
+	fmt.Println("  - So objects uploaded without a type differ in HeadObject depending on which SDK wrote them")
+}
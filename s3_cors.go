@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// corsRule is an SDK-neutral view of a CORS rule. Lists keep the order they
+// were written in, which S3 preserves.
+type corsRule struct {
+	ID             string
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+	ExposeHeaders  []string
+	MaxAgeSeconds  int64
+}
+
+func (r corsRule) String() string {
+	return fmt.Sprintf("id=%s origins=[%s] methods=[%s] headers=[%s] expose=[%s] max-age=%d",
+		r.ID, strings.Join(r.AllowedOrigins, ","), strings.Join(r.AllowedMethods, ","),
+		strings.Join(r.AllowedHeaders, ","), strings.Join(r.ExposeHeaders, ","), r.MaxAgeSeconds)
+}
+
+// This example demonstrates that a CORS configuration written with SDK v1 is
+// read back identically with SDK v2. It covers several rules, including a
+// fully wildcarded origin and a wildcarded subdomain origin.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 CORS Configuration Interop Test ===\n\n")
+
+	bucketName := interop.GenerateBucketName("cors")
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test bucket name: %s\n\n", bucketName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create bucket with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket using SDK v1")
+	fmt.Println("---------------------------------------")
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing CORS configuration and bucket")
+		fmt.Println("--------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s'", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		_, err := s3ClientV2.DeleteBucketCors(ctx, &s3v2.DeleteBucketCorsInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete CORS configuration: %v", err)
+		} else {
+			fmt.Println("✓ CORS configuration removed with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	// ===== PHASE 2: Put CORS configuration with SDK v1 =====
+	fmt.Println("\n\nPHASE 2: Putting CORS configuration using SDK v1")
+	fmt.Println("--------------------------------------------------")
+
+	rules := []*s3v1.CORSRule{
+		{
+			ID:             aws.String("public-read"),
+			AllowedOrigins: aws.StringSlice([]string{"*"}),
+			AllowedMethods: aws.StringSlice([]string{"GET", "HEAD"}),
+			MaxAgeSeconds:  aws.Int64(3600),
+		},
+		{
+			ID:             aws.String("app-upload"),
+			AllowedOrigins: aws.StringSlice([]string{"https://*.example.com", "https://example.com"}),
+			AllowedMethods: aws.StringSlice([]string{"PUT", "POST", "DELETE"}),
+			AllowedHeaders: aws.StringSlice([]string{"*"}),
+			ExposeHeaders:  aws.StringSlice([]string{"ETag", "x-amz-request-id"}),
+			MaxAgeSeconds:  aws.Int64(600),
+		},
+		{
+			// No ID and no MaxAgeSeconds: both must stay unset on read.
+			AllowedOrigins: aws.StringSlice([]string{"http://localhost:8080"}),
+			AllowedMethods: aws.StringSlice([]string{"GET"}),
+			AllowedHeaders: aws.StringSlice([]string{"Authorization", "Content-Type"}),
+		},
+	}
+	_, err = s3ClientV1.PutBucketCors(&s3v1.PutBucketCorsInput{
+		Bucket:            aws.String(bucketName),
+		CORSConfiguration: &s3v1.CORSConfiguration{CORSRules: rules},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put CORS configuration with v1: %v", err)
+	}
+	want := make([]corsRule, 0, len(rules))
+	for _, rule := range rules {
+		r := corsRuleFromV1(rule)
+		want = append(want, r)
+		fmt.Printf("✓ Written with SDK v1: %s\n", r)
+	}
+
+	// ===== PHASE 3: Read CORS configuration with SDK v2 =====
+	fmt.Println("\n\nPHASE 3: Reading CORS configuration using SDK v2")
+	fmt.Println("--------------------------------------------------")
+
+	// Like other bucket sub-resources, CORS is eventually consistent: a read
+	// straight after the write may fail with NoSuchCORSConfiguration.
+	var got []corsRule
+	err = interop.Poll(ctx, 2*time.Second, 30*time.Second, func(ctx context.Context) (bool, error) {
+		out, err := s3ClientV2.GetBucketCors(ctx, &s3v2.GetBucketCorsInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			fmt.Printf("  %v, retrying...\n", err)
+			return false, nil
+		}
+		got = got[:0]
+		for _, rule := range out.CORSRules {
+			got = append(got, corsRuleFromV2(rule))
+		}
+		return len(got) == len(want), nil
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to read CORS configuration with v2: %v", err)
+	}
+
+	mismatches := 0
+	for i := range want {
+		if want[i].String() != got[i].String() {
+			fmt.Printf("✗ Rule %d differs\n     v1: %s\n     v2: %s\n", i+1, want[i], got[i])
+			mismatches++
+			continue
+		}
+		fmt.Printf("✓ Read back with SDK v2: %s\n", got[i])
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d CORS rules did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Printf("✓ All %d CORS rules written with SDK v1 are read back unchanged with SDK v2\n", len(want))
+	fmt.Println("✓ Wildcard origins and headers are stored verbatim")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 CORSRules is []*CORSRule with []*string lists, v2 is []types.CORSRule with []string")
+	fmt.Println("  - v1 MaxAgeSeconds is *int64, v2 is *int32")
+	fmt.Println("  - v1 PutBucketCors nests rules in a *CORSConfiguration, v2 GetBucketCors returns them flat")
+}
+
+func corsRuleFromV1(rule *s3v1.CORSRule) corsRule {
+	return corsRule{
+		ID:             aws.StringValue(rule.ID),
+		AllowedOrigins: aws.StringValueSlice(rule.AllowedOrigins),
+		AllowedMethods: aws.StringValueSlice(rule.AllowedMethods),
+		AllowedHeaders: aws.StringValueSlice(rule.AllowedHeaders),
+		ExposeHeaders:  aws.StringValueSlice(rule.ExposeHeaders),
+		MaxAgeSeconds:  aws.Int64Value(rule.MaxAgeSeconds),
+	}
+}
+
+func corsRuleFromV2(rule s3types.CORSRule) corsRule {
+	r := corsRule{
+		AllowedOrigins: rule.AllowedOrigins,
+		AllowedMethods: rule.AllowedMethods,
+		AllowedHeaders: rule.AllowedHeaders,
+		ExposeHeaders:  rule.ExposeHeaders,
+	}
+	if rule.ID != nil {
+		r.ID = *rule.ID
+	}
+	if rule.MaxAgeSeconds != nil {
+		r.MaxAgeSeconds = int64(*rule.MaxAgeSeconds)
+	}
+	return r
+}
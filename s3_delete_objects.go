@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// deleteError is an SDK-neutral view of one per-key failure reported by
+// DeleteObjects.
+type deleteError struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+func (e deleteError) String() string {
+	return fmt.Sprintf("%s: %s: %s", e.Key, e.Code, e.Message)
+}
+
+// deleteResult is an SDK-neutral view of a DeleteObjects response. Keys are
+// sorted, since S3 does not promise any order.
+type deleteResult struct {
+	Deleted []string
+	Errors  []deleteError
+}
+
+func (r deleteResult) String() string {
+	errs := make([]string, len(r.Errors))
+	for i, e := range r.Errors {
+		errs[i] = e.String()
+	}
+	return fmt.Sprintf("deleted=[%s] errors=[%s]", strings.Join(r.Deleted, ","), strings.Join(errs, "; "))
+}
+
+// This example demonstrates a batch DeleteObjects call across SDKs. Objects
+// uploaded with SDK v1 are deleted in a single SDK v2 call with Quiet unset,
+// so that S3 reports every key as either deleted or failed. The batch also
+// names a key that was never written, to show how a missing key is reported.
+func main() {
+	count := flag.Int("objects", 5, "number of objects to upload and delete")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 DeleteObjects Interop Test ===\n\n")
+
+	// DeleteObjects accepts at most 1000 keys, one of which is the missing key.
+	if *count < 1 || *count > 999 {
+		fmt.Fprintln(os.Stderr, "-objects must be between 1 and 999")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	bucketName := interop.GenerateBucketName("delete")
+	region := cfg.Region
+	ctx := context.Background()
+	missingKey := "batch/never-written.txt"
+
+	fmt.Printf("Test bucket name: %s\n\n", bucketName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create bucket and upload objects with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket and uploading objects using SDK v1")
+	fmt.Println("-------------------------------------------------------------")
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	cleanup := func() {
+		fmt.Println("\n\nCLEANUP: Removing remaining objects and bucket")
+		fmt.Println("------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s'", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		// Anything the batch failed to delete would keep the bucket alive.
+		deleted, errs := interop.DrainBucketV2(ctx, s3ClientV2, bucketName, interop.DefaultWorkers, false)
+		for _, err := range errs {
+			log.Printf("Warning: Failed to empty bucket: %v", err)
+		}
+		if deleted > 0 {
+			fmt.Printf("✓ Deleted %d remaining objects with SDK v2\n", deleted)
+		}
+		_, err := s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	keys := make([]string, 0, *count)
+	for i := 1; i <= *count; i++ {
+		key := fmt.Sprintf("batch/object-%03d.txt", i)
+		_, err := s3ClientV1.PutObjectWithContext(ctx, &s3v1.PutObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(key),
+			Body:   strings.NewReader(fmt.Sprintf("object %d written with SDK v1", i)),
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to put object %s with v1: %v", key, err)
+		}
+		keys = append(keys, key)
+	}
+	fmt.Printf("✓ Uploaded %d objects with SDK v1\n", len(keys))
+
+	// ===== PHASE 2: Delete them in one batch with SDK v2 =====
+	fmt.Println("\n\nPHASE 2: Deleting all objects in one DeleteObjects call using SDK v2")
+	fmt.Println("----------------------------------------------------------------------")
+
+	batch := append(append([]string(nil), keys...), missingKey)
+	identifiers := make([]s3types.ObjectIdentifier, len(batch))
+	for i, key := range batch {
+		identifiers[i] = s3types.ObjectIdentifier{Key: aws.String(key)}
+	}
+	outV2, err := s3ClientV2.DeleteObjects(ctx, &s3v2.DeleteObjectsInput{
+		Bucket: aws.String(bucketName),
+		Delete: &s3types.Delete{
+			Objects: identifiers,
+			// Quiet would leave successful deletes out of the response.
+			Quiet: aws.Bool(false),
+		},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to delete objects with v2: %v", err)
+	}
+	resultV2 := deleteResultFromV2(outV2)
+	fmt.Printf("✓ SDK v2 response: %d deleted, %d errors\n", len(resultV2.Deleted), len(resultV2.Errors))
+
+	mismatches := 0
+	for _, e := range resultV2.Errors {
+		fmt.Printf("✗ Per-key error: %s\n", e)
+		mismatches++
+	}
+	deleted := make(map[string]bool, len(resultV2.Deleted))
+	for _, key := range resultV2.Deleted {
+		deleted[key] = true
+	}
+	for _, key := range keys {
+		if !deleted[key] {
+			fmt.Printf("✗ %s is neither deleted nor reported as an error\n", key)
+			mismatches++
+		}
+	}
+	// Deleting a key that does not exist is not an error in S3: without
+	// versioning there is nothing to remove, and the key is reported deleted.
+	if deleted[missingKey] {
+		fmt.Printf("✓ Missing key %s is reported as deleted, not as an error\n", missingKey)
+	} else {
+		fmt.Printf("✗ Missing key %s is not in the Deleted list\n", missingKey)
+		mismatches++
+	}
+
+	// ===== PHASE 3: Repeat the batch with SDK v1 =====
+	fmt.Println("\n\nPHASE 3: Repeating the same batch using SDK v1")
+	fmt.Println("------------------------------------------------")
+
+	objectsV1 := make([]*s3v1.ObjectIdentifier, len(batch))
+	for i, key := range batch {
+		objectsV1[i] = &s3v1.ObjectIdentifier{Key: aws.String(key)}
+	}
+	outV1, err := s3ClientV1.DeleteObjectsWithContext(ctx, &s3v1.DeleteObjectsInput{
+		Bucket: aws.String(bucketName),
+		Delete: &s3v1.Delete{
+			Objects: objectsV1,
+			Quiet:   aws.Bool(false),
+		},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to delete objects with v1: %v", err)
+	}
+	resultV1 := deleteResultFromV1(outV1)
+	// Every key is now missing, so both SDKs must report the same result.
+	if resultV1.String() != resultV2.String() {
+		fmt.Printf("✗ The SDKs report the batch differently\n     v1: %s\n     v2: %s\n", resultV1, resultV2)
+		mismatches++
+	} else {
+		fmt.Printf("✓ SDK v1 reports the same %d deleted keys and %d errors\n", len(resultV1.Deleted), len(resultV1.Errors))
+	}
+
+	// ===== PHASE 4: Verify the bucket is empty =====
+	fmt.Println("\n\nPHASE 4: Verifying no objects remain using SDK v1")
+	fmt.Println("---------------------------------------------------")
+
+	listed, err := s3ClientV1.ListObjectsV2WithContext(ctx, &s3v1.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		log.Printf("Warning: Failed to list objects with v1: %v", err)
+		mismatches++
+	} else if n := len(listed.Contents); n > 0 {
+		fmt.Printf("✗ %d objects remain, first: %s\n", n, aws.StringValue(listed.Contents[0].Key))
+		mismatches++
+	} else {
+		fmt.Println("✓ The bucket is empty")
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d DeleteObjects checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ Objects uploaded with SDK v1 are deleted in one SDK v2 batch, with every key accounted for")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Deleted and Errors are []*DeletedObject and []*Error, v2 are value slices of types structs")
+	fmt.Println("  - Both SDKs return a nil error when only some keys fail; the Errors list must always be checked")
+	fmt.Println("  - v1 sends a Content-MD5 header for the batch, v2 a flexible checksum (CRC32 by default)")
+}
+
+// deleteResultFromV1 converts a v1 DeleteObjects response.
+func deleteResultFromV1(out *s3v1.DeleteObjectsOutput) deleteResult {
+	var r deleteResult
+	for _, d := range out.Deleted {
+		r.Deleted = append(r.Deleted, aws.StringValue(d.Key))
+	}
+	for _, e := range out.Errors {
+		r.Errors = append(r.Errors, deleteError{
+			Key:     aws.StringValue(e.Key),
+			Code:    aws.StringValue(e.Code),
+			Message: aws.StringValue(e.Message),
+		})
+	}
+	r.sort()
+	return r
+}
+
+// deleteResultFromV2 converts a v2 DeleteObjects response.
+func deleteResultFromV2(out *s3v2.DeleteObjectsOutput) deleteResult {
+	var r deleteResult
+	for _, d := range out.Deleted {
+		if d.Key != nil {
+			r.Deleted = append(r.Deleted, *d.Key)
+		}
+	}
+	for _, e := range out.Errors {
+		var de deleteError
+		if e.Key != nil {
+			de.Key = *e.Key
+		}
+		if e.Code != nil {
+			de.Code = *e.Code
+		}
+		if e.Message != nil {
+			de.Message = *e.Message
+		}
+		r.Errors = append(r.Errors, de)
+	}
+	r.sort()
+	return r
+}
+
+func (r *deleteResult) sort() {
+	sort.Strings(r.Deleted)
+	sort.Slice(r.Errors, func(i, j int) bool { return r.Errors[i].Key < r.Errors[j].Key })
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// expressContent is the body of the object written to the directory bucket.
+const expressContent = "written to S3 Express One Zone with SDK v2"
+
+// directoryBucketName returns the name of a directory bucket in the
+// Availability Zone with ID azID: S3 requires the base name to be followed
+// by "--<az-id>--x-s3", and the whole to be at most 63 characters.
+func directoryBucketName(base, azID string) (string, error) {
+	name := base + "--" + azID + "--x-s3"
+	if len(name) > 63 {
+		return "", fmt.Errorf("directory bucket name %s is longer than 63 characters", name)
+	}
+	return name, nil
+}
+
+// This example demonstrates S3 Express One Zone directory buckets, which
+// only SDK v2 supports. A directory bucket lives in one Availability Zone,
+// is named after its zone ID, and is reached through zonal s3express
+// endpoints with session credentials from CreateSession, which v2 fetches
+// and refreshes on its own. The bucket is created and used with v2; v1 has
+// the operations in its model but sends them to the general-purpose S3
+// endpoint with plain SigV4, so its calls never reach the bucket. Code that
+// uses directory buckets must be migrated to v2 outright.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	region := flag.String("region", "us-east-1", "region to create the directory bucket in")
+	azID := flag.String("az-id", "use1-az4", "ID (not name) of an Availability Zone of -region that supports S3 Express One Zone")
+	interop.ParseFlags()
+
+	fmt.Print("=== S3 Express One Zone Directory Bucket Test ===\n\n")
+
+	bucketName, err := directoryBucketName(interop.GenerateBucketName("express"), *azID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	objectKey := "express/object.txt"
+	ctx := context.Background()
+	failures := 0
+
+	fmt.Printf("Directory bucket name: %s\n", bucketName)
+	fmt.Printf("Object: %s\n\n", objectKey)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(*region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Directory bucket names carry the zone ID, which is the same zone in
+	// every account, unlike zone names such as us-east-1a.
+	fmt.Printf("1. Using SDK v2 to look up Availability Zone ID %s...\n", *azID)
+	zones, err := ec2ClientV2.DescribeAvailabilityZones(ctx, &ec2v2.DescribeAvailabilityZonesInput{
+		ZoneIds: []string{*azID},
+	})
+	if err != nil || len(zones.AvailabilityZones) == 0 {
+		log.Fatalf("Availability Zone ID %s not found in %s (choose one with -az-id): %v", *azID, *region, err)
+	}
+	fmt.Printf("   ✓ %s is %s in this account\n", *azID, aws.StringValue(zones.AvailabilityZones[0].ZoneName))
+
+	fmt.Println("\n2. Using SDK v2 to create the directory bucket...")
+	_, err = s3ClientV2.CreateBucket(ctx, &s3v2.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+		CreateBucketConfiguration: &s3types.CreateBucketConfiguration{
+			Location: &s3types.LocationInfo{
+				Type: s3types.LocationTypeAvailabilityZone,
+				Name: aws.String(*azID),
+			},
+			Bucket: &s3types.BucketInfo{
+				Type:           s3types.BucketTypeDirectory,
+				DataRedundancy: s3types.DataRedundancySingleAvailabilityZone,
+			},
+		},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create directory bucket with v2 (is S3 Express One Zone available in %s?): %v", *azID, err)
+	}
+	fmt.Println("   ✓ Directory bucket created with SDK v2")
+
+	cleanup := func() {
+		fmt.Println("\nCLEANUP: Removing object and directory bucket")
+		if !interop.ConfirmDestructive(fmt.Sprintf("directory bucket '%s' and its object", bucketName)) {
+			fmt.Printf("\nPlease manually delete directory bucket: %s\n", bucketName)
+			return
+		}
+		cleanupCtx := context.WithoutCancel(ctx)
+		_, err := s3ClientV2.DeleteObject(cleanupCtx, &s3v2.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete object: %v", err)
+		} else {
+			fmt.Println("✓ Object deleted with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(cleanupCtx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete directory bucket: %v", err)
+			fmt.Printf("\nPlease manually delete directory bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Directory bucket deleted with SDK v2")
+		}
+	}
+
+	// PutObject and GetObject go to the zonal endpoint, signed with the
+	// session credentials v2 gets from CreateSession and caches.
+	fmt.Println("\n3. Using SDK v2 to put and get an object...")
+	_, err = s3ClientV2.PutObject(ctx, &s3v2.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader(expressContent),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put object with v2: %v", err)
+	}
+	out, err := s3ClientV2.GetObject(ctx, &s3v2.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get object with v2: %v", err)
+	}
+	body, err := io.ReadAll(out.Body)
+	out.Body.Close()
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to read object with v2: %v", err)
+	}
+	if string(body) != expressContent {
+		fmt.Printf("   ✗ Read %q, want %q\n", body, expressContent)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Read back %d bytes (storage class %s)\n", len(body), out.StorageClass)
+	}
+
+	fmt.Println("\n4. Using SDK v2 to list directory buckets...")
+	foundV2 := false
+	paginator := s3v2.NewListDirectoryBucketsPaginator(s3ClientV2, &s3v2.ListDirectoryBucketsInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to list directory buckets with v2: %v", err)
+		}
+		for _, b := range page.Buckets {
+			if aws.StringValue(b.Name) == bucketName {
+				foundV2 = true
+			}
+		}
+	}
+	if !foundV2 {
+		fmt.Printf("   ✗ %s is missing from ListDirectoryBuckets\n", bucketName)
+		failures++
+	} else {
+		fmt.Println("   ✓ The bucket is listed")
+	}
+
+	// v1 knows these operations but not the s3express endpoints, so its
+	// calls reach the general-purpose endpoint, which has no such bucket.
+	fmt.Println("\n5. Using SDK v1 to reach the directory bucket...")
+	_, err = s3ClientV1.HeadBucketWithContext(ctx, &s3v1.HeadBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err == nil {
+		fmt.Println("   ✗ v1 HeadBucket succeeded; does v1 support directory buckets now?")
+		failures++
+	} else {
+		fmt.Printf("   ✓ v1 HeadBucket fails: %s\n", interop.ErrorCode(err))
+	}
+	listV1, err := s3ClientV1.ListDirectoryBucketsWithContext(ctx, &s3v1.ListDirectoryBucketsInput{})
+	switch {
+	case err != nil:
+		fmt.Printf("   ✓ v1 ListDirectoryBuckets fails: %s\n", interop.ErrorCode(err))
+	case directoryBucketListed(listV1.Buckets, bucketName):
+		fmt.Println("   ✗ v1 ListDirectoryBuckets lists the bucket; does v1 support directory buckets now?")
+		failures++
+	default:
+		// GET / on the general-purpose endpoint is ListBuckets.
+		fmt.Printf("   ✓ v1 ListDirectoryBuckets answers with %d general-purpose buckets, without the directory bucket\n", len(listV1.Buckets))
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d directory bucket checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ SDK v2 creates, uses, lists and deletes an S3 Express One Zone directory bucket")
+	fmt.Println("✗ SDK v1 cannot reach it: code using directory buckets has to move to v2")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v2 resolves zonal s3express endpoints and signs with CreateSession credentials it caches; v1 does neither")
+	fmt.Println("  - v1's model has CreateSession and ListDirectoryBuckets, but sends them to the general-purpose S3 endpoint")
+	fmt.Println("  - Directory bucket names end in --<az-id>--x-s3, with the zone ID (use1-az4), not the zone name (us-east-1a)")
+}
+
+// directoryBucketListed reports whether a v1 ListDirectoryBuckets result
+// contains name.
+func directoryBucketListed(buckets []*s3v1.Bucket, name string) bool {
+	for _, b := range buckets {
+		if aws.StringValue(b.Name) == name {
+			return true
+		}
+	}
+	return false
+}
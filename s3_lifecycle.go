@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// lifecycleRule is an SDK-neutral view of a lifecycle rule, used to compare
+// what v1 wrote with what v2 reads back.
+type lifecycleRule struct {
+	ID             string
+	Status         string
+	FilterPrefix   string
+	FilterTag      string
+	ExpirationDays int64
+	Transitions    []string
+}
+
+func (r lifecycleRule) String() string {
+	filter := "prefix=" + r.FilterPrefix
+	if r.FilterTag != "" {
+		filter = "tag=" + r.FilterTag
+	}
+	return fmt.Sprintf("%s [%s] filter(%s) expire=%dd transitions=%v",
+		r.ID, r.Status, filter, r.ExpirationDays, r.Transitions)
+}
+
+// This example demonstrates that a lifecycle configuration written with SDK v1
+// is read back identically with SDK v2, even though the filter, expiration and
+// transition structs differ between the two versions.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 Lifecycle Configuration Interop Test ===\n\n")
+
+	bucketName := interop.GenerateBucketName("lifecycle")
+	region := cfg.Region
+	ctx := context.Background()
+
+	fmt.Printf("Test bucket name: %s\n\n", bucketName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create bucket and lifecycle rules with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket and lifecycle rules using SDK v1")
+	fmt.Println("-----------------------------------------------------------")
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	cleanup := func() {
+		fmt.Println("\n\nCLEANUP: Removing lifecycle configuration and bucket")
+		fmt.Println("------------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s'", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		_, err := s3ClientV2.DeleteBucketLifecycle(ctx, &s3v2.DeleteBucketLifecycleInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete lifecycle configuration: %v", err)
+		} else {
+			fmt.Println("✓ Lifecycle configuration removed with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	// One prefix-filtered and one tag-filtered rule, each with a transition
+	// and an expiration.
+	rulesV1 := []*s3v1.LifecycleRule{
+		{
+			ID:     aws.String("expire-logs"),
+			Status: aws.String(s3v1.ExpirationStatusEnabled),
+			Filter: &s3v1.LifecycleRuleFilter{
+				Prefix: aws.String("logs/"),
+			},
+			Transitions: []*s3v1.Transition{
+				{Days: aws.Int64(30), StorageClass: aws.String(s3v1.TransitionStorageClassStandardIa)},
+			},
+			Expiration: &s3v1.LifecycleExpiration{Days: aws.Int64(365)},
+		},
+		{
+			ID:     aws.String("archive-tagged"),
+			Status: aws.String(s3v1.ExpirationStatusEnabled),
+			Filter: &s3v1.LifecycleRuleFilter{
+				Tag: &s3v1.Tag{Key: aws.String("archive"), Value: aws.String("true")},
+			},
+			Transitions: []*s3v1.Transition{
+				{Days: aws.Int64(90), StorageClass: aws.String(s3v1.TransitionStorageClassGlacier)},
+			},
+			Expiration: &s3v1.LifecycleExpiration{Days: aws.Int64(180)},
+		},
+	}
+
+	fmt.Println("Putting lifecycle configuration with SDK v1...")
+	_, err = s3ClientV1.PutBucketLifecycleConfiguration(&s3v1.PutBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucketName),
+		LifecycleConfiguration: &s3v1.BucketLifecycleConfiguration{
+			Rules: rulesV1,
+		},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put lifecycle configuration with v1: %v", err)
+	}
+	fmt.Printf("✓ %d lifecycle rules written with SDK v1\n", len(rulesV1))
+
+	// ===== PHASE 2: Read lifecycle rules with SDK v2 =====
+	fmt.Println("\n\nPHASE 2: Reading the lifecycle rules using SDK v2")
+	fmt.Println("---------------------------------------------------")
+
+	// The configuration is eventually consistent, so a read straight after
+	// the write may still report NoSuchLifecycleConfiguration.
+	var getResult *s3v2.GetBucketLifecycleConfigurationOutput
+	for attempt := 1; attempt <= 5; attempt++ {
+		getResult, err = s3ClientV2.GetBucketLifecycleConfiguration(ctx, &s3v2.GetBucketLifecycleConfigurationInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err == nil {
+			break
+		}
+		fmt.Printf("  Attempt %d: %v, retrying...\n", attempt, err)
+		time.Sleep(2 * time.Second)
+	}
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get lifecycle configuration with v2: %v", err)
+	}
+	fmt.Printf("✓ SDK v2 read back %d lifecycle rules\n", len(getResult.Rules))
+
+	// ===== PHASE 3: Compare =====
+	fmt.Println("\n\nPHASE 3: Comparing v1 and v2 lifecycle rules")
+	fmt.Println("----------------------------------------------")
+
+	expected := make(map[string]lifecycleRule)
+	for _, rule := range rulesV1 {
+		r := lifecycleRuleFromV1(rule)
+		expected[r.ID] = r
+	}
+	actual := make(map[string]lifecycleRule)
+	for _, rule := range getResult.Rules {
+		r := lifecycleRuleFromV2(rule)
+		actual[r.ID] = r
+	}
+
+	mismatches := 0
+	for id, want := range expected {
+		got, ok := actual[id]
+		if !ok {
+			fmt.Printf("✗ Rule %s missing from v2 result\n", id)
+			mismatches++
+			continue
+		}
+		if want.String() != got.String() {
+			fmt.Printf("✗ Rule %s differs\n     v1: %s\n     v2: %s\n", id, want, got)
+			mismatches++
+			continue
+		}
+		fmt.Printf("✓ %s\n", got)
+	}
+	for id := range actual {
+		if _, ok := expected[id]; !ok {
+			fmt.Printf("✗ Unexpected rule %s in v2 result\n", id)
+			mismatches++
+		}
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d lifecycle rules did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ Lifecycle rules written with SDK v1 are read back unchanged with SDK v2")
+	fmt.Println("✓ Prefix and tag filters map onto v2's LifecycleRuleFilter fields")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 uses *int64 for Days, v2 uses *int32")
+	fmt.Println("  - v1 storage classes and statuses are *string, v2 uses typed enums")
+	fmt.Println("  - v1 uses slices of pointers ([]*Transition), v2 uses slices of values")
+}
+
+func lifecycleRuleFromV1(rule *s3v1.LifecycleRule) lifecycleRule {
+	r := lifecycleRule{
+		ID:     aws.StringValue(rule.ID),
+		Status: aws.StringValue(rule.Status),
+	}
+	if rule.Filter != nil {
+		r.FilterPrefix = aws.StringValue(rule.Filter.Prefix)
+		if rule.Filter.Tag != nil {
+			r.FilterTag = aws.StringValue(rule.Filter.Tag.Key) + "=" + aws.StringValue(rule.Filter.Tag.Value)
+		}
+	}
+	if rule.Expiration != nil {
+		r.ExpirationDays = aws.Int64Value(rule.Expiration.Days)
+	}
+	for _, t := range rule.Transitions {
+		r.Transitions = append(r.Transitions, fmt.Sprintf("%dd:%s", aws.Int64Value(t.Days), aws.StringValue(t.StorageClass)))
+	}
+	sort.Strings(r.Transitions)
+	return r
+}
+
+func lifecycleRuleFromV2(rule s3types.LifecycleRule) lifecycleRule {
+	r := lifecycleRule{
+		Status: string(rule.Status),
+	}
+	if rule.ID != nil {
+		r.ID = *rule.ID
+	}
+	if rule.Filter != nil {
+		if rule.Filter.Prefix != nil {
+			r.FilterPrefix = *rule.Filter.Prefix
+		}
+		if tag := rule.Filter.Tag; tag != nil && tag.Key != nil {
+			value := ""
+			if tag.Value != nil {
+				value = *tag.Value
+			}
+			r.FilterTag = *tag.Key + "=" + value
+		}
+	}
+	if rule.Expiration != nil && rule.Expiration.Days != nil {
+		r.ExpirationDays = int64(*rule.Expiration.Days)
+	}
+	for _, t := range rule.Transitions {
+		days := int32(0)
+		if t.Days != nil {
+			days = *t.Days
+		}
+		r.Transitions = append(r.Transitions, fmt.Sprintf("%dd:%s", days, t.StorageClass))
+	}
+	sort.Strings(r.Transitions)
+	return r
+}
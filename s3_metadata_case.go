@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"maps"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// putMetadata is the user metadata the object is put with: mixed-case keys,
+// and values with inner spaces and with leading and trailing ones.
+var putMetadata = map[string]string{
+	"ReleaseNotes": "first draft, see ticket 42",
+	"Build-ID":     "2026.10 rc 1",
+	"owner_team":   "Platform Engineering",
+	"X-Trace":      "  padded value  ",
+}
+
+// storedMetadata is putMetadata as S3 stores it: keys lowercased, and
+// values without the surrounding spaces, which are not part of an HTTP
+// header value.
+func storedMetadata() map[string]string {
+	stored := make(map[string]string, len(putMetadata))
+	for k, v := range putMetadata {
+		stored[strings.ToLower(k)] = strings.TrimSpace(v)
+	}
+	return stored
+}
+
+// formatMetadata prints metadata sorted by key.
+func formatMetadata(metadata map[string]string) string {
+	pairs := make([]string, 0, len(metadata))
+	for _, k := range slices.Sorted(maps.Keys(metadata)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", k, metadata[k]))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// This example demonstrates how user metadata keys change case between the
+// SDKs. Metadata travels as x-amz-meta-* headers, and S3 stores and returns
+// the header names in lowercase. An object is put with mixed-case keys with
+// SDK v1 and headed with v2, which returns the keys in lowercase, and with v1,
+// which canonicalizes them like HTTP header names ("Releasenotes", not the
+// "ReleaseNotes" it was put with) unless the session sets
+// LowerCaseHeaderMaps. No SDK returns the case the keys were put with, so
+// code that looks metadata up by its original key breaks in both.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 User Metadata Case Test ===\n\n")
+
+	bucketName := interop.GenerateBucketName("meta")
+	objectKey := "metadata/mixed-case.txt"
+	region := cfg.Region
+	ctx := context.Background()
+	failures := 0
+
+	fmt.Printf("Test bucket name: %s\n", bucketName)
+	fmt.Printf("Object: %s\n\n", objectKey)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+	// The copy keeps the handlers installed above.
+	s3ClientV1Lower := s3v1.New(sessV1.Copy(&aws.Config{LowerCaseHeaderMaps: aws.Bool(true)}))
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	fmt.Println("1. Using SDK v1 to create the bucket...")
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("   ✓ Bucket created with SDK v1")
+
+	cleanup := func() {
+		fmt.Println("\nCLEANUP: Removing object and bucket")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s' and its object", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		cleanupCtx := context.WithoutCancel(ctx)
+		_, err := s3ClientV2.DeleteObject(cleanupCtx, &s3v2.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete object: %v", err)
+		} else {
+			fmt.Println("✓ Object deleted with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(cleanupCtx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted with SDK v2")
+		}
+	}
+
+	fmt.Println("\n2. Using SDK v1 to put the object with mixed-case metadata...")
+	fmt.Printf("   Sent: %s\n", formatMetadata(putMetadata))
+	_, err = s3ClientV1.PutObjectWithContext(ctx, &s3v1.PutObjectInput{
+		Bucket:   aws.String(bucketName),
+		Key:      aws.String(objectKey),
+		Body:     strings.NewReader("metadata case test"),
+		Metadata: aws.StringMap(putMetadata),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put object with v1: %v", err)
+	}
+	fmt.Println("   ✓ Object uploaded with SDK v1")
+
+	want := storedMetadata()
+
+	fmt.Println("\n3. Using SDK v2 to read the metadata back...")
+	headV2, err := s3ClientV2.HeadObject(ctx, &s3v2.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to head object with v2: %v", err)
+	}
+	fmt.Printf("   v2: %s\n", formatMetadata(headV2.Metadata))
+	if !maps.Equal(headV2.Metadata, want) {
+		fmt.Printf("   ✗ want %s\n", formatMetadata(want))
+		failures++
+	} else {
+		fmt.Println("   ✓ v2 returns every key in lowercase, with the surrounding spaces of values dropped")
+	}
+
+	fmt.Println("\n4. Using SDK v1 to read the metadata back...")
+	headV1, err := s3ClientV1.HeadObjectWithContext(ctx, &s3v1.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to head object with v1: %v", err)
+	}
+	gotV1 := aws.StringValueMap(headV1.Metadata)
+	fmt.Printf("   v1: %s\n", formatMetadata(gotV1))
+	wantV1 := make(map[string]string, len(want))
+	for k, v := range want {
+		wantV1[http.CanonicalHeaderKey(k)] = v
+	}
+	if !maps.Equal(gotV1, wantV1) {
+		fmt.Printf("   ✗ want %s\n", formatMetadata(wantV1))
+		failures++
+	} else {
+		fmt.Println("   ✓ v1 returns the keys canonicalized like HTTP header names")
+	}
+	for _, k := range slices.Sorted(maps.Keys(putMetadata)) {
+		if _, ok := headV1.Metadata[k]; ok {
+			continue
+		}
+		if _, ok := headV2.Metadata[k]; ok {
+			continue
+		}
+		fmt.Printf("   - %q was put with SDK v1 but cannot be looked up by that key in either SDK\n", k)
+	}
+
+	fmt.Println("\n5. Using SDK v1 with LowerCaseHeaderMaps to read the metadata back...")
+	headV1Lower, err := s3ClientV1Lower.HeadObjectWithContext(ctx, &s3v1.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to head object with v1: %v", err)
+	}
+	gotV1Lower := aws.StringValueMap(headV1Lower.Metadata)
+	fmt.Printf("   v1: %s\n", formatMetadata(gotV1Lower))
+	if !maps.Equal(gotV1Lower, headV2.Metadata) {
+		fmt.Println("   ✗ The keys differ from v2's")
+		failures++
+	} else {
+		fmt.Println("   ✓ v1 returns the same keys as v2")
+	}
+
+	fmt.Println("\n6. Comparing the metadata after interop.UserMetadataV1 and interop.UserMetadataV2...")
+	diffs, err := interop.DiffJSON(interop.UserMetadataV1(headV1.Metadata), interop.UserMetadataV2(headV2.Metadata))
+	switch {
+	case err != nil:
+		fmt.Printf("   ✗ %v\n", err)
+		failures++
+	case len(diffs) > 0:
+		for _, d := range diffs {
+			fmt.Printf("   ✗ %s\n", d)
+		}
+		failures++
+	default:
+		fmt.Printf("   ✓ Both SDKs agree on all %d keys and values\n", len(want))
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d metadata checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ User metadata round-trips between the SDKs once keys are compared in lowercase")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - S3 lowercases metadata keys; neither SDK returns them in the case they were put with")
+	fmt.Println("  - v1 canonicalizes keys like HTTP headers (\"Build-Id\"), v2 lowercases them (\"build-id\")")
+	fmt.Println("  - v1's aws.Config.LowerCaseHeaderMaps makes v1 lowercase them too; v2 has no such option")
+	fmt.Println("  - Leading and trailing spaces of values are lost in both SDKs, since HTTP drops them")
+}
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// queueNotification is an SDK-neutral view of a bucket notification to SQS.
+// Events are sorted, and filter rule names are lowercased because S3 accepts
+// "prefix" but returns "Prefix".
+type queueNotification struct {
+	ID       string
+	QueueArn string
+	Events   []string
+	Filters  []string
+}
+
+func (n queueNotification) String() string {
+	return fmt.Sprintf("id=%s queue=%s events=[%s] filters=[%s]",
+		n.ID, n.QueueArn, strings.Join(n.Events, ","), strings.Join(n.Filters, ","))
+}
+
+// This example demonstrates that a bucket notification configuration written
+// with SDK v1 is read back identically with SDK v2. It creates an SQS queue
+// whose policy lets S3 publish to it, points two notifications at the queue,
+// and compares the configured events, filters and queue ARN.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 Event Notification Interop Test ===\n\n")
+
+	suffix := interop.DefaultClock.Now().Unix()
+	bucketName := fmt.Sprintf("sdk-migration-notify-%d", suffix)
+	queueName := fmt.Sprintf("sdk-migration-notify-%d", suffix)
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test bucket name: %s\n", bucketName)
+	fmt.Printf("Test queue name: %s\n\n", queueName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	sqsClientV2 := sqsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create queue and bucket =====
+	fmt.Println("PHASE 1: Creating queue (SDK v2) and bucket (SDK v1)")
+	fmt.Println("------------------------------------------------------")
+
+	queue, err := sqsClientV2.CreateQueue(ctx, &sqsv2.CreateQueueInput{
+		QueueName: aws.String(queueName),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create queue with v2: %v", err)
+	}
+	queueURL := aws.StringValue(queue.QueueUrl)
+	fmt.Printf("✓ Queue created with SDK v2: %s\n", queueURL)
+
+	var bucketCreated bool
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing notification configuration, bucket and queue")
+		fmt.Println("-----------------------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s' and queue '%s'", bucketName, queueName)) {
+			if bucketCreated {
+				fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			}
+			fmt.Printf("Please manually delete queue: %s\n", queueURL)
+			return
+		}
+		if bucketCreated {
+			// An empty configuration removes every notification.
+			_, err := s3ClientV2.PutBucketNotificationConfiguration(ctx, &s3v2.PutBucketNotificationConfigurationInput{
+				Bucket:                    aws.String(bucketName),
+				NotificationConfiguration: &s3types.NotificationConfiguration{},
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to remove notification configuration: %v", err)
+			} else {
+				fmt.Println("✓ Notification configuration removed with SDK v2")
+			}
+			_, err = s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+				Bucket: aws.String(bucketName),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete bucket: %v", err)
+				fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			} else {
+				fmt.Println("✓ Bucket deleted successfully with SDK v2")
+			}
+		}
+		_, err := sqsClientV2.DeleteQueue(ctx, &sqsv2.DeleteQueueInput{
+			QueueUrl: aws.String(queueURL),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete queue: %v", err)
+			fmt.Printf("\nPlease manually delete queue: %s\n", queueURL)
+		} else {
+			fmt.Println("✓ Queue deleted successfully with SDK v2")
+		}
+	}
+
+	attrs, err := sqsClientV2.GetQueueAttributes(ctx, &sqsv2.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get queue ARN with v2: %v", err)
+	}
+	queueArn := interop.QueueAttributes(attrs.Attributes).QueueArn()
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	bucketCreated = true
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	// S3 refuses a notification configuration unless it can deliver its
+	// test event, so the queue policy must allow this bucket to publish.
+	policy, err := queuePolicy(queueArn, "arn:aws:s3:::"+bucketName)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to build queue policy: %v", err)
+	}
+	_, err = sqsClientV2.SetQueueAttributes(ctx, &sqsv2.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueURL),
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): policy},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to set queue policy with v2: %v", err)
+	}
+	fmt.Println("✓ Queue policy allows s3.amazonaws.com to send messages from the bucket")
+
+	// ===== PHASE 2: Put notification configuration with SDK v1 =====
+	fmt.Println("\n\nPHASE 2: Putting notification configuration using SDK v1")
+	fmt.Println("-----------------------------------------------------------")
+
+	notifications := []*s3v1.QueueConfiguration{
+		{
+			Id:       aws.String("image-uploads"),
+			QueueArn: aws.String(queueArn),
+			Events:   aws.StringSlice([]string{s3v1.EventS3ObjectCreatedPut, s3v1.EventS3ObjectCreatedCompleteMultipartUpload}),
+			Filter: &s3v1.NotificationConfigurationFilter{
+				Key: &s3v1.KeyFilter{
+					FilterRules: []*s3v1.FilterRule{
+						{Name: aws.String(s3v1.FilterRuleNamePrefix), Value: aws.String("uploads/")},
+						{Name: aws.String(s3v1.FilterRuleNameSuffix), Value: aws.String(".jpg")},
+					},
+				},
+			},
+		},
+		{
+			Id:       aws.String("all-removals"),
+			QueueArn: aws.String(queueArn),
+			Events:   aws.StringSlice([]string{s3v1.EventS3ObjectRemoved}),
+		},
+	}
+	want := make(map[string]queueNotification)
+	for _, n := range notifications {
+		qn := queueNotificationFromV1(n)
+		want[qn.ID] = qn
+	}
+
+	// The queue policy takes a moment to apply, and until it does S3 rejects
+	// the configuration because it cannot deliver the test event.
+	err = interop.Poll(ctx, 2*time.Second, 60*time.Second, func(ctx context.Context) (bool, error) {
+		_, err := s3ClientV1.PutBucketNotificationConfigurationWithContext(ctx, &s3v1.PutBucketNotificationConfigurationInput{
+			Bucket:                    aws.String(bucketName),
+			NotificationConfiguration: &s3v1.NotificationConfiguration{QueueConfigurations: notifications},
+		})
+		if err != nil {
+			fmt.Printf("  %v, retrying...\n", err)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put notification configuration with v1: %v", err)
+	}
+	for _, n := range sortedNotifications(want) {
+		fmt.Printf("✓ Written with SDK v1: %s\n", n)
+	}
+
+	// ===== PHASE 3: Read notification configuration with SDK v2 =====
+	fmt.Println("\n\nPHASE 3: Reading notification configuration using SDK v2")
+	fmt.Println("-----------------------------------------------------------")
+
+	out, err := s3ClientV2.GetBucketNotificationConfiguration(ctx, &s3v2.GetBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get notification configuration with v2: %v", err)
+	}
+	got := make(map[string]queueNotification)
+	for _, n := range out.QueueConfigurations {
+		qn := queueNotificationFromV2(n)
+		got[qn.ID] = qn
+	}
+
+	mismatches := 0
+	for _, n := range sortedNotifications(want) {
+		g, ok := got[n.ID]
+		switch {
+		case !ok:
+			fmt.Printf("✗ Notification %s missing from the SDK v2 read\n", n.ID)
+			mismatches++
+		case g.String() != n.String():
+			fmt.Printf("✗ Notification %s differs\n     v1: %s\n     v2: %s\n", n.ID, n, g)
+			mismatches++
+		default:
+			fmt.Printf("✓ Read back with SDK v2: %s\n", g)
+		}
+	}
+	for id := range got {
+		if _, ok := want[id]; !ok {
+			fmt.Printf("✗ Unexpected notification %s in the SDK v2 read\n", id)
+			mismatches++
+		}
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d notifications did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Printf("✓ All %d queue notifications written with SDK v1 are read back unchanged with SDK v2\n", len(want))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Events is []*string, v2 is []types.Event")
+	fmt.Println("  - v1 FilterRule.Name is a *string, v2 uses the types.FilterRuleName enum")
+	fmt.Println("  - v2 GetBucketNotificationConfiguration returns the configurations flat on the output")
+	fmt.Println("  - The deprecated v1 PutBucketNotification/QueueConfigurationDeprecated API has no v2 equivalent")
+}
+
+// queuePolicy returns an SQS policy allowing S3 to send messages to queueArn
+// on behalf of bucketArn only.
+func queuePolicy(queueArn, bucketArn string) (string, error) {
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Sid":       "AllowS3Notifications",
+			"Effect":    "Allow",
+			"Principal": map[string]string{"Service": "s3.amazonaws.com"},
+			"Action":    "sqs:SendMessage",
+			"Resource":  queueArn,
+			"Condition": map[string]interface{}{
+				"ArnEquals": map[string]string{"aws:SourceArn": bucketArn},
+			},
+		}},
+	}
+	b, err := json.Marshal(policy)
+	return string(b), err
+}
+
+func sortedNotifications(m map[string]queueNotification) []queueNotification {
+	out := make([]queueNotification, 0, len(m))
+	for _, n := range m {
+		out = append(out, n)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func queueNotificationFromV1(n *s3v1.QueueConfiguration) queueNotification {
+	qn := queueNotification{
+		ID:       aws.StringValue(n.Id),
+		QueueArn: aws.StringValue(n.QueueArn),
+		Events:   aws.StringValueSlice(n.Events),
+	}
+	if n.Filter != nil && n.Filter.Key != nil {
+		for _, r := range n.Filter.Key.FilterRules {
+			qn.Filters = append(qn.Filters, strings.ToLower(aws.StringValue(r.Name))+"="+aws.StringValue(r.Value))
+		}
+	}
+	sort.Strings(qn.Events)
+	sort.Strings(qn.Filters)
+	return qn
+}
+
+func queueNotificationFromV2(n s3types.QueueConfiguration) queueNotification {
+	qn := queueNotification{}
+	if n.Id != nil {
+		qn.ID = *n.Id
+	}
+	if n.QueueArn != nil {
+		qn.QueueArn = *n.QueueArn
+	}
+	for _, e := range n.Events {
+		qn.Events = append(qn.Events, string(e))
+	}
+	if n.Filter != nil && n.Filter.Key != nil {
+		for _, r := range n.Filter.Key.FilterRules {
+			value := ""
+			if r.Value != nil {
+				value = *r.Value
+			}
+			qn.Filters = append(qn.Filters, strings.ToLower(string(r.Name))+"="+value)
+		}
+	}
+	sort.Strings(qn.Events)
+	sort.Strings(qn.Filters)
+	return qn
+}
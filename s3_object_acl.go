@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// logDeliveryGroup is the S3 log delivery group. Unlike the AllUsers and
+// AuthenticatedUsers groups, granting it access is not public, so the
+// bucket's default Block Public Access settings allow it.
+const logDeliveryGroup = "http://acs.amazonaws.com/groups/s3/LogDelivery"
+
+// aclGrant is an SDK-neutral view of one grant. Grantee is the canonical
+// user ID, group URI or email address, depending on Type. Display names are
+// left out: S3 no longer returns them in every region.
+type aclGrant struct {
+	Type       string
+	Grantee    string
+	Permission string
+}
+
+func (g aclGrant) String() string {
+	return fmt.Sprintf("%s:%s=%s", g.Type, g.Grantee, g.Permission)
+}
+
+// objectACL is an SDK-neutral view of an object ACL, with grants sorted.
+type objectACL struct {
+	Owner  string
+	Grants []aclGrant
+}
+
+func (a objectACL) String() string {
+	grants := make([]string, len(a.Grants))
+	for i, g := range a.Grants {
+		grants[i] = g.String()
+	}
+	return fmt.Sprintf("owner=%s grants=[%s]", a.Owner, strings.Join(grants, " "))
+}
+
+func (a *objectACL) sort() {
+	sort.Slice(a.Grants, func(i, j int) bool { return a.Grants[i].String() < a.Grants[j].String() })
+}
+
+// This example demonstrates object ACLs across SDKs. An ACL with a
+// canonical-user grant and a group grant is set with SDK v1 PutObjectAcl,
+// then read back with SDK v2 GetObjectAcl, and the owner and grants are
+// compared. New buckets have ACLs disabled, so the test bucket is created
+// with the BucketOwnerPreferred object ownership setting.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 Object ACL Interop Test ===\n\n")
+
+	bucketName := interop.GenerateBucketName("acl")
+	objectKey := "acl/object.txt"
+	region := cfg.Region
+	ctx := context.Background()
+
+	fmt.Printf("Test bucket name: %s\n\n", bucketName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create bucket and upload an object with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket with ACLs enabled and uploading an object using SDK v1")
+	fmt.Println("---------------------------------------------------------------------------------")
+
+	// With the default BucketOwnerEnforced ownership, PutObjectAcl fails
+	// with AccessControlListNotSupported.
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region, func(in *s3v1.CreateBucketInput) {
+		in.ObjectOwnership = aws.String(s3v1.ObjectOwnershipBucketOwnerPreferred)
+	})
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created with SDK v1 (object ownership BucketOwnerPreferred)")
+
+	cleanup := func() {
+		fmt.Println("\n\nCLEANUP: Removing object and bucket")
+		fmt.Println("-------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s' and its object", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		_, err := s3ClientV2.DeleteObject(ctx, &s3v2.DeleteObjectInput{
+			Bucket: aws.String(bucketName),
+			Key:    aws.String(objectKey),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete object: %v", err)
+		} else {
+			fmt.Println("✓ Object deleted successfully with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	_, err = s3ClientV1.PutObjectWithContext(ctx, &s3v1.PutObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		Body:   strings.NewReader("object with a custom ACL"),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put object with v1: %v", err)
+	}
+	fmt.Println("✓ Object uploaded successfully with SDK v1")
+
+	// The default ACL names the owner, whose canonical ID the new ACL needs.
+	defaultACL, err := s3ClientV1.GetObjectAclWithContext(ctx, &s3v1.GetObjectAclInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get the default object ACL with v1: %v", err)
+	}
+	if defaultACL.Owner == nil || aws.StringValue(defaultACL.Owner.ID) == "" {
+		cleanup()
+		log.Fatalf("The default object ACL has no owner")
+	}
+	ownerID := aws.StringValue(defaultACL.Owner.ID)
+	fmt.Printf("✓ Object owner (canonical user ID): %s\n", ownerID)
+
+	// ===== PHASE 2: Set the ACL with SDK v1 =====
+	fmt.Println("\n\nPHASE 2: Setting the object ACL using SDK v1")
+	fmt.Println("----------------------------------------------")
+
+	want := objectACL{
+		Owner: ownerID,
+		Grants: []aclGrant{
+			{Type: s3v1.TypeCanonicalUser, Grantee: ownerID, Permission: s3v1.PermissionFullControl},
+			{Type: s3v1.TypeGroup, Grantee: logDeliveryGroup, Permission: s3v1.PermissionRead},
+			{Type: s3v1.TypeGroup, Grantee: logDeliveryGroup, Permission: s3v1.PermissionReadAcp},
+		},
+	}
+	want.sort()
+
+	// v1 puts the grantee's type in an xsi:type attribute, so Type must be
+	// set along with the field it selects: ID for a user, URI for a group.
+	var grantsV1 []*s3v1.Grant
+	for _, g := range want.Grants {
+		grantee := &s3v1.Grantee{Type: aws.String(g.Type)}
+		if g.Type == s3v1.TypeGroup {
+			grantee.URI = aws.String(g.Grantee)
+		} else {
+			grantee.ID = aws.String(g.Grantee)
+		}
+		grantsV1 = append(grantsV1, &s3v1.Grant{Grantee: grantee, Permission: aws.String(g.Permission)})
+	}
+	_, err = s3ClientV1.PutObjectAclWithContext(ctx, &s3v1.PutObjectAclInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+		AccessControlPolicy: &s3v1.AccessControlPolicy{
+			Owner:  &s3v1.Owner{ID: aws.String(ownerID)},
+			Grants: grantsV1,
+		},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to put object ACL with v1: %v", err)
+	}
+	fmt.Printf("✓ ACL set with SDK v1: %s\n", want)
+
+	// ===== PHASE 3: Read the ACL with both SDKs =====
+	fmt.Println("\n\nPHASE 3: Reading the object ACL using SDK v2 and SDK v1")
+	fmt.Println("---------------------------------------------------------")
+
+	outV2, err := s3ClientV2.GetObjectAcl(ctx, &s3v2.GetObjectAclInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get object ACL with v2: %v", err)
+	}
+	aclV2 := objectACLFromV2(outV2)
+	fmt.Printf("✓ SDK v2 read: %s\n", aclV2)
+
+	outV1, err := s3ClientV1.GetObjectAclWithContext(ctx, &s3v1.GetObjectAclInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(objectKey),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get object ACL with v1: %v", err)
+	}
+	aclV1 := objectACLFromV1(outV1)
+	fmt.Printf("✓ SDK v1 read: %s\n", aclV1)
+
+	cleanup()
+
+	mismatches := 0
+	fmt.Println("\nComparing ACLs...")
+	if aclV2.String() != want.String() {
+		fmt.Printf("✗ SDK v2 reads a different ACL than was set\n     set: %s\n     v2:  %s\n", want, aclV2)
+		mismatches++
+	} else {
+		fmt.Println("✓ SDK v2 reads the owner and every grant set with SDK v1")
+	}
+	if aclV1.String() != aclV2.String() {
+		fmt.Printf("✗ The SDKs read different ACLs\n     v1: %s\n     v2: %s\n", aclV1, aclV2)
+		mismatches++
+	} else {
+		fmt.Println("✓ Both SDKs read the same ACL")
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d ACL comparisons failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ An object ACL set with SDK v1 reads back identically with SDK v2")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Grantee.Type and Grant.Permission are *string, v2 use the types.Type and types.Permission enums")
+	fmt.Println("  - v1 Grants is []*Grant, v2 []types.Grant; Grantee stays a pointer in both")
+	fmt.Println("  - In both SDKs the grantee type selects the field: ID for CanonicalUser, URI for Group, EmailAddress for AmazonCustomerByEmail")
+	fmt.Println("  - PutObjectAcl fails on buckets with the default BucketOwnerEnforced ownership in both SDKs")
+}
+
+// objectACLFromV1 converts a v1 GetObjectAcl response.
+func objectACLFromV1(out *s3v1.GetObjectAclOutput) objectACL {
+	var acl objectACL
+	if out.Owner != nil {
+		acl.Owner = aws.StringValue(out.Owner.ID)
+	}
+	for _, g := range out.Grants {
+		grant := aclGrant{Permission: aws.StringValue(g.Permission)}
+		if g.Grantee != nil {
+			grant.Type = aws.StringValue(g.Grantee.Type)
+			switch grant.Type {
+			case s3v1.TypeGroup:
+				grant.Grantee = aws.StringValue(g.Grantee.URI)
+			case s3v1.TypeAmazonCustomerByEmail:
+				grant.Grantee = aws.StringValue(g.Grantee.EmailAddress)
+			default:
+				grant.Grantee = aws.StringValue(g.Grantee.ID)
+			}
+		}
+		acl.Grants = append(acl.Grants, grant)
+	}
+	acl.sort()
+	return acl
+}
+
+// objectACLFromV2 converts a v2 GetObjectAcl response.
+func objectACLFromV2(out *s3v2.GetObjectAclOutput) objectACL {
+	var acl objectACL
+	if out.Owner != nil && out.Owner.ID != nil {
+		acl.Owner = *out.Owner.ID
+	}
+	for _, g := range out.Grants {
+		grant := aclGrant{Permission: string(g.Permission)}
+		if g.Grantee != nil {
+			grant.Type = string(g.Grantee.Type)
+			var value *string
+			switch g.Grantee.Type {
+			case s3types.TypeGroup:
+				value = g.Grantee.URI
+			case s3types.TypeAmazonCustomerByEmail:
+				value = g.Grantee.EmailAddress
+			default:
+				value = g.Grantee.ID
+			}
+			if value != nil {
+				grant.Grantee = *value
+			}
+		}
+		acl.Grants = append(acl.Grants, grant)
+	}
+	acl.sort()
+	return acl
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// websiteConfig is an SDK-neutral view of a bucket website configuration.
+// Routing rules are flattened to one string each, in order, since S3
+// evaluates them in the order they were written.
+type websiteConfig struct {
+	IndexDocument string
+	ErrorDocument string
+	RedirectAll   string
+	RoutingRules  []string
+}
+
+func (w websiteConfig) String() string {
+	if w.RedirectAll != "" {
+		return "redirect all requests to " + w.RedirectAll
+	}
+	return fmt.Sprintf("index=%s error=%s rules=[%s]", w.IndexDocument, w.ErrorDocument, strings.Join(w.RoutingRules, "; "))
+}
+
+// This example demonstrates that a static website configuration written with
+// SDK v1 is read back identically with SDK v2. It covers an index/error
+// document setup with routing rules, then a redirect-all-requests setup.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== S3 Website Configuration Interop Test ===\n\n")
+
+	bucketName := interop.GenerateBucketName("website")
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test bucket name: %s\n\n", bucketName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	s3ClientV1 := s3v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create bucket with SDK v1 =====
+	fmt.Println("PHASE 1: Creating bucket using SDK v1")
+	fmt.Println("---------------------------------------")
+
+	err = interop.CreateBucketInRegionV1(ctx, s3ClientV1, bucketName, region)
+	if err != nil {
+		log.Fatalf("Failed to create bucket with v1: %v", err)
+	}
+	fmt.Println("✓ Bucket created successfully with SDK v1")
+
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing website configuration and bucket")
+		fmt.Println("-----------------------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("bucket '%s'", bucketName)) {
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+			return
+		}
+		_, err := s3ClientV2.DeleteBucketWebsite(ctx, &s3v2.DeleteBucketWebsiteInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete website configuration: %v", err)
+		} else {
+			fmt.Println("✓ Website configuration removed with SDK v2")
+		}
+		_, err = s3ClientV2.DeleteBucket(ctx, &s3v2.DeleteBucketInput{
+			Bucket: aws.String(bucketName),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete bucket: %v", err)
+			fmt.Printf("\nPlease manually delete bucket: %s\n", bucketName)
+		} else {
+			fmt.Println("✓ Bucket deleted successfully with SDK v2")
+		}
+	}
+
+	configs := []struct {
+		name string
+		cfg  *s3v1.WebsiteConfiguration
+	}{
+		{
+			name: "index/error documents with routing rules",
+			cfg: &s3v1.WebsiteConfiguration{
+				IndexDocument: &s3v1.IndexDocument{Suffix: aws.String("index.html")},
+				ErrorDocument: &s3v1.ErrorDocument{Key: aws.String("error.html")},
+				RoutingRules: []*s3v1.RoutingRule{
+					{
+						Condition: &s3v1.Condition{KeyPrefixEquals: aws.String("docs/")},
+						Redirect:  &s3v1.Redirect{ReplaceKeyPrefixWith: aws.String("documents/")},
+					},
+					{
+						Condition: &s3v1.Condition{HttpErrorCodeReturnedEquals: aws.String("404")},
+						Redirect: &s3v1.Redirect{
+							HostName:         aws.String("example.com"),
+							Protocol:         aws.String(s3v1.ProtocolHttps),
+							HttpRedirectCode: aws.String("302"),
+							ReplaceKeyWith:   aws.String("not-found.html"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "redirect all requests",
+			cfg: &s3v1.WebsiteConfiguration{
+				RedirectAllRequestsTo: &s3v1.RedirectAllRequestsTo{
+					HostName: aws.String("example.com"),
+					Protocol: aws.String(s3v1.ProtocolHttps),
+				},
+			},
+		},
+	}
+
+	mismatches := 0
+	for i, c := range configs {
+		// ===== PHASE 2+: Write with v1, read with v2 =====
+		fmt.Printf("\n\nPHASE %d: %s\n", i+2, c.name)
+		fmt.Println("-----------------------------------------------------")
+
+		want := websiteConfigFromV1(c.cfg)
+		fmt.Println("Putting website configuration with SDK v1...")
+		_, err = s3ClientV1.PutBucketWebsite(&s3v1.PutBucketWebsiteInput{
+			Bucket:               aws.String(bucketName),
+			WebsiteConfiguration: c.cfg,
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to put website configuration with v1: %v", err)
+		}
+		fmt.Printf("✓ Written with SDK v1: %s\n", want)
+
+		// The configuration is eventually consistent: a read straight after
+		// the write may fail or still return the previous configuration.
+		fmt.Println("Reading website configuration with SDK v2...")
+		var got websiteConfig
+		err = interop.Poll(ctx, 2*time.Second, 30*time.Second, func(ctx context.Context) (bool, error) {
+			out, err := s3ClientV2.GetBucketWebsite(ctx, &s3v2.GetBucketWebsiteInput{
+				Bucket: aws.String(bucketName),
+			})
+			if err != nil {
+				fmt.Printf("  %v, retrying...\n", err)
+				return false, nil
+			}
+			got = websiteConfigFromV2(out)
+			return got.String() == want.String(), nil
+		})
+		if err != nil {
+			fmt.Printf("✗ Website configuration differs\n     v1: %s\n     v2: %s\n", want, got)
+			mismatches++
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		fmt.Printf("✓ Read back with SDK v2: %s\n", got)
+	}
+
+	cleanup()
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d website configurations did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ Website configurations written with SDK v1 are read back unchanged with SDK v2")
+	fmt.Println("✓ Routing rules keep their order and nested Condition/Redirect fields")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 PutBucketWebsite takes a *WebsiteConfiguration, v2 GetBucketWebsite returns its fields flat on the output")
+	fmt.Println("  - v1 RoutingRules is []*RoutingRule, v2 is []types.RoutingRule")
+	fmt.Println("  - v1 Protocol is a *string, v2 uses the types.Protocol enum")
+	fmt.Println("  - HttpRedirectCode and HttpErrorCodeReturnedEquals stay strings in both")
+}
+
+func routingRuleString(prefix, errorCode, host, protocol, code, replacePrefix, replaceKey string) string {
+	return fmt.Sprintf("if(prefix=%q code=%q) -> redirect(host=%q protocol=%q code=%q prefix=%q key=%q)",
+		prefix, errorCode, host, protocol, code, replacePrefix, replaceKey)
+}
+
+func websiteConfigFromV1(cfg *s3v1.WebsiteConfiguration) websiteConfig {
+	w := websiteConfig{}
+	if cfg.IndexDocument != nil {
+		w.IndexDocument = aws.StringValue(cfg.IndexDocument.Suffix)
+	}
+	if cfg.ErrorDocument != nil {
+		w.ErrorDocument = aws.StringValue(cfg.ErrorDocument.Key)
+	}
+	if r := cfg.RedirectAllRequestsTo; r != nil {
+		w.RedirectAll = aws.StringValue(r.Protocol) + "://" + aws.StringValue(r.HostName)
+	}
+	for _, rule := range cfg.RoutingRules {
+		var prefix, errorCode string
+		if rule.Condition != nil {
+			prefix = aws.StringValue(rule.Condition.KeyPrefixEquals)
+			errorCode = aws.StringValue(rule.Condition.HttpErrorCodeReturnedEquals)
+		}
+		r := rule.Redirect
+		if r == nil {
+			r = &s3v1.Redirect{}
+		}
+		w.RoutingRules = append(w.RoutingRules, routingRuleString(prefix, errorCode,
+			aws.StringValue(r.HostName), aws.StringValue(r.Protocol), aws.StringValue(r.HttpRedirectCode),
+			aws.StringValue(r.ReplaceKeyPrefixWith), aws.StringValue(r.ReplaceKeyWith)))
+	}
+	return w
+}
+
+func websiteConfigFromV2(out *s3v2.GetBucketWebsiteOutput) websiteConfig {
+	w := websiteConfig{}
+	if out.IndexDocument != nil && out.IndexDocument.Suffix != nil {
+		w.IndexDocument = *out.IndexDocument.Suffix
+	}
+	if out.ErrorDocument != nil && out.ErrorDocument.Key != nil {
+		w.ErrorDocument = *out.ErrorDocument.Key
+	}
+	if r := out.RedirectAllRequestsTo; r != nil {
+		host := ""
+		if r.HostName != nil {
+			host = *r.HostName
+		}
+		w.RedirectAll = string(r.Protocol) + "://" + host
+	}
+	for _, rule := range out.RoutingRules {
+		var prefix, errorCode string
+		if c := rule.Condition; c != nil {
+			if c.KeyPrefixEquals != nil {
+				prefix = *c.KeyPrefixEquals
+			}
+			if c.HttpErrorCodeReturnedEquals != nil {
+				errorCode = *c.HttpErrorCodeReturnedEquals
+			}
+		}
+		r := rule.Redirect
+		if r == nil {
+			r = &s3types.Redirect{}
+		}
+		w.RoutingRules = append(w.RoutingRules, routingRuleString(prefix, errorCode,
+			stringValue(r.HostName), string(r.Protocol), stringValue(r.HttpRedirectCode),
+			stringValue(r.ReplaceKeyPrefixWith), stringValue(r.ReplaceKeyWith)))
+	}
+	return w
+}
+
+// stringValue dereferences an optional v2 string field.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/client"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	dynamodbv1 "github.com/aws/aws-sdk-go/service/dynamodb"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	dynamodbv2 "github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// How each value in the table was obtained.
+const (
+	sourceIntrospected = "introspected"
+	sourceProbed       = "probed"
+	sourceDocumented   = "SDK constant"
+)
+
+// retryEnvVars are the environment variables that change retry defaults in
+// SDK v2. SDK v1 reads none of them.
+var retryEnvVars = []string{"AWS_MAX_ATTEMPTS", "AWS_RETRY_MODE", "AWS_DEFAULTS_MODE"}
+
+// unavailableTransport answers every request with a retryable 503, as S3 or
+// DynamoDB would, so that a call shows how many attempts an SDK makes by
+// default before it gives up.
+type unavailableTransport struct{}
+
+func (unavailableTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	header := http.Header{}
+	var body string
+	if strings.HasPrefix(req.URL.Host, "dynamodb.") {
+		body = `{"__type":"com.amazon.coral.availability#ServiceUnavailableException","message":"The service is temporarily unavailable."}`
+		header.Set("Content-Type", "application/x-amz-json-1.0")
+		// Both SDKs verify DynamoDB responses against X-Amz-Crc32.
+		header.Set("X-Amz-Crc32", strconv.FormatUint(uint64(crc32.ChecksumIEEE([]byte(body))), 10))
+	} else {
+		body = `<Error><Code>ServiceUnavailable</Code><Message>Please reduce your request rate.</Message></Error>`
+		header.Set("Content-Type", "application/xml")
+	}
+	return &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// noDelayRetryer keeps every retry decision of the retryer it wraps but does
+// not wait between attempts, so that probing a retryer that backs off for
+// seconds returns at once.
+type noDelayRetryer struct {
+	awsv2.RetryerV2
+}
+
+func (noDelayRetryer) RetryDelay(int, error) (time.Duration, error) {
+	return 0, nil
+}
+
+// withoutRetryDelay wraps the client's resolved retryer in a noDelayRetryer.
+func withoutRetryDelay(retryer awsv2.Retryer) awsv2.Retryer {
+	if r, ok := retryer.(awsv2.RetryerV2); ok {
+		return noDelayRetryer{r}
+	}
+	return retryer
+}
+
+// This example demonstrates that SDK v1 and SDK v2 ship different retry and
+// timeout defaults. It loads a fresh v1 session and v2 config, exactly as an
+// application would, introspects the retry mode, maximum attempts, maximum
+// backoff and HTTP client timeouts of the clients they produce, and prints
+// them side by side. The number of attempts is also probed: a call against
+// a transport that always answers 503 must make as many attempts as the
+// retryer reports. No request reaches AWS, so no credentials are needed.
+func main() {
+	output := interop.OutputFlag(flag.CommandLine, "`format` of the table: text or csv", interop.OutputText, interop.OutputCSV)
+	interop.ParseFlags()
+
+	fmt.Print("=== SDK Default Retry and Timeout Test ===\n\n")
+
+	region := "us-east-1"
+	ctx := context.Background()
+	failures := 0
+
+	for _, name := range retryEnvVars {
+		if v, ok := os.LookupEnv(name); ok {
+			fmt.Printf("Note: %s=%s is set; SDK v2 honors it, SDK v1 ignores it\n\n", name, v)
+		}
+	}
+
+	// Load
+	fmt.Println("1. Loading a fresh v1 session and v2 config...")
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	s3ClientV1, dynamoClientV1 := s3v1.New(sessV1), dynamodbv1.New(sessV1)
+	s3OptionsV2 := s3v2.NewFromConfig(cfgV2).Options()
+	dynamoOptionsV2 := dynamodbv2.NewFromConfig(cfgV2).Options()
+	fmt.Println("   ✓ Created S3 and DynamoDB clients with both SDKs")
+
+	table := interop.NewTablePrinter("SETTING", "SDK v1", "SDK v2", "SOURCE")
+	table.Indent = "     "
+	table.MaxWidth = -1
+
+	// Introspect
+	fmt.Println("\n2. Introspecting the clients' retryers and HTTP clients...")
+	retryMode := string(s3OptionsV2.RetryMode)
+	if retryMode == "" {
+		retryMode = string(awsv2.RetryModeStandard)
+	}
+	table.AddRow("Retry mode", retryerNameV1(s3ClientV1.Client), retryMode, sourceIntrospected)
+	attemptsS3V1 := s3ClientV1.Client.Retryer.MaxRetries() + 1
+	attemptsS3V2 := s3OptionsV2.Retryer.MaxAttempts()
+	attemptsDynamoV1 := dynamoClientV1.Client.Retryer.MaxRetries() + 1
+	attemptsDynamoV2 := dynamoOptionsV2.Retryer.MaxAttempts()
+	table.AddRow("S3 max attempts", strconv.Itoa(attemptsS3V1), strconv.Itoa(attemptsS3V2), sourceIntrospected)
+	table.AddRow("DynamoDB max attempts", strconv.Itoa(attemptsDynamoV1), strconv.Itoa(attemptsDynamoV2), sourceIntrospected)
+	table.AddRow("Max retry backoff", maxBackoffV1(s3ClientV1.Client), retry.DefaultMaxBackoff.String(), sourceDocumented)
+
+	httpV1 := httpSettingsFromV1(sessV1.Config.HTTPClient)
+	// LoadDefaultConfig leaves HTTPClient nil; each client builds its own.
+	httpV2 := httpSettingsFromV2(s3OptionsV2.HTTPClient)
+	table.AddRow("HTTP client", httpV1.client, httpV2.client, sourceIntrospected)
+	table.AddRow("Request timeout", httpV1.timeout, httpV2.timeout, sourceIntrospected)
+	table.AddRow("Dial timeout", httpV1.dial, httpV2.dial, sourceIntrospected)
+	table.AddRow("TLS handshake timeout", httpV1.tlsHandshake, httpV2.tlsHandshake, sourceIntrospected)
+	table.AddRow("Response header timeout", httpV1.responseHeader, httpV2.responseHeader, sourceIntrospected)
+	table.AddRow("Idle connection timeout", httpV1.idleConn, httpV2.idleConn, sourceIntrospected)
+	fmt.Println("   ✓ Read the retryers and HTTP clients")
+
+	// Probe
+	fmt.Println("\n3. Probing the attempts each SDK makes against a transport that always answers 503...")
+	recorder := interop.NewRetryRecorder()
+	probeV1 := sessV1.Copy(&aws.Config{
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: unavailableTransport{}},
+		// The delays are still computed, only not waited for.
+		SleepDelay: func(time.Duration) {},
+	})
+	recorder.InstallV1(probeV1)
+	probeCfgV2 := cfgV2.Copy()
+	probeCfgV2.Credentials = credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")
+	probeCfgV2.HTTPClient = &http.Client{Transport: unavailableTransport{}}
+	recorder.InstallV2(&probeCfgV2)
+	noDelay := func(o *s3v2.Options) { o.Retryer = withoutRetryDelay(o.Retryer) }
+	noDelayDynamo := func(o *dynamodbv2.Options) { o.Retryer = withoutRetryDelay(o.Retryer) }
+
+	s3v1.New(probeV1).ListBucketsWithContext(ctx, &s3v1.ListBucketsInput{})
+	s3v2.NewFromConfig(probeCfgV2, noDelay).ListBuckets(ctx, &s3v2.ListBucketsInput{})
+	dynamodbv1.New(probeV1).ListTablesWithContext(ctx, &dynamodbv1.ListTablesInput{})
+	dynamodbv2.NewFromConfig(probeCfgV2, noDelayDynamo).ListTables(ctx, &dynamodbv2.ListTablesInput{})
+
+	for _, p := range []struct {
+		service, operation string
+		wantV1, wantV2     int
+	}{
+		{"S3", "ListBuckets", attemptsS3V1, attemptsS3V2},
+		{"DynamoDB", "ListTables", attemptsDynamoV1, attemptsDynamoV2},
+	} {
+		c, ok := recorder.Compare(p.operation)
+		if !ok {
+			fmt.Printf("   ✗ %s %s was not recorded by both SDKs\n", p.service, p.operation)
+			failures++
+			continue
+		}
+		table.AddRow(p.service+" attempts on 503", strconv.Itoa(c.V1.Attempts), strconv.Itoa(c.V2.Attempts), sourceProbed)
+		if c.V1.Attempts != p.wantV1 || c.V2.Attempts != p.wantV2 {
+			fmt.Printf("   ✗ %s %s made %d (v1) and %d (v2) attempts, the retryers report %d and %d\n",
+				p.service, p.operation, c.V1.Attempts, c.V2.Attempts, p.wantV1, p.wantV2)
+			failures++
+		} else {
+			fmt.Printf("   ✓ %s %s made the %d (v1) and %d (v2) attempts the retryers report\n",
+				p.service, p.operation, c.V1.Attempts, c.V2.Attempts)
+		}
+	}
+
+	fmt.Print("\n4. Effective defaults:\n\n")
+	if *output == interop.OutputCSV {
+		table.Indent = ""
+	}
+	if err := table.Write(interop.Output, *output); err != nil {
+		log.Printf("Warning: Failed to print table: %v", err)
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d probes disagreed with the introspected defaults\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ The probed attempts match what both SDKs' retryers report")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 retries per service: DynamoDB makes 11 attempts by default, most services 4; v2 makes 3 everywhere")
+	fmt.Println("  - v1 backs off for up to 5 minutes between attempts, v2 for up to 20s")
+	fmt.Println("  - v1 uses http.DefaultClient; v2 builds its own client with explicit dial and TLS timeouts")
+	fmt.Println("  - Neither SDK sets an overall request timeout: pass a context deadline with v2, or WithContext with v1")
+	fmt.Println("  - Only v2 reads AWS_MAX_ATTEMPTS, AWS_RETRY_MODE and max_attempts/retry_mode from the shared config")
+}
+
+// retryerNameV1 describes the retryer of a v1 client. v1 has no retry modes;
+// every client uses client.DefaultRetryer unless one is configured.
+func retryerNameV1(c *client.Client) string {
+	if _, ok := c.Retryer.(client.DefaultRetryer); ok {
+		return "legacy (client.DefaultRetryer)"
+	}
+	return fmt.Sprintf("%T", c.Retryer)
+}
+
+// maxBackoffV1 returns the longest delay the v1 client's retryer waits
+// between attempts. client.DefaultRetryer applies its defaults lazily, so a
+// zero MaxRetryDelay means client.DefaultRetryerMaxRetryDelay.
+func maxBackoffV1(c *client.Client) string {
+	r, ok := c.Retryer.(client.DefaultRetryer)
+	if !ok {
+		return "unknown"
+	}
+	if r.MaxRetryDelay == 0 {
+		return client.DefaultRetryerMaxRetryDelay.String()
+	}
+	return r.MaxRetryDelay.String()
+}
+
+// httpSettings are the timeouts of one SDK's default HTTP client, formatted
+// for the table.
+type httpSettings struct {
+	client         string
+	timeout        string
+	dial           string
+	tlsHandshake   string
+	responseHeader string
+	idleConn       string
+}
+
+// httpSettingsFromV1 reads the timeouts of a v1 session's HTTP client, which
+// is http.DefaultClient unless one is configured. net/http dials through a
+// function, so the dial timeout of its default transport cannot be read.
+func httpSettingsFromV1(c *http.Client) httpSettings {
+	s := httpSettings{client: "*http.Client", dial: "not exposed"}
+	if c == nil {
+		return s
+	}
+	if c == http.DefaultClient {
+		s.client = "http.DefaultClient"
+	}
+	s.timeout = formatTimeout(c.Timeout)
+	tr, ok := c.Transport.(*http.Transport)
+	if c.Transport == nil {
+		tr, ok = http.DefaultTransport.(*http.Transport)
+	}
+	if !ok {
+		return s
+	}
+	s.tlsHandshake = formatTimeout(tr.TLSHandshakeTimeout)
+	s.responseHeader = formatTimeout(tr.ResponseHeaderTimeout)
+	s.idleConn = formatTimeout(tr.IdleConnTimeout)
+	return s
+}
+
+// httpSettingsFromV2 reads the timeouts of a v2 client's HTTP client, an
+// awshttp.BuildableClient unless one is configured.
+func httpSettingsFromV2(c awsv2.HTTPClient) httpSettings {
+	b, ok := c.(*awshttp.BuildableClient)
+	if !ok {
+		return httpSettings{client: fmt.Sprintf("%T", c)}
+	}
+	tr := b.GetTransport()
+	return httpSettings{
+		client:         "awshttp.BuildableClient",
+		timeout:        formatTimeout(b.GetTimeout()),
+		dial:           formatTimeout(b.GetDialer().Timeout),
+		tlsHandshake:   formatTimeout(tr.TLSHandshakeTimeout),
+		responseHeader: formatTimeout(tr.ResponseHeaderTimeout),
+		idleConn:       formatTimeout(tr.IdleConnTimeout),
+	}
+}
+
+// formatTimeout formats d, where zero means no timeout as in net/http.
+func formatTimeout(d time.Duration) string {
+	if d == 0 {
+		return "none"
+	}
+	return d.String()
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// missingInstanceID is well formed but belongs to no instance, so that
+// DescribeInstances fails with InvalidInstanceID.NotFound.
+const missingInstanceID = "i-0123456789abcdef0"
+
+// parityInstance is the result both code paths return: the fields of an
+// instance that the code being migrated relies on.
+type parityInstance struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	State     string            `json:"state"`
+	Zone      string            `json:"zone"`
+	PrivateIP string            `json:"privateIp,omitempty"`
+	Tags      map[string]string `json:"tags,omitempty"`
+}
+
+// describeInstancesV1 is the v1 code path: every instance matching
+// filters, sorted by ID.
+func describeInstancesV1(ctx context.Context, client *ec2.EC2, input *ec2.DescribeInstancesInput) ([]parityInstance, error) {
+	var out []parityInstance
+	err := client.DescribeInstancesPagesWithContext(ctx, input, func(page *ec2.DescribeInstancesOutput, lastPage bool) bool {
+		for _, reservation := range page.Reservations {
+			for _, inst := range reservation.Instances {
+				p := parityInstance{
+					ID:        aws.StringValue(inst.InstanceId),
+					Type:      aws.StringValue(inst.InstanceType),
+					PrivateIP: aws.StringValue(inst.PrivateIpAddress),
+				}
+				if inst.State != nil {
+					p.State = aws.StringValue(inst.State.Name)
+				}
+				if inst.Placement != nil {
+					p.Zone = aws.StringValue(inst.Placement.AvailabilityZone)
+				}
+				for _, tag := range inst.Tags {
+					if p.Tags == nil {
+						p.Tags = make(map[string]string)
+					}
+					p.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				out = append(out, p)
+			}
+		}
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, err
+}
+
+// describeInstancesV2 is the v2 rewrite of describeInstancesV1.
+func describeInstancesV2(ctx context.Context, client *ec2v2.Client, input *ec2v2.DescribeInstancesInput) ([]parityInstance, error) {
+	var out []parityInstance
+	paginator := ec2v2.NewDescribeInstancesPaginator(client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, reservation := range page.Reservations {
+			for _, inst := range reservation.Instances {
+				p := parityInstance{
+					ID:        aws.StringValue(inst.InstanceId),
+					Type:      string(inst.InstanceType),
+					PrivateIP: aws.StringValue(inst.PrivateIpAddress),
+				}
+				if inst.State != nil {
+					p.State = string(inst.State.Name)
+				}
+				if inst.Placement != nil {
+					p.Zone = aws.StringValue(inst.Placement.AvailabilityZone)
+				}
+				for _, tag := range inst.Tags {
+					if p.Tags == nil {
+						p.Tags = make(map[string]string)
+					}
+					p.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+				}
+				out = append(out, p)
+			}
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// This example demonstrates interop.AssertSDKParity, which runs a v1 code
+// path and its v2 rewrite and reports any difference between their
+// results. Here the code being migrated is a DescribeInstances helper: the
+// rewrite is checked over every instance, with a filter, and on an
+// instance ID that does not exist, where both must fail the same way. In a
+// test, pass the *testing.T in place of the interop.ParityReport.
+func main() {
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	region := flag.String("region", "us-east-1", "region to describe instances in")
+	interop.ParseFlags()
+
+	fmt.Print("=== SDK Parity Test ===\n\n")
+
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(*region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ec2ClientV1 := ec2.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(*region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ec2ClientV2 := ec2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	report := &interop.ParityReport{}
+
+	fmt.Printf("1. Checking that both code paths describe the same instances in %s...\n", *region)
+	count := 0
+	if interop.AssertSDKParity(report, "DescribeInstances",
+		func() (any, error) {
+			out, err := describeInstancesV1(ctx, ec2ClientV1, &ec2.DescribeInstancesInput{})
+			count = len(out)
+			return out, err
+		},
+		func() (any, error) {
+			return describeInstancesV2(ctx, ec2ClientV2, &ec2v2.DescribeInstancesInput{})
+		}) {
+		fmt.Printf("   ✓ Both return the same %d instances\n", count)
+	}
+
+	fmt.Println("\n2. Checking a filter on the instance state...")
+	if interop.AssertSDKParity(report, "DescribeInstances instance-state-name=running",
+		func() (any, error) {
+			out, err := describeInstancesV1(ctx, ec2ClientV1, &ec2.DescribeInstancesInput{
+				Filters: []*ec2.Filter{{
+					Name:   aws.String("instance-state-name"),
+					Values: []*string{aws.String("running")},
+				}},
+			})
+			count = len(out)
+			return out, err
+		},
+		func() (any, error) {
+			return describeInstancesV2(ctx, ec2ClientV2, &ec2v2.DescribeInstancesInput{
+				Filters: []ec2types.Filter{{
+					Name:   aws.String("instance-state-name"),
+					Values: []string{"running"},
+				}},
+			})
+		}) {
+		fmt.Printf("   ✓ Both return the same %d running instances\n", count)
+	}
+
+	// An error is part of the result: the rewrite must fail where the
+	// original did, with the same kind of error.
+	fmt.Printf("\n3. Checking that both fail the same way on %s...\n", missingInstanceID)
+	if interop.AssertSDKParity(report, "DescribeInstances "+missingInstanceID,
+		func() (any, error) {
+			return describeInstancesV1(ctx, ec2ClientV1, &ec2.DescribeInstancesInput{
+				InstanceIds: []*string{aws.String(missingInstanceID)},
+			})
+		},
+		func() (any, error) {
+			return describeInstancesV2(ctx, ec2ClientV2, &ec2v2.DescribeInstancesInput{
+				InstanceIds: []string{missingInstanceID},
+			})
+		}) {
+		fmt.Println("   ✓ Both fail with the same error kind and code")
+	}
+
+	if report.Failures > 0 {
+		fmt.Printf("\n✗ %d parity checks failed\n", report.Failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ The v2 rewrite of the DescribeInstances helper returns what the v1 code did, errors included")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 InstanceType and State.Name are *string, v2 uses the ec2types.InstanceType and InstanceStateName enums")
+	fmt.Println("  - v1 filters take []*string values, v2 []string")
+	fmt.Println("  - Both SDKs' results must be mapped to one SDK-neutral type before AssertSDKParity can compare them")
+}
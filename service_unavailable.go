@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// mockRegion is the region the mock pretends S3 is missing from.
+const mockRegion = "il-central-1"
+
+// regionTransport answers for the services of a region that exists only in
+// memory. The hosts of absent services fail like a DNS lookup of a name that
+// does not exist, which is how both SDKs fail to reach a service missing
+// from a region; present services answer with an empty result.
+type regionTransport struct {
+	absent map[string]bool
+}
+
+func (t regionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	if t.absent[req.URL.Hostname()] {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: req.URL.Hostname(), IsNotFound: true}}
+	}
+	body := `<DescribeInstancesResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/"><requestId>mock</requestId><reservationSet/></DescribeInstancesResponse>`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// This example demonstrates how a service that does not exist in a region is
+// recognized. Neither SDK knows which services each region offers: both
+// build the endpoint from the partition's host template, so the call fails
+// with a DNS lookup of a host that does not exist, wrapped differently by
+// each SDK. interop.NormalizeError reports it, and v1's UnknownEndpointError
+// from strict endpoint matching, as ErrorKindUnavailable, and interop.Compare
+// marks such results Unavailable so that a sweep reports "service not
+// available" and continues. The region is mocked in memory, with EC2 present
+// and S3 absent; nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Service Unavailable in Region Test ===\n\n")
+
+	ctx := context.Background()
+	failures := 0
+	transport := regionTransport{absent: map[string]bool{
+		"s3." + mockRegion + ".amazonaws.com": true,
+	}}
+	fmt.Printf("Mocked region: %s (EC2 present, S3 absent)\n\n", mockRegion)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(mockRegion),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transport},
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(mockRegion),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transport}),
+		config.WithRetryMaxAttempts(1),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	clients := &interop.Clients{
+		Region:    mockRegion,
+		SessionV1: sessV1,
+		ConfigV2:  cfgV2,
+		EC2V1:     ec2v1.New(sessV1),
+		EC2V2:     ec2v2.NewFromConfig(cfgV2),
+		S3V1:      s3v1.New(sessV1),
+		S3V2:      s3v2.NewFromConfig(cfgV2),
+	}
+
+	// Errors from both SDKs, and look-alikes that must not count.
+	_, errV1 := clients.S3V1.ListBucketsWithContext(ctx, &s3v1.ListBucketsInput{})
+	_, errV2 := clients.S3V2.ListBuckets(ctx, &s3v2.ListBucketsInput{})
+	_, errStrict := endpoints.DefaultResolver().EndpointFor("s3", "xx-nowhere-1", endpoints.StrictMatchingOption)
+	cases := []struct {
+		name string
+		err  error
+		want interop.ErrorKind
+	}{
+		{"v1 call to a host that does not exist", errV1, interop.ErrorKindUnavailable},
+		{"v2 call to a host that does not exist", errV2, interop.ErrorKindUnavailable},
+		{"v1 strict endpoint matching in an unknown region", errStrict, interop.ErrorKindUnavailable},
+		{"DNS lookup timeout", &net.DNSError{Err: "i/o timeout", Name: "s3." + mockRegion + ".amazonaws.com", IsTimeout: true}, interop.ErrorKindUnknown},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, interop.ErrorKindUnknown},
+		{"access denied", awserr.NewRequestFailure(awserr.New("AccessDenied", "Access Denied", nil), http.StatusForbidden, "mock"), interop.ErrorKindAccessDenied},
+	}
+
+	fmt.Printf("1. Classifying %d errors with interop.NormalizeError...\n", len(cases))
+	for _, c := range cases {
+		if c.err == nil {
+			fmt.Printf("   ✗ %s: no error\n", c.name)
+			failures++
+			continue
+		}
+		got := interop.NormalizeError(c.err)
+		if got.Kind != c.want {
+			fmt.Printf("   ✗ %s: %s, want %s\n       %v\n", c.name, got.Kind, c.want, c.err)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ %s: %s\n", c.name, got.Kind)
+	}
+
+	fmt.Println("\n2. Comparing S3 and EC2 in the mocked region...")
+	var results interop.ComparisonResults
+	for _, name := range []string{"s3", "ec2"} {
+		sc, ok := interop.LookupComparer(name)
+		if !ok {
+			log.Fatalf("The %s comparer is not registered", name)
+		}
+		result := interop.Compare(ctx, clients, sc)
+		results = append(results, result)
+		switch {
+		case name == "s3" && !result.Unavailable:
+			fmt.Printf("   ✗ %s: not reported unavailable (%v)\n", name, result.Err)
+			failures++
+		case name == "s3" && !strings.Contains(result.Err.Error(), "service not available in "+mockRegion):
+			fmt.Printf("   ✗ %s: unclear error %q\n", name, result.Err)
+			failures++
+		case name == "s3":
+			fmt.Printf("   ✓ %s: service not available in %s\n", name, clients.Region)
+		case !result.Match():
+			fmt.Printf("   ✗ %s: %v %v\n", name, result.Err, result.Diffs)
+			failures++
+		default:
+			fmt.Printf("   ✓ %s: normalized results match, so the sweep went on after s3\n", name)
+		}
+	}
+
+	fmt.Println("\n3. Summarizing the results in Markdown...")
+	report := results.RenderMarkdown()
+	switch {
+	case results.Mismatched() != 0 || results.Unavailable() != 1:
+		fmt.Printf("   ✗ %d mismatched and %d unavailable, want 0 and 1\n", results.Mismatched(), results.Unavailable())
+		failures++
+	case !strings.Contains(report, "| s3 | – | unavailable |"):
+		fmt.Printf("   ✗ no unavailable row for s3 in:\n%s\n", report)
+		failures++
+	default:
+		fmt.Println("   ✓ s3 is listed as unavailable and not counted as a mismatch")
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Services missing from a region are reported as unavailable in both SDKs, and the other comparers still run")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 holds the DNS error in the awserr.Error's OrigErr chain, which errors.As does not follow")
+	fmt.Println("  - v2 wraps it in smithy.OperationError, and errors.As finds the *net.DNSError")
+	fmt.Println("  - Only v1 can refuse an unknown service or region up front, with endpoints.StrictMatchingOption")
+}
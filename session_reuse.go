@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// listBucketsTransport answers every request with an empty ListBuckets
+// result, so only SDK overhead is measured.
+type listBucketsTransport struct{}
+
+func (listBucketsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(strings.NewReader(`<ListAllMyBucketsResult><Buckets></Buckets></ListAllMyBucketsResult>`)),
+		Request:    req,
+	}, nil
+}
+
+// minSpeedupV1 is how many times faster reusing a v1 session and client
+// must be than recreating them on every call.
+const minSpeedupV1 = 2.0
+
+// This example demonstrates the cost of creating a v1 session and client per
+// call instead of once, and contrasts it with v2's load-config-once pattern.
+// Each variant makes S3 ListBuckets calls against a stub transport, so it
+// runs offline and measures only SDK overhead.
+func main() {
+	fmt.Print("=== Session Reuse Benchmark ===\n\n")
+
+	region := "us-east-1"
+	ctx := context.Background()
+	httpClient := &http.Client{Transport: listBucketsTransport{}}
+
+	// Use a throwaway shared config profile, as a typical developer setup
+	// would, so that creating a session or config parses the files and
+	// resolves credentials the way it does outside this example.
+	dir, err := os.MkdirTemp("", "session-reuse")
+	if err != nil {
+		log.Fatalf("Failed to create temp dir: %v", err)
+	}
+	credentialsFile := filepath.Join(dir, "credentials")
+	configFile := filepath.Join(dir, "config")
+	profile := "sdk-migration-test"
+	if err := os.WriteFile(credentialsFile, []byte("["+profile+"]\naws_access_key_id = AKIDEXAMPLE\naws_secret_access_key = secret\n"), 0o600); err != nil {
+		log.Fatalf("Failed to write credentials file: %v", err)
+	}
+	if err := os.WriteFile(configFile, []byte("[profile "+profile+"]\nregion = "+region+"\n"), 0o600); err != nil {
+		log.Fatalf("Failed to write config file: %v", err)
+	}
+
+	newSessionV1 := func() *session.Session {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Profile:           profile,
+			SharedConfigState: session.SharedConfigEnable,
+			SharedConfigFiles: []string{credentialsFile, configFile},
+			Config: aws.Config{
+				HTTPClient: httpClient,
+			},
+		})
+		if err != nil {
+			log.Fatalf("Failed to create v1 session: %v", err)
+		}
+		return sess
+	}
+	loadConfigV2 := func() *s3v2.Client {
+		cfg, err := config.LoadDefaultConfig(ctx,
+			config.WithSharedConfigProfile(profile),
+			config.WithSharedCredentialsFiles([]string{credentialsFile}),
+			config.WithSharedConfigFiles([]string{configFile}),
+			config.WithHTTPClient(httpClient),
+		)
+		if err != nil {
+			log.Fatalf("Failed to load v2 config: %v", err)
+		}
+		return s3v2.NewFromConfig(cfg)
+	}
+
+	callV1 := func(client *s3v1.S3) {
+		if _, err := client.ListBucketsWithContext(ctx, &s3v1.ListBucketsInput{}); err != nil {
+			log.Fatalf("ListBuckets failed with v1: %v", err)
+		}
+	}
+	callV2 := func(client *s3v2.Client) {
+		if _, err := client.ListBuckets(ctx, &s3v2.ListBucketsInput{}); err != nil {
+			log.Fatalf("ListBuckets failed with v2: %v", err)
+		}
+	}
+
+	type variant struct {
+		name   string
+		result testing.BenchmarkResult
+	}
+	run := func(name string, fn func(b *testing.B)) variant {
+		fmt.Printf("   running %s...\n", name)
+		return variant{name: name, result: testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b)
+		})}
+	}
+
+	fmt.Println("1. Benchmarking SDK v1...")
+	v1Reuse := run("v1 session+client reused", func(b *testing.B) {
+		client := s3v1.New(newSessionV1())
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			callV1(client)
+		}
+	})
+	v1PerCall := run("v1 session+client per call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			callV1(s3v1.New(newSessionV1()))
+		}
+	})
+
+	fmt.Println("\n2. Benchmarking SDK v2...")
+	v2Reuse := run("v2 config+client reused", func(b *testing.B) {
+		client := loadConfigV2()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			callV2(client)
+		}
+	})
+	v2PerCall := run("v2 config+client per call", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			callV2(loadConfigV2())
+		}
+	})
+
+	os.RemoveAll(dir)
+
+	fmt.Println("\n3. Results (per ListBuckets call)")
+	fmt.Printf("   %-28s %12s %12s %10s\n", "variant", "time", "bytes", "allocs")
+	for _, v := range []variant{v1Reuse, v1PerCall, v2Reuse, v2PerCall} {
+		fmt.Printf("   %-28s %10dns %12d %10d\n", v.name, v.result.NsPerOp(), v.result.AllocedBytesPerOp(), v.result.AllocsPerOp())
+	}
+
+	fmt.Println("\n4. Checking that reuse is substantially cheaper...")
+	speedupV1 := float64(v1PerCall.result.NsPerOp()) / float64(v1Reuse.result.NsPerOp())
+	speedupV2 := float64(v2PerCall.result.NsPerOp()) / float64(v2Reuse.result.NsPerOp())
+	fmt.Printf("   v2: loading the config once is %.1fx faster than per call\n", speedupV2)
+	if speedupV1 < minSpeedupV1 {
+		fmt.Printf("   ✗ v1: reusing the session is only %.1fx faster than per call (want at least %.0fx)\n", speedupV1, minSpeedupV1)
+		os.Exit(1)
+	}
+	fmt.Printf("   ✓ v1: reusing the session is %.1fx faster than per call\n", speedupV1)
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Creating the session (v1) or config (v2) once and sharing clients is much cheaper")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 session.NewSession resolves config, credentials and handlers on every call")
+	fmt.Println("  - v2 config.LoadDefaultConfig does the same work; load it once at startup")
+	fmt.Println("  - Clients are safe for concurrent use in both SDKs, so one per service is enough")
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	snsv1 "github.com/aws/aws-sdk-go/service/sns"
+
+	// AWS SDK v2
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// invalidAttribute is a v1 message attribute the converters must reject,
+// in both directions, with an error containing wantErr.
+type invalidAttribute struct {
+	name    string
+	attr    *snsv1.MessageAttributeValue
+	wantErr string
+}
+
+// toV2 returns attr as a v2 value, field by field, without validating it.
+func toV2(attr *snsv1.MessageAttributeValue) snstypes.MessageAttributeValue {
+	return snstypes.MessageAttributeValue{DataType: attr.DataType, StringValue: attr.StringValue, BinaryValue: attr.BinaryValue}
+}
+
+// attributeNames returns the names of attrs, sorted.
+func attributeNames(attrs map[string]*snsv1.MessageAttributeValue) []string {
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// This example demonstrates interop.ConvertSNSAttributesV1ToV2 and
+// ConvertSNSAttributesV2ToV1, which convert SNS message attributes between
+// v1's map of pointers and v2's map of values. Attributes of every data
+// type, String, Number, Binary and String.Array, plus custom types such as
+// Number.float, must survive a round trip in each direction unchanged and
+// without aliasing their input, and attributes with an invalid data type or
+// a value in the wrong field must be rejected by both converters. Nothing
+// is sent to AWS.
+func main() {
+	fmt.Print("=== SNS Message Attributes Test ===\n\n")
+
+	attrsV1 := map[string]*snsv1.MessageAttributeValue{
+		"customer":  {DataType: aws.String("String"), StringValue: aws.String("Ada Lovelace")},
+		"empty":     {DataType: aws.String("String"), StringValue: aws.String("")},
+		"quantity":  {DataType: aws.String("Number"), StringValue: aws.String("-12.5e3")},
+		"price":     {DataType: aws.String("Number.float"), StringValue: aws.String("9.99")},
+		"signature": {DataType: aws.String("Binary"), BinaryValue: []byte{0x00, 0xff, 0x10, 0x80}},
+		"thumbnail": {DataType: aws.String("Binary.gif"), BinaryValue: []byte("GIF89a")},
+		"regions":   {DataType: aws.String("String.Array"), StringValue: aws.String(`["us-east-1", "eu-west-1", 3, true, null]`)},
+	}
+
+	failures := 0
+
+	fmt.Printf("1. Converting %d attributes from v1 to v2 and back...\n", len(attrsV1))
+	attrsV2, err := interop.ConvertSNSAttributesV1ToV2(attrsV1)
+	if err != nil {
+		fmt.Printf("   ✗ v1 to v2: %v\n", err)
+		failures++
+	}
+	back, err := interop.ConvertSNSAttributesV2ToV1(attrsV2)
+	if err != nil {
+		fmt.Printf("   ✗ v2 to v1: %v\n", err)
+		failures++
+	}
+	for _, name := range attributeNames(attrsV1) {
+		switch want := attrsV1[name]; {
+		case !reflect.DeepEqual(attrsV2[name], toV2(want)):
+			fmt.Printf("   ✗ %s: v2 value %+v, want %+v\n", name, attrsV2[name], toV2(want))
+			failures++
+		case !reflect.DeepEqual(back[name], want):
+			fmt.Printf("   ✗ %s: round trip gave %v, want %v\n", name, back[name], want)
+			failures++
+		default:
+			fmt.Printf("   ✓ %s (%s) unchanged\n", name, aws.StringValue(want.DataType))
+		}
+	}
+
+	fmt.Println("\n2. Converting from v2 to v1 and back...")
+	fromV2, err := interop.ConvertSNSAttributesV2ToV1(attrsV2)
+	if err != nil {
+		fmt.Printf("   ✗ v2 to v1: %v\n", err)
+		failures++
+	}
+	backV2, err := interop.ConvertSNSAttributesV1ToV2(fromV2)
+	if err != nil {
+		fmt.Printf("   ✗ v1 to v2: %v\n", err)
+		failures++
+	}
+	if reflect.DeepEqual(backV2, attrsV2) {
+		fmt.Printf("   ✓ All %d attributes unchanged\n", len(backV2))
+	} else {
+		fmt.Printf("   ✗ Round trip gave %+v, want %+v\n", backV2, attrsV2)
+		failures++
+	}
+
+	fmt.Println("\n3. Changing converted values...")
+	// Converted values are copies: changing them must not change the input.
+	attrsV2["signature"].BinaryValue[0] = 0x42
+	*attrsV2["customer"].StringValue = "changed"
+	if attrsV1["signature"].BinaryValue[0] == 0x00 && aws.StringValue(attrsV1["customer"].StringValue) == "Ada Lovelace" {
+		fmt.Println("   ✓ The v1 input is unchanged")
+	} else {
+		fmt.Println("   ✗ Changing the v2 values changed the v1 input")
+		failures++
+	}
+
+	fmt.Println("\n4. Converting nil attribute maps...")
+	if nilV2, err := interop.ConvertSNSAttributesV1ToV2(nil); nilV2 != nil || err != nil {
+		fmt.Printf("   ✗ v1 to v2 gave %v, %v; want nil, nil\n", nilV2, err)
+		failures++
+	} else if nilV1, err := interop.ConvertSNSAttributesV2ToV1(nil); nilV1 != nil || err != nil {
+		fmt.Printf("   ✗ v2 to v1 gave %v, %v; want nil, nil\n", nilV1, err)
+		failures++
+	} else {
+		fmt.Println("   ✓ nil stays nil in both directions")
+	}
+
+	invalid := []invalidAttribute{
+		{"Unknown data type", &snsv1.MessageAttributeValue{DataType: aws.String("Integer"), StringValue: aws.String("1")}, `unsupported data type "Integer"`},
+		{"Lowercase data type", &snsv1.MessageAttributeValue{DataType: aws.String("string"), StringValue: aws.String("a")}, `unsupported data type "string"`},
+		{"Missing data type", &snsv1.MessageAttributeValue{StringValue: aws.String("a")}, "missing data type"},
+		{"Empty data type", &snsv1.MessageAttributeValue{DataType: aws.String(""), StringValue: aws.String("a")}, "missing data type"},
+		{"Binary value in StringValue", &snsv1.MessageAttributeValue{DataType: aws.String("Binary"), StringValue: aws.String("AAEC")}, "Binary value must be set in BinaryValue"},
+		{"Binary attribute with both values", &snsv1.MessageAttributeValue{DataType: aws.String("Binary"), StringValue: aws.String("AAEC"), BinaryValue: []byte{1}}, "must not set StringValue"},
+		{"Number value in BinaryValue", &snsv1.MessageAttributeValue{DataType: aws.String("Number"), BinaryValue: []byte("1")}, "Number value must be set in StringValue"},
+		{"String attribute with both values", &snsv1.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String("a"), BinaryValue: []byte("a")}, "must not set BinaryValue"},
+		{"String.Array that is not an array", &snsv1.MessageAttributeValue{DataType: aws.String("String.Array"), StringValue: aws.String(`"us-east-1"`)}, "String.Array value is not a JSON array"},
+		{"String.Array without a value", &snsv1.MessageAttributeValue{DataType: aws.String("String.Array")}, "String.Array value must be set in StringValue"},
+	}
+
+	fmt.Println("\n5. Converting invalid attributes...")
+	for _, c := range invalid {
+		_, errV1 := interop.ConvertSNSAttributesV1ToV2(map[string]*snsv1.MessageAttributeValue{"attr": c.attr})
+		_, errV2 := interop.ConvertSNSAttributesV2ToV1(map[string]snstypes.MessageAttributeValue{"attr": toV2(c.attr)})
+		var problems []string
+		for sdk, err := range map[string]error{"v1 to v2": errV1, "v2 to v1": errV2} {
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) || !strings.Contains(err.Error(), `"attr"`) {
+				problems = append(problems, fmt.Sprintf("%s gave %v", sdk, err))
+			}
+		}
+		if len(problems) > 0 {
+			sort.Strings(problems)
+			fmt.Printf("   ✗ %s: %s, want an error naming \"attr\" and %q\n", c.name, strings.Join(problems, "; "), c.wantErr)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ %s: %v\n", c.name, errV1)
+	}
+	if _, err := interop.ConvertSNSAttributesV1ToV2(map[string]*snsv1.MessageAttributeValue{"attr": nil}); err != nil && strings.Contains(err.Error(), "nil value") {
+		fmt.Printf("   ✓ nil v1 attribute: %v\n", err)
+	} else {
+		fmt.Printf("   ✗ nil v1 attribute: got %v, want a nil value error\n", err)
+		failures++
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d SNS attribute checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Attributes of every data type round-trip unchanged in both directions, and invalid ones are rejected by both converters")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 attributes are a map of *sns.MessageAttributeValue, v2 a map of types.MessageAttributeValue values, so only v1 can hold a nil attribute")
+	fmt.Println("  - Neither SDK checks that a value is in the field matching its data type; both send it, and SNS rejects the Publish")
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	snsv1 "github.com/aws/aws-sdk-go/service/sns"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	snsv2 "github.com/aws/aws-sdk-go-v2/service/sns"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// publishRecorder wraps a transport and records the Message and
+// MessageStructure form values of every Publish request, decoded as SNS
+// receives them.
+type publishRecorder struct {
+	next http.RoundTripper
+
+	mu        sync.Mutex
+	published []url.Values
+}
+
+func (p *publishRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Method == http.MethodPost {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		if form, err := url.ParseQuery(string(body)); err == nil && form.Get("Action") == "Publish" {
+			p.mu.Lock()
+			p.published = append(p.published, form)
+			p.mu.Unlock()
+		}
+	}
+	return p.next.RoundTrip(req)
+}
+
+// last returns the form of the last Publish request, or nil.
+func (p *publishRecorder) last() url.Values {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.published) == 0 {
+		return nil
+	}
+	return p.published[len(p.published)-1]
+}
+
+// pushMessages returns the per-protocol messages published to the endpoint:
+// a default for protocols without their own message, and a payload for the
+// push platforms. The same structure goes to an endpoint of any platform;
+// SNS picks the key matching the application's platform.
+func pushMessages(platform string) map[string]string {
+	apns := `{"aps":{"alert":"SDK migration test","badge":1},"source":"sdk-migration"}`
+	messages := map[string]string{
+		"default":      "SDK migration test",
+		"APNS":         apns,
+		"APNS_SANDBOX": apns,
+		"GCM":          `{"notification":{"title":"SDK migration","body":"SDK migration test"},"data":{"source":"sdk-migration"}}`,
+	}
+	switch platform {
+	case "ADM", "BAIDU":
+		messages[platform] = `{"data":{"message":"SDK migration test"}}`
+	case "APNS_VOIP", "APNS_VOIP_SANDBOX", "MACOS", "MACOS_SANDBOX":
+		messages[platform] = apns
+	}
+	return messages
+}
+
+// This example demonstrates publishing a multi-protocol message to an SNS
+// mobile push platform endpoint across SDKs. A platform endpoint is created
+// with SDK v1 under an existing platform application, then a message with
+// MessageStructure "json" is published to it with SDK v2 and with SDK v1.
+// The per-protocol message map is validated with
+// interop.BuildSNSMessageStructure before anything is sent, and the Message
+// each SDK put on the wire is decoded and compared with it.
+//
+// A platform application needs credentials from Apple, Google or another
+// push service, so it is passed with -platform-app-arn rather than created.
+// The endpoint's device token is random unless -token is given; a push
+// service may disable an endpoint with a made-up token after the first
+// delivery fails, which SNS then reports as EndpointDisabled.
+func main() {
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	appArn := flag.String("platform-app-arn", "", "ARN of the SNS platform application to create the endpoint under (required)")
+	token := flag.String("token", "", "device token of the endpoint (default: a random 64-digit hex token)")
+	interop.ParseFlags()
+
+	if *appArn == "" {
+		fmt.Fprintln(os.Stderr, "-platform-app-arn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	parsed, err := arn.Parse(*appArn)
+	// Platform application resources look like app/GCM/my-app.
+	parts := strings.Split(parsed.Resource, "/")
+	if err != nil || parsed.Service != "sns" || len(parts) != 3 || parts[0] != "app" {
+		fmt.Fprintf(os.Stderr, "Invalid -platform-app-arn %q: want arn:aws:sns:REGION:ACCOUNT:app/PLATFORM/NAME\n", *appArn)
+		flag.Usage()
+		os.Exit(2)
+	}
+	platform := parts[1]
+	if *token == "" {
+		b := make([]byte, 32)
+		rand.Read(b)
+		*token = hex.EncodeToString(b)
+	}
+
+	fmt.Print("=== SNS Platform Endpoint Interop Test ===\n\n")
+
+	region := parsed.Region
+	ctx := context.Background()
+	failures := 0
+
+	fmt.Printf("Platform application: %s (%s)\n", *appArn, platform)
+	fmt.Printf("Device token: %s\n\n", *token)
+
+	recorderV1 := &publishRecorder{next: http.DefaultTransport}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:     aws.String(region),
+		HTTPClient: &http.Client{Transport: recorderV1},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	snsClientV1 := snsv1.New(sessV1)
+
+	recorderV2 := &publishRecorder{next: http.DefaultTransport}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithHTTPClient(&http.Client{Transport: recorderV2}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	snsClientV2 := snsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Build the message
+	fmt.Println("1. Building the per-protocol message structure...")
+	messages := pushMessages(platform)
+	message, err := interop.BuildSNSMessageStructure(messages)
+	if err != nil {
+		log.Fatalf("Failed to build message structure: %v", err)
+	}
+	fmt.Printf("   ✓ %d protocols: %s\n", len(messages), message)
+	// Nesting a push payload as an object instead of a JSON string is the
+	// usual mistake; SNS would reject it or deliver the default message.
+	nested := strings.Replace(message, fmt.Sprintf("%q", messages["GCM"]), messages["GCM"], 1)
+	if _, err := interop.ParseSNSMessageStructure(nested); err == nil {
+		fmt.Println("   ✗ A GCM payload nested as an object was accepted")
+		failures++
+	} else {
+		fmt.Printf("   ✓ A GCM payload nested as an object is rejected: %v\n", err)
+	}
+
+	// Create the endpoint with v1
+	fmt.Println("\n2. Creating the platform endpoint with SDK v1...")
+	created, err := snsClientV1.CreatePlatformEndpointWithContext(ctx, &snsv1.CreatePlatformEndpointInput{
+		PlatformApplicationArn: aws.String(*appArn),
+		Token:                  aws.String(*token),
+		CustomUserData:         aws.String("sdk-migration-test"),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create platform endpoint with v1: %v", err)
+	}
+	endpointArn := aws.StringValue(created.EndpointArn)
+	fmt.Printf("   ✓ Endpoint created: %s\n", endpointArn)
+
+	cleanup := func() {
+		fmt.Println("\nCleaning up...")
+		if !interop.ConfirmDestructive(fmt.Sprintf("platform endpoint %s", endpointArn)) {
+			fmt.Printf("   Please manually delete endpoint: %s\n", endpointArn)
+			return
+		}
+		_, err := snsClientV2.DeleteEndpoint(ctx, &snsv2.DeleteEndpointInput{EndpointArn: aws.String(endpointArn)})
+		if err != nil {
+			log.Printf("Warning: Failed to delete endpoint: %v", err)
+			fmt.Printf("   Please manually delete endpoint: %s\n", endpointArn)
+		} else {
+			fmt.Println("   ✓ Endpoint deleted with SDK v2")
+		}
+	}
+
+	// Read it back with v2
+	fmt.Println("\n3. Reading the endpoint attributes with SDK v2...")
+	attrs, err := snsClientV2.GetEndpointAttributes(ctx, &snsv2.GetEndpointAttributesInput{EndpointArn: aws.String(endpointArn)})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get endpoint attributes with v2: %v", err)
+	}
+	if attrs.Attributes["Token"] != *token || attrs.Attributes["CustomUserData"] != "sdk-migration-test" {
+		fmt.Printf("   ✗ Unexpected attributes: %v\n", attrs.Attributes)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Token and CustomUserData match, Enabled=%s\n", attrs.Attributes["Enabled"])
+	}
+
+	// Publish with both SDKs
+	fmt.Println("\n4. Publishing the message structure with SDK v2, then SDK v1...")
+	_, errV2 := snsClientV2.Publish(ctx, &snsv2.PublishInput{
+		TargetArn:        aws.String(endpointArn),
+		Message:          aws.String(message),
+		MessageStructure: aws.String(interop.SNSMessageStructureJSON),
+	})
+	_, errV1 := snsClientV1.PublishWithContext(ctx, &snsv1.PublishInput{
+		TargetArn:        aws.String(endpointArn),
+		Message:          aws.String(message),
+		MessageStructure: aws.String(interop.SNSMessageStructureJSON),
+	})
+	for _, r := range []struct {
+		sdk string
+		err error
+	}{{"v2", errV2}, {"v1", errV1}} {
+		switch {
+		case r.err == nil:
+			fmt.Printf("   ✓ SDK %s: published\n", r.sdk)
+		case interop.NormalizeError(r.err).Code == "EndpointDisabled":
+			// The message structure was already accepted when the push
+			// service disabled the endpoint.
+			fmt.Printf("   ✓ SDK %s: the push service disabled the endpoint after an earlier delivery failed\n", r.sdk)
+		default:
+			fmt.Printf("   ✗ SDK %s: %v\n", r.sdk, r.err)
+			failures++
+		}
+	}
+
+	// Compare what went on the wire
+	fmt.Println("\n5. Comparing the message each SDK sent...")
+	for _, r := range []struct {
+		sdk  string
+		form url.Values
+	}{{"v1", recorderV1.last()}, {"v2", recorderV2.last()}} {
+		if r.form == nil {
+			fmt.Printf("   ✗ SDK %s sent no Publish request\n", r.sdk)
+			failures++
+			continue
+		}
+		sent, err := interop.ParseSNSMessageStructure(r.form.Get("Message"))
+		switch {
+		case r.form.Get("MessageStructure") != interop.SNSMessageStructureJSON:
+			fmt.Printf("   ✗ SDK %s sent MessageStructure %q\n", r.sdk, r.form.Get("MessageStructure"))
+			failures++
+		case err != nil:
+			fmt.Printf("   ✗ SDK %s sent an invalid message structure: %v\n", r.sdk, err)
+			failures++
+		case !reflect.DeepEqual(sent, messages):
+			got, _ := json.Marshal(sent)
+			fmt.Printf("   ✗ SDK %s sent different messages: %s\n", r.sdk, got)
+			failures++
+		default:
+			fmt.Printf("   ✓ SDK %s sent all %d protocol messages unchanged\n", r.sdk, len(sent))
+		}
+	}
+
+	cleanup()
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d platform endpoint checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs published the same %d-protocol message structure to an endpoint created with v1\n", len(messages))
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Both SDKs send Message verbatim: neither checks that a \"json\" structure is valid")
+	fmt.Println("  - Push payloads such as APNS and GCM must be JSON strings inside the structure, not nested objects")
+	fmt.Println("  - v1 GetEndpointAttributes returns map[string]*string, v2 map[string]string")
+	fmt.Println("  - EndpointDisabled is an awserr.Error code in v1 and a *types.EndpointDisabledException in v2")
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	snsv1 "github.com/aws/aws-sdk-go/service/sns"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	snsv2 "github.com/aws/aws-sdk-go-v2/service/sns"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// sampleCertURL is the SigningCertURL of the sample messages. It has the
+// shape of a real one, but the certificate is generated locally.
+const sampleCertURL = "https://sns.us-east-1.amazonaws.com/SimpleNotificationService-9c6465fa7f48f5cacd23014631ec1136.pem"
+
+// capturedMessage is a Notification that SNS delivered to an SQS queue in
+// us-east-2, recorded by the gocloud.dev v0.40.0 conformance tests
+// (pubsub/awssnssqs/testdata/TestConformanceSNSTopicV2/TestBatching.replay).
+// Unlike the sample messages, it was signed by SNS itself, so verifying it
+// checks the string to sign against what SNS signs. Its signing
+// certificate, the file named by its SigningCertURL, goes in the same
+// directory.
+const capturedMessage = "testdata/sns/notification.json"
+
+// sampleMessages returns a Notification and a SubscriptionConfirmation,
+// unsigned, with the fields of messages captured from an SQS subscription
+// and an HTTPS endpoint.
+func sampleMessages() []interop.SNSMessage {
+	topicArn := "arn:aws:sns:us-east-1:123456789012:sdk-migration-signature"
+	return []interop.SNSMessage{
+		{
+			Type:           interop.SNSTypeNotification,
+			MessageId:      "22b80b92-fdea-4c2c-8f9d-bdfb0c7bf324",
+			TopicArn:       topicArn,
+			Subject:        "sample",
+			Message:        `{"order":1234,"status":"shipped"}`,
+			Timestamp:      "2024-06-01T12:00:00.000Z",
+			SigningCertURL: sampleCertURL,
+			UnsubscribeURL: "https://sns.us-east-1.amazonaws.com/?Action=Unsubscribe&SubscriptionArn=" + topicArn + ":5f1e1b5c",
+		},
+		{
+			Type:           interop.SNSTypeSubscriptionConfirmation,
+			MessageId:      "165545c9-2a5c-472c-8df2-7ff2be2b3b1b",
+			Token:          "2336412f37fb687f5d51e6e241d09c805a5a57b30d712f794cc5f6a988666d92768dd60a747ba6f3beb71854e285d6ad02428b09ceece29417f1f02d609c582afbacc99c583a916b9981dd2728f4ae6fdb82efd087cc3b7849e05798d2d2785c03b0879594eeac82c01f235d0e717736",
+			TopicArn:       topicArn,
+			Message:        "You have chosen to subscribe to the topic " + topicArn + ".\nTo confirm the subscription, visit the SubscribeURL included in this message.",
+			Timestamp:      "2024-06-01T11:59:00.000Z",
+			SigningCertURL: sampleCertURL,
+			SubscribeURL:   "https://sns.us-east-1.amazonaws.com/?Action=ConfirmSubscription&TopicArn=" + topicArn + "&Token=2336412f37fb",
+		},
+	}
+}
+
+// sampleSigner signs messages the way SNS does, with a locally generated key
+// standing in for the SNS signing key.
+type sampleSigner struct {
+	key  *rsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newSampleSigner() (*sampleSigner, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &sampleSigner{key: key, cert: cert}, nil
+}
+
+// sign sets msg's SignatureVersion and Signature.
+func (s *sampleSigner) sign(msg *interop.SNSMessage, version string) error {
+	msg.SignatureVersion = version
+	stringToSign, err := interop.SNSStringToSign(msg)
+	if err != nil {
+		return err
+	}
+	var hash crypto.Hash
+	var digest []byte
+	if version == "1" {
+		sum := sha1.Sum([]byte(stringToSign))
+		hash, digest = crypto.SHA1, sum[:]
+	} else {
+		sum := sha256.Sum256([]byte(stringToSign))
+		hash, digest = crypto.SHA256, sum[:]
+	}
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.key, hash, digest)
+	if err != nil {
+		return err
+	}
+	msg.Signature = base64.StdEncoding.EncodeToString(signature)
+	return nil
+}
+
+// This example demonstrates verifying the signature of SNS messages, which
+// neither SDK does for you. Sample messages are first verified offline,
+// signed with a local key for both signature versions, along with tampered
+// copies that must be rejected. Then messages published with each SDK to a
+// topic are delivered to an SQS queue and verified against the real SNS
+// signing certificate, once with SignatureVersion 1 (SHA1) and once with 2
+// (SHA256). The signature covers the delivered document, so it verifies the
+// same way whichever SDK published.
+func main() {
+	messageFile := flag.String("message", "", "only verify the captured SNS message in FILE (JSON, e.g. an SQS message body)")
+	offline := flag.Bool("offline", false, "only run the offline checks with sample messages")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== SNS Message Signature Verification Test ===\n\n")
+
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	if *messageFile != "" {
+		data, err := os.ReadFile(*messageFile)
+		if err != nil {
+			log.Fatalf("Failed to read message: %v", err)
+		}
+		msg, err := interop.ParseSNSMessage(data)
+		if err != nil {
+			log.Fatalf("Failed to parse %s: %v", *messageFile, err)
+		}
+		if err := interop.VerifySNSMessage(ctx, msg); err != nil {
+			fmt.Printf("✗ %s (%s, SignatureVersion %s) does not verify: %v\n", msg.MessageId, msg.Type, msg.SignatureVersion, err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ %s (%s, SignatureVersion %s) was signed by SNS\n", msg.MessageId, msg.Type, msg.SignatureVersion)
+		return
+	}
+
+	// ===== PHASE 1: Verify sample messages offline =====
+	fmt.Println("PHASE 1: Verifying sample messages offline")
+	fmt.Println("-------------------------------------------")
+
+	signer, err := newSampleSigner()
+	if err != nil {
+		log.Fatalf("Failed to generate sample signing key: %v", err)
+	}
+	verifier := &interop.SNSVerifier{
+		FetchCertificate: func(ctx context.Context, certURL string) (*x509.Certificate, error) {
+			return signer.cert, nil
+		},
+	}
+
+	mismatches := 0
+	for _, version := range []string{"1", "2"} {
+		for _, msg := range sampleMessages() {
+			if err := signer.sign(&msg, version); err != nil {
+				log.Fatalf("Failed to sign sample message: %v", err)
+			}
+			label := fmt.Sprintf("%s, SignatureVersion %s", msg.Type, version)
+			if err := verifier.Verify(ctx, &msg); err != nil {
+				fmt.Printf("✗ %s: sample does not verify: %v\n", label, err)
+				mismatches++
+			} else {
+				fmt.Printf("✓ %s: sample verifies\n", label)
+			}
+
+			tampered := msg
+			tampered.Message += " (edited)"
+			if err := verifier.Verify(ctx, &tampered); err == nil {
+				fmt.Printf("✗ %s: tampered Message verifies\n", label)
+				mismatches++
+			} else {
+				fmt.Printf("✓ %s: tampered Message is rejected\n", label)
+			}
+		}
+	}
+	forged := sampleMessages()[0]
+	if err := signer.sign(&forged, "2"); err != nil {
+		log.Fatalf("Failed to sign sample message: %v", err)
+	}
+	forged.SigningCertURL = "https://sns.us-east-1.example.com/SimpleNotificationService.pem"
+	if err := verifier.Verify(ctx, &forged); err == nil {
+		fmt.Println("✗ A SigningCertURL outside amazonaws.com is accepted")
+		mismatches++
+	} else {
+		fmt.Printf("✓ A SigningCertURL outside amazonaws.com is rejected: %v\n", err)
+	}
+
+	mismatches += verifyCapturedMessage(ctx)
+
+	if *offline {
+		if mismatches > 0 {
+			fmt.Printf("\n✗ %d offline signature checks failed\n", mismatches)
+			os.Exit(1)
+		}
+		fmt.Println("\n=== Conclusion ===")
+		fmt.Println("✓ Signature verification accepts signed sample messages of both versions and rejects tampered ones")
+		return
+	}
+
+	topicName := interop.GenerateName("signature")
+	queueName := topicName
+	region := cfg.Region
+
+	fmt.Printf("\nTest topic and queue name: %s\n\n", topicName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	snsClientV1 := snsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	snsClientV2 := snsv2.NewFromConfig(cfgV2)
+	sqsClientV2 := sqsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 2: Create topic, queue and subscription =====
+	fmt.Println("PHASE 2: Creating topic with SDK v1 and queue with SDK v2")
+	fmt.Println("-----------------------------------------------------------")
+
+	topic, err := snsClientV1.CreateTopicWithContext(ctx, &snsv1.CreateTopicInput{
+		Name: aws.String(topicName),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create topic with v1: %v", err)
+	}
+	topicArn := aws.StringValue(topic.TopicArn)
+	fmt.Printf("✓ Topic created with SDK v1: %s\n", topicArn)
+
+	var queueURL string
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing queue and topic")
+		fmt.Println("-----------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("topic '%s' and queue '%s'", topicName, queueName)) {
+			fmt.Printf("\nPlease manually delete topic %s and queue %s\n", topicArn, queueName)
+			return
+		}
+		if queueURL != "" {
+			_, err := sqsClientV2.DeleteQueue(ctx, &sqsv2.DeleteQueueInput{
+				QueueUrl: aws.String(queueURL),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete queue: %v", err)
+				fmt.Printf("\nPlease manually delete queue: %s\n", queueURL)
+			} else {
+				fmt.Println("✓ Queue deleted successfully with SDK v2")
+			}
+		}
+		// Deleting the topic also deletes its subscriptions.
+		_, err := snsClientV2.DeleteTopic(ctx, &snsv2.DeleteTopicInput{
+			TopicArn: aws.String(topicArn),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete topic: %v", err)
+			fmt.Printf("\nPlease manually delete topic: %s\n", topicArn)
+		} else {
+			fmt.Println("✓ Topic deleted successfully with SDK v2")
+		}
+	}
+
+	queue, err := sqsClientV2.CreateQueue(ctx, &sqsv2.CreateQueueInput{
+		QueueName: aws.String(queueName),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to create queue with v2: %v", err)
+	}
+	queueURL = aws.StringValue(queue.QueueUrl)
+	attrs, err := sqsClientV2.GetQueueAttributes(ctx, &sqsv2.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to get queue ARN with v2: %v", err)
+	}
+	queueArn := interop.QueueAttributes(attrs.Attributes).QueueArn()
+	fmt.Printf("✓ Queue created with SDK v2: %s\n", queueURL)
+
+	policy, err := topicQueuePolicy(queueArn, topicArn)
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to build queue policy: %v", err)
+	}
+	_, err = sqsClientV2.SetQueueAttributes(ctx, &sqsv2.SetQueueAttributesInput{
+		QueueUrl:   aws.String(queueURL),
+		Attributes: map[string]string{string(sqstypes.QueueAttributeNamePolicy): policy},
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to set queue policy with v2: %v", err)
+	}
+	fmt.Println("✓ Queue policy allows the topic to send messages")
+
+	// Without raw message delivery, which is off by default, the queue
+	// receives the signed JSON document an HTTPS endpoint would.
+	_, err = snsClientV1.SubscribeWithContext(ctx, &snsv1.SubscribeInput{
+		TopicArn: aws.String(topicArn),
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String(queueArn),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to subscribe queue with v1: %v", err)
+	}
+	fmt.Println("✓ Queue subscribed to the topic with SDK v1")
+
+	// ===== PHASE 3: Publish with both SDKs and verify deliveries =====
+	fmt.Println("\n\nPHASE 3: Publishing with SDK v1 and SDK v2 and verifying signatures")
+	fmt.Println("---------------------------------------------------------------------")
+
+	verified := 0
+	for _, version := range []string{"1", "2"} {
+		if ctx.Err() != nil {
+			break
+		}
+		fmt.Printf("\nSignatureVersion %s:\n", version)
+		_, err := snsClientV2.SetTopicAttributes(ctx, &snsv2.SetTopicAttributesInput{
+			TopicArn:       aws.String(topicArn),
+			AttributeName:  aws.String("SignatureVersion"),
+			AttributeValue: aws.String(version),
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to set SignatureVersion with v2: %v", err)
+		}
+
+		// The v1 message has a Subject and the v2 message none, since
+		// Subject is only part of the signed string when present.
+		sentBy := map[string]string{}
+		bodyV1 := fmt.Sprintf("published with SDK v1 (SignatureVersion %s)", version)
+		_, err = snsClientV1.PublishWithContext(ctx, &snsv1.PublishInput{
+			TopicArn: aws.String(topicArn),
+			Subject:  aws.String("signature test"),
+			Message:  aws.String(bodyV1),
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to publish with v1: %v", err)
+		}
+		sentBy[bodyV1] = "SDK v1"
+		bodyV2 := fmt.Sprintf("published with SDK v2 (SignatureVersion %s)", version)
+		_, err = snsClientV2.Publish(ctx, &snsv2.PublishInput{
+			TopicArn: aws.String(topicArn),
+			Message:  aws.String(bodyV2),
+		})
+		if err != nil {
+			cleanup()
+			log.Fatalf("Failed to publish with v2: %v", err)
+		}
+		sentBy[bodyV2] = "SDK v2"
+
+		err = interop.Poll(ctx, time.Second, 60*time.Second, func(ctx context.Context) (bool, error) {
+			out, err := sqsClientV2.ReceiveMessage(ctx, &sqsv2.ReceiveMessageInput{
+				QueueUrl:            aws.String(queueURL),
+				MaxNumberOfMessages: 10,
+				WaitTimeSeconds:     10,
+			})
+			if err != nil {
+				return false, err
+			}
+			for _, m := range out.Messages {
+				_, err := sqsClientV2.DeleteMessage(ctx, &sqsv2.DeleteMessageInput{
+					QueueUrl:      aws.String(queueURL),
+					ReceiptHandle: m.ReceiptHandle,
+				})
+				if err != nil {
+					log.Printf("Warning: Failed to delete message: %v", err)
+				}
+				msg, err := interop.ParseSNSMessage([]byte(aws.StringValue(m.Body)))
+				if err != nil {
+					fmt.Printf("  ✗ Received a message that is not an SNS document: %v\n", err)
+					mismatches++
+					continue
+				}
+				sdk, ok := sentBy[msg.Message]
+				if !ok {
+					// A late delivery from the previous round.
+					continue
+				}
+				delete(sentBy, msg.Message)
+				if msg.SignatureVersion != version {
+					// The topic attribute may take a moment to apply;
+					// the signature must verify either way.
+					log.Printf("Warning: Message published with %s was signed with SignatureVersion %s, not %s", sdk, msg.SignatureVersion, version)
+				}
+				if err := interop.VerifySNSMessage(ctx, msg); err != nil {
+					fmt.Printf("  ✗ Message published with %s does not verify: %v\n", sdk, err)
+					mismatches++
+					continue
+				}
+				fmt.Printf("  ✓ Message published with %s verifies (SignatureVersion %s, certificate %s)\n", sdk, msg.SignatureVersion, msg.SigningCertURL)
+				verified++
+			}
+			return len(sentBy) == 0, nil
+		})
+		if err != nil {
+			for _, sdk := range sentBy {
+				fmt.Printf("  ✗ Message published with %s was not delivered: %v\n", sdk, err)
+				mismatches++
+			}
+		}
+	}
+
+	cleanup()
+
+	if ctx.Err() != nil {
+		fmt.Println("\n✗ Interrupted before every message was verified")
+		os.Exit(1)
+	}
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d signature checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Printf("✓ %d messages published with both SDKs verify against the SNS signing certificate\n", verified)
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Neither SDK verifies SNS signatures; the delivered document and its signature are the same whichever SDK published")
+	fmt.Println("  - SignatureVersion is a topic attribute set with SetTopicAttributes in both SDKs, \"1\" (SHA1) by default")
+	fmt.Println("  - SNS rotates its signing certificate, so verifiers fetch it by SigningCertURL instead of pinning one")
+}
+
+// topicQueuePolicy returns an SQS policy allowing topicArn only to send
+// messages to queueArn.
+func topicQueuePolicy(queueArn, topicArn string) (string, error) {
+	policy := map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Sid":       "AllowSNSDelivery",
+			"Effect":    "Allow",
+			"Principal": map[string]string{"Service": "sns.amazonaws.com"},
+			"Action":    "sqs:SendMessage",
+			"Resource":  queueArn,
+			"Condition": map[string]interface{}{
+				"ArnEquals": map[string]string{"aws:SourceArn": topicArn},
+			},
+		}},
+	}
+	b, err := json.Marshal(policy)
+	return string(b), err
+}
+
+// verifyCapturedMessage verifies capturedMessage offline, against the
+// certificate next to it and with DefaultClock set to the message's
+// Timestamp, when the certificate was valid, and checks that a tampered
+// copy is rejected. It returns the number of failed checks.
+func verifyCapturedMessage(ctx context.Context) int {
+	data, err := os.ReadFile(capturedMessage)
+	if err != nil {
+		log.Fatalf("Failed to read captured message: %v", err)
+	}
+	msg, err := interop.ParseSNSMessage(data)
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", capturedMessage, err)
+	}
+	certFile := filepath.Join(filepath.Dir(capturedMessage), path.Base(msg.SigningCertURL))
+	certPEM, err := os.ReadFile(certFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		fmt.Printf("- Captured %s: skipped, %s is missing; download it from %s\n", msg.Type, certFile, msg.SigningCertURL)
+		return 0
+	}
+	if err != nil {
+		log.Fatalf("Failed to read signing certificate: %v", err)
+	}
+	block, _ := pem.Decode(certPEM)
+	if block == nil || block.Type != "CERTIFICATE" {
+		log.Fatalf("%s is not a PEM certificate", certFile)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		log.Fatalf("Failed to parse %s: %v", certFile, err)
+	}
+	sent, err := time.Parse(time.RFC3339, msg.Timestamp)
+	if err != nil {
+		log.Fatalf("Failed to parse the Timestamp of %s: %v", capturedMessage, err)
+	}
+
+	// The certificate has expired since; check it as of the delivery.
+	clock := interop.DefaultClock
+	interop.DefaultClock = interop.NewFakeClock(sent)
+	defer func() { interop.DefaultClock = clock }()
+	verifier := &interop.SNSVerifier{
+		FetchCertificate: func(ctx context.Context, certURL string) (*x509.Certificate, error) {
+			return cert, nil
+		},
+	}
+
+	failures := 0
+	label := fmt.Sprintf("Captured %s, SignatureVersion %s", msg.Type, msg.SignatureVersion)
+	if err := verifier.Verify(ctx, msg); err != nil {
+		fmt.Printf("✗ %s: does not verify against %s: %v\n", label, certFile, err)
+		failures++
+	} else {
+		fmt.Printf("✓ %s: verifies against %s\n", label, certFile)
+	}
+	tampered := *msg
+	tampered.Message += " (edited)"
+	if err := verifier.Verify(ctx, &tampered); err == nil {
+		fmt.Printf("✗ %s: tampered Message verifies\n", label)
+		failures++
+	} else {
+		fmt.Printf("✓ %s: tampered Message is rejected\n", label)
+	}
+	return failures
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	sqsv1 "github.com/aws/aws-sdk-go/service/sqs"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// dlqArn is the dead-letter queue of the canned queue.
+const dlqArn = "arn:aws:sqs:us-east-1:123456789012:sdk-migration-test-dlq"
+
+// queueAttributesBody is a GetQueueAttributes response as SQS sends it: every
+// value is a string, and RedrivePolicy is a JSON document in one of them.
+const queueAttributesBody = `{"Attributes":{` +
+	`"QueueArn":"arn:aws:sqs:us-east-1:123456789012:sdk-migration-test",` +
+	`"VisibilityTimeout":"30",` +
+	`"DelaySeconds":"0",` +
+	`"ApproximateNumberOfMessages":"7",` +
+	`"RedrivePolicy":"{\"deadLetterTargetArn\":\"` + dlqArn + `\",\"maxReceiveCount\":5}"}}`
+
+// attributesTransport answers every request with queueAttributesBody.
+type attributesTransport struct{}
+
+func (attributesTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/x-amz-json-1.0"}},
+		Body:       io.NopCloser(strings.NewReader(queueAttributesBody)),
+		Request:    req,
+	}, nil
+}
+
+// redriveCase is one RedrivePolicy document and the expected parse result;
+// wantErr is empty when parsing must succeed.
+type redriveCase struct {
+	name    string
+	policy  string
+	want    interop.RedrivePolicy
+	wantErr string
+}
+
+var redriveCases = []redriveCase{
+	{"count as a number", `{"deadLetterTargetArn":"` + dlqArn + `","maxReceiveCount":5}`, interop.RedrivePolicy{DeadLetterTargetArn: dlqArn, MaxReceiveCount: 5}, ""},
+	{"count as a string", `{"deadLetterTargetArn":"` + dlqArn + `","maxReceiveCount":"10"}`, interop.RedrivePolicy{DeadLetterTargetArn: dlqArn, MaxReceiveCount: 10}, ""},
+	{"missing target", `{"maxReceiveCount":5}`, interop.RedrivePolicy{}, "deadLetterTargetArn is missing"},
+	{"missing count", `{"deadLetterTargetArn":"` + dlqArn + `"}`, interop.RedrivePolicy{}, "maxReceiveCount is missing"},
+	{"zero count", `{"deadLetterTargetArn":"` + dlqArn + `","maxReceiveCount":0}`, interop.RedrivePolicy{}, "not a positive whole number"},
+	{"fractional count", `{"deadLetterTargetArn":"` + dlqArn + `","maxReceiveCount":"2.5"}`, interop.RedrivePolicy{}, "not a positive whole number"},
+	{"not JSON", `deadLetterTargetArn=` + dlqArn, interop.RedrivePolicy{}, "invalid character"},
+}
+
+// This example demonstrates the interop SQS queue attribute converters. Both
+// SDKs decode the same canned GetQueueAttributes response, v1 into a map of
+// pointers and v2 into a map of values, and the converted attributes must
+// match and parse to the same typed values. It then checks the parsing of
+// numeric attributes and of RedrivePolicy documents, including the
+// maxReceiveCount written as a string that SQS also accepts.
+func main() {
+	fmt.Print("=== SQS Queue Attribute Converter Test ===\n\n")
+
+	region := "us-east-1"
+	ctx := context.Background()
+	queueURL := "https://sqs.us-east-1.amazonaws.com/123456789012/sdk-migration-test"
+	failures := 0
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: attributesTransport{}},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	sqsClientV1 := sqsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: attributesTransport{}}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	sqsClientV2 := sqsv2.NewFromConfig(cfgV2)
+
+	// Decode with both SDKs
+	fmt.Println("1. Decoding the same GetQueueAttributes response with both SDKs...")
+	outV1, err := sqsClientV1.GetQueueAttributesWithContext(ctx, &sqsv1.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: aws.StringSlice([]string{sqsv1.QueueAttributeNameAll}),
+	})
+	if err != nil {
+		log.Fatalf("Failed to get queue attributes with v1: %v", err)
+	}
+	outV2, err := sqsClientV2.GetQueueAttributes(ctx, &sqsv2.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []sqstypes.QueueAttributeName{sqstypes.QueueAttributeNameAll},
+	})
+	if err != nil {
+		log.Fatalf("Failed to get queue attributes with v2: %v", err)
+	}
+	attrsV1 := interop.ConvertSQSQueueAttributesV1ToV2(outV1.Attributes)
+	attrsV2 := interop.QueueAttributes(outV2.Attributes)
+	if !reflect.DeepEqual(attrsV1, attrsV2) {
+		fmt.Printf("   ✗ Converted v1 attributes differ from v2's\n       v1: %v\n       v2: %v\n", attrsV1, attrsV2)
+		failures++
+	} else {
+		fmt.Printf("   ✓ %d attributes match after converting v1's map[string]*string\n", len(attrsV2))
+	}
+	back := interop.ConvertSQSQueueAttributesV2ToV1(attrsV2)
+	if !reflect.DeepEqual(back, outV1.Attributes) {
+		fmt.Println("   ✗ Converting v2's attributes back to v1 does not give v1's map")
+		failures++
+	} else {
+		fmt.Println("   ✓ Converting back to v1 gives v1's map")
+	}
+
+	// Typed accessors
+	fmt.Println("\n2. Reading typed attributes from both...")
+	for _, a := range []struct {
+		sdk   string
+		attrs interop.QueueAttributes
+	}{{"v1", attrsV1}, {"v2", attrsV2}} {
+		timeout, errTimeout := a.attrs.VisibilityTimeout()
+		messages, errMessages := a.attrs.Int(sqstypes.QueueAttributeNameApproximateNumberOfMessages)
+		redrive, errRedrive := a.attrs.RedrivePolicy()
+		want := interop.RedrivePolicy{DeadLetterTargetArn: dlqArn, MaxReceiveCount: 5}
+		switch {
+		case errTimeout != nil || errMessages != nil || errRedrive != nil:
+			fmt.Printf("   ✗ SDK %s: %v, %v, %v\n", a.sdk, errTimeout, errMessages, errRedrive)
+			failures++
+		case timeout != 30 || messages != 7 || redrive != want:
+			fmt.Printf("   ✗ SDK %s: VisibilityTimeout %d, ApproximateNumberOfMessages %d, RedrivePolicy %+v\n", a.sdk, timeout, messages, redrive)
+			failures++
+		default:
+			fmt.Printf("   ✓ SDK %s: VisibilityTimeout %d, ApproximateNumberOfMessages %d, dead-letter queue after %d receives\n",
+				a.sdk, timeout, messages, redrive.MaxReceiveCount)
+		}
+	}
+
+	// Numeric attributes are strings on the wire
+	fmt.Println("\n3. Checking numeric attribute edge cases...")
+	bad := interop.QueueAttributes{string(sqstypes.QueueAttributeNameVisibilityTimeout): "thirty"}
+	if _, err := bad.VisibilityTimeout(); err == nil {
+		fmt.Println("   ✗ VisibilityTimeout \"thirty\" was accepted")
+		failures++
+	} else {
+		fmt.Printf("   ✓ Rejected: %v\n", err)
+	}
+	if _, err := (interop.QueueAttributes{}).VisibilityTimeout(); !errors.Is(err, interop.ErrNoQueueAttribute) {
+		fmt.Printf("   ✗ A missing VisibilityTimeout gave %v, want ErrNoQueueAttribute\n", err)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Missing: %v\n", err)
+	}
+	set := interop.QueueAttributes{}
+	set.SetInt(sqstypes.QueueAttributeNameDelaySeconds, 15)
+	if got := set[string(sqstypes.QueueAttributeNameDelaySeconds)]; got != "15" {
+		fmt.Printf("   ✗ SetInt stored %q, want \"15\"\n", got)
+		failures++
+	} else {
+		fmt.Println("   ✓ SetInt stores DelaySeconds as \"15\"")
+	}
+
+	// RedrivePolicy documents
+	fmt.Println("\n4. Parsing RedrivePolicy documents...")
+	for _, c := range redriveCases {
+		attrs := interop.QueueAttributes{string(sqstypes.QueueAttributeNameRedrivePolicy): c.policy}
+		got, err := attrs.RedrivePolicy()
+		switch {
+		case c.wantErr == "" && err != nil:
+			fmt.Printf("   ✗ %s: %v\n", c.name, err)
+			failures++
+		case c.wantErr == "" && got != c.want:
+			fmt.Printf("   ✗ %s: got %+v, want %+v\n", c.name, got, c.want)
+			failures++
+		case c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)):
+			fmt.Printf("   ✗ %s: got error %v, want one containing %q\n", c.name, err, c.wantErr)
+			failures++
+		case c.wantErr == "":
+			fmt.Printf("   ✓ %s: max receive count %d\n", c.name, got.MaxReceiveCount)
+		default:
+			fmt.Printf("   ✓ %s: rejected (%v)\n", c.name, err)
+		}
+	}
+	if _, err := (interop.QueueAttributes{}).RedrivePolicy(); !errors.Is(err, interop.ErrNoQueueAttribute) {
+		fmt.Printf("   ✗ A queue without a RedrivePolicy gave %v, want ErrNoQueueAttribute\n", err)
+		failures++
+	} else {
+		fmt.Println("   ✓ A queue without a RedrivePolicy reports ErrNoQueueAttribute")
+	}
+	roundTrip := interop.QueueAttributes{}
+	want := interop.RedrivePolicy{DeadLetterTargetArn: dlqArn, MaxReceiveCount: 3}
+	if err := roundTrip.SetRedrivePolicy(want); err != nil {
+		log.Fatalf("Failed to set RedrivePolicy: %v", err)
+	}
+	if got, err := roundTrip.RedrivePolicy(); err != nil || got != want {
+		fmt.Printf("   ✗ SetRedrivePolicy then RedrivePolicy gave %+v, %v\n", got, err)
+		failures++
+	} else {
+		fmt.Printf("   ✓ SetRedrivePolicy round-trips: %s\n", roundTrip[string(sqstypes.QueueAttributeNameRedrivePolicy)])
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d queue attribute checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Queue attributes convert losslessly between v1 and v2, and parse the same from both")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Attributes is map[string]*string, v2 map[string]string")
+	fmt.Println("  - v1 attribute names are string constants, v2 names are the sqstypes.QueueAttributeName enum")
+	fmt.Println("  - Neither SDK parses values: numbers and the RedrivePolicy JSON stay strings in both")
+}
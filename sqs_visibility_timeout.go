@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	sqsv1 "github.com/aws/aws-sdk-go/service/sqs"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	sqsv2 "github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// queueVisibilityTimeout is the queue's default visibility timeout, kept
+// short so that a message that was not extended would visibly come back.
+const queueVisibilityTimeout = 5 * time.Second
+
+// This example demonstrates extending a message's visibility timeout across
+// SDKs. A message is received with SDK v1 under the queue's short default
+// timeout, which SDK v2 then extends with ChangeMessageVisibility using the
+// v1 receipt handle. The message must stay hidden past the original timeout
+// and reappear once the extension ends, with a new receipt handle.
+func main() {
+	extend := flag.Duration("extend", 20*time.Second, "visibility timeout to extend the message to (at least 15s)")
+	flag.BoolVar(&interop.AssumeYes, "yes", false, "delete test resources without asking (required when stdin is not a terminal)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== SQS Visibility Timeout Interop Test ===\n\n")
+
+	// The hidden window is checked until 5s past the original timeout, and
+	// that check must end well before the extension does.
+	if *extend < 3*queueVisibilityTimeout || *extend > 12*time.Hour {
+		fmt.Fprintf(os.Stderr, "-extend must be between %s and 12h\n", 3*queueVisibilityTimeout)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	queueName := interop.GenerateName("visibility")
+	body := "extend my visibility"
+	region := cfg.Region
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	fmt.Printf("Test queue name: %s\n\n", queueName)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	sqsClientV1 := sqsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	sqsClientV2 := sqsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// ===== PHASE 1: Create queue and send a message with SDK v1 =====
+	fmt.Println("PHASE 1: Creating queue and sending a message using SDK v1")
+	fmt.Println("------------------------------------------------------------")
+
+	attrs := interop.QueueAttributes{}
+	attrs.SetInt(sqstypes.QueueAttributeNameVisibilityTimeout, int(queueVisibilityTimeout.Seconds()))
+	queue, err := sqsClientV1.CreateQueueWithContext(ctx, &sqsv1.CreateQueueInput{
+		QueueName:  aws.String(queueName),
+		Attributes: interop.ConvertSQSQueueAttributesV2ToV1(attrs),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create queue with v1: %v", err)
+	}
+	queueURL := aws.StringValue(queue.QueueUrl)
+	fmt.Printf("✓ Queue created with SDK v1 (visibility timeout %s): %s\n", queueVisibilityTimeout, queueURL)
+
+	// receiptHandle is the handle of the latest receive; each receive issues
+	// a new one, and only the latest can delete the message.
+	var receiptHandle string
+	cleanup := func() {
+		// Cleanup also runs after an interrupt has canceled ctx.
+		ctx := context.WithoutCancel(ctx)
+		fmt.Println("\n\nCLEANUP: Removing message and queue")
+		fmt.Println("-------------------------------------")
+		if !interop.ConfirmDestructive(fmt.Sprintf("queue '%s'", queueName)) {
+			fmt.Printf("\nPlease manually delete queue: %s\n", queueURL)
+			return
+		}
+		if receiptHandle != "" {
+			_, err := sqsClientV2.DeleteMessage(ctx, &sqsv2.DeleteMessageInput{
+				QueueUrl:      aws.String(queueURL),
+				ReceiptHandle: aws.String(receiptHandle),
+			})
+			if err != nil {
+				log.Printf("Warning: Failed to delete message: %v", err)
+			} else {
+				fmt.Println("✓ Message deleted successfully with SDK v2")
+			}
+		}
+		_, err := sqsClientV2.DeleteQueue(ctx, &sqsv2.DeleteQueueInput{
+			QueueUrl: aws.String(queueURL),
+		})
+		if err != nil {
+			log.Printf("Warning: Failed to delete queue: %v", err)
+			fmt.Printf("\nPlease manually delete queue: %s\n", queueURL)
+		} else {
+			fmt.Println("✓ Queue deleted successfully with SDK v2")
+		}
+	}
+
+	sent, err := sqsClientV1.SendMessageWithContext(ctx, &sqsv1.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(body),
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to send message with v1: %v", err)
+	}
+	messageID := aws.StringValue(sent.MessageId)
+	fmt.Printf("✓ Message sent with SDK v1: %s\n", messageID)
+
+	// ===== PHASE 2: Receive the message with SDK v1 =====
+	fmt.Println("\n\nPHASE 2: Receiving the message using SDK v1")
+	fmt.Println("---------------------------------------------")
+
+	var received *sqsv1.Message
+	err = interop.Poll(ctx, time.Second, 30*time.Second, func(ctx context.Context) (bool, error) {
+		out, err := sqsClientV1.ReceiveMessageWithContext(ctx, &sqsv1.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: aws.Int64(1),
+			WaitTimeSeconds:     aws.Int64(5),
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(out.Messages) == 0 {
+			return false, nil
+		}
+		received = out.Messages[0]
+		return true, nil
+	})
+	if err != nil {
+		cleanup()
+		log.Fatalf("Failed to receive message with v1: %v", err)
+	}
+	receivedAt := time.Now()
+	receiptHandle = aws.StringValue(received.ReceiptHandle)
+	fmt.Printf("✓ Received %s with SDK v1; it is hidden for %s\n", aws.StringValue(received.MessageId), queueVisibilityTimeout)
+
+	// ===== PHASE 3: Extend the visibility timeout with SDK v2 =====
+	fmt.Println("\n\nPHASE 3: Extending the visibility timeout using SDK v2")
+	fmt.Println("--------------------------------------------------------")
+
+	// The receipt handle only changes the visibility of a message that is
+	// still in flight: once the original timeout has passed, SQS rejects it
+	// with MessageNotInflight.
+	_, err = sqsClientV2.ChangeMessageVisibility(ctx, &sqsv2.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(queueURL),
+		ReceiptHandle:     aws.String(receiptHandle),
+		VisibilityTimeout: int32(extend.Seconds()),
+	})
+	if err != nil {
+		cleanup()
+		if interop.ErrorCode(err) == sqsv1.ErrCodeMessageNotInflight {
+			log.Fatalf("The message became visible again %s after the receive, before it could be extended: %v",
+				time.Since(receivedAt).Round(time.Millisecond), err)
+		}
+		log.Fatalf("Failed to change message visibility with v2: %v", err)
+	}
+	extendedAt := time.Now()
+	fmt.Printf("✓ Visibility extended with SDK v2 to %s, %s after the receive, using the v1 receipt handle\n",
+		*extend, extendedAt.Sub(receivedAt).Round(time.Millisecond))
+
+	// ===== PHASE 4: Verify the message stays hidden =====
+	fmt.Println("\n\nPHASE 4: Verifying the message is not redelivered within the window")
+	fmt.Println("---------------------------------------------------------------------")
+
+	mismatches := 0
+	hiddenUntil := receivedAt.Add(queueVisibilityTimeout + 5*time.Second)
+	fmt.Printf("Receiving with SDK v2 until %s past the original timeout...\n", 5*time.Second)
+	for time.Now().Before(hiddenUntil) && ctx.Err() == nil {
+		wait := max(1, int32(time.Until(hiddenUntil).Seconds()))
+		out, err := sqsClientV2.ReceiveMessage(ctx, &sqsv2.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: 1,
+			WaitTimeSeconds:     min(wait, 20),
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			cleanup()
+			log.Fatalf("Failed to receive message with v2: %v", err)
+		}
+		if len(out.Messages) > 0 {
+			// The message is back in flight under a new handle, which is
+			// the one cleanup must delete it with.
+			receiptHandle = aws.StringValue(out.Messages[0].ReceiptHandle)
+			fmt.Printf("✗ Message redelivered %s after the receive, despite the extension\n",
+				time.Since(receivedAt).Round(time.Millisecond))
+			mismatches++
+			break
+		}
+	}
+	if mismatches == 0 && ctx.Err() == nil {
+		fmt.Printf("✓ Message stayed hidden for %s, past the original %s timeout\n",
+			time.Since(receivedAt).Round(time.Second), queueVisibilityTimeout)
+
+		// ===== PHASE 5: Receive it again once the extension ends =====
+		fmt.Println("\n\nPHASE 5: Receiving the message again after the extension ends using SDK v2")
+		fmt.Println("-----------------------------------------------------------------------------")
+
+		fmt.Printf("Waiting until the extension ends at %s...\n", extendedAt.Add(*extend).Format(time.TimeOnly))
+		var redelivered sqstypes.Message
+		err = interop.Poll(ctx, time.Second, time.Until(extendedAt.Add(*extend))+30*time.Second, func(ctx context.Context) (bool, error) {
+			out, err := sqsClientV2.ReceiveMessage(ctx, &sqsv2.ReceiveMessageInput{
+				QueueUrl:                    aws.String(queueURL),
+				MaxNumberOfMessages:         1,
+				WaitTimeSeconds:             20,
+				MessageSystemAttributeNames: []sqstypes.MessageSystemAttributeName{sqstypes.MessageSystemAttributeNameApproximateReceiveCount},
+			})
+			if err != nil {
+				return false, err
+			}
+			if len(out.Messages) == 0 {
+				return false, nil
+			}
+			redelivered = out.Messages[0]
+			return true, nil
+		})
+		if err != nil {
+			fmt.Printf("✗ Message was not redelivered after the extension: %v\n", err)
+			mismatches++
+		} else {
+			newHandle := aws.StringValue(redelivered.ReceiptHandle)
+			count := redelivered.Attributes[string(sqstypes.MessageSystemAttributeNameApproximateReceiveCount)]
+			switch {
+			case aws.StringValue(redelivered.MessageId) != messageID || aws.StringValue(redelivered.Body) != body:
+				fmt.Printf("✗ Received %s %q, want %s %q\n", aws.StringValue(redelivered.MessageId), aws.StringValue(redelivered.Body), messageID, body)
+				mismatches++
+			case newHandle == receiptHandle:
+				fmt.Println("✗ The redelivered message kept the v1 receipt handle")
+				mismatches++
+			case count != "2":
+				fmt.Printf("✗ ApproximateReceiveCount is %s, want 2\n", count)
+				mismatches++
+			default:
+				fmt.Printf("✓ Message redelivered %s after the extension began, with a new receipt handle (receive count %s)\n",
+					time.Since(extendedAt).Round(time.Second), count)
+			}
+			// The v1 handle is stale now; only the new one deletes the message.
+			receiptHandle = newHandle
+		}
+	}
+
+	cleanup()
+
+	if ctx.Err() != nil {
+		fmt.Println("\n✗ Interrupted before the visibility checks completed")
+		os.Exit(1)
+	}
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d visibility timeout checks failed\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n\n=== Conclusion ===")
+	fmt.Println("✓ A receipt handle from SDK v1 extends the message's visibility through SDK v2")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 VisibilityTimeout and WaitTimeSeconds are *int64, v2 plain int32")
+	fmt.Println("  - v2 requests system attributes with MessageSystemAttributeNames; v1 AttributeNames is deprecated")
+	fmt.Println("  - In both SDKs the extension counts from the ChangeMessageVisibility call, not from the receive")
+}
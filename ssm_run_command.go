@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ssmv1 "github.com/aws/aws-sdk-go/service/ssm"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	ssmv2 "github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// This example demonstrates SSM Run Command interop: a harmless echo command
+// is sent with SDK v1 and its invocation results are read with both SDKs.
+// It runs a command on real instances, so it only does so with
+// -allow-run-command.
+func main() {
+	allow := flag.Bool("allow-run-command", false, "actually send the echo command to the tagged instances")
+	tag := flag.String("tag", "app=sdk-migration-test", "key=value tag selecting the target instances")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== SSM Run Command Interop Test ===\n\n")
+
+	if !*allow {
+		fmt.Println("This example runs a command on every instance tagged", *tag)
+		fmt.Println("Re-run with -allow-run-command to proceed.")
+		return
+	}
+	tagKey, tagValue, ok := strings.Cut(*tag, "=")
+	if !ok || tagKey == "" {
+		log.Fatalf("Invalid -tag %q, expected key=value", *tag)
+	}
+
+	region := cfg.Region
+	marker := interop.GenerateName("test")
+	// Cancel in-flight calls on Ctrl-C so that cleanup still runs.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	ssmClientV1 := ssmv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	ssmClientV2 := ssmv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Send the command with v1
+	fmt.Printf("1. Using SDK v1 to send AWS-RunShellScript to instances tagged %s...\n", *tag)
+	sendResult, err := ssmClientV1.SendCommand(&ssmv1.SendCommandInput{
+		DocumentName: aws.String("AWS-RunShellScript"),
+		Comment:      aws.String("aws-sdk-migration-tests interop check"),
+		Targets: []*ssmv1.Target{
+			{Key: aws.String("tag:" + tagKey), Values: aws.StringSlice([]string{tagValue})},
+		},
+		Parameters: map[string][]*string{
+			"commands": aws.StringSlice([]string{"echo " + marker}),
+		},
+		TimeoutSeconds: aws.Int64(60),
+	})
+	if err != nil {
+		log.Fatalf("Failed to send command with v1: %v", err)
+	}
+	commandID := aws.StringValue(sendResult.Command.CommandId)
+	fmt.Printf("   ✓ Command %s sent (status: %s)\n", commandID, aws.StringValue(sendResult.Command.Status))
+
+	// Wait for completion with v2
+	fmt.Println("\n2. Using SDK v2 to wait for the command to finish...")
+	var command ssmtypes.Command
+	err = interop.Poll(ctx, 3*time.Second, 3*time.Minute, func(ctx context.Context) (bool, error) {
+		out, err := ssmClientV2.ListCommands(ctx, &ssmv2.ListCommandsInput{
+			CommandId: aws.String(commandID),
+		})
+		if err != nil {
+			return false, err
+		}
+		if len(out.Commands) == 0 {
+			return false, nil
+		}
+		command = out.Commands[0]
+		fmt.Printf("   status: %s (%d/%d targets complete)\n", command.Status, command.CompletedCount, command.TargetCount)
+		switch command.Status {
+		case ssmtypes.CommandStatusPending, ssmtypes.CommandStatusInProgress, ssmtypes.CommandStatusCancelling:
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			// Interrupted: do not leave the command running on the instances.
+			_, cancelErr := ssmClientV2.CancelCommand(context.WithoutCancel(ctx), &ssmv2.CancelCommandInput{
+				CommandId: aws.String(commandID),
+			})
+			if cancelErr != nil {
+				log.Printf("Warning: Failed to cancel command %s: %v", commandID, cancelErr)
+			} else {
+				fmt.Printf("   ✓ Command %s canceled with SDK v2\n", commandID)
+			}
+		}
+		log.Fatalf("Command %s did not finish: %v", commandID, err)
+	}
+	if command.TargetCount == 0 {
+		fmt.Printf("   No instances are tagged %s (or none are managed by SSM); nothing to compare.\n", *tag)
+		return
+	}
+
+	// Compare each invocation
+	fmt.Println("\n3. Comparing invocation results from both SDKs...")
+	invocations, err := ssmClientV2.ListCommandInvocations(ctx, &ssmv2.ListCommandInvocationsInput{
+		CommandId: aws.String(commandID),
+	})
+	if err != nil {
+		log.Fatalf("Failed to list command invocations with v2: %v", err)
+	}
+
+	mismatches := 0
+	for _, invocation := range invocations.CommandInvocations {
+		instanceID := aws.StringValue(invocation.InstanceId)
+
+		resultV1, err := ssmClientV1.GetCommandInvocation(&ssmv1.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		if err != nil {
+			log.Printf("   ✗ %s: failed to get invocation with v1: %v", instanceID, err)
+			mismatches++
+			continue
+		}
+		resultV2, err := ssmClientV2.GetCommandInvocation(ctx, &ssmv2.GetCommandInvocationInput{
+			CommandId:  aws.String(commandID),
+			InstanceId: aws.String(instanceID),
+		})
+		if err != nil {
+			log.Printf("   ✗ %s: failed to get invocation with v2: %v", instanceID, err)
+			mismatches++
+			continue
+		}
+
+		// v1 reports the status as a *string and the exit code as *int64;
+		// v2 uses the CommandInvocationStatus enum and a plain int32.
+		statusV1 := aws.StringValue(resultV1.Status)
+		statusV2 := string(resultV2.Status)
+		codeV1 := aws.Int64Value(resultV1.ResponseCode)
+		codeV2 := int64(resultV2.ResponseCode)
+		outV1 := strings.TrimSpace(aws.StringValue(resultV1.StandardOutputContent))
+		outV2 := ""
+		if resultV2.StandardOutputContent != nil {
+			outV2 = strings.TrimSpace(*resultV2.StandardOutputContent)
+		}
+
+		fmt.Printf("   %s\n", instanceID)
+		fmt.Printf("     v1: status=%q exit=%d output=%q\n", statusV1, codeV1, outV1)
+		fmt.Printf("     v2: status=%s (%T) exit=%d output=%q\n", resultV2.Status, resultV2.Status, codeV2, outV2)
+		if statusV1 != statusV2 || codeV1 != codeV2 || outV1 != outV2 {
+			fmt.Println("     ✗ Results differ")
+			mismatches++
+			continue
+		}
+		if outV2 != marker {
+			fmt.Printf("     ✗ Unexpected output, wanted %q\n", marker)
+			mismatches++
+			continue
+		}
+		fmt.Println("     ✓ Results match")
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d invocations did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ A command sent with SDK v1 can be tracked and read with SDK v2")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Status is a *string, v2 uses CommandStatus and CommandInvocationStatus enums")
+	fmt.Println("  - v1 ResponseCode is *int64, v2 is a plain int32")
+	fmt.Println("  - v1 Parameters is map[string][]*string, v2 is map[string][]string")
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/session"
+	sfnv1 "github.com/aws/aws-sdk-go/service/sfn"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	sfnv2 "github.com/aws/aws-sdk-go-v2/service/sfn"
+	sfntypes "github.com/aws/aws-sdk-go-v2/service/sfn/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// diffExecutionJSON compares two execution payloads as JSON documents, so
+// that key order and whitespace do not count. A nil payload is JSON null.
+func diffExecutionJSON(a, b *string) ([]string, error) {
+	var docA, docB any
+	if a != nil {
+		if err := json.Unmarshal([]byte(*a), &docA); err != nil {
+			return nil, fmt.Errorf("first payload: %w", err)
+		}
+	}
+	if b != nil {
+		if err := json.Unmarshal([]byte(*b), &docB); err != nil {
+			return nil, fmt.Errorf("second payload: %w", err)
+		}
+	}
+	return interop.DiffJSON(docA, docB)
+}
+
+// This example demonstrates Step Functions interop: an execution of a
+// Standard state machine is started with SDK v1, polled with SDK v2 until it
+// leaves RUNNING, and described with both SDKs. Input and output are raw
+// JSON strings in both SDKs and are compared as JSON documents; the status
+// is a *string in v1 and the ExecutionStatus enum in v2. Executions cannot
+// be deleted and expire on their own, so nothing is cleaned up; the
+// execution ARN is printed so that it can be looked up in the console.
+func main() {
+	stateMachineArn := flag.String("state-machine-arn", "", "Standard state machine to start an execution of; it should finish within -timeout")
+	timeout := flag.Duration("timeout", 5*time.Minute, "how long to wait for the execution to finish")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	if *stateMachineArn == "" {
+		fmt.Fprintln(os.Stderr, "-state-machine-arn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	parsed, err := arn.Parse(*stateMachineArn)
+	if err != nil || parsed.Service != "states" || !strings.HasPrefix(parsed.Resource, "stateMachine:") {
+		fmt.Fprintf(os.Stderr, "Invalid -state-machine-arn %q: want arn:aws:states:REGION:ACCOUNT:stateMachine:NAME\n", *stateMachineArn)
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *timeout <= 0 {
+		fmt.Fprintln(os.Stderr, "-timeout must be positive")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== Step Functions Execution Interop Test ===\n\n")
+
+	// The state machine can only be reached in its own region.
+	region := parsed.Region
+	suffix := interop.DefaultClock.Now().Unix()
+	executionName := fmt.Sprintf("sdk-migration-test-%d", suffix)
+	input := fmt.Sprintf(`{"source": "aws-sdk-migration-tests", "run": %d, "sdks": ["v1", "v2"]}`, suffix)
+	// Stop waiting on Ctrl-C; the execution itself is left to finish.
+	ctx, stop := interop.ShutdownContext(context.Background())
+	defer stop()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	sfnClientV1 := sfnv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	sfnClientV2 := sfnv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// Express executions are not recorded, so DescribeExecution cannot see
+	// them; check the type before starting anything.
+	fmt.Println("1. Using SDK v2 to describe the state machine...")
+	machine, err := sfnClientV2.DescribeStateMachine(ctx, &sfnv2.DescribeStateMachineInput{
+		StateMachineArn: stateMachineArn,
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe state machine with v2: %v", err)
+	}
+	if machine.Type != sfntypes.StateMachineTypeStandard {
+		log.Fatalf("State machine %s is %s; DescribeExecution only works for %s state machines",
+			aws.StringValue(machine.Name), machine.Type, sfntypes.StateMachineTypeStandard)
+	}
+	fmt.Printf("   ✓ %s is a %s state machine (status: %s)\n", aws.StringValue(machine.Name), machine.Type, machine.Status)
+
+	fmt.Printf("\n2. Using SDK v1 to start execution %s...\n", executionName)
+	started, err := sfnClientV1.StartExecutionWithContext(ctx, &sfnv1.StartExecutionInput{
+		StateMachineArn: stateMachineArn,
+		Name:            aws.String(executionName),
+		Input:           aws.String(input),
+	})
+	if err != nil {
+		log.Fatalf("Failed to start execution with v1: %v", err)
+	}
+	executionArn := aws.StringValue(started.ExecutionArn)
+	fmt.Printf("   ✓ Execution started at %s\n", interop.FormatTime(started.StartDate))
+	fmt.Printf("   Execution ARN: %s\n", executionArn)
+
+	fmt.Println("\n3. Using SDK v2 to wait for the execution to finish...")
+	var executionV2 *sfnv2.DescribeExecutionOutput
+	err = interop.Poll(ctx, 2*time.Second, *timeout, func(ctx context.Context) (bool, error) {
+		out, err := sfnClientV2.DescribeExecution(ctx, &sfnv2.DescribeExecutionInput{
+			ExecutionArn: aws.String(executionArn),
+		})
+		if err != nil {
+			return false, err
+		}
+		executionV2 = out
+		fmt.Printf("   status: %s\n", out.Status)
+		return out.Status != sfntypes.ExecutionStatusRunning, nil
+	})
+	if err != nil {
+		log.Fatalf("Execution %s did not finish: %v", executionArn, err)
+	}
+
+	fmt.Println("\n4. Using SDK v1 to describe the finished execution...")
+	executionV1, err := sfnClientV1.DescribeExecutionWithContext(ctx, &sfnv1.DescribeExecutionInput{
+		ExecutionArn: aws.String(executionArn),
+	})
+	if err != nil {
+		log.Fatalf("Failed to describe execution with v1: %v", err)
+	}
+	fmt.Printf("   v1: status=%q (%T) stopped=%s\n", aws.StringValue(executionV1.Status), executionV1.Status, interop.FormatTime(executionV1.StopDate))
+	fmt.Printf("   v2: status=%s (%T) stopped=%s\n", executionV2.Status, executionV2.Status, interop.FormatTime(executionV2.StopDate))
+
+	fmt.Println("\n5. Comparing the execution as seen by both SDKs...")
+	failures := 0
+	check := func(what string, ok bool, detail string) {
+		if !ok {
+			fmt.Printf("   ✗ %s differs: %s\n", what, detail)
+			failures++
+			return
+		}
+		fmt.Printf("   ✓ %s matches\n", what)
+	}
+	statusV1, statusV2 := aws.StringValue(executionV1.Status), string(executionV2.Status)
+	check("Status", statusV1 == statusV2, fmt.Sprintf("v1 %q, v2 %q", statusV1, statusV2))
+	check("StartDate", interop.TimeEqual(executionV1.StartDate, executionV2.StartDate),
+		fmt.Sprintf("v1 %s, v2 %s", interop.FormatTime(executionV1.StartDate), interop.FormatTime(executionV2.StartDate)))
+	check("StopDate", interop.TimeEqual(executionV1.StopDate, executionV2.StopDate),
+		fmt.Sprintf("v1 %s, v2 %s", interop.FormatTime(executionV1.StopDate), interop.FormatTime(executionV2.StopDate)))
+	for _, payload := range []struct {
+		what   string
+		v1, v2 *string
+	}{
+		{"Input read back with v1", executionV1.Input, aws.String(input)},
+		{"Input", executionV1.Input, executionV2.Input},
+		{"Output", executionV1.Output, executionV2.Output},
+	} {
+		diffs, err := diffExecutionJSON(payload.v1, payload.v2)
+		if err != nil {
+			check(payload.what, false, err.Error())
+			continue
+		}
+		check(payload.what, len(diffs) == 0, strings.Join(diffs, "; "))
+	}
+	interop.Verbosef("output: %s", aws.StringValue(executionV2.Output))
+
+	if executionV2.Status != sfntypes.ExecutionStatusSucceeded {
+		fmt.Printf("   ✗ Execution ended %s: %s %s\n", executionV2.Status, aws.StringValue(executionV2.Error), aws.StringValue(executionV2.Cause))
+		failures++
+	}
+
+	fmt.Printf("\nExecution ARN: %s\n", executionArn)
+	if failures > 0 {
+		fmt.Printf("\n✗ %d execution checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ An execution started with SDK v1 can be tracked and read with SDK v2")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Status is a *string, v2 uses the ExecutionStatus enum")
+	fmt.Println("  - Input and Output are raw JSON strings in both SDKs; neither decodes them")
+	fmt.Println("  - Neither SDK has a waiter for executions, so the program polls DescribeExecution with interop.Poll")
+}
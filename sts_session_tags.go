@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	stsv2 "github.com/aws/aws-sdk-go-v2/service/sts"
+	ststypes "github.com/aws/aws-sdk-go-v2/service/sts/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// Session tag keys. The run tag is transitive and survives role chaining;
+// the SDK tag is not and must be gone from a chained session.
+const (
+	runTagKey = "sdk-migration-run"
+	sdkTagKey = "sdk-migration-sdk"
+)
+
+// assumedSession is an SDK-neutral view of an AssumeRole response.
+type assumedSession struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	AssumedRoleArn  string
+	// PackedPolicySize is the percentage of the allowed size that the
+	// session policy and tags use together.
+	PackedPolicySize int64
+}
+
+// principalTagPolicy returns a session policy that allows ec2:DescribeRegions
+// only when every tag in equals is set on the session with that value and
+// every tag in absent is not set, so that a successful DescribeRegions proves
+// what aws:PrincipalTag holds. It also allows assuming roleArn again with
+// tags, for role chaining.
+func principalTagPolicy(roleArn string, equals map[string]string, absent []string) string {
+	condition := map[string]map[string]string{"StringEquals": {}}
+	for k, v := range equals {
+		condition["StringEquals"]["aws:PrincipalTag/"+k] = v
+	}
+	if len(absent) > 0 {
+		condition["Null"] = map[string]string{}
+		for _, k := range absent {
+			condition["Null"]["aws:PrincipalTag/"+k] = "true"
+		}
+	}
+	policy := map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{
+			{"Effect": "Allow", "Action": "ec2:DescribeRegions", "Resource": "*", "Condition": condition},
+			{"Effect": "Allow", "Action": []string{"sts:AssumeRole", "sts:TagSession"}, "Resource": roleArn},
+		},
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		log.Fatalf("Failed to marshal session policy: %v", err)
+	}
+	return string(data)
+}
+
+// This example demonstrates passing session tags to AssumeRole with both
+// SDKs. Tags are []*sts.Tag and TransitiveTagKeys []*string in v1, while v2
+// uses []types.Tag and []string. AWS never returns the tags, so each session
+// gets a session policy that allows ec2:DescribeRegions only when
+// aws:PrincipalTag holds the expected values: the call succeeding proves the
+// tags arrived, and an untagged control session must be denied. The sessions'
+// credentials are used crosswise, v1's with v2 clients and v2's with v1
+// clients. Finally the v1 session assumes the role again: its transitive run
+// tag must carry over to the chained session and its SDK tag must not.
+func main() {
+	roleArn := flag.String("role-arn", "", "role to assume; its trust policy must allow sts:AssumeRole and sts:TagSession for the caller and for the role itself")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	if *roleArn == "" {
+		fmt.Fprintln(os.Stderr, "-role-arn is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+	if parsed, err := arn.Parse(*roleArn); err != nil || parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") {
+		fmt.Fprintf(os.Stderr, "Invalid -role-arn %q: want arn:aws:iam::ACCOUNT:role/NAME\n", *roleArn)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== STS Session Tags Interop Test ===\n\n")
+
+	region := cfg.Region
+	ctx := context.Background()
+	suffix := interop.DefaultClock.Now().Unix()
+	runID := fmt.Sprintf("run-%d", suffix)
+	fmt.Printf("Role: %s\nRun tag: %s=%s (transitive)\n\n", *roleArn, runTagKey, runID)
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	stsClientV1 := stsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	stsClientV2 := stsv2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	// sessionV1 and configV2 build clients that use an assumed session's
+	// credentials, whichever SDK obtained them.
+	sessionV1 := func(s assumedSession) *session.Session {
+		sess, err := session.NewSession(&aws.Config{
+			Region:      aws.String(region),
+			Credentials: credentials.NewStaticCredentials(s.AccessKeyID, s.SecretAccessKey, s.SessionToken),
+		})
+		if err != nil {
+			log.Fatalf("Failed to create v1 session: %v", err)
+		}
+		if *readOnly {
+			interop.ReadOnly.InstallV1(sess)
+		}
+		interop.InstallOperationSummaryV1(sess)
+		interop.InstallUserAgentV1(sess)
+		return sess
+	}
+	configV2 := func(s assumedSession) awsv2.Config {
+		cfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region),
+			config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider(s.AccessKeyID, s.SecretAccessKey, s.SessionToken)))
+		if err != nil {
+			log.Fatalf("Failed to load v2 config: %v", err)
+		}
+		if *readOnly {
+			interop.ReadOnly.InstallV2(&cfg)
+		}
+		interop.InstallOperationSummaryV2(&cfg)
+		interop.InstallUserAgentV2(&cfg)
+		return cfg
+	}
+	explain := func(sdk string, err error) {
+		if interop.ErrorCode(err) == "AccessDenied" {
+			log.Fatalf("STS denied AssumeRole with tags (SDK %s): the role's trust policy must allow sts:TagSession as well as sts:AssumeRole: %v", sdk, err)
+		}
+		log.Fatalf("Failed to assume role with SDK %s: %v", sdk, err)
+	}
+
+	// Use v1 to assume the role with tags
+	fmt.Println("1. Using SDK v1 to assume the role with session tags...")
+	outV1, err := stsClientV1.AssumeRoleWithContext(ctx, &stsv1.AssumeRoleInput{
+		RoleArn:         aws.String(*roleArn),
+		RoleSessionName: aws.String(fmt.Sprintf("sdk-migration-tags-v1-%d", suffix)),
+		DurationSeconds: aws.Int64(900),
+		Policy:          aws.String(principalTagPolicy(*roleArn, map[string]string{runTagKey: runID, sdkTagKey: "v1"}, nil)),
+		Tags: []*stsv1.Tag{
+			{Key: aws.String(runTagKey), Value: aws.String(runID)},
+			{Key: aws.String(sdkTagKey), Value: aws.String("v1")},
+		},
+		TransitiveTagKeys: aws.StringSlice([]string{runTagKey}),
+	})
+	if err != nil {
+		explain("v1", err)
+	}
+	taggedV1 := assumedSessionFromV1(outV1)
+	fmt.Printf("   ✓ Assumed %s (packed policy size %d%%)\n", taggedV1.AssumedRoleArn, taggedV1.PackedPolicySize)
+
+	// Use v2 to assume the role with tags
+	fmt.Println("\n2. Using SDK v2 to assume the role with session tags...")
+	policyV2 := principalTagPolicy(*roleArn, map[string]string{runTagKey: runID, sdkTagKey: "v2"}, nil)
+	outV2, err := stsClientV2.AssumeRole(ctx, &stsv2.AssumeRoleInput{
+		RoleArn:         aws.String(*roleArn),
+		RoleSessionName: aws.String(fmt.Sprintf("sdk-migration-tags-v2-%d", suffix)),
+		DurationSeconds: aws.Int32(900),
+		Policy:          aws.String(policyV2),
+		Tags: []ststypes.Tag{
+			{Key: aws.String(runTagKey), Value: aws.String(runID)},
+			{Key: aws.String(sdkTagKey), Value: aws.String("v2")},
+		},
+		TransitiveTagKeys: []string{runTagKey},
+	})
+	if err != nil {
+		explain("v2", err)
+	}
+	taggedV2 := assumedSessionFromV2(outV2)
+	fmt.Printf("   ✓ Assumed %s (packed policy size %d%%)\n", taggedV2.AssumedRoleArn, taggedV2.PackedPolicySize)
+
+	// The control session has the same policy but no tags.
+	fmt.Println("\n3. Using SDK v2 to assume the role without tags, as a control...")
+	outControl, err := stsClientV2.AssumeRole(ctx, &stsv2.AssumeRoleInput{
+		RoleArn:         aws.String(*roleArn),
+		RoleSessionName: aws.String(fmt.Sprintf("sdk-migration-tags-none-%d", suffix)),
+		DurationSeconds: aws.Int32(900),
+		Policy:          aws.String(policyV2),
+	})
+	if err != nil {
+		log.Fatalf("Failed to assume role with SDK v2: %v", err)
+	}
+	control := assumedSessionFromV2(outControl)
+	fmt.Printf("   ✓ Assumed %s\n", control.AssumedRoleArn)
+
+	failures := 0
+	check := func(ok bool, pass, fail string) {
+		if ok {
+			fmt.Printf("   ✓ %s\n", pass)
+		} else {
+			fmt.Printf("   ✗ %s\n", fail)
+			failures++
+		}
+	}
+
+	fmt.Println("\n4. Checking the caller identity of each tagged session with the other SDK...")
+	identityV2, err := stsv2.NewFromConfig(configV2(taggedV1)).GetCallerIdentity(ctx, &stsv2.GetCallerIdentityInput{})
+	if err != nil {
+		log.Fatalf("Failed to get caller identity with SDK v2: %v", err)
+	}
+	check(aws.StringValue(identityV2.Arn) == taggedV1.AssumedRoleArn,
+		fmt.Sprintf("SDK v2 with v1's credentials is %s", aws.StringValue(identityV2.Arn)),
+		fmt.Sprintf("SDK v2 with v1's credentials is %s, want %s", aws.StringValue(identityV2.Arn), taggedV1.AssumedRoleArn))
+	identityV1, err := stsv1.New(sessionV1(taggedV2)).GetCallerIdentityWithContext(ctx, &stsv1.GetCallerIdentityInput{})
+	if err != nil {
+		log.Fatalf("Failed to get caller identity with SDK v1: %v", err)
+	}
+	check(aws.StringValue(identityV1.Arn) == taggedV2.AssumedRoleArn,
+		fmt.Sprintf("SDK v1 with v2's credentials is %s", aws.StringValue(identityV1.Arn)),
+		fmt.Sprintf("SDK v1 with v2's credentials is %s, want %s", aws.StringValue(identityV1.Arn), taggedV2.AssumedRoleArn))
+
+	// describeRegions reports whether the session policy let s call
+	// DescribeRegions, which it only does when the tags match. Any error
+	// other than a denial is fatal.
+	describeRegionsV1 := func(s assumedSession) bool {
+		_, err := ec2v1.New(sessionV1(s)).DescribeRegionsWithContext(ctx, &ec2v1.DescribeRegionsInput{})
+		if err != nil && interop.ErrorCode(err) != "UnauthorizedOperation" {
+			log.Fatalf("DescribeRegions failed with SDK v1: %v", err)
+		}
+		return err == nil
+	}
+	describeRegionsV2 := func(s assumedSession) bool {
+		_, err := ec2v2.NewFromConfig(configV2(s)).DescribeRegions(ctx, &ec2v2.DescribeRegionsInput{})
+		if err != nil && interop.ErrorCode(err) != "UnauthorizedOperation" {
+			log.Fatalf("DescribeRegions failed with SDK v2: %v", err)
+		}
+		return err == nil
+	}
+
+	fmt.Println("\n5. Reading the tags back through aws:PrincipalTag with DescribeRegions...")
+	check(describeRegionsV2(taggedV1),
+		fmt.Sprintf("SDK v2 with v1's session: allowed, so %s=%s and %s=v1 are set", runTagKey, runID, sdkTagKey),
+		"SDK v2 with v1's session: denied, so its tags are missing or wrong")
+	check(describeRegionsV1(taggedV2),
+		fmt.Sprintf("SDK v1 with v2's session: allowed, so %s=%s and %s=v2 are set", runTagKey, runID, sdkTagKey),
+		"SDK v1 with v2's session: denied, so its tags are missing or wrong")
+	// A session is allowed what both the role's and the session policy
+	// allow, so denying the control shows that the tag condition decides.
+	check(!describeRegionsV2(control),
+		"Untagged control session: denied, so the policy depends on the tags",
+		"Untagged control session: allowed, so the tag checks above prove nothing")
+
+	fmt.Println("\n6. Chaining from v1's session with SDK v2, without passing tags...")
+	outChained, err := stsv2.NewFromConfig(configV2(taggedV1)).AssumeRole(ctx, &stsv2.AssumeRoleInput{
+		RoleArn:         aws.String(*roleArn),
+		RoleSessionName: aws.String(fmt.Sprintf("sdk-migration-tags-chained-%d", suffix)),
+		DurationSeconds: aws.Int32(900),
+		Policy:          aws.String(principalTagPolicy(*roleArn, map[string]string{runTagKey: runID}, []string{sdkTagKey})),
+	})
+	if err != nil {
+		if interop.ErrorCode(err) == "AccessDenied" {
+			log.Fatalf("STS denied role chaining: the role's trust policy must allow the role itself sts:AssumeRole and sts:TagSession: %v", err)
+		}
+		log.Fatalf("Failed to chain AssumeRole with SDK v2: %v", err)
+	}
+	chained := assumedSessionFromV2(outChained)
+	fmt.Printf("   ✓ Assumed %s\n", chained.AssumedRoleArn)
+	check(describeRegionsV1(chained),
+		fmt.Sprintf("Chained session: %s carried over and %s did not", runTagKey, sdkTagKey),
+		fmt.Sprintf("Chained session: denied, so %s was lost or %s carried over", runTagKey, sdkTagKey))
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d session tag checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Session tags passed by either SDK reach aws:PrincipalTag, and transitive tags survive role chaining")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Tags is []*sts.Tag and TransitiveTagKeys []*string, v2 []types.Tag and []string")
+	fmt.Println("  - v1 DurationSeconds and PackedPolicySize are *int64, v2 *int32")
+	fmt.Println("  - Neither SDK can read session tags back; only policies see them, through aws:PrincipalTag")
+}
+
+func assumedSessionFromV1(out *stsv1.AssumeRoleOutput) assumedSession {
+	s := assumedSession{PackedPolicySize: aws.Int64Value(out.PackedPolicySize)}
+	if u := out.AssumedRoleUser; u != nil {
+		s.AssumedRoleArn = aws.StringValue(u.Arn)
+	}
+	if c := out.Credentials; c != nil {
+		s.AccessKeyID = aws.StringValue(c.AccessKeyId)
+		s.SecretAccessKey = aws.StringValue(c.SecretAccessKey)
+		s.SessionToken = aws.StringValue(c.SessionToken)
+	}
+	return s
+}
+
+func assumedSessionFromV2(out *stsv2.AssumeRoleOutput) assumedSession {
+	s := assumedSession{}
+	if out.PackedPolicySize != nil {
+		s.PackedPolicySize = int64(*out.PackedPolicySize)
+	}
+	if u := out.AssumedRoleUser; u != nil {
+		s.AssumedRoleArn = aws.StringValue(u.Arn)
+	}
+	if c := out.Credentials; c != nil {
+		s.AccessKeyID = aws.StringValue(c.AccessKeyId)
+		s.SecretAccessKey = aws.StringValue(c.SecretAccessKey)
+		s.SessionToken = aws.StringValue(c.SessionToken)
+	}
+	return s
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	stsv2 "github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// webIdentityCredentials is an SDK-neutral view of the temporary credentials
+// returned by AssumeRoleWithWebIdentity. The secret and session token are
+// never printed.
+type webIdentityCredentials struct {
+	AccessKeyID     string
+	Expiration      time.Time
+	AssumedRoleArn  string
+	SubjectFromIdP  string
+	hasSecret       bool
+	hasSessionToken bool
+}
+
+func (c webIdentityCredentials) String() string {
+	return fmt.Sprintf("key %s... expires %s as %s", c.AccessKeyID[:min(len(c.AccessKeyID), 4)],
+		c.Expiration.UTC().Format(time.RFC3339), c.AssumedRoleArn)
+}
+
+// This example demonstrates assuming a role with a web identity token, as
+// EKS IRSA and other OIDC integrations do, with both SDKs. The token is read
+// from a file; by default the same file and role the SDKs' web identity
+// credential providers use (AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN).
+func main() {
+	roleArn := flag.String("role-arn", os.Getenv("AWS_ROLE_ARN"), "role to assume (default $AWS_ROLE_ARN)")
+	tokenFile := flag.String("token-file", os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE"), "file holding the OIDC token (default $AWS_WEB_IDENTITY_TOKEN_FILE)")
+	duration := flag.Duration("duration", 15*time.Minute, "lifetime of the temporary credentials (at least 15m)")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	cfg := interop.ParseFlags()
+
+	fmt.Print("=== STS AssumeRoleWithWebIdentity Interop Test ===\n\n")
+
+	if *roleArn == "" || *tokenFile == "" {
+		fmt.Fprintln(os.Stderr, "-role-arn and -token-file are required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	region := cfg.Region
+	ctx := context.Background()
+	sessionName := fmt.Sprintf("sdk-migration-%d", interop.DefaultClock.Now().Unix())
+
+	// Read the token once so both SDKs present the same one.
+	data, err := os.ReadFile(*tokenFile)
+	if err != nil {
+		log.Fatalf("Failed to read token file: %v", err)
+	}
+	token := strings.TrimSpace(string(data))
+	if exp, ok := tokenExpiry(token); ok {
+		if time.Until(exp) <= 0 {
+			log.Fatalf("The token in %s expired at %s; fetch a fresh one (projected service account tokens rotate hourly)",
+				*tokenFile, exp.UTC().Format(time.RFC3339))
+		}
+		fmt.Printf("Token expires at %s (in %s)\n", exp.UTC().Format(time.RFC3339), time.Until(exp).Round(time.Second))
+	}
+	fmt.Printf("Role: %s\nSession name: %s\n\n", *roleArn, sessionName)
+
+	// AssumeRoleWithWebIdentity is not signed: the token is the credential.
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	stsClientV1 := stsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	stsClientV2 := stsv2.NewFromConfig(cfgV2)
+
+	explain := func(sdk string, err error) {
+		switch interop.ErrorCode(err) {
+		case stsv1.ErrCodeExpiredTokenException:
+			log.Fatalf("STS rejected the token as expired (SDK %s); fetch a fresh one and retry", sdk)
+		case stsv1.ErrCodeInvalidIdentityTokenException:
+			log.Fatalf("STS rejected the token (SDK %s): check the role's trust policy and the OIDC provider: %v", sdk, err)
+		}
+		log.Fatalf("Failed to assume role with %s: %v", sdk, err)
+	}
+
+	// Use v1 to assume the role
+	fmt.Println("1. Using SDK v1 to assume the role with the web identity token...")
+	outV1, err := stsClientV1.AssumeRoleWithWebIdentityWithContext(ctx, &stsv1.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(*roleArn),
+		RoleSessionName:  aws.String(sessionName),
+		WebIdentityToken: aws.String(token),
+		DurationSeconds:  aws.Int64(int64(duration.Seconds())),
+	})
+	if err != nil {
+		explain("v1", err)
+	}
+	credsV1 := webIdentityCredentials{
+		SubjectFromIdP: aws.StringValue(outV1.SubjectFromWebIdentityToken),
+	}
+	if u := outV1.AssumedRoleUser; u != nil {
+		credsV1.AssumedRoleArn = aws.StringValue(u.Arn)
+	}
+	if c := outV1.Credentials; c != nil {
+		credsV1.AccessKeyID = aws.StringValue(c.AccessKeyId)
+		credsV1.Expiration = aws.TimeValue(c.Expiration)
+		credsV1.hasSecret = aws.StringValue(c.SecretAccessKey) != ""
+		credsV1.hasSessionToken = aws.StringValue(c.SessionToken) != ""
+	}
+	fmt.Printf("   ✓ %s\n", credsV1)
+
+	// Use v2 to assume the role
+	fmt.Println("\n2. Using SDK v2 to assume the role with the same token...")
+	outV2, err := stsClientV2.AssumeRoleWithWebIdentity(ctx, &stsv2.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(*roleArn),
+		RoleSessionName:  aws.String(sessionName),
+		WebIdentityToken: aws.String(token),
+		DurationSeconds:  aws.Int32(int32(duration.Seconds())),
+	})
+	if err != nil {
+		explain("v2", err)
+	}
+	credsV2 := webIdentityCredentials{}
+	if u := outV2.AssumedRoleUser; u != nil && u.Arn != nil {
+		credsV2.AssumedRoleArn = *u.Arn
+	}
+	if outV2.SubjectFromWebIdentityToken != nil {
+		credsV2.SubjectFromIdP = *outV2.SubjectFromWebIdentityToken
+	}
+	if c := outV2.Credentials; c != nil {
+		credsV2.AccessKeyID = aws.StringValue(c.AccessKeyId)
+		credsV2.Expiration = aws.TimeValue(c.Expiration)
+		credsV2.hasSecret = aws.StringValue(c.SecretAccessKey) != ""
+		credsV2.hasSessionToken = aws.StringValue(c.SessionToken) != ""
+	}
+	fmt.Printf("   ✓ %s\n", credsV2)
+
+	// Compare
+	fmt.Println("\n3. Comparing the temporary credentials...")
+	failures := 0
+	check := func(ok bool, pass, fail string) {
+		if ok {
+			fmt.Printf("   ✓ %s\n", pass)
+		} else {
+			fmt.Printf("   ✗ %s\n", fail)
+			failures++
+		}
+	}
+	for _, c := range []struct {
+		sdk   string
+		creds webIdentityCredentials
+	}{{"v1", credsV1}, {"v2", credsV2}} {
+		// Temporary keys are 20 characters starting with ASIA; long-term
+		// IAM user keys start with AKIA.
+		check(len(c.creds.AccessKeyID) == 20 && strings.HasPrefix(c.creds.AccessKeyID, "ASIA"),
+			fmt.Sprintf("SDK %s returned a temporary (ASIA...) access key", c.sdk),
+			fmt.Sprintf("SDK %s access key %q does not look temporary", c.sdk, c.creds.AccessKeyID))
+		check(c.creds.hasSecret && c.creds.hasSessionToken,
+			fmt.Sprintf("SDK %s returned a secret key and session token", c.sdk),
+			fmt.Sprintf("SDK %s is missing the secret key or session token", c.sdk))
+	}
+	check(credsV1.AssumedRoleArn == credsV2.AssumedRoleArn,
+		fmt.Sprintf("Both SDKs assumed %s", credsV1.AssumedRoleArn),
+		fmt.Sprintf("Assumed role ARNs differ: v1 %s, v2 %s", credsV1.AssumedRoleArn, credsV2.AssumedRoleArn))
+	check(credsV1.SubjectFromIdP == credsV2.SubjectFromIdP,
+		fmt.Sprintf("Both SDKs report token subject %q", credsV1.SubjectFromIdP),
+		fmt.Sprintf("Token subjects differ: v1 %q, v2 %q", credsV1.SubjectFromIdP, credsV2.SubjectFromIdP))
+	// The calls are made one after the other, so the expiries are close
+	// but not necessarily identical.
+	skew := credsV2.Expiration.Sub(credsV1.Expiration)
+	check(skew >= 0 && skew < time.Minute && time.Until(credsV1.Expiration) > *duration-time.Minute,
+		fmt.Sprintf("Expiries match the requested %s lifetime (%s apart)", *duration, skew.Round(time.Second)),
+		fmt.Sprintf("Expiries differ by %s or do not match the requested %s", skew.Round(time.Second), *duration))
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d web identity checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs exchange the same web identity token for equivalent temporary credentials")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 DurationSeconds is *int64, v2 is *int32")
+	fmt.Println("  - v2 AssumedRoleUser and Credentials are pointers to types structs; check them for nil")
+	fmt.Println("  - Both web identity credential providers read AWS_WEB_IDENTITY_TOKEN_FILE and AWS_ROLE_ARN")
+}
+
+// tokenExpiry returns the exp claim of a JWT, without verifying the token.
+// ok is false when token is not a JWT with an exp claim.
+func tokenExpiry(token string) (exp time.Time, ok bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == 0 {
+		return time.Time{}, false
+	}
+	return time.Unix(claims.Exp, 0), true
+}
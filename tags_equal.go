@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// tagsCase is one pair of tag maps and whether interop.TagsEqual must find
+// them equal with the given prefixes.
+type tagsCase struct {
+	name     string
+	a, b     map[string]string
+	prefixes []string
+	want     bool
+}
+
+// stackTags are the tags CloudFormation adds to every resource of a stack.
+func stackTags(stack string) map[string]string {
+	return map[string]string{
+		"aws:cloudformation:stack-name": stack,
+		"aws:cloudformation:stack-id":   "arn:aws:cloudformation:us-east-1:123456789012:stack/" + stack + "/1a2b3c4d",
+		"aws:cloudformation:logical-id": "AppInstance",
+	}
+}
+
+// withTags returns a copy of base with extra added.
+func withTags(base, extra map[string]string) map[string]string {
+	out := make(map[string]string, len(base)+len(extra))
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
+
+// This example demonstrates interop.TagsEqual, which compares tag maps while
+// ignoring AWS-managed aws: tags. The same resource deployed by two stacks,
+// or read from two accounts, carries different aws:cloudformation:* tags;
+// those must not count as a difference, while any difference between the
+// caller's own tags, including one hidden among managed tags, must. The ec2
+// comparer must likewise find no differences between instances whose only
+// difference is their managed tags. Nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Tag Comparison Test ===\n\n")
+
+	app := map[string]string{"app": "sdk-migration-test", "Name": "web-1", "team": "platform"}
+	failures := 0
+
+	cases := []tagsCase{
+		{name: "identical tags", a: app, b: withTags(app, nil), want: true},
+		{name: "nil and empty", a: nil, b: map[string]string{}, want: true},
+		{name: "only managed tags on one side", a: nil, b: stackTags("blue"), want: true},
+		{name: "different stacks, same own tags", a: withTags(app, stackTags("blue")), b: withTags(app, stackTags("green")), want: true},
+		{name: "managed tags on one side only", a: app, b: withTags(app, stackTags("blue")), want: true},
+		{name: "own tag value differs", a: withTags(app, stackTags("blue")), b: withTags(app, map[string]string{"team": "data"}), want: false},
+		{name: "own tag missing", a: app, b: map[string]string{"app": "sdk-migration-test", "Name": "web-1"}, want: false},
+		{name: "overlapping but extra own tag", a: app, b: withTags(app, map[string]string{"env": "prod"}), want: false},
+		// The prefix is case-sensitive, as AWS reserves only lowercase aws:.
+		{name: "AWS: is not a managed prefix", a: app, b: withTags(app, map[string]string{"AWS:owner": "me"}), want: false},
+		{name: "custom prefixes replace the default", a: withTags(app, map[string]string{"kubernetes.io/cluster/a": "owned"}), b: withTags(app, stackTags("blue")), prefixes: []string{"kubernetes.io/"}, want: false},
+		{name: "several custom prefixes", a: withTags(app, map[string]string{"kubernetes.io/cluster/a": "owned"}), b: withTags(app, stackTags("blue")), prefixes: []string{"kubernetes.io/", "aws:"}, want: true},
+	}
+
+	fmt.Printf("1. Comparing %d pairs of tag maps with interop.TagsEqual...\n", len(cases))
+	for _, c := range cases {
+		got := interop.TagsEqual(c.a, c.b, c.prefixes...)
+		// Equality must not depend on the order of the arguments.
+		swapped := interop.TagsEqual(c.b, c.a, c.prefixes...)
+		if got != c.want || swapped != c.want {
+			fmt.Printf("   ✗ %s: got %t (%t swapped), want %t\n", c.name, got, swapped, c.want)
+			failures++
+		} else {
+			fmt.Printf("   ✓ %s: %t\n", c.name, got)
+		}
+	}
+
+	fmt.Println("\n2. Comparing instances that differ only in managed tags with the ec2 comparer...")
+	comparer, ok := interop.LookupComparer("ec2")
+	if !ok {
+		log.Fatal("The ec2 comparer is not registered")
+	}
+	var tagsV1 []*ec2v1.Tag
+	for k, v := range withTags(app, stackTags("blue")) {
+		tagsV1 = append(tagsV1, &ec2v1.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	var tagsV2 []ec2types.Tag
+	for k, v := range withTags(app, stackTags("green")) {
+		tagsV2 = append(tagsV2, ec2types.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	instancesV1 := []*ec2v1.Instance{{
+		InstanceId:   aws.String("i-0123456789abcdef0"),
+		InstanceType: aws.String("t3.micro"),
+		State:        &ec2v1.InstanceState{Name: aws.String(ec2v1.InstanceStateNameRunning)},
+		Tags:         tagsV1,
+	}}
+	instancesV2 := []ec2types.Instance{{
+		InstanceId:   aws.String("i-0123456789abcdef0"),
+		InstanceType: ec2types.InstanceTypeT3Micro,
+		State:        &ec2types.InstanceState{Name: ec2types.InstanceStateNameRunning},
+		Tags:         tagsV2,
+	}}
+	diffs, err := interop.DiffJSON(comparer.Normalize(instancesV1), comparer.Normalize(instancesV2))
+	switch {
+	case err != nil:
+		fmt.Printf("   ✗ %v\n", err)
+		failures++
+	case len(diffs) > 0:
+		fmt.Printf("   ✗ %d differences, first %s\n", len(diffs), diffs[0])
+		failures++
+	default:
+		fmt.Println("   ✓ No differences: aws:cloudformation:* tags are left out of the normalized instances")
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d tag comparison checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ interop.TagsEqual ignores AWS-managed tags and catches every difference in the caller's own tags")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 tags are []*ec2.Tag and []*s3.Tag, v2 tags []types.Tag values; both convert to the same map")
+	fmt.Println("  - Neither SDK hides aws: tags: DescribeInstances and GetBucketTagging return them with the caller's own")
+	fmt.Println("  - aws: tags cannot be set or removed with either SDK, so a comparison has to skip them")
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// timeCase is a pair of timestamps, as v1 and v2 results hold them, and
+// what TimeEqual and FormatTime must say of them.
+type timeCase struct {
+	name      string
+	a, b      *time.Time
+	wantEqual bool
+	// wantA and wantB are the FormatTime of a and b.
+	wantA, wantB string
+}
+
+// at returns a pointer to t, as both SDKs return timestamps.
+func at(t time.Time) *time.Time { return &t }
+
+// This example demonstrates interop.TimeEqual and interop.FormatTime, which
+// the comparers use to compare timestamps from v1 and v2 results. Both
+// truncate to the second in UTC and treat a nil pointer and the zero time
+// as unset; each case checks a pair of timestamps, from nil and zero values
+// to sub-second and time zone differences, against both functions. Nothing
+// is sent to AWS.
+func main() {
+	fmt.Print("=== Time Helpers Test ===\n\n")
+
+	launch := time.Date(2024, 3, 9, 14, 30, 5, 0, time.UTC)
+	paris := time.FixedZone("CET", 3600)
+	const launchText = "2024-03-09T14:30:05Z"
+
+	cases := []timeCase{
+		{name: "nil and nil", wantEqual: true},
+		{name: "nil and the zero time", b: at(time.Time{}), wantEqual: true},
+		{name: "nil and a time", b: at(launch), wantB: launchText},
+		{name: "A time and nil", a: at(launch), wantA: launchText},
+		{name: "The zero time and a time", a: at(time.Time{}), b: at(launch), wantB: launchText},
+		{name: "The same time", a: at(launch), b: at(launch), wantEqual: true, wantA: launchText, wantB: launchText},
+		{
+			name: "Milliseconds from one SDK only", a: at(launch), b: at(launch.Add(250 * time.Millisecond)),
+			wantEqual: true, wantA: launchText, wantB: launchText,
+		},
+		{
+			// Truncated, not rounded: 999ms is still the same second.
+			name: "999ms past the second", a: at(launch.Add(999 * time.Millisecond)), b: at(launch),
+			wantEqual: true, wantA: launchText, wantB: launchText,
+		},
+		{
+			name: "One second apart", a: at(launch.Add(999 * time.Millisecond)), b: at(launch.Add(time.Second)),
+			wantA: launchText, wantB: "2024-03-09T14:30:06Z",
+		},
+		{
+			name: "The same instant in another time zone", a: at(launch), b: at(launch.In(paris)),
+			wantEqual: true, wantA: launchText, wantB: launchText,
+		},
+		{
+			name: "The same wall clock in another time zone", a: at(launch), b: at(time.Date(2024, 3, 9, 14, 30, 5, 0, paris)),
+			wantA: launchText, wantB: "2024-03-09T13:30:05Z",
+		},
+		{
+			name: "Nanoseconds before the epoch", a: at(time.Unix(-1, 500)), b: at(time.Unix(-1, 0)),
+			wantEqual: true, wantA: "1969-12-31T23:59:59Z", wantB: "1969-12-31T23:59:59Z",
+		},
+	}
+
+	failures := 0
+	fmt.Printf("1. Comparing and formatting %d pairs of timestamps...\n", len(cases))
+	for _, c := range cases {
+		equal, reverse := interop.TimeEqual(c.a, c.b), interop.TimeEqual(c.b, c.a)
+		gotA, gotB := interop.FormatTime(c.a), interop.FormatTime(c.b)
+		switch {
+		case equal != c.wantEqual || reverse != equal:
+			fmt.Printf("   ✗ %s: TimeEqual gave %t and, reversed, %t; want %t\n", c.name, equal, reverse, c.wantEqual)
+			failures++
+		case gotA != c.wantA || gotB != c.wantB:
+			fmt.Printf("   ✗ %s: FormatTime gave %q and %q, want %q and %q\n", c.name, gotA, gotB, c.wantA, c.wantB)
+			failures++
+		default:
+			fmt.Printf("   ✓ %s: equal=%t, formatted %q and %q\n", c.name, equal, gotA, gotB)
+		}
+	}
+
+	fmt.Println("\n2. Formatting a sub-second time...")
+	// The time is truncated for formatting, not changed.
+	precise := launch.Add(123456789 * time.Nanosecond)
+	before := precise
+	if got := interop.FormatTime(&precise); got != launchText || !precise.Equal(before) {
+		fmt.Printf("   ✗ Got %q and the time became %s, want %q and the time unchanged\n", got, precise, launchText)
+		failures++
+	} else {
+		fmt.Printf("   ✓ Formatted %q, and the time still has its %dns\n", got, precise.Nanosecond())
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d time checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Timestamps compare and format alike to the second in UTC, and nil and the zero time are both unset")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - Both SDKs return timestamps as *time.Time, but may parse the same field with different sub-second precision, which TimeEqual and FormatTime ignore")
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+	s3v1 "github.com/aws/aws-sdk-go/service/s3"
+
+	// AWS SDK v2
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// describeRegionsBody is an EC2 DescribeRegions response with two regions.
+const describeRegionsBody = `<DescribeRegionsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">` +
+	`<requestId>fixture</requestId><regionInfo>` +
+	`<item><regionName>us-east-1</regionName><regionEndpoint>ec2.us-east-1.amazonaws.com</regionEndpoint><optInStatus>opt-in-not-required</optInStatus></item>` +
+	`<item><regionName>eu-west-1</regionName><regionEndpoint>ec2.eu-west-1.amazonaws.com</regionEndpoint><optInStatus>opt-in-not-required</optInStatus></item>` +
+	`</regionInfo></DescribeRegionsResponse>`
+
+// accountTransport stands in for AWS while capturing. Every ListBuckets
+// call sees one more bucket than the previous one, so that replay can show
+// repeated calls are answered in the order they were captured.
+type accountTransport struct {
+	mu       sync.Mutex
+	requests int
+	buckets  int
+}
+
+func (t *accountTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.requests++
+	body := describeRegionsBody
+	if strings.HasPrefix(req.URL.Host, "s3.") {
+		t.buckets++
+		var b strings.Builder
+		b.WriteString(`<ListAllMyBucketsResult><Owner><ID>fixture</ID></Owner><Buckets>`)
+		for i := 1; i <= t.buckets; i++ {
+			fmt.Fprintf(&b, `<Bucket><Name>bucket-%d</Name><CreationDate>2024-01-02T03:04:05.000Z</CreationDate></Bucket>`, i)
+		}
+		b.WriteString(`</Buckets></ListAllMyBucketsResult>`)
+		body = b.String()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// callResults are the decoded results of the calls each phase makes: the
+// bucket names of each ListBuckets call, and the region names.
+type callResults struct {
+	BucketsV1 [][]string
+	BucketsV2 [][]string
+	RegionsV1 []string
+	RegionsV2 []string
+}
+
+// This example demonstrates interop.Trace, which records the responses of
+// API calls into a directory and replays them later without credentials or
+// network access. Calls made with both SDKs are captured from a stand-in
+// account, then replayed through interop.NewClients with WithTrace, and the
+// decoded results must match; the transport must not see a single replayed
+// request. A call whose input was never captured must fail with
+// interop.ErrNotCaptured rather than be retried or sent.
+func main() {
+	dir := flag.String("dir", "", "capture into this directory and keep it (default: a temporary directory that is removed)")
+	interop.ParseFlags()
+
+	fmt.Print("=== API Trace Capture and Replay Test ===\n\n")
+
+	region := "us-east-1"
+	ctx := context.Background()
+	failures := 0
+
+	if *dir == "" {
+		tmp, err := os.MkdirTemp("", "sdk-migration-trace-")
+		if err != nil {
+			log.Fatalf("Failed to create a temporary directory: %v", err)
+		}
+		defer os.RemoveAll(tmp)
+		*dir = tmp
+	}
+
+	// Capture
+	fmt.Printf("1. Capturing calls made with both SDKs into %s...\n", *dir)
+	capture, err := interop.NewTrace(*dir, interop.TraceCapture)
+	if err != nil {
+		log.Fatalf("Failed to create capture: %v", err)
+	}
+	account := &accountTransport{}
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: account},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	capture.InstallV1(sessV1)
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: account}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	capture.InstallV2(&cfgV2)
+
+	captured, err := runCalls(ctx, s3v1.New(sessV1), s3v2.NewFromConfig(cfgV2), ec2v1.New(sessV1), ec2v2.NewFromConfig(cfgV2), 2)
+	if err != nil {
+		log.Fatalf("Failed to make the calls to capture: %v", err)
+	}
+	var files []string
+	filepath.WalkDir(*dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(*dir, path)
+			files = append(files, rel)
+		}
+		return nil
+	})
+	fmt.Printf("   ✓ %d requests captured into %d files:\n", account.requests, len(files))
+	for _, f := range files {
+		fmt.Printf("       %s\n", f)
+	}
+
+	// Replay
+	fmt.Println("\n2. Replaying the same calls through interop.NewClients, without credentials or network...")
+	replay, err := interop.NewTrace(*dir, interop.TraceReplay)
+	if err != nil {
+		log.Fatalf("Failed to open capture for replay: %v", err)
+	}
+	clients, err := interop.NewClients(ctx, region, interop.WithTrace(replay))
+	if err != nil {
+		log.Fatalf("Failed to create clients: %v", err)
+	}
+	sent := account.requests
+	replayed, err := runCalls(ctx, clients.S3V1, clients.S3V2, clients.EC2V1, clients.EC2V2, 3)
+	switch {
+	case err != nil:
+		fmt.Printf("   ✗ Replay failed: %v\n", err)
+		failures++
+	case account.requests != sent:
+		fmt.Printf("   ✗ %d replayed requests reached the transport\n", account.requests-sent)
+		failures++
+	default:
+		fmt.Println("   ✓ Every call was answered from the capture")
+	}
+
+	// Compare
+	fmt.Println("\n3. Comparing replayed results with the captured ones...")
+	// The third v2 ListBuckets call was never captured: replay repeats the
+	// last response, as it does for polls.
+	want := captured
+	want.BucketsV2 = append(want.BucketsV2, want.BucketsV2[len(want.BucketsV2)-1])
+	if err == nil {
+		if !reflect.DeepEqual(replayed, want) {
+			fmt.Printf("   ✗ Results differ\n       captured: %+v\n       replayed: %+v\n", want, replayed)
+			failures++
+		} else {
+			fmt.Printf("   ✓ v1 ListBuckets: %v\n", replayed.BucketsV1)
+			fmt.Printf("   ✓ v2 ListBuckets: %v (the last response repeats once the capture runs out)\n", replayed.BucketsV2)
+			fmt.Printf("   ✓ DescribeRegions: v1 %v, v2 %v\n", replayed.RegionsV1, replayed.RegionsV2)
+		}
+	}
+
+	// Missing capture
+	fmt.Println("\n4. Calling with an input that was never captured...")
+	_, errV1 := clients.EC2V1.DescribeRegionsWithContext(ctx, &ec2v1.DescribeRegionsInput{AllRegions: aws.Bool(true)})
+	_, errV2 := clients.EC2V2.DescribeRegions(ctx, &ec2v2.DescribeRegionsInput{AllRegions: awsv2.Bool(true)})
+	for _, r := range []struct {
+		sdk string
+		err error
+	}{{"v1", errV1}, {"v2", errV2}} {
+		if !errors.Is(r.err, interop.ErrNotCaptured) {
+			fmt.Printf("   ✗ SDK %s: got %v, want ErrNotCaptured\n", r.sdk, r.err)
+			failures++
+		} else {
+			fmt.Printf("   ✓ SDK %s: %v\n", r.sdk, r.err)
+		}
+	}
+	if account.requests != sent {
+		fmt.Printf("   ✗ %d requests reached the transport\n", account.requests-sent)
+		failures++
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d trace checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Captured responses replay to the same results in both SDKs, without sending anything")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 is hooked through session handlers: Validate keys the call, Send is swapped for replay")
+	fmt.Println("  - v2 is hooked through an Initialize middleware and a wrapped aws.Config.HTTPClient")
+	fmt.Println("  - The SDKs encode inputs differently, so each has its own captures under v1/ and v2/")
+}
+
+// runCalls makes the calls both phases compare: ListBuckets once with v1
+// and listsV2 times with v2, and DescribeRegions once with each SDK.
+func runCalls(ctx context.Context, s3ClientV1 *s3v1.S3, s3ClientV2 *s3v2.Client, ec2ClientV1 *ec2v1.EC2, ec2ClientV2 *ec2v2.Client, listsV2 int) (callResults, error) {
+	var r callResults
+	outV1, err := s3ClientV1.ListBucketsWithContext(ctx, &s3v1.ListBucketsInput{})
+	if err != nil {
+		return r, fmt.Errorf("v1 ListBuckets: %w", err)
+	}
+	var names []string
+	for _, b := range outV1.Buckets {
+		names = append(names, aws.StringValue(b.Name))
+	}
+	r.BucketsV1 = append(r.BucketsV1, names)
+
+	for i := 0; i < listsV2; i++ {
+		out, err := s3ClientV2.ListBuckets(ctx, &s3v2.ListBucketsInput{})
+		if err != nil {
+			return r, fmt.Errorf("v2 ListBuckets: %w", err)
+		}
+		var names []string
+		for _, b := range out.Buckets {
+			names = append(names, awsv2.ToString(b.Name))
+		}
+		r.BucketsV2 = append(r.BucketsV2, names)
+	}
+
+	regionsV1, err := ec2ClientV1.DescribeRegionsWithContext(ctx, &ec2v1.DescribeRegionsInput{})
+	if err != nil {
+		return r, fmt.Errorf("v1 DescribeRegions: %w", err)
+	}
+	for _, reg := range regionsV1.Regions {
+		r.RegionsV1 = append(r.RegionsV1, aws.StringValue(reg.RegionName))
+	}
+	regionsV2, err := ec2ClientV2.DescribeRegions(ctx, &ec2v2.DescribeRegionsInput{})
+	if err != nil {
+		return r, fmt.Errorf("v2 DescribeRegions: %w", err)
+	}
+	for _, reg := range regionsV2.Regions {
+		r.RegionsV2 = append(r.RegionsV2, awsv2.ToString(reg.RegionName))
+	}
+	return r, nil
+}
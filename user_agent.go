@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	stsv1 "github.com/aws/aws-sdk-go/service/sts"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	stsv2 "github.com/aws/aws-sdk-go-v2/service/sts"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// userAgentTransport records the User-Agent header of the last request and
+// answers it with an empty GetCallerIdentity result.
+type userAgentTransport struct {
+	mu   sync.Mutex
+	last string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	t.mu.Lock()
+	t.last = req.Header.Get("User-Agent")
+	t.mu.Unlock()
+	body := `<GetCallerIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/"><GetCallerIdentityResult><Account>123456789012</Account></GetCallerIdentityResult><ResponseMetadata><RequestId>mock</RequestId></ResponseMetadata></GetCallerIdentityResponse>`
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// This example demonstrates interop.InstallUserAgentV1 and
+// interop.InstallUserAgentV2, which add interop.UserAgentSuffix to the
+// User-Agent header of every request so that a program's calls can be found
+// in CloudTrail. v1 appends it in a Build handler, v2 through its user-agent
+// middleware, which replaces characters that are not allowed in a header
+// token. The header of GetCallerIdentity calls is captured from both SDKs
+// for the default suffix, -user-agent, a single token, the {run-id}
+// placeholder, a suffix that needs sanitizing and an empty one; nothing is
+// sent to AWS.
+func main() {
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	fmt.Print("=== User-Agent Suffix Test ===\n\n")
+
+	ctx := context.Background()
+	failures := 0
+	transport := &userAgentTransport{}
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: transport},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	interop.InstallUserAgentV1(sessV1)
+	stsClientV1 := stsv1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	interop.InstallUserAgentV2(&cfgV2)
+	stsClientV2 := stsv2.NewFromConfig(cfgV2)
+
+	// userAgents makes one call with each SDK and returns the headers sent.
+	userAgents := func() (v1, v2 string) {
+		if _, err := stsClientV1.GetCallerIdentityWithContext(ctx, &stsv1.GetCallerIdentityInput{}); err != nil {
+			log.Fatalf("v1 GetCallerIdentity failed: %v", err)
+		}
+		v1 = transport.last
+		if _, err := stsClientV2.GetCallerIdentity(ctx, &stsv2.GetCallerIdentityInput{}); err != nil {
+			log.Fatalf("v2 GetCallerIdentity failed: %v", err)
+		}
+		return v1, transport.last
+	}
+
+	fmt.Println("1. Sending a request with the -user-agent suffix...")
+	suffix := strings.ReplaceAll(interop.UserAgentSuffix, "{run-id}", interop.RunID())
+	fmt.Printf("   Suffix: %s\n", suffix)
+	v1, v2 := userAgents()
+	fmt.Printf("   v1: %s\n   v2: %s\n", v1, v2)
+	// v1 ends the header with the suffix, v2 puts its feature metadata last.
+	if !strings.HasSuffix(v1, " "+suffix) || !strings.Contains(v2, " "+suffix) {
+		fmt.Println("   ✗ The suffix is missing from a header")
+		failures++
+	} else {
+		fmt.Println("   ✓ Both SDKs send the suffix")
+	}
+
+	cases := []struct {
+		name, suffix, want string
+	}{
+		{"another run ID", "sdk-migration-test/ci-4711", "sdk-migration-test/ci-4711"},
+		{"a single token", "nightly-validation", "nightly-validation"},
+		{"the run ID placeholder", "nightly/{run-id}", "nightly/" + interop.RunID()},
+		{"characters outside the token set", "migration check/run 7", "migration-check/run-7"},
+	}
+	fmt.Printf("\n2. Changing the suffix after the clients were created (%d cases)...\n", len(cases))
+	for _, c := range cases {
+		interop.UserAgentSuffix = c.suffix
+		v1, v2 := userAgents()
+		if !strings.HasSuffix(v1, " "+c.want) || !strings.Contains(v2, " "+c.want) {
+			fmt.Printf("   ✗ %s: want %q in both\n       v1: %s\n       v2: %s\n", c.name, c.want, v1, v2)
+			failures++
+			continue
+		}
+		fmt.Printf("   ✓ %s: %q sent as %s\n", c.name, c.suffix, c.want)
+	}
+
+	fmt.Println("\n3. Clearing the suffix...")
+	interop.UserAgentSuffix = ""
+	v1, v2 = userAgents()
+	if strings.Contains(v1, "migration") || strings.Contains(v2, "migration") {
+		fmt.Printf("   ✗ A suffix is still sent\n       v1: %s\n       v2: %s\n", v1, v2)
+		failures++
+	} else {
+		fmt.Println("   ✓ Neither SDK adds anything")
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d user-agent checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs send the same User-Agent suffix, which CloudTrail records as userAgent")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 adds to the header in request.Handlers.Build, v2 with the AddUserAgentKey middleware options")
+	fmt.Println("  - v2 replaces characters outside the header token set with \"-\"; v1's request.AddToUserAgent sends them as given")
+	fmt.Println("  - v2 ends the header with its feature metadata (m/...), v1 with the last token added")
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	ec2v1 "github.com/aws/aws-sdk-go/service/ec2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	ec2v2 "github.com/aws/aws-sdk-go-v2/service/ec2"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// describeVpcsBody is a DescribeVpcs response with a VPC that has two
+// secondary IPv4 blocks, a disassociated one and two IPv6 blocks, a VPC with
+// only its primary block, and a VPC whose secondary block overlaps the
+// first VPC.
+const describeVpcsBody = `<DescribeVpcsResponse xmlns="http://ec2.amazonaws.com/doc/2016-11-15/">
+  <requestId>mock</requestId>
+  <vpcSet>
+    <item>
+      <vpcId>vpc-0a1b2c3d4e5f60001</vpcId>
+      <cidrBlock>10.0.0.0/16</cidrBlock>
+      <cidrBlockAssociationSet>
+        <item><associationId>vpc-cidr-assoc-1</associationId><cidrBlock>10.0.0.0/16</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+        <item><associationId>vpc-cidr-assoc-2</associationId><cidrBlock>10.1.0.0/16</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+        <item><associationId>vpc-cidr-assoc-3</associationId><cidrBlock>100.64.0.0/20</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+        <item><associationId>vpc-cidr-assoc-4</associationId><cidrBlock>10.9.0.0/16</cidrBlock><cidrBlockState><state>disassociated</state></cidrBlockState></item>
+      </cidrBlockAssociationSet>
+      <ipv6CidrBlockAssociationSet>
+        <item><associationId>vpc-cidr-assoc-5</associationId><ipv6CidrBlock>2600:1f18:1234:5600::/56</ipv6CidrBlock><ipv6CidrBlockState><state>associated</state></ipv6CidrBlockState></item>
+        <item><associationId>vpc-cidr-assoc-6</associationId><ipv6CidrBlock>2600:1f18:abcd:ef00::/56</ipv6CidrBlock><ipv6CidrBlockState><state>associated</state></ipv6CidrBlockState></item>
+      </ipv6CidrBlockAssociationSet>
+      <isDefault>false</isDefault>
+    </item>
+    <item>
+      <vpcId>vpc-0a1b2c3d4e5f60002</vpcId>
+      <cidrBlock>172.31.0.0/16</cidrBlock>
+      <cidrBlockAssociationSet>
+        <item><associationId>vpc-cidr-assoc-7</associationId><cidrBlock>172.31.0.0/16</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+      </cidrBlockAssociationSet>
+      <isDefault>true</isDefault>
+    </item>
+    <item>
+      <vpcId>vpc-0a1b2c3d4e5f60003</vpcId>
+      <cidrBlock>192.168.0.0/16</cidrBlock>
+      <cidrBlockAssociationSet>
+        <item><associationId>vpc-cidr-assoc-8</associationId><cidrBlock>192.168.0.0/16</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+        <item><associationId>vpc-cidr-assoc-9</associationId><cidrBlock>10.1.128.0/17</cidrBlock><cidrBlockState><state>associated</state></cidrBlockState></item>
+      </cidrBlockAssociationSet>
+      <isDefault>false</isDefault>
+    </item>
+  </vpcSet>
+</DescribeVpcsResponse>`
+
+// describeVpcsTransport answers every request with describeVpcsBody.
+type describeVpcsTransport struct{}
+
+func (describeVpcsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/xml"}},
+		Body:       io.NopCloser(strings.NewReader(describeVpcsBody)),
+		Request:    req,
+	}, nil
+}
+
+// This example demonstrates the CIDR analysis interop.VPCCIDRsV1,
+// interop.VPCCIDRsV2 and interop.DiffVPCCIDRs perform. A VPC can have
+// secondary IPv4 blocks and IPv6 blocks besides its primary CidrBlock, so
+// comparing that one string misses most of what each SDK decoded. The
+// DescribeVpcs response of both SDKs is mocked; nothing is sent to AWS.
+func main() {
+	fmt.Print("=== VPC CIDR Analysis Test ===\n\n")
+
+	ctx := context.Background()
+	failures := 0
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Credentials: credentials.NewStaticCredentials("AKIDEXAMPLE", "secret", ""),
+		HTTPClient:  &http.Client{Transport: describeVpcsTransport{}},
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: describeVpcsTransport{}}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+
+	fmt.Println("1. Describing the mocked VPCs with both SDKs...")
+	outV1, err := ec2v1.New(sessV1).DescribeVpcsWithContext(ctx, &ec2v1.DescribeVpcsInput{})
+	if err != nil {
+		log.Fatalf("v1 DescribeVpcs failed: %v", err)
+	}
+	outV2, err := ec2v2.NewFromConfig(cfgV2).DescribeVpcs(ctx, &ec2v2.DescribeVpcsInput{})
+	if err != nil {
+		log.Fatalf("v2 DescribeVpcs failed: %v", err)
+	}
+	var cidrsV1, cidrsV2 []interop.VPCCIDRs
+	for _, vpc := range outV1.Vpcs {
+		c, err := interop.VPCCIDRsV1(vpc)
+		if err != nil {
+			fmt.Printf("   ✗ v1 %v\n", err)
+			failures++
+		}
+		cidrsV1 = append(cidrsV1, c)
+	}
+	for _, vpc := range outV2.Vpcs {
+		c, err := interop.VPCCIDRsV2(vpc)
+		if err != nil {
+			fmt.Printf("   ✗ v2 %v\n", err)
+			failures++
+		}
+		cidrsV2 = append(cidrsV2, c)
+	}
+	fmt.Printf("   ✓ v1 returned %d VPCs, v2 %d\n", len(cidrsV1), len(cidrsV2))
+
+	fmt.Println("\n2. Checking the parsed CIDR sets...")
+	want := map[string][]string{
+		"vpc-0a1b2c3d4e5f60001": {"10.0.0.0/16", "10.1.0.0/16", "100.64.0.0/20", "2600:1f18:1234:5600::/56", "2600:1f18:abcd:ef00::/56"},
+		"vpc-0a1b2c3d4e5f60002": {"172.31.0.0/16"},
+		"vpc-0a1b2c3d4e5f60003": {"10.1.128.0/17", "192.168.0.0/16"},
+	}
+	for _, sdk := range []struct {
+		name  string
+		cidrs []interop.VPCCIDRs
+	}{{"v1", cidrsV1}, {"v2", cidrsV2}} {
+		for _, c := range sdk.cidrs {
+			got := fmt.Sprint(c.Prefixes)
+			if exp := fmt.Sprint(mustParsePrefixes(want[c.VpcID])); got != exp {
+				fmt.Printf("   ✗ %s %s: %s, want %s\n", sdk.name, c.VpcID, got, exp)
+				failures++
+				continue
+			}
+			fmt.Printf("   ✓ %s %s: %s\n", sdk.name, c.VpcID, got)
+		}
+	}
+
+	fmt.Println("\n3. Diffing the CIDR sets of both SDKs...")
+	if mismatches := interop.DiffVPCCIDRs(cidrsV1, cidrsV2); len(mismatches) > 0 {
+		for _, m := range mismatches {
+			fmt.Printf("   ✗ %s\n", m)
+		}
+		failures++
+	} else {
+		fmt.Println("   ✓ Both SDKs agree on every VPC's CIDR set")
+	}
+
+	// Drop an IPv6 block and a whole VPC from the v2 side, as a decoder that
+	// skipped Ipv6CidrBlockAssociationSet or a page would.
+	fmt.Println("\n4. Diffing against a v2 result missing blocks...")
+	damaged := []interop.VPCCIDRs{
+		{VpcID: cidrsV2[0].VpcID, Prefixes: cidrsV2[0].Prefixes[:len(cidrsV2[0].Prefixes)-1]},
+		cidrsV2[1],
+	}
+	mismatches := interop.DiffVPCCIDRs(cidrsV1, damaged)
+	got := fmt.Sprint(mismatches)
+	exp := "[vpc-0a1b2c3d4e5f60001: only v1 [2600:1f18:abcd:ef00::/56], only v2 [] vpc-0a1b2c3d4e5f60003: only v1 [10.1.128.0/17 192.168.0.0/16], only v2 []]"
+	if got != exp {
+		fmt.Printf("   ✗ got %s\n       want %s\n", got, exp)
+		failures++
+	} else {
+		for _, m := range mismatches {
+			fmt.Printf("   ✓ %s\n", m)
+		}
+	}
+
+	fmt.Println("\n5. Looking for blocks that overlap between VPCs...")
+	overlaps := interop.VPCCIDROverlaps(cidrsV2)
+	if len(overlaps) != 1 || overlaps[0].PrefixA.String() != "10.1.0.0/16" || overlaps[0].PrefixB.String() != "10.1.128.0/17" {
+		fmt.Printf("   ✗ got %v, want the 10.1.0.0/16 and 10.1.128.0/17 overlap\n", overlaps)
+		failures++
+	} else {
+		fmt.Printf("   ✓ %s\n", overlaps[0])
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d VPC CIDR checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ Both SDKs decode the same primary, secondary and IPv6 CIDR blocks")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 association states are *string, v2 the types.VpcCidrBlockStateCode enum")
+	fmt.Println("  - v2 association sets are slices of structs, v1 slices of pointers")
+	fmt.Println("  - Both keep disassociated blocks in the association sets until they age out")
+}
+
+// mustParsePrefixes parses CIDR blocks written in the order VPCCIDRs sorts
+// them.
+func mustParsePrefixes(blocks []string) []netip.Prefix {
+	out := make([]netip.Prefix, 0, len(blocks))
+	for _, b := range blocks {
+		out = append(out, netip.MustParsePrefix(b))
+	}
+	return out
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	// AWS SDK v1
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	wafv2v1 "github.com/aws/aws-sdk-go/service/wafv2"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	wafv2v2 "github.com/aws/aws-sdk-go-v2/service/wafv2"
+	waftypes "github.com/aws/aws-sdk-go-v2/service/wafv2/types"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// webACL is an SDK-neutral view of a WAFv2 web ACL, combining its list
+// summary with the rules GetWebACL returns.
+type webACL struct {
+	Scope         string
+	Name          string
+	ID            string
+	DefaultAction string
+	Capacity      int64
+	Rules         []string
+	Managed       bool
+	// LockToken is the token ListWebACLs returned; GetWebACL must return
+	// the same one as long as nobody updates the web ACL in between.
+	LockToken string
+}
+
+func (w webACL) String() string {
+	return fmt.Sprintf("%s/%s (ID: %s, Default: %s, Capacity: %d WCU, %d rules, Firewall Manager: %v)",
+		w.Scope, w.Name, w.ID, w.DefaultAction, w.Capacity, len(w.Rules), w.Managed)
+}
+
+// key identifies a web ACL across scopes, which may reuse names.
+func (w webACL) key() string {
+	return w.Scope + "/" + w.ID
+}
+
+// This example demonstrates listing WAFv2 web ACLs with both SDKs and
+// comparing their names, scopes, default actions and rules. The scope is a
+// *string in v1 and the waftypes.Scope enum in v2, and must be passed on
+// every call; CLOUDFRONT web ACLs are only reachable through us-east-1.
+// Neither SDK has a paginator for ListWebACLs, so NextMarker is followed by
+// hand. GetWebACL returns the lock token beside the web ACL rather than in
+// it, and the token must match the one ListWebACLs returned in both SDKs.
+func main() {
+	scopeFlag := flag.String("scope", "all", "web ACL scope to list: regional, cloudfront or all")
+	readOnly := flag.Bool("read-only", false, "block every mutating API call before it is sent")
+	flag.BoolVar(&interop.Verbose, "verbose", false, "print diagnostic details such as credential sources")
+	flag.StringVar(&interop.UserAgentSuffix, "user-agent", interop.UserAgentSuffix, "suffix added to the User-Agent header of every AWS request, for finding them in CloudTrail")
+	interop.ParseFlags()
+
+	var scopes []string
+	switch strings.ToLower(*scopeFlag) {
+	case "regional":
+		scopes = []string{wafv2v1.ScopeRegional}
+	case "cloudfront":
+		scopes = []string{wafv2v1.ScopeCloudfront}
+	case "all":
+		scopes = []string{wafv2v1.ScopeRegional, wafv2v1.ScopeCloudfront}
+	default:
+		fmt.Fprintf(os.Stderr, "Invalid -scope %q: want regional, cloudfront or all\n", *scopeFlag)
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	fmt.Print("=== WAFv2 Web ACL Interop Test ===\n\n")
+
+	// CLOUDFRONT-scoped web ACLs can only be listed in us-east-1.
+	region := "us-east-1"
+	ctx := context.Background()
+
+	sessV1, err := session.NewSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		log.Fatalf("Failed to create v1 session: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV1(sessV1)
+	}
+	interop.InstallOperationSummaryV1(sessV1)
+	interop.InstallUserAgentV1(sessV1)
+	wafClientV1 := wafv2v1.New(sessV1)
+
+	cfgV2, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	if *readOnly {
+		interop.ReadOnly.InstallV2(&cfgV2)
+	}
+	interop.InstallOperationSummaryV2(&cfgV2)
+	interop.InstallUserAgentV2(&cfgV2)
+	wafClientV2 := wafv2v2.NewFromConfig(cfgV2)
+	interop.PrintCredentialSources(ctx, sessV1, cfgV2)
+
+	aclsV1 := make(map[string]webACL)
+	aclsV2 := make(map[string]webACL)
+	step := 1
+	for _, scope := range scopes {
+		fmt.Printf("%d. Using SDK v1 to list %s web ACLs...\n", step, scope)
+		step++
+		found, err := listWebACLsV1(ctx, wafClientV1, scope)
+		if err != nil {
+			log.Fatalf("Failed to list %s web ACLs with v1: %v", scope, err)
+		}
+		for _, w := range found {
+			aclsV1[w.key()] = w
+		}
+		fmt.Printf("   ✓ Found %d %s web ACLs using SDK v1\n", len(found), scope)
+
+		fmt.Printf("\n%d. Using SDK v2 to list %s web ACLs...\n", step, scope)
+		step++
+		found, err = listWebACLsV2(ctx, wafClientV2, waftypes.Scope(scope))
+		if err != nil {
+			log.Fatalf("Failed to list %s web ACLs with v2: %v", scope, err)
+		}
+		for _, w := range found {
+			aclsV2[w.key()] = w
+		}
+		fmt.Printf("   ✓ Found %d %s web ACLs using SDK v2\n\n", len(found), scope)
+	}
+
+	if len(aclsV1) == 0 && len(aclsV2) == 0 {
+		fmt.Printf("No %s web ACLs in this account; both SDKs agree on the empty list.\n", strings.Join(scopes, " or "))
+		return
+	}
+
+	fmt.Printf("%d. Comparing web ACLs...\n", step)
+	keys := make([]string, 0, len(aclsV1))
+	for k := range aclsV1 {
+		keys = append(keys, k)
+	}
+	for k := range aclsV2 {
+		if _, ok := aclsV1[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	mismatches := 0
+	rules := 0
+	for _, k := range keys {
+		v1, inV1 := aclsV1[k]
+		v2, inV2 := aclsV2[k]
+		switch {
+		case !inV1:
+			fmt.Printf("   ✗ %s only seen by SDK v2\n", k)
+			mismatches++
+		case !inV2:
+			fmt.Printf("   ✗ %s only seen by SDK v1\n", k)
+			mismatches++
+		case v1.String() != v2.String() || strings.Join(v1.Rules, ",") != strings.Join(v2.Rules, ","):
+			fmt.Printf("   ✗ %s differs\n       v1: %s %v\n       v2: %s %v\n", k, v1, v1.Rules, v2, v2.Rules)
+			mismatches++
+		case v1.LockToken != v2.LockToken:
+			// Every update issues a new token, so this usually means the
+			// web ACL changed between the two listings.
+			fmt.Printf("   ✗ %s lock tokens differ (v1 %s, v2 %s); was it updated during the run?\n", k, v1.LockToken, v2.LockToken)
+			mismatches++
+		default:
+			rules += len(v1.Rules)
+			fmt.Printf("   ✓ %s\n", v1)
+		}
+	}
+
+	if mismatches > 0 {
+		fmt.Printf("\n✗ %d web ACLs did not match\n", mismatches)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Printf("✓ Both SDKs report the same %d web ACLs with %d rules\n", len(keys), rules)
+	fmt.Println("✓ Lock tokens from ListWebACLs and GetWebACL agree across SDKs")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - v1 Scope is a *string (wafv2.ScopeRegional), v2 the waftypes.Scope enum; both require it on every call")
+	fmt.Println("  - v1 Limit and Capacity are *int64, v2 Limit is *int32 and Capacity a plain int64")
+	fmt.Println("  - v1 ManagedByFirewallManager is *bool, v2 bool; Rules is []*Rule in v1 and []Rule in v2")
+	fmt.Println("  - GetWebACL returns LockToken on the output, not the WebACL, in both SDKs; updates and deletes need it")
+}
+
+// listWebACLsV1 lists the web ACLs of scope with SDK v1, following
+// NextMarker, and reads each one's rules with GetWebACL.
+func listWebACLsV1(ctx context.Context, client *wafv2v1.WAFV2, scope string) ([]webACL, error) {
+	var acls []webACL
+	input := &wafv2v1.ListWebACLsInput{Scope: aws.String(scope), Limit: aws.Int64(100)}
+	for {
+		out, err := client.ListWebACLsWithContext(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range out.WebACLs {
+			got, err := client.GetWebACLWithContext(ctx, &wafv2v1.GetWebACLInput{
+				Id:    summary.Id,
+				Name:  summary.Name,
+				Scope: aws.String(scope),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("getting %s: %w", aws.StringValue(summary.Name), err)
+			}
+			w := webACLFromV1(scope, got.WebACL)
+			w.LockToken = aws.StringValue(summary.LockToken)
+			if token := aws.StringValue(got.LockToken); token != w.LockToken {
+				return nil, fmt.Errorf("%s: GetWebACL lock token %s differs from ListWebACLs token %s", w.Name, token, w.LockToken)
+			}
+			acls = append(acls, w)
+		}
+		if aws.StringValue(out.NextMarker) == "" {
+			return acls, nil
+		}
+		input.NextMarker = out.NextMarker
+	}
+}
+
+// listWebACLsV2 is listWebACLsV1 with SDK v2.
+func listWebACLsV2(ctx context.Context, client *wafv2v2.Client, scope waftypes.Scope) ([]webACL, error) {
+	var acls []webACL
+	input := &wafv2v2.ListWebACLsInput{Scope: scope, Limit: aws.Int32(100)}
+	for {
+		out, err := client.ListWebACLs(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		for _, summary := range out.WebACLs {
+			got, err := client.GetWebACL(ctx, &wafv2v2.GetWebACLInput{
+				Id:    summary.Id,
+				Name:  summary.Name,
+				Scope: scope,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("getting %s: %w", aws.StringValue(summary.Name), err)
+			}
+			w := webACLFromV2(scope, got.WebACL)
+			w.LockToken = aws.StringValue(summary.LockToken)
+			if token := aws.StringValue(got.LockToken); token != w.LockToken {
+				return nil, fmt.Errorf("%s: GetWebACL lock token %s differs from ListWebACLs token %s", w.Name, token, w.LockToken)
+			}
+			acls = append(acls, w)
+		}
+		if aws.StringValue(out.NextMarker) == "" {
+			return acls, nil
+		}
+		input.NextMarker = out.NextMarker
+	}
+}
+
+func webACLFromV1(scope string, acl *wafv2v1.WebACL) webACL {
+	w := webACL{
+		Scope:    scope,
+		Name:     aws.StringValue(acl.Name),
+		ID:       aws.StringValue(acl.Id),
+		Capacity: aws.Int64Value(acl.Capacity),
+		Managed:  aws.BoolValue(acl.ManagedByFirewallManager),
+	}
+	if acl.DefaultAction != nil {
+		switch {
+		case acl.DefaultAction.Allow != nil:
+			w.DefaultAction = "allow"
+		case acl.DefaultAction.Block != nil:
+			w.DefaultAction = "block"
+		}
+	}
+	sort.Slice(acl.Rules, func(i, j int) bool {
+		return aws.Int64Value(acl.Rules[i].Priority) < aws.Int64Value(acl.Rules[j].Priority)
+	})
+	for _, r := range acl.Rules {
+		w.Rules = append(w.Rules, aws.StringValue(r.Name))
+	}
+	return w
+}
+
+func webACLFromV2(scope waftypes.Scope, acl *waftypes.WebACL) webACL {
+	w := webACL{
+		Scope:    string(scope),
+		Name:     aws.StringValue(acl.Name),
+		ID:       aws.StringValue(acl.Id),
+		Capacity: acl.Capacity,
+		Managed:  acl.ManagedByFirewallManager,
+	}
+	if acl.DefaultAction != nil {
+		switch {
+		case acl.DefaultAction.Allow != nil:
+			w.DefaultAction = "allow"
+		case acl.DefaultAction.Block != nil:
+			w.DefaultAction = "block"
+		}
+	}
+	sort.Slice(acl.Rules, func(i, j int) bool {
+		return acl.Rules[i].Priority < acl.Rules[j].Priority
+	})
+	for _, r := range acl.Rules {
+		w.Rules = append(w.Rules, aws.StringValue(r.Name))
+	}
+	return w
+}
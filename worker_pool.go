@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	// AWS SDK v2
+	"github.com/aws/aws-sdk-go-v2/config"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	s3v2 "github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/sdminonne/aws-sdk-migration-tests/interop"
+)
+
+// errMultipleOfFive is returned for the items the pool test function fails on.
+var errMultipleOfFive = errors.New("item is a multiple of 5")
+
+// poolRun calls interop.ForEachConcurrently on items with a function that
+// takes a few milliseconds and fails on multiples of 5. It returns the
+// errors, how often each item was passed to the function and the largest
+// number of calls that ran at once.
+func poolRun(items []int, workers int) (errs []error, calls map[int]int, peak int32) {
+	var mu sync.Mutex
+	var running atomic.Int32
+	calls = make(map[int]int)
+	errs = interop.ForEachConcurrently(items, workers, func(n int) error {
+		now := running.Add(1)
+		defer running.Add(-1)
+		mu.Lock()
+		calls[n]++
+		peak = max(peak, now)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		if n%5 == 0 {
+			return fmt.Errorf("%d: %w", n, errMultipleOfFive)
+		}
+		return nil
+	})
+	return errs, calls, peak
+}
+
+// drainTransport answers ListObjectVersions with two pages of versions and
+// delete markers, and DeleteObject with success, except for the keys under
+// locked/, which are denied.
+type drainTransport struct {
+	mu      sync.Mutex
+	deletes map[string]bool
+	bypass  int
+}
+
+func (t *drainTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+		req.Body.Close()
+	}
+	query := req.URL.Query()
+	status, body := http.StatusOK, ""
+	switch {
+	case req.Method == http.MethodGet && query.Has("versions") && query.Get("key-marker") == "":
+		body = `<ListVersionsResult><Name>b</Name><IsTruncated>true</IsTruncated><NextKeyMarker>c.txt</NextKeyMarker><NextVersionIdMarker>v1</NextVersionIdMarker>` +
+			`<Version><Key>a.txt</Key><VersionId>v1</VersionId></Version><Version><Key>a.txt</Key><VersionId>v2</VersionId></Version>` +
+			`<Version><Key>c.txt</Key><VersionId>v1</VersionId></Version><DeleteMarker><Key>b.txt</Key><VersionId>m1</VersionId></DeleteMarker></ListVersionsResult>`
+	case req.Method == http.MethodGet && query.Has("versions"):
+		body = `<ListVersionsResult><Name>b</Name><IsTruncated>false</IsTruncated>` +
+			`<Version><Key>d.txt</Key><VersionId>null</VersionId></Version><Version><Key>locked/e.txt</Key><VersionId>v1</VersionId></Version>` +
+			`<Version><Key>locked/f.txt</Key><VersionId>v1</VersionId></Version></ListVersionsResult>`
+	case req.Method == http.MethodDelete:
+		key := strings.TrimPrefix(req.URL.Path, "/b/")
+		t.mu.Lock()
+		t.deletes[key+"@"+query.Get("versionId")] = true
+		if req.Header.Get("X-Amz-Bypass-Governance-Retention") == "true" {
+			t.bypass++
+		}
+		t.mu.Unlock()
+		if strings.HasPrefix(key, "locked/") {
+			status, body = http.StatusForbidden, `<Error><Code>AccessDenied</Code><Message>Access Denied because object protected by object lock.</Message></Error>`
+		} else {
+			status = http.StatusNoContent
+		}
+	default:
+		status, body = http.StatusNotImplemented, `<Error><Code>NotImplemented</Code></Error>`
+	}
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/xml"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+// This example demonstrates interop.ForEachConcurrently, the bounded worker
+// pool behind the cleanups, and interop.DrainBucketV2, which uses it to
+// delete every version in a bucket. The pool is run with a function that
+// fails on some items, to check that every item is called once, that no
+// more calls than workers overlap and that each error lands at its item's
+// index. The drainer then empties a mocked bucket whose locked objects
+// cannot be deleted; nothing is sent to AWS.
+func main() {
+	fmt.Print("=== Worker Pool Test ===\n\n")
+
+	failures := 0
+	items := make([]int, 40)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	fmt.Printf("1. Running %d items on 4 workers, failing every multiple of 5...\n", len(items))
+	errs, calls, peak := poolRun(items, 4)
+	wrong := 0
+	for i, n := range items {
+		if calls[n] != 1 {
+			fmt.Printf("   ✗ Item %d was called %d times\n", n, calls[n])
+			wrong++
+		}
+		if failed := errors.Is(errs[i], errMultipleOfFive); failed != (n%5 == 0) {
+			fmt.Printf("   ✗ Item %d: error %v at index %d\n", n, errs[i], i)
+			wrong++
+		}
+	}
+	switch {
+	case len(errs) != len(items):
+		fmt.Printf("   ✗ Got %d errors for %d items\n", len(errs), len(items))
+		failures++
+	case wrong > 0:
+		failures++
+	case peak > 4 || peak < 2:
+		fmt.Printf("   ✗ Up to %d calls ran at once, want 2 to 4\n", peak)
+		failures++
+	default:
+		fmt.Printf("   ✓ Every item called once, up to %d at a time; %d errors, each at its item's index\n", peak, len(items)/5)
+		fmt.Printf("     errs[4] = %v\n", errs[4])
+	}
+
+	fmt.Println("\n2. Running with 0 workers and with no items...")
+	_, _, peak = poolRun(items[:10], 0)
+	errs, calls, _ = poolRun(nil, 4)
+	switch {
+	case peak != 1:
+		fmt.Printf("   ✗ 0 workers ran %d calls at once, want 1\n", peak)
+		failures++
+	case len(errs) != 0 || len(calls) != 0:
+		fmt.Printf("   ✗ No items returned %d errors after %d calls\n", len(errs), len(calls))
+		failures++
+	default:
+		fmt.Println("   ✓ 0 workers run the calls one at a time; no items make no calls")
+	}
+
+	fmt.Println("\n3. Draining a mocked bucket with interop.DrainBucketV2...")
+	ctx := context.Background()
+	transport := &drainTransport{deletes: make(map[string]bool)}
+	cfgV2, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(credentialsv2.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", "")),
+		config.WithHTTPClient(&http.Client{Transport: transport}),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load v2 config: %v", err)
+	}
+	s3ClientV2 := s3v2.NewFromConfig(cfgV2, func(o *s3v2.Options) {
+		o.UsePathStyle = true
+	})
+	deleted, drainErrs := interop.DrainBucketV2(ctx, s3ClientV2, "b", 3, true)
+	want := []string{"a.txt@v1", "a.txt@v2", "b.txt@m1", "c.txt@v1", "d.txt@null", "locked/e.txt@v1", "locked/f.txt@v1"}
+	missing := 0
+	for _, w := range want {
+		if !transport.deletes[w] {
+			fmt.Printf("   ✗ %s was not deleted\n", w)
+			missing++
+		}
+	}
+	switch {
+	case missing > 0 || len(transport.deletes) != len(want):
+		fmt.Printf("   ✗ %d DeleteObject calls, want one for each of the %d versions and markers\n", len(transport.deletes), len(want))
+		failures++
+	case deleted != 5 || len(drainErrs) != 2:
+		fmt.Printf("   ✗ Deleted %d with %d errors, want 5 with 2 (%v)\n", deleted, len(drainErrs), drainErrs)
+		failures++
+	case transport.bypass != len(want):
+		fmt.Printf("   ✗ %d of %d deletes bypassed governance retention\n", transport.bypass, len(want))
+		failures++
+	default:
+		fmt.Printf("   ✓ Deleted %d versions and markers across 2 pages, bypassing governance retention\n", deleted)
+		for _, err := range drainErrs {
+			fmt.Printf("     %v\n", err)
+		}
+	}
+
+	if failures > 0 {
+		fmt.Printf("\n✗ %d worker pool checks failed\n", failures)
+		os.Exit(1)
+	}
+
+	fmt.Println("\n=== Conclusion ===")
+	fmt.Println("✓ ForEachConcurrently bounds concurrency and reports every failed item without stopping the others")
+	fmt.Println("\nKey differences between v1 and v2:")
+	fmt.Println("  - None in the pool: both SDKs' clients are safe for concurrent use, so one client serves every worker")
+	fmt.Println("  - DeleteObjects also takes version IDs, up to 1000 per call, but reports failed keys inside a successful response in both SDKs")
+}